@@ -0,0 +1,156 @@
+package adaptivecards
+
+import "encoding/json"
+
+// Action is a card action. Actions of this package are OpenURL, Submit, Execute, ShowCard and ToggleVisibility.
+type Action interface {
+	actionType() string
+	validate(v *validator, path string)
+}
+
+// ActionStyle is the style of an action button.
+type ActionStyle string
+
+// ActionStyle values.
+const (
+	ActionStyleDefault     ActionStyle = "default"
+	ActionStylePositive    ActionStyle = "positive"
+	ActionStyleDestructive ActionStyle = "destructive"
+)
+
+// ActionProps holds properties shared by all actions.
+type ActionProps struct {
+	ID      string      `json:"id,omitempty"`
+	Title   string      `json:"title,omitempty"`
+	IconURL string      `json:"iconUrl,omitempty"`
+	Style   ActionStyle `json:"style,omitempty"`
+	Tooltip string      `json:"tooltip,omitempty"`
+}
+
+// OpenURL (Action.OpenUrl) opens a URL in the browser or in Teams.
+type OpenURL struct {
+	ActionProps
+	URL string `json:"url"`
+}
+
+// NewOpenURL returns an Action.OpenUrl.
+func NewOpenURL(title, url string) OpenURL {
+	return OpenURL{ActionProps: ActionProps{Title: title}, URL: url}
+}
+
+func (OpenURL) actionType() string { return "Action.OpenUrl" }
+
+// MarshalJSON encodes the action with its type.
+func (a OpenURL) MarshalJSON() ([]byte, error) {
+	type alias OpenURL
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{a.actionType(), alias(a)})
+}
+
+// Submit (Action.Submit) sends input values and Data to the bot that posted the card.
+type Submit struct {
+	ActionProps
+	// Data is merged with input values; it must be encodable as JSON.
+	Data any `json:"data,omitempty"`
+}
+
+// NewSubmit returns an Action.Submit.
+func NewSubmit(title string, data any) Submit {
+	return Submit{ActionProps: ActionProps{Title: title}, Data: data}
+}
+
+func (Submit) actionType() string { return "Action.Submit" }
+
+// MarshalJSON encodes the action with its type.
+func (a Submit) MarshalJSON() ([]byte, error) {
+	type alias Submit
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{a.actionType(), alias(a)})
+}
+
+// Execute (Action.Execute) invokes Verb on the bot that posted the card (Universal Actions).
+type Execute struct {
+	ActionProps
+	Verb string `json:"verb,omitempty"`
+	Data any    `json:"data,omitempty"`
+}
+
+// NewExecute returns an Action.Execute.
+func NewExecute(title, verb string, data any) Execute {
+	return Execute{ActionProps: ActionProps{Title: title}, Verb: verb, Data: data}
+}
+
+func (Execute) actionType() string { return "Action.Execute" }
+
+// MarshalJSON encodes the action with its type.
+func (a Execute) MarshalJSON() ([]byte, error) {
+	type alias Execute
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{a.actionType(), alias(a)})
+}
+
+// ShowCard (Action.ShowCard) expands a nested card below the actions.
+type ShowCard struct {
+	ActionProps
+	Card *Card `json:"card"`
+}
+
+// NewShowCard returns an Action.ShowCard.
+func NewShowCard(title string, card *Card) ShowCard {
+	return ShowCard{ActionProps: ActionProps{Title: title}, Card: card}
+}
+
+func (ShowCard) actionType() string { return "Action.ShowCard" }
+
+// MarshalJSON encodes the action with its type.
+func (a ShowCard) MarshalJSON() ([]byte, error) {
+	type alias ShowCard
+	if a.Card != nil {
+		nested := *a.Card
+		nested.nested = true
+		a.Card = &nested
+	}
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{a.actionType(), alias(a)})
+}
+
+// TargetElement is an element toggled by Action.ToggleVisibility.
+type TargetElement struct {
+	ElementID string `json:"elementId"`
+	// IsVisible sets the visibility instead of toggling it.
+	IsVisible *bool `json:"isVisible,omitempty"`
+}
+
+// ToggleVisibility (Action.ToggleVisibility) shows or hides elements of the card.
+type ToggleVisibility struct {
+	ActionProps
+	TargetElements []TargetElement `json:"targetElements"`
+}
+
+// NewToggleVisibility returns an Action.ToggleVisibility toggling the elements with the given IDs.
+func NewToggleVisibility(title string, elementIDs ...string) ToggleVisibility {
+	targets := make([]TargetElement, 0, len(elementIDs))
+	for _, id := range elementIDs {
+		targets = append(targets, TargetElement{ElementID: id})
+	}
+	return ToggleVisibility{ActionProps: ActionProps{Title: title}, TargetElements: targets}
+}
+
+func (ToggleVisibility) actionType() string { return "Action.ToggleVisibility" }
+
+// MarshalJSON encodes the action with its type.
+func (a ToggleVisibility) MarshalJSON() ([]byte, error) {
+	type alias ToggleVisibility
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{a.actionType(), alias(a)})
+}
@@ -0,0 +1,155 @@
+// Package adaptivecards builds Adaptive Cards (schema 1.5) to be sent in Microsoft Teams messages.
+//
+// Cards are composed of typed elements and actions and validated locally before they are sent:
+//
+//	card := adaptivecards.NewCard(
+//		adaptivecards.TextBlock{Text: "Deployment finished", Size: adaptivecards.TextSizeLarge, Weight: adaptivecards.TextWeightBolder},
+//		adaptivecards.NewFactSet(
+//			adaptivecards.NewFact("Service", "api"),
+//			adaptivecards.NewFact("Version", "1.4.2"),
+//		),
+//	).AddActions(adaptivecards.NewOpenURL("Open dashboard", "https://example.com/dashboard"))
+//
+//	att, err := card.Attachment()
+//	...
+//	msg, err := client.Channels.SendMessage(ctx, "Team", "General", models.MessageBody{Attachments: []models.Attachment{att}})
+package adaptivecards
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+const (
+	// ContentType is the attachment content type of Adaptive Cards.
+	ContentType = "application/vnd.microsoft.card.adaptive"
+	// SchemaURL is the JSON schema of Adaptive Cards.
+	SchemaURL = "http://adaptivecards.io/schemas/adaptive-card.json"
+	// Version is the schema version of cards built by this package.
+	Version = "1.5"
+	// MaxSize is the largest card payload, in bytes, accepted by Microsoft Teams.
+	MaxSize = 28 * 1024
+)
+
+// ErrInvalidCard is returned when a card does not pass local validation.
+var ErrInvalidCard = errors.New("invalid adaptive card")
+
+// MSTeams holds Teams-specific card properties.
+type MSTeams struct {
+	// Width is "Full" to stretch the card to the width of the conversation.
+	Width string `json:"width,omitempty"`
+}
+
+// Card is an Adaptive Card.
+type Card struct {
+	Body                     []Element         `json:"body,omitempty"`
+	Actions                  []Action          `json:"actions,omitempty"`
+	FallbackText             string            `json:"fallbackText,omitempty"`
+	Speak                    string            `json:"speak,omitempty"`
+	Lang                     string            `json:"lang,omitempty"`
+	MinHeight                string            `json:"minHeight,omitempty"`
+	VerticalContentAlignment VerticalAlignment `json:"verticalContentAlignment,omitempty"`
+	MSTeams                  *MSTeams          `json:"msteams,omitempty"`
+
+	// nested is set for cards shown by Action.ShowCard, which carry no schema or version.
+	nested bool
+}
+
+// NewCard returns a card with the given body elements.
+func NewCard(body ...Element) *Card {
+	return &Card{Body: body}
+}
+
+// AddBody appends elements to the card body.
+func (c *Card) AddBody(elements ...Element) *Card {
+	c.Body = append(c.Body, elements...)
+	return c
+}
+
+// AddActions appends actions shown at the bottom of the card.
+func (c *Card) AddActions(actions ...Action) *Card {
+	c.Actions = append(c.Actions, actions...)
+	return c
+}
+
+// FullWidth stretches the card to the width of the conversation in Teams.
+func (c *Card) FullWidth() *Card {
+	c.MSTeams = &MSTeams{Width: "Full"}
+	return c
+}
+
+// MarshalJSON encodes the card with its type, schema and version.
+func (c Card) MarshalJSON() ([]byte, error) {
+	type alias Card
+	if c.nested {
+		return json.Marshal(struct {
+			Type string `json:"type"`
+			alias
+		}{"AdaptiveCard", alias(c)})
+	}
+	return json.Marshal(struct {
+		Type    string `json:"type"`
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		alias
+	}{"AdaptiveCard", SchemaURL, Version, alias(c)})
+}
+
+// Validate checks the card against the rules of schema 1.5 that can be verified locally,
+// such as required properties, allowed values, unique element IDs and the size limit of Teams.
+func (c *Card) Validate() error {
+	if _, err := c.marshal(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// JSON validates the card and returns its JSON encoding.
+func (c *Card) JSON() (string, error) {
+	data, err := c.marshal()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Attachment validates the card and returns it as a message attachment with a new ID.
+// The attachment is referenced from the message body when the message is sent.
+func (c *Card) Attachment() (models.Attachment, error) {
+	content, err := c.JSON()
+	if err != nil {
+		return models.Attachment{}, err
+	}
+	return models.Attachment{
+		ID:          newAttachmentID(),
+		ContentType: ContentType,
+		Content:     content,
+	}, nil
+}
+
+func (c *Card) marshal() ([]byte, error) {
+	v := newValidator()
+	v.card(c, "")
+	if err := v.finish(); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCard, err)
+	}
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("%w: card is %d bytes, Teams accepts at most %d", ErrInvalidCard, len(data), MaxSize)
+	}
+	return data, nil
+}
+
+func newAttachmentID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,165 @@
+package adaptivecards
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var out map[string]any
+	require.NoError(t, json.Unmarshal([]byte(s), &out))
+	return out
+}
+
+func TestCard_JSON(t *testing.T) {
+	t.Parallel()
+
+	card := NewCard(
+		TextBlock{Text: "Deployment finished", Weight: TextWeightBolder},
+		NewFactSet(NewFact("Service", "api")),
+		NewColumnSet(NewColumn("auto", NewImage("https://example.com/a.png", "avatar"))),
+		NewContainer(NewTextBlock("details")),
+	).AddActions(
+		NewOpenURL("Open", "https://example.com"),
+		NewShowCard("More", NewCard(NewTextBlock("nested"))),
+	).FullWidth()
+
+	s, err := card.JSON()
+	require.NoError(t, err)
+	got := decode(t, s)
+
+	require.Equal(t, "AdaptiveCard", got["type"])
+	require.Equal(t, SchemaURL, got["$schema"])
+	require.Equal(t, Version, got["version"])
+	require.Equal(t, map[string]any{"width": "Full"}, got["msteams"])
+
+	body := got["body"].([]any)
+	require.Len(t, body, 4)
+	for i, want := range []string{"TextBlock", "FactSet", "ColumnSet", "Container"} {
+		require.Equal(t, want, body[i].(map[string]any)["type"])
+	}
+	text := body[0].(map[string]any)
+	require.Equal(t, "bolder", text["weight"])
+	require.NotContains(t, text, "size")
+	column := body[2].(map[string]any)["columns"].([]any)[0].(map[string]any)
+	require.Equal(t, "Column", column["type"])
+	require.Equal(t, "Image", column["items"].([]any)[0].(map[string]any)["type"])
+
+	actions := got["actions"].([]any)
+	require.Equal(t, "Action.OpenUrl", actions[0].(map[string]any)["type"])
+	show := actions[1].(map[string]any)
+	require.Equal(t, "Action.ShowCard", show["type"])
+	nested := show["card"].(map[string]any)
+	require.Equal(t, "AdaptiveCard", nested["type"])
+	require.NotContains(t, nested, "version")
+	require.NotContains(t, nested, "$schema")
+}
+
+func TestCard_Validate(t *testing.T) {
+	t.Parallel()
+
+	hidden := false
+	tests := []struct {
+		name            string
+		card            *Card
+		wantErrContains string
+	}{
+		{
+			name: "valid card",
+			card: NewCard(
+				TextBlock{ElementProps: ElementProps{ID: "details", IsVisible: &hidden}, Text: "hidden"},
+				NewImage("data:image/png;base64,AAAA", "dot"),
+			).AddActions(NewToggleVisibility("Details", "details"), NewSubmit("Send", map[string]string{"k": "v"})),
+		},
+		{
+			name:            "empty card",
+			card:            NewCard(),
+			wantErrContains: "card: card has no body elements or actions",
+		},
+		{
+			name:            "empty text",
+			card:            NewCard(NewTextBlock(" ")),
+			wantErrContains: "body[0].text: is required",
+		},
+		{
+			name:            "relative image URL",
+			card:            NewCard(NewImage("/a.png", "a")),
+			wantErrContains: `body[0].url: "/a.png" is not an absolute URL`,
+		},
+		{
+			name:            "unsupported enum value",
+			card:            NewCard(TextBlock{Text: "x", Size: "huge"}),
+			wantErrContains: `body[0].size: unsupported value "huge"`,
+		},
+		{
+			name:            "duplicate id",
+			card:            NewCard(NewContainer(TextBlock{ElementProps: ElementProps{ID: "a"}, Text: "x"}), TextBlock{ElementProps: ElementProps{ID: "a"}, Text: "y"}),
+			wantErrContains: `body[1].id: duplicate id "a"`,
+		},
+		{
+			name:            "missing toggle target",
+			card:            NewCard(NewTextBlock("x")).AddActions(NewToggleVisibility("Toggle", "nope")),
+			wantErrContains: `actions[0].targetElements[0]: target element "nope" does not exist`,
+		},
+		{
+			name:            "show card as select action",
+			card:            NewCard(Container{Items: []Element{NewTextBlock("x")}, SelectAction: NewShowCard("More", NewCard(NewTextBlock("y")))}),
+			wantErrContains: "body[0].selectAction: Action.ShowCard is not allowed here",
+		},
+		{
+			name:            "invalid nested card",
+			card:            NewCard(NewTextBlock("x")).AddActions(NewShowCard("More", NewCard())),
+			wantErrContains: "actions[0].card: card has no body elements or actions",
+		},
+		{
+			name:            "execute without verb or data",
+			card:            NewCard(NewTextBlock("x")).AddActions(NewExecute("Run", "", nil)),
+			wantErrContains: "actions[0]: verb or data is required",
+		},
+		{
+			name:            "too large",
+			card:            NewCard(NewTextBlock(strings.Repeat("a", MaxSize))),
+			wantErrContains: "Teams accepts at most",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.card.Validate()
+
+			if tt.wantErrContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrInvalidCard))
+			require.Contains(t, err.Error(), tt.wantErrContains)
+		})
+	}
+}
+
+func TestCard_Attachment(t *testing.T) {
+	t.Parallel()
+
+	card := NewCard(NewTextBlock("hello"))
+
+	a1, err := card.Attachment()
+	require.NoError(t, err)
+	a2, err := card.Attachment()
+	require.NoError(t, err)
+
+	require.Equal(t, ContentType, a1.ContentType)
+	require.Len(t, a1.ID, 32)
+	require.NotEqual(t, a1.ID, a2.ID)
+	require.Equal(t, "hello", decode(t, a1.Content)["body"].([]any)[0].(map[string]any)["text"])
+
+	_, err = NewCard().Attachment()
+	require.ErrorIs(t, err, ErrInvalidCard)
+}
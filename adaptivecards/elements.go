@@ -0,0 +1,326 @@
+package adaptivecards
+
+import "encoding/json"
+
+// Element is a card element placed in a card body, container or column.
+// Elements of this package are TextBlock, Image, FactSet, ColumnSet, Container and ActionSet.
+type Element interface {
+	elementType() string
+	validate(v *validator, path string)
+}
+
+// ElementProps holds properties shared by all elements.
+type ElementProps struct {
+	// ID identifies the element, e.g. as a target of Action.ToggleVisibility.
+	ID        string  `json:"id,omitempty"`
+	Spacing   Spacing `json:"spacing,omitempty"`
+	Separator bool    `json:"separator,omitempty"`
+	// IsVisible hides the element when set to false.
+	IsVisible *bool `json:"isVisible,omitempty"`
+}
+
+// Spacing controls the space between an element and the preceding one.
+type Spacing string
+
+// Spacing values.
+const (
+	SpacingNone       Spacing = "none"
+	SpacingSmall      Spacing = "small"
+	SpacingDefault    Spacing = "default"
+	SpacingMedium     Spacing = "medium"
+	SpacingLarge      Spacing = "large"
+	SpacingExtraLarge Spacing = "extraLarge"
+	SpacingPadding    Spacing = "padding"
+)
+
+// TextSize is the size of text.
+type TextSize string
+
+// TextSize values.
+const (
+	TextSizeSmall      TextSize = "small"
+	TextSizeDefault    TextSize = "default"
+	TextSizeMedium     TextSize = "medium"
+	TextSizeLarge      TextSize = "large"
+	TextSizeExtraLarge TextSize = "extraLarge"
+)
+
+// TextWeight is the weight of text.
+type TextWeight string
+
+// TextWeight values.
+const (
+	TextWeightLighter TextWeight = "lighter"
+	TextWeightDefault TextWeight = "default"
+	TextWeightBolder  TextWeight = "bolder"
+)
+
+// Color is the color of text.
+type Color string
+
+// Color values.
+const (
+	ColorDefault   Color = "default"
+	ColorDark      Color = "dark"
+	ColorLight     Color = "light"
+	ColorAccent    Color = "accent"
+	ColorGood      Color = "good"
+	ColorWarning   Color = "warning"
+	ColorAttention Color = "attention"
+)
+
+// FontType is the font of text.
+type FontType string
+
+// FontType values.
+const (
+	FontTypeDefault   FontType = "default"
+	FontTypeMonospace FontType = "monospace"
+)
+
+// HorizontalAlignment aligns an element horizontally.
+type HorizontalAlignment string
+
+// HorizontalAlignment values.
+const (
+	HorizontalAlignmentLeft   HorizontalAlignment = "left"
+	HorizontalAlignmentCenter HorizontalAlignment = "center"
+	HorizontalAlignmentRight  HorizontalAlignment = "right"
+)
+
+// VerticalAlignment aligns the content of a container vertically.
+type VerticalAlignment string
+
+// VerticalAlignment values.
+const (
+	VerticalAlignmentTop    VerticalAlignment = "top"
+	VerticalAlignmentCenter VerticalAlignment = "center"
+	VerticalAlignmentBottom VerticalAlignment = "bottom"
+)
+
+// ImageSize is the size of an image.
+type ImageSize string
+
+// ImageSize values.
+const (
+	ImageSizeAuto    ImageSize = "auto"
+	ImageSizeStretch ImageSize = "stretch"
+	ImageSizeSmall   ImageSize = "small"
+	ImageSizeMedium  ImageSize = "medium"
+	ImageSizeLarge   ImageSize = "large"
+)
+
+// ImageStyle is the style of an image.
+type ImageStyle string
+
+// ImageStyle values.
+const (
+	ImageStyleDefault ImageStyle = "default"
+	ImageStylePerson  ImageStyle = "person"
+)
+
+// ContainerStyle is the style of a container or column.
+type ContainerStyle string
+
+// ContainerStyle values.
+const (
+	ContainerStyleDefault   ContainerStyle = "default"
+	ContainerStyleEmphasis  ContainerStyle = "emphasis"
+	ContainerStyleGood      ContainerStyle = "good"
+	ContainerStyleAttention ContainerStyle = "attention"
+	ContainerStyleWarning   ContainerStyle = "warning"
+	ContainerStyleAccent    ContainerStyle = "accent"
+)
+
+// TextBlock displays text, optionally formatted with a subset of Markdown.
+type TextBlock struct {
+	ElementProps
+	Text                string              `json:"text"`
+	Size                TextSize            `json:"size,omitempty"`
+	Weight              TextWeight          `json:"weight,omitempty"`
+	Color               Color               `json:"color,omitempty"`
+	FontType            FontType            `json:"fontType,omitempty"`
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
+	IsSubtle            bool                `json:"isSubtle,omitempty"`
+	Wrap                bool                `json:"wrap,omitempty"`
+	MaxLines            int                 `json:"maxLines,omitempty"`
+}
+
+// NewTextBlock returns a wrapping text block.
+func NewTextBlock(text string) TextBlock {
+	return TextBlock{Text: text, Wrap: true}
+}
+
+func (TextBlock) elementType() string { return "TextBlock" }
+
+// MarshalJSON encodes the text block with its type.
+func (t TextBlock) MarshalJSON() ([]byte, error) {
+	type alias TextBlock
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{t.elementType(), alias(t)})
+}
+
+// Image displays an image.
+type Image struct {
+	ElementProps
+	// URL is an http(s) or data URL of the image.
+	URL                 string              `json:"url"`
+	AltText             string              `json:"altText,omitempty"`
+	Size                ImageSize           `json:"size,omitempty"`
+	Style               ImageStyle          `json:"style,omitempty"`
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
+	// Width and Height are sizes in pixels, e.g. "50px"; they take precedence over Size.
+	Width  string `json:"width,omitempty"`
+	Height string `json:"height,omitempty"`
+	// SelectAction is invoked when the image is selected; ShowCard is not allowed here.
+	SelectAction Action `json:"selectAction,omitempty"`
+}
+
+// NewImage returns an image with alternative text.
+func NewImage(url, altText string) Image {
+	return Image{URL: url, AltText: altText}
+}
+
+func (Image) elementType() string { return "Image" }
+
+// MarshalJSON encodes the image with its type.
+func (i Image) MarshalJSON() ([]byte, error) {
+	type alias Image
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{i.elementType(), alias(i)})
+}
+
+// Fact is a title and value pair of a FactSet.
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// NewFact returns a fact.
+func NewFact(title, value string) Fact {
+	return Fact{Title: title, Value: value}
+}
+
+// FactSet displays facts as a table of titles and values.
+type FactSet struct {
+	ElementProps
+	Facts []Fact `json:"facts"`
+}
+
+// NewFactSet returns a fact set.
+func NewFactSet(facts ...Fact) FactSet {
+	return FactSet{Facts: facts}
+}
+
+func (FactSet) elementType() string { return "FactSet" }
+
+// MarshalJSON encodes the fact set with its type.
+func (f FactSet) MarshalJSON() ([]byte, error) {
+	type alias FactSet
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{f.elementType(), alias(f)})
+}
+
+// Column is a column of a ColumnSet.
+type Column struct {
+	ElementProps
+	Items []Element `json:"items,omitempty"`
+	// Width is "auto", "stretch", a relative weight such as "2", or pixels such as "50px".
+	Width                    string            `json:"width,omitempty"`
+	Style                    ContainerStyle    `json:"style,omitempty"`
+	VerticalContentAlignment VerticalAlignment `json:"verticalContentAlignment,omitempty"`
+	SelectAction             Action            `json:"selectAction,omitempty"`
+}
+
+// NewColumn returns a column with the given items.
+func NewColumn(width string, items ...Element) Column {
+	return Column{Width: width, Items: items}
+}
+
+// MarshalJSON encodes the column with its type.
+func (c Column) MarshalJSON() ([]byte, error) {
+	type alias Column
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{"Column", alias(c)})
+}
+
+// ColumnSet lays out columns side by side.
+type ColumnSet struct {
+	ElementProps
+	Columns             []Column            `json:"columns,omitempty"`
+	Style               ContainerStyle      `json:"style,omitempty"`
+	HorizontalAlignment HorizontalAlignment `json:"horizontalAlignment,omitempty"`
+	SelectAction        Action              `json:"selectAction,omitempty"`
+}
+
+// NewColumnSet returns a column set.
+func NewColumnSet(columns ...Column) ColumnSet {
+	return ColumnSet{Columns: columns}
+}
+
+func (ColumnSet) elementType() string { return "ColumnSet" }
+
+// MarshalJSON encodes the column set with its type.
+func (c ColumnSet) MarshalJSON() ([]byte, error) {
+	type alias ColumnSet
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{c.elementType(), alias(c)})
+}
+
+// Container groups elements, e.g. to style them or toggle their visibility together.
+type Container struct {
+	ElementProps
+	Items                    []Element         `json:"items"`
+	Style                    ContainerStyle    `json:"style,omitempty"`
+	VerticalContentAlignment VerticalAlignment `json:"verticalContentAlignment,omitempty"`
+	Bleed                    bool              `json:"bleed,omitempty"`
+	SelectAction             Action            `json:"selectAction,omitempty"`
+}
+
+// NewContainer returns a container with the given items.
+func NewContainer(items ...Element) Container {
+	return Container{Items: items}
+}
+
+func (Container) elementType() string { return "Container" }
+
+// MarshalJSON encodes the container with its type.
+func (c Container) MarshalJSON() ([]byte, error) {
+	type alias Container
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{c.elementType(), alias(c)})
+}
+
+// ActionSet displays actions anywhere in the card body.
+type ActionSet struct {
+	ElementProps
+	Actions []Action `json:"actions"`
+}
+
+// NewActionSet returns an action set.
+func NewActionSet(actions ...Action) ActionSet {
+	return ActionSet{Actions: actions}
+}
+
+func (ActionSet) elementType() string { return "ActionSet" }
+
+// MarshalJSON encodes the action set with its type.
+func (a ActionSet) MarshalJSON() ([]byte, error) {
+	type alias ActionSet
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{a.elementType(), alias(a)})
+}
@@ -0,0 +1,302 @@
+package adaptivecards
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	pixelSize   = regexp.MustCompile(`^\d+px$`)
+	columnWidth = regexp.MustCompile(`^(auto|stretch|\d+(\.\d+)?|\d+px)$`)
+)
+
+// validator walks a card, recording the first problem found and the element IDs seen.
+type validator struct {
+	err     error
+	ids     map[string]bool
+	targets map[string]string // target element ID -> path of the toggling action
+}
+
+func newValidator() *validator {
+	return &validator{ids: make(map[string]bool), targets: make(map[string]string)}
+}
+
+func (v *validator) failf(path, format string, args ...any) {
+	if v.err != nil {
+		return
+	}
+	if path == "" {
+		path = "card"
+	}
+	v.err = fmt.Errorf("%w: %s: %s", ErrInvalidCard, path, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) finish() error {
+	if v.err != nil {
+		return v.err
+	}
+	paths := make([]string, 0, len(v.targets))
+	for id, path := range v.targets {
+		if !v.ids[id] {
+			paths = append(paths, fmt.Sprintf("%s: target element %q does not exist", path, id))
+		}
+	}
+	if len(paths) > 0 {
+		slices.Sort(paths)
+		return fmt.Errorf("%w: %s", ErrInvalidCard, paths[0])
+	}
+	return nil
+}
+
+func (v *validator) card(c *Card, path string) {
+	if c == nil {
+		v.failf(path, "card is nil")
+		return
+	}
+	if len(c.Body) == 0 && len(c.Actions) == 0 {
+		v.failf(path, "card has no body elements or actions")
+	}
+	checkEnum(v, join(path, "verticalContentAlignment"), c.VerticalContentAlignment,
+		VerticalAlignmentTop, VerticalAlignmentCenter, VerticalAlignmentBottom)
+	if c.MinHeight != "" && !pixelSize.MatchString(c.MinHeight) {
+		v.failf(join(path, "minHeight"), "%q is not a pixel size such as \"100px\"", c.MinHeight)
+	}
+	if c.MSTeams != nil && c.MSTeams.Width != "" && c.MSTeams.Width != "Full" {
+		v.failf(join(path, "msteams.width"), "%q is not supported, use \"Full\"", c.MSTeams.Width)
+	}
+	v.elements(c.Body, join(path, "body"))
+	v.actions(c.Actions, join(path, "actions"), true)
+}
+
+func (v *validator) elements(elements []Element, path string) {
+	for i, e := range elements {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		if e == nil {
+			v.failf(p, "element is nil")
+			continue
+		}
+		e.validate(v, p)
+	}
+}
+
+func (v *validator) actions(actions []Action, path string, allowShowCard bool) {
+	for i, a := range actions {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		v.action(a, p, allowShowCard)
+	}
+}
+
+func (v *validator) action(a Action, path string, allowShowCard bool) {
+	if a == nil {
+		v.failf(path, "action is nil")
+		return
+	}
+	switch a.(type) {
+	case ShowCard, *ShowCard:
+		if !allowShowCard {
+			v.failf(path, "Action.ShowCard is not allowed here")
+			return
+		}
+	}
+	a.validate(v, path)
+}
+
+func (v *validator) selectAction(a Action, path string) {
+	if a != nil {
+		v.action(a, path, false)
+	}
+}
+
+func (v *validator) elementProps(p ElementProps, path string) {
+	checkEnum(v, join(path, "spacing"), p.Spacing, SpacingNone, SpacingSmall, SpacingDefault,
+		SpacingMedium, SpacingLarge, SpacingExtraLarge, SpacingPadding)
+	v.id(p.ID, join(path, "id"))
+}
+
+func (v *validator) actionProps(p ActionProps, path string) {
+	checkEnum(v, join(path, "style"), p.Style, ActionStyleDefault, ActionStylePositive, ActionStyleDestructive)
+	v.id(p.ID, join(path, "id"))
+	if p.IconURL != "" {
+		v.url(p.IconURL, join(path, "iconUrl"), true)
+	}
+}
+
+func (v *validator) id(id, path string) {
+	if id == "" {
+		return
+	}
+	if v.ids[id] {
+		v.failf(path, "duplicate id %q", id)
+	}
+	v.ids[id] = true
+}
+
+func (v *validator) url(raw, path string, allowData bool) {
+	if raw == "" {
+		v.failf(path, "is required")
+		return
+	}
+	if allowData && strings.HasPrefix(raw, "data:") {
+		return
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" && u.Opaque == "" {
+		v.failf(path, "%q is not an absolute URL", raw)
+	}
+}
+
+func (v *validator) data(data any, path string) {
+	if data == nil {
+		return
+	}
+	if _, err := json.Marshal(data); err != nil {
+		v.failf(path, "cannot be encoded as JSON: %v", err)
+	}
+}
+
+// checkEnum records a failure when value is set but not one of allowed.
+func checkEnum[T ~string](v *validator, path string, value T, allowed ...T) {
+	if value != "" && !slices.Contains(allowed, value) {
+		v.failf(path, "unsupported value %q", value)
+	}
+}
+
+func join(path, property string) string {
+	if path == "" {
+		return property
+	}
+	return path + "." + property
+}
+
+func (t TextBlock) validate(v *validator, path string) {
+	v.elementProps(t.ElementProps, path)
+	if strings.TrimSpace(t.Text) == "" {
+		v.failf(join(path, "text"), "is required")
+	}
+	checkEnum(v, join(path, "size"), t.Size, TextSizeSmall, TextSizeDefault, TextSizeMedium, TextSizeLarge, TextSizeExtraLarge)
+	checkEnum(v, join(path, "weight"), t.Weight, TextWeightLighter, TextWeightDefault, TextWeightBolder)
+	checkEnum(v, join(path, "color"), t.Color, ColorDefault, ColorDark, ColorLight, ColorAccent, ColorGood, ColorWarning, ColorAttention)
+	checkEnum(v, join(path, "fontType"), t.FontType, FontTypeDefault, FontTypeMonospace)
+	checkEnum(v, join(path, "horizontalAlignment"), t.HorizontalAlignment,
+		HorizontalAlignmentLeft, HorizontalAlignmentCenter, HorizontalAlignmentRight)
+	if t.MaxLines < 0 {
+		v.failf(join(path, "maxLines"), "must not be negative")
+	}
+}
+
+func (i Image) validate(v *validator, path string) {
+	v.elementProps(i.ElementProps, path)
+	v.url(i.URL, join(path, "url"), true)
+	checkEnum(v, join(path, "size"), i.Size, ImageSizeAuto, ImageSizeStretch, ImageSizeSmall, ImageSizeMedium, ImageSizeLarge)
+	checkEnum(v, join(path, "style"), i.Style, ImageStyleDefault, ImageStylePerson)
+	checkEnum(v, join(path, "horizontalAlignment"), i.HorizontalAlignment,
+		HorizontalAlignmentLeft, HorizontalAlignmentCenter, HorizontalAlignmentRight)
+	if i.Width != "" && !pixelSize.MatchString(i.Width) {
+		v.failf(join(path, "width"), "%q is not a pixel size such as \"50px\"", i.Width)
+	}
+	if i.Height != "" && i.Height != "auto" && i.Height != "stretch" && !pixelSize.MatchString(i.Height) {
+		v.failf(join(path, "height"), "%q is not a pixel size such as \"50px\"", i.Height)
+	}
+	v.selectAction(i.SelectAction, join(path, "selectAction"))
+}
+
+func (f FactSet) validate(v *validator, path string) {
+	v.elementProps(f.ElementProps, path)
+	if len(f.Facts) == 0 {
+		v.failf(join(path, "facts"), "at least one fact is required")
+	}
+	for i, fact := range f.Facts {
+		if strings.TrimSpace(fact.Title) == "" {
+			v.failf(fmt.Sprintf("%s.facts[%d].title", path, i), "is required")
+		}
+	}
+}
+
+func (c ColumnSet) validate(v *validator, path string) {
+	v.elementProps(c.ElementProps, path)
+	checkEnum(v, join(path, "style"), c.Style, containerStyles...)
+	checkEnum(v, join(path, "horizontalAlignment"), c.HorizontalAlignment,
+		HorizontalAlignmentLeft, HorizontalAlignmentCenter, HorizontalAlignmentRight)
+	for i, col := range c.Columns {
+		p := fmt.Sprintf("%s.columns[%d]", path, i)
+		v.elementProps(col.ElementProps, p)
+		if col.Width != "" && !columnWidth.MatchString(col.Width) {
+			v.failf(join(p, "width"), "%q is not auto, stretch, a weight or a pixel size", col.Width)
+		}
+		checkEnum(v, join(p, "style"), col.Style, containerStyles...)
+		checkEnum(v, join(p, "verticalContentAlignment"), col.VerticalContentAlignment,
+			VerticalAlignmentTop, VerticalAlignmentCenter, VerticalAlignmentBottom)
+		v.elements(col.Items, join(p, "items"))
+		v.selectAction(col.SelectAction, join(p, "selectAction"))
+	}
+	v.selectAction(c.SelectAction, join(path, "selectAction"))
+}
+
+func (c Container) validate(v *validator, path string) {
+	v.elementProps(c.ElementProps, path)
+	if len(c.Items) == 0 {
+		v.failf(join(path, "items"), "at least one item is required")
+	}
+	checkEnum(v, join(path, "style"), c.Style, containerStyles...)
+	checkEnum(v, join(path, "verticalContentAlignment"), c.VerticalContentAlignment,
+		VerticalAlignmentTop, VerticalAlignmentCenter, VerticalAlignmentBottom)
+	v.elements(c.Items, join(path, "items"))
+	v.selectAction(c.SelectAction, join(path, "selectAction"))
+}
+
+func (a ActionSet) validate(v *validator, path string) {
+	v.elementProps(a.ElementProps, path)
+	if len(a.Actions) == 0 {
+		v.failf(join(path, "actions"), "at least one action is required")
+	}
+	v.actions(a.Actions, join(path, "actions"), true)
+}
+
+func (a OpenURL) validate(v *validator, path string) {
+	v.actionProps(a.ActionProps, path)
+	v.url(a.URL, join(path, "url"), false)
+}
+
+func (a Submit) validate(v *validator, path string) {
+	v.actionProps(a.ActionProps, path)
+	v.data(a.Data, join(path, "data"))
+}
+
+func (a Execute) validate(v *validator, path string) {
+	v.actionProps(a.ActionProps, path)
+	if a.Verb == "" && a.Data == nil {
+		v.failf(path, "verb or data is required")
+	}
+	v.data(a.Data, join(path, "data"))
+}
+
+func (a ShowCard) validate(v *validator, path string) {
+	v.actionProps(a.ActionProps, path)
+	v.card(a.Card, join(path, "card"))
+}
+
+func (a ToggleVisibility) validate(v *validator, path string) {
+	v.actionProps(a.ActionProps, path)
+	if len(a.TargetElements) == 0 {
+		v.failf(join(path, "targetElements"), "at least one target element is required")
+	}
+	for i, t := range a.TargetElements {
+		p := fmt.Sprintf("%s.targetElements[%d]", path, i)
+		if t.ElementID == "" {
+			v.failf(join(p, "elementId"), "is required")
+			continue
+		}
+		if _, ok := v.targets[t.ElementID]; !ok {
+			v.targets[t.ElementID] = p
+		}
+	}
+}
+
+var containerStyles = []ContainerStyle{
+	ContainerStyleDefault, ContainerStyleEmphasis, ContainerStyleGood,
+	ContainerStyleAttention, ContainerStyleWarning, ContainerStyleAccent,
+}
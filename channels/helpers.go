@@ -2,6 +2,7 @@ package channels
 
 import (
 	"strings"
+	"time"
 
 	"github.com/pzsp-teams/lib/internal/mentions"
 	"github.com/pzsp-teams/lib/models"
@@ -28,3 +29,21 @@ func isTeamRef(low, raw, teamRef, teamID string) bool {
 func isChannelRef(low, raw, channelRef, channelID string) bool {
 	return low == "channel" || raw == channelRef || raw == channelID
 }
+
+// formatSyncToken joins the delta link of the next sync with the last modification time
+// of the newest message synced so far.
+func formatSyncToken(since time.Time, deltaLink string) string {
+	return since.UTC().Format(time.RFC3339Nano) + " " + deltaLink
+}
+
+func parseSyncToken(token string) (since time.Time, deltaLink string, ok bool) {
+	ts, deltaLink, ok := strings.Cut(token, " ")
+	if !ok || deltaLink == "" {
+		return time.Time{}, "", false
+	}
+	since, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return since, deltaLink, true
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
 	"github.com/pzsp-teams/lib/internal/adapter"
 	"github.com/pzsp-teams/lib/internal/api"
+	"github.com/pzsp-teams/lib/internal/attachments"
 	"github.com/pzsp-teams/lib/internal/mentions"
 	"github.com/pzsp-teams/lib/internal/resources"
 	snd "github.com/pzsp-teams/lib/internal/sender"
@@ -69,14 +72,14 @@ func (o *ops) DeleteChannel(ctx context.Context, teamID, channelID, channelRef s
 }
 
 func (o *ops) SendMessage(ctx context.Context, teamID, channelID string, body models.MessageBody) (*models.Message, error) {
-	ments, err := mentions.PrepareMentions(&body)
+	atts, hosted, ments, err := attachments.PrepareBody(&body)
 	if err != nil {
 		return nil, snd.MapError(&snd.RequestError{
 			Code:    http.StatusBadRequest,
-			Message: "Failed to prepare mentions: " + err.Error(),
+			Message: err.Error(),
 		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID))
 	}
-	resp, requestErr := o.channelAPI.SendMessage(ctx, teamID, channelID, body.Content, string(body.ContentType), ments)
+	resp, requestErr := o.channelAPI.SendMessage(ctx, teamID, channelID, body.Content, string(body.ContentType), body.Subject, string(body.Importance), ments, atts, hosted)
 	if requestErr != nil {
 		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID))
 	}
@@ -84,26 +87,116 @@ func (o *ops) SendMessage(ctx context.Context, teamID, channelID string, body mo
 }
 
 func (o *ops) SendReply(ctx context.Context, teamID, channelID, messageID string, body models.MessageBody) (*models.Message, error) {
-	ments, err := mentions.PrepareMentions(&body)
+	atts, hosted, ments, err := attachments.PrepareBody(&body)
 	if err != nil {
 		return nil, snd.MapError(&snd.RequestError{
 			Code:    http.StatusBadRequest,
-			Message: "Failed to prepare mentions: " + err.Error(),
+			Message: err.Error(),
 		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
 	}
-	resp, requestErr := o.channelAPI.SendReply(ctx, teamID, channelID, messageID, body.Content, string(body.ContentType), ments)
+	resp, requestErr := o.channelAPI.SendReply(ctx, teamID, channelID, messageID, body.Content, string(body.ContentType), body.Subject, string(body.Importance), ments, atts, hosted)
 	if requestErr != nil {
 		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
 	}
 	return adapter.MapGraphMessage(resp), nil
 }
 
+// UpdateMessage replaces the body of a message. Graph answers the update with no content,
+// so the message is read back to report its lastEditedDateTime.
+func (o *ops) UpdateMessage(ctx context.Context, teamID, channelID, messageID string, body models.MessageBody) (*models.Message, error) {
+	if len(body.HostedContents) > 0 {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: "Hosted contents can only be sent with new messages",
+		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+	}
+	if body.Subject != "" || body.Importance != "" {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: "Subject and importance can only be set on new messages",
+		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+	}
+	atts, _, ments, err := attachments.PrepareBody(&body)
+	if err != nil {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+	}
+	requestErr := o.channelAPI.UpdateMessage(ctx, teamID, channelID, messageID, body.Content, string(body.ContentType), ments, atts)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+	}
+	return o.GetMessage(ctx, teamID, channelID, messageID)
+}
+
+// UpdateReply replaces the body of a reply and reads it back, like UpdateMessage.
+func (o *ops) UpdateReply(ctx context.Context, teamID, channelID, messageID, replyID string, body models.MessageBody) (*models.Message, error) {
+	if len(body.HostedContents) > 0 {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: "Hosted contents can only be sent with new messages",
+		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+	}
+	if body.Subject != "" || body.Importance != "" {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: "Subject and importance can only be set on new messages",
+		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+	}
+	atts, _, ments, err := attachments.PrepareBody(&body)
+	if err != nil {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+	}
+	requestErr := o.channelAPI.UpdateReply(ctx, teamID, channelID, messageID, replyID, body.Content, string(body.ContentType), ments, atts)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+	}
+	return o.GetReply(ctx, teamID, channelID, messageID, replyID)
+}
+
+func (o *ops) DeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	return snd.MapError(o.channelAPI.DeleteMessage(ctx, teamID, channelID, messageID), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+}
+
+func (o *ops) DeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error {
+	return snd.MapError(o.channelAPI.DeleteReply(ctx, teamID, channelID, messageID, replyID), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+}
+
+func (o *ops) UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	return snd.MapError(o.channelAPI.UndoDeleteMessage(ctx, teamID, channelID, messageID), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+}
+
+func (o *ops) UndoDeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error {
+	return snd.MapError(o.channelAPI.UndoDeleteReply(ctx, teamID, channelID, messageID, replyID), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+}
+
+func (o *ops) SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	return snd.MapError(o.channelAPI.SetReaction(ctx, teamID, channelID, messageID, reactionType), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+}
+
+func (o *ops) UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	return snd.MapError(o.channelAPI.UnsetReaction(ctx, teamID, channelID, messageID, reactionType), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+}
+
+func (o *ops) SetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error {
+	return snd.MapError(o.channelAPI.SetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+}
+
+func (o *ops) UnsetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error {
+	return snd.MapError(o.channelAPI.UnsetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType), snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+}
+
 func (o *ops) ListMessages(ctx context.Context, teamID, channelID string, opts *models.ListMessagesOptions, includeSystem bool) (*models.MessageCollection, error) {
 	var top *int32
-	if opts != nil && opts.Top != nil {
-		top = opts.Top
+	expandReplies := false
+	if opts != nil {
+		top, expandReplies = opts.Top, opts.ExpandReplies
 	}
-	resp, requestErr := o.channelAPI.ListMessages(ctx, teamID, channelID, top, includeSystem)
+	resp, requestErr := o.channelAPI.ListMessages(ctx, teamID, channelID, top, expandReplies, includeSystem)
 	if requestErr != nil {
 		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID))
 	}
@@ -191,6 +284,61 @@ func (o *ops) GetReply(ctx context.Context, teamID, channelID, messageID, replyI
 	return adapter.MapGraphMessage(resp), nil
 }
 
+func (o *ops) ListHostedContents(ctx context.Context, teamID, channelID, messageID string) ([]*models.HostedContent, error) {
+	resp, requestErr := o.channelAPI.ListHostedContents(ctx, teamID, channelID, messageID)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID))
+	}
+	return util.MapSlices(resp.GetValue(), adapter.MapGraphHostedContent), nil
+}
+
+func (o *ops) ListReplyHostedContents(ctx context.Context, teamID, channelID, messageID, replyID string) ([]*models.HostedContent, error) {
+	resp, requestErr := o.channelAPI.ListReplyHostedContents(ctx, teamID, channelID, messageID, replyID)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID))
+	}
+	return util.MapSlices(resp.GetValue(), adapter.MapGraphHostedContent), nil
+}
+
+func (o *ops) DownloadHostedContent(ctx context.Context, teamID, channelID, messageID, hostedContentID string, w io.Writer) error {
+	content, requestErr := o.channelAPI.GetHostedContent(ctx, teamID, channelID, messageID, hostedContentID)
+	if requestErr != nil {
+		return snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.HostedContent, hostedContentID))
+	}
+	return attachments.WriteHostedContent(w, content)
+}
+
+func (o *ops) DownloadReplyHostedContent(ctx context.Context, teamID, channelID, messageID, replyID, hostedContentID string, w io.Writer) error {
+	content, requestErr := o.channelAPI.GetReplyHostedContent(ctx, teamID, channelID, messageID, replyID, hostedContentID)
+	if requestErr != nil {
+		return snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.Message, replyID), snd.WithResource(resources.HostedContent, hostedContentID))
+	}
+	return attachments.WriteHostedContent(w, content)
+}
+
+func (o *ops) UploadFile(ctx context.Context, teamID, channelID, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	resp, requestErr := o.channelAPI.UploadFile(ctx, teamID, channelID, name, r, size)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID))
+	}
+	return adapter.MapGraphDriveItemAttachment(resp), nil
+}
+
+func (o *ops) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	if attachment.ContentType != models.AttachmentContentTypeReference || attachment.ContentURL == "" {
+		return snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("attachment %q is not a file", attachment.ID),
+		})
+	}
+	body, requestErr := o.channelAPI.OpenFile(ctx, attachment.ContentURL)
+	if requestErr != nil {
+		return snd.MapError(requestErr)
+	}
+	defer body.Close()
+	return attachments.CopyFile(w, body)
+}
+
 func (o *ops) ListMembers(ctx context.Context, teamID, channelID string) ([]*models.Member, error) {
 	resp, requestErr := o.channelAPI.ListMembers(ctx, teamID, channelID)
 	if requestErr != nil {
@@ -270,8 +418,14 @@ func (o *ops) SearchChannelMessages(ctx context.Context, teamID, channelID *stri
 	if opts == nil {
 		return nil, errors.New("missing opts")
 	}
+	if searchConfig != nil && searchConfig.Local {
+		return nil, search.ErrLocalSearchUnavailable
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 
-	resp, requestErr, nextFrom := o.channelAPI.SearchChannelMessages(ctx, teamID, channelID, opts, searchConfig)
+	resp, requestErr := o.channelAPI.SearchChannelMessages(ctx, teamID, channelID, opts, searchConfig)
 	if requestErr != nil {
 		if teamID == nil || channelID == nil {
 			return nil, snd.MapError(requestErr)
@@ -282,16 +436,66 @@ func (o *ops) SearchChannelMessages(ctx context.Context, teamID, channelID *stri
 		)
 	}
 	var results []*search.SearchResult
-	for _, msg := range resp {
+	for _, msg := range resp.Messages {
 		results = append(results, &search.SearchResult{
 			Message:   adapter.MapGraphMessage(msg.Message),
 			ChannelID: msg.ChannelID,
 			TeamID:    msg.TeamID,
 			ChatID:    msg.ChatID,
+			Summary:   msg.Hit.Summary,
+			Rank:      msg.Hit.Rank,
+			HitID:     msg.Hit.HitID,
 		})
 	}
 	return &search.SearchResults{
-		Messages: results,
-		NextFrom: nextFrom,
+		Messages:             results,
+		NextFrom:             resp.NextFrom,
+		Total:                resp.Total,
+		MoreResultsAvailable: resp.MoreResultsAvailable,
+	}, nil
+}
+
+func (o *ops) ListMessagesModifiedSince(ctx context.Context, teamID, channelID string, since time.Time) ([]*models.Message, error) {
+	resp, requestErr := o.channelAPI.ListMessagesModifiedSince(ctx, teamID, channelID, since)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID))
+	}
+	return util.MapSlices(resp, adapter.MapGraphMessage), nil
+}
+
+// SyncMessages runs the delta query from the delta link in token. Besides the link, the token keeps
+// the last modification time of the newest synced message, which tells messages created since
+// the previous sync from older ones. A token without it starts over.
+func (o *ops) SyncMessages(ctx context.Context, teamID, channelID, token string) (*models.MessageChanges, string, error) {
+	since, deltaLink, ok := parseSyncToken(token)
+	if !ok {
+		since, deltaLink = time.Time{}, ""
+	}
+
+	resp, nextLink, requestErr := o.channelAPI.SyncMessages(ctx, teamID, channelID, deltaLink)
+	if requestErr != nil {
+		return nil, "", snd.MapError(requestErr, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID))
+	}
+
+	latest := since
+	for _, msg := range resp {
+		if modified := msg.GetLastModifiedDateTime(); modified != nil && modified.After(latest) {
+			latest = *modified
+		}
+	}
+
+	changes := adapter.MapGraphMessageChanges(resp, since)
+	changes.Reset = deltaLink == ""
+	return changes, formatSyncToken(latest, nextLink), nil
+}
+
+func (o *ops) GetCurrentUser(ctx context.Context) (*models.MessageFrom, error) {
+	resp, requestErr := o.userAPI.GetMe(ctx)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	return &models.MessageFrom{
+		UserID:      util.Deref(resp.GetId()),
+		DisplayName: util.Deref(resp.GetDisplayName()),
 	}, nil
 }
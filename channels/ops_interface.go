@@ -2,6 +2,8 @@ package channels
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/pzsp-teams/lib/models"
 	"github.com/pzsp-teams/lib/search"
@@ -15,10 +17,26 @@ type channelOps interface {
 	DeleteChannel(ctx context.Context, teamID, channelID, channelRef string) error
 	SendMessage(ctx context.Context, teamID, channelID string, body models.MessageBody) (*models.Message, error)
 	SendReply(ctx context.Context, teamID, channelID, messageID string, body models.MessageBody) (*models.Message, error)
+	UpdateMessage(ctx context.Context, teamID, channelID, messageID string, body models.MessageBody) (*models.Message, error)
+	UpdateReply(ctx context.Context, teamID, channelID, messageID, replyID string, body models.MessageBody) (*models.Message, error)
+	DeleteMessage(ctx context.Context, teamID, channelID, messageID string) error
+	DeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error
+	UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) error
+	UndoDeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error
+	SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error
+	UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error
+	SetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error
+	UnsetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error
 	ListMessages(ctx context.Context, teamID, channelID string, opts *models.ListMessagesOptions, includeSystem bool) (*models.MessageCollection, error)
 	GetMessage(ctx context.Context, teamID, channelID, messageID string) (*models.Message, error)
 	ListReplies(ctx context.Context, teamID, channelID, messageID string, opts *models.ListMessagesOptions, includeSystem bool) (*models.MessageCollection, error)
 	GetReply(ctx context.Context, teamID, channelID, messageID, replyID string) (*models.Message, error)
+	ListHostedContents(ctx context.Context, teamID, channelID, messageID string) ([]*models.HostedContent, error)
+	ListReplyHostedContents(ctx context.Context, teamID, channelID, messageID, replyID string) ([]*models.HostedContent, error)
+	DownloadHostedContent(ctx context.Context, teamID, channelID, messageID, hostedContentID string, w io.Writer) error
+	DownloadReplyHostedContent(ctx context.Context, teamID, channelID, messageID, replyID, hostedContentID string, w io.Writer) error
+	UploadFile(ctx context.Context, teamID, channelID, name string, r io.Reader, size int64) (*models.Attachment, error)
+	DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error
 	ListMembers(ctx context.Context, teamID, channelID string) ([]*models.Member, error)
 	AddMember(ctx context.Context, teamID, channelID, userID string, isOwner bool) (*models.Member, error)
 	UpdateMemberRoles(ctx context.Context, teamID, channelID, memberID string, isOwner bool) (*models.Member, error)
@@ -27,4 +45,7 @@ type channelOps interface {
 	ListMessagesNext(ctx context.Context, teamID, channelID, nextLink string, includeSystem bool) (*models.MessageCollection, error)
 	ListRepliesNext(ctx context.Context, teamID, channelID, messageID, nextLink string, includeSystem bool) (*models.MessageCollection, error)
 	SearchChannelMessages(ctx context.Context, teamID, channelID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error)
+	ListMessagesModifiedSince(ctx context.Context, teamID, channelID string, since time.Time) ([]*models.Message, error)
+	SyncMessages(ctx context.Context, teamID, channelID, token string) (*models.MessageChanges, string, error)
+	GetCurrentUser(ctx context.Context) (*models.MessageFrom, error)
 }
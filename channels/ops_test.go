@@ -1,10 +1,14 @@
 package channels
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
 	iapi "github.com/pzsp-teams/lib/internal/api"
@@ -297,8 +301,8 @@ func TestOps_SendMessage(t *testing.T) {
 	t.Run("calls api and maps message", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
-				SendMessage(gomock.Any(), "team-1", "chan-1", "hello", "text", gomock.Any()).
-				DoAndReturn(func(_ context.Context, _ string, _ string, _ string, _ string, ments []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *snd.RequestError) {
+				SendMessage(gomock.Any(), "team-1", "chan-1", "hello", "text", "", "", gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ string, _ string, _ string, _ string, _ string, ments []msmodels.ChatMessageMentionable, _ []msmodels.ChatMessageAttachmentable, _ []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *snd.RequestError) {
 					assert.Len(t, ments, 0)
 					return testutil.NewGraphMessage(&testutil.NewMessageParams{
 						ID:      util.Ptr("m-1"),
@@ -319,7 +323,7 @@ func TestOps_SendMessage(t *testing.T) {
 	t.Run("maps api error via sender", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
-				SendMessage(gomock.Any(), "team-1", "chan-1", gomock.Any(), gomock.Any(), gomock.Any()).
+				SendMessage(gomock.Any(), "team-1", "chan-1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(nil, &snd.RequestError{Code: 403, Message: "nope"}).
 				Times(1)
 		})
@@ -340,7 +344,7 @@ func TestOps_SendMessage(t *testing.T) {
 	t.Run("returns 400 when mentions cannot be prepared", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
-				SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				Times(0)
 		})
 
@@ -361,7 +365,67 @@ func TestOps_SendMessage(t *testing.T) {
 		var re *snd.RequestError
 		require.ErrorAs(t, err, &re)
 		assert.Equal(t, 400, re.Code)
-		assert.Contains(t, re.Message, "Failed to prepare mentions")
+		assert.Contains(t, re.Message, "failed to prepare mentions")
+	})
+}
+
+func TestOps_SendMessage_SubjectAndImportance(t *testing.T) {
+	op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+		d.channelAPI.EXPECT().
+			SendMessage(gomock.Any(), "team-1", "chan-1", "down", "text", "Incident", "urgent", gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-1")}), nil).
+			Times(1)
+	})
+
+	body := models.MessageBody{
+		Content:     "down",
+		ContentType: models.MessageContentTypeText,
+		Subject:     "Incident",
+		Importance:  models.MessageImportanceUrgent,
+	}
+	got, err := op.SendMessage(ctx, "team-1", "chan-1", body)
+	require.NoError(t, err)
+	assert.Equal(t, "m-1", got.ID)
+}
+
+func TestOps_SendMessage_Attachments(t *testing.T) {
+	t.Run("passes attachments and references them from the body", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SendMessage(gomock.Any(), "team-1", "chan-1", `<attachment id="card-1"></attachment>`, "html", "", "", gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _, _, _, _, _ string, _ []msmodels.ChatMessageMentionable, atts []msmodels.ChatMessageAttachmentable, _ []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *snd.RequestError) {
+					require.Len(t, atts, 1)
+					assert.Equal(t, "card-1", *atts[0].GetId())
+					assert.Equal(t, "application/vnd.microsoft.card.adaptive", *atts[0].GetContentType())
+					return testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-1")}), nil
+				}).
+				Times(1)
+		})
+
+		body := models.MessageBody{Attachments: []models.Attachment{{
+			ID:          "card-1",
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     `{"type":"AdaptiveCard"}`,
+		}}}
+		got, err := op.SendMessage(ctx, "team-1", "chan-1", body)
+		require.NoError(t, err)
+		assert.Equal(t, "m-1", got.ID)
+	})
+
+	t.Run("invalid attachment -> 400 without calling api", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SendMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+		})
+
+		body := models.MessageBody{
+			Content:     "hi",
+			ContentType: models.MessageContentTypeText,
+			Attachments: []models.Attachment{{ID: "card-1", ContentType: "application/vnd.microsoft.card.adaptive", Content: "{}"}},
+		}
+		_, err := op.SendMessage(ctx, "team-1", "chan-1", body)
+		requireStatus(t, err, http.StatusBadRequest)
 	})
 }
 
@@ -369,8 +433,8 @@ func TestOps_SendReply(t *testing.T) {
 	t.Run("calls api and maps reply", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
-				SendReply(gomock.Any(), "team-1", "chan-1", "parent-1", "hi", "text", gomock.Any()).
-				DoAndReturn(func(_ context.Context, _ string, _ string, _ string, _ string, _ string, ments []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *snd.RequestError) {
+				SendReply(gomock.Any(), "team-1", "chan-1", "parent-1", "hi", "text", "", "", gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ string, _ string, _ string, _ string, _ string, _ string, ments []msmodels.ChatMessageMentionable, _ []msmodels.ChatMessageAttachmentable, _ []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *snd.RequestError) {
 					assert.Len(t, ments, 0)
 					return testutil.NewGraphMessage(&testutil.NewMessageParams{
 						ID:      util.Ptr("r-1"),
@@ -391,7 +455,7 @@ func TestOps_SendReply(t *testing.T) {
 	t.Run("maps api error via sender", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
-				SendReply(gomock.Any(), "team-1", "chan-1", "parent-1", gomock.Any(), gomock.Any(), gomock.Any()).
+				SendReply(gomock.Any(), "team-1", "chan-1", "parent-1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(nil, &snd.RequestError{Code: 403, Message: "nope"}).
 				Times(1)
 		})
@@ -413,7 +477,7 @@ func TestOps_SendReply(t *testing.T) {
 	t.Run("returns 400 when mentions cannot be prepared", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
-				SendReply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				SendReply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				Times(0)
 		})
 
@@ -433,7 +497,174 @@ func TestOps_SendReply(t *testing.T) {
 
 		var re *snd.RequestError
 		require.ErrorAs(t, err, &re)
-		assert.Contains(t, re.Message, "Failed to prepare mentions")
+		assert.Contains(t, re.Message, "failed to prepare mentions")
+	})
+}
+
+func TestOps_UpdateMessage(t *testing.T) {
+	edited := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("patches body and reads message back", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			gomock.InOrder(
+				d.channelAPI.EXPECT().
+					UpdateMessage(gomock.Any(), "team-1", "chan-1", "msg-1", "50%", "text", gomock.Any(), gomock.Any()).
+					Return(nil).
+					Times(1),
+				d.channelAPI.EXPECT().
+					GetMessage(gomock.Any(), "team-1", "chan-1", "msg-1").
+					Return(testutil.NewGraphMessage(&testutil.NewMessageParams{
+						ID:                 util.Ptr("msg-1"),
+						Content:            util.Ptr("50%"),
+						LastEditedDateTime: &edited,
+					}), nil).
+					Times(1),
+			)
+		})
+
+		body := models.MessageBody{Content: "50%", ContentType: models.MessageContentTypeText}
+		got, err := op.UpdateMessage(ctx, "team-1", "chan-1", "msg-1", body)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "50%", got.Content)
+		require.NotNil(t, got.LastEditedDateTime)
+		assert.Equal(t, edited, *got.LastEditedDateTime)
+	})
+
+	t.Run("maps api error via sender", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				UpdateMessage(gomock.Any(), "team-1", "chan-1", "msg-1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&snd.RequestError{Code: 403, Message: "nope"}).
+				Times(1)
+			d.channelAPI.EXPECT().GetMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		})
+
+		body := models.MessageBody{Content: "hi", ContentType: models.MessageContentTypeText}
+		got, err := op.UpdateMessage(ctx, "team-1", "chan-1", "msg-1", body)
+		require.Nil(t, got)
+		requireStatus(t, err, 403)
+		requireErrDataHas(t, err, resources.Message, "msg-1")
+	})
+
+	t.Run("returns 400 when mentions cannot be prepared", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				UpdateMessage(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+		})
+
+		body := models.MessageBody{
+			Content:     "hi",
+			ContentType: models.MessageContentTypeText,
+			Mentions: []models.Mention{
+				{Kind: models.MentionTeam, TargetID: "not-a-guid", Text: "team", AtID: 0},
+			},
+		}
+
+		got, err := op.UpdateMessage(ctx, "team-1", "chan-1", "msg-1", body)
+		require.Nil(t, got)
+		requireStatus(t, err, 400)
+	})
+}
+
+func TestOps_UpdateReply(t *testing.T) {
+	t.Run("patches reply and reads it back", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			gomock.InOrder(
+				d.channelAPI.EXPECT().
+					UpdateReply(gomock.Any(), "team-1", "chan-1", "parent-1", "r-1", "done", "text", gomock.Any(), gomock.Any()).
+					Return(nil).
+					Times(1),
+				d.channelAPI.EXPECT().
+					GetReply(gomock.Any(), "team-1", "chan-1", "parent-1", "r-1").
+					Return(testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("r-1"), Content: util.Ptr("done")}), nil).
+					Times(1),
+			)
+		})
+
+		body := models.MessageBody{Content: "done", ContentType: models.MessageContentTypeText}
+		got, err := op.UpdateReply(ctx, "team-1", "chan-1", "parent-1", "r-1", body)
+		require.NoError(t, err)
+		assert.Equal(t, "r-1", got.ID)
+	})
+
+	t.Run("maps api error via sender", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				UpdateReply(gomock.Any(), "team-1", "chan-1", "parent-1", "r-1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&snd.RequestError{Code: 404, Message: "gone"}).
+				Times(1)
+		})
+
+		body := models.MessageBody{Content: "done", ContentType: models.MessageContentTypeText}
+		got, err := op.UpdateReply(ctx, "team-1", "chan-1", "parent-1", "r-1", body)
+		require.Nil(t, got)
+		requireStatus(t, err, 404)
+		requireErrDataHas(t, err, resources.Message, "r-1")
+	})
+}
+
+func TestOps_DeleteAndUndoDelete(t *testing.T) {
+	t.Run("calls soft delete and undo endpoints", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().DeleteMessage(gomock.Any(), "team-1", "chan-1", "msg-1").Return(nil).Times(1)
+			d.channelAPI.EXPECT().DeleteReply(gomock.Any(), "team-1", "chan-1", "msg-1", "r-1").Return(nil).Times(1)
+			d.channelAPI.EXPECT().UndoDeleteMessage(gomock.Any(), "team-1", "chan-1", "msg-1").Return(nil).Times(1)
+			d.channelAPI.EXPECT().UndoDeleteReply(gomock.Any(), "team-1", "chan-1", "msg-1", "r-1").Return(nil).Times(1)
+		})
+
+		require.NoError(t, op.DeleteMessage(ctx, "team-1", "chan-1", "msg-1"))
+		require.NoError(t, op.DeleteReply(ctx, "team-1", "chan-1", "msg-1", "r-1"))
+		require.NoError(t, op.UndoDeleteMessage(ctx, "team-1", "chan-1", "msg-1"))
+		require.NoError(t, op.UndoDeleteReply(ctx, "team-1", "chan-1", "msg-1", "r-1"))
+	})
+
+	t.Run("maps request error via sender", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				UndoDeleteReply(gomock.Any(), "team-1", "chan-1", "msg-1", "r-1").
+				Return(&snd.RequestError{Code: 404, Message: "gone"}).
+				Times(1)
+		})
+
+		err := op.UndoDeleteReply(ctx, "team-1", "chan-1", "msg-1", "r-1")
+		requireStatus(t, err, 404)
+		requireErrDataHas(t, err, resources.Team, "team-1")
+		requireErrDataHas(t, err, resources.Channel, "chan-1")
+		requireErrDataHas(t, err, resources.Message, "r-1")
+
+		var nf *snd.ErrResourceNotFound
+		require.ErrorAs(t, err, &nf)
+	})
+}
+
+func TestOps_Reactions(t *testing.T) {
+	t.Run("calls reaction endpoints", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().SetReaction(gomock.Any(), "team-1", "chan-1", "msg-1", "👍").Return(nil).Times(1)
+			d.channelAPI.EXPECT().UnsetReaction(gomock.Any(), "team-1", "chan-1", "msg-1", "👍").Return(nil).Times(1)
+			d.channelAPI.EXPECT().SetReplyReaction(gomock.Any(), "team-1", "chan-1", "msg-1", "r-1", "🎉").Return(nil).Times(1)
+			d.channelAPI.EXPECT().UnsetReplyReaction(gomock.Any(), "team-1", "chan-1", "msg-1", "r-1", "🎉").Return(nil).Times(1)
+		})
+
+		require.NoError(t, op.SetReaction(ctx, "team-1", "chan-1", "msg-1", "👍"))
+		require.NoError(t, op.UnsetReaction(ctx, "team-1", "chan-1", "msg-1", "👍"))
+		require.NoError(t, op.SetReplyReaction(ctx, "team-1", "chan-1", "msg-1", "r-1", "🎉"))
+		require.NoError(t, op.UnsetReplyReaction(ctx, "team-1", "chan-1", "msg-1", "r-1", "🎉"))
+	})
+
+	t.Run("maps request error via sender", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SetReaction(gomock.Any(), "team-1", "chan-1", "msg-1", "👍").
+				Return(&snd.RequestError{Code: 403, Message: "nope"}).
+				Times(1)
+		})
+
+		err := op.SetReaction(ctx, "team-1", "chan-1", "msg-1", "👍")
+		requireStatus(t, err, 403)
+		requireErrDataHas(t, err, resources.Message, "msg-1")
 	})
 }
 
@@ -446,7 +677,7 @@ func TestOps_ListMessages(t *testing.T) {
 				testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m2"), Content: util.Ptr("b")}),
 			})
 			d.channelAPI.EXPECT().
-				ListMessages(gomock.Any(), "team-1", "chan-1", nil, false).
+				ListMessages(gomock.Any(), "team-1", "chan-1", nil, false, false).
 				Return(col, nil).
 				Times(1)
 		})
@@ -464,7 +695,7 @@ func TestOps_ListMessages(t *testing.T) {
 			col := msmodels.NewChatMessageCollectionResponse()
 			col.SetValue([]msmodels.ChatMessageable{})
 			d.channelAPI.EXPECT().
-				ListMessages(gomock.Any(), "team-1", "chan-1", &top, false).
+				ListMessages(gomock.Any(), "team-1", "chan-1", &top, false, false).
 				Return(col, nil).
 				Times(1)
 		})
@@ -473,11 +704,25 @@ func TestOps_ListMessages(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("passes expand replies when requested", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			col := msmodels.NewChatMessageCollectionResponse()
+			col.SetValue([]msmodels.ChatMessageable{})
+			d.channelAPI.EXPECT().
+				ListMessages(gomock.Any(), "team-1", "chan-1", nil, true, false).
+				Return(col, nil).
+				Times(1)
+		})
+
+		_, err := op.ListMessages(ctx, "team-1", "chan-1", &models.ListMessagesOptions{ExpandReplies: true}, false)
+		require.NoError(t, err)
+	})
+
 	t.Run("maps api error via sender", func(t *testing.T) {
 		var top int32 = 5
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
-				ListMessages(gomock.Any(), "team-1", "chan-1", &top, false).
+				ListMessages(gomock.Any(), "team-1", "chan-1", &top, false, false).
 				Return(nil, &snd.RequestError{Code: 404, Message: "missing"}).
 				Times(1)
 		})
@@ -504,7 +749,7 @@ func TestOps_ListMessages(t *testing.T) {
 			col.SetOdataNextLink(&next)
 
 			d.channelAPI.EXPECT().
-				ListMessages(gomock.Any(), "team-1", "chan-1", nil, false).
+				ListMessages(gomock.Any(), "team-1", "chan-1", nil, false, false).
 				Return(col, nil).
 				Times(1)
 		})
@@ -679,6 +924,146 @@ func TestOps_GetReply(t *testing.T) {
 	})
 }
 
+func TestOps_HostedContents(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G'}
+
+	t.Run("sends hosted contents referenced from the body", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SendMessage(gomock.Any(), "team-1", "chan-1", `<p>cpu</p><img src="../hostedContents/1/$value">`, "html", "", "", gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _, _, _, _, _, _ string, _ []msmodels.ChatMessageMentionable, _ []msmodels.ChatMessageAttachmentable, hosted []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *snd.RequestError) {
+					require.Len(t, hosted, 1)
+					assert.Equal(t, "1", hosted[0].GetAdditionalData()["@microsoft.graph.temporaryId"])
+					assert.Equal(t, png, hosted[0].GetContentBytes())
+					return testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-1")}), nil
+				}).
+				Times(1)
+		})
+
+		body := models.MessageBody{
+			Content:        "<p>cpu</p>",
+			ContentType:    models.MessageContentTypeHTML,
+			HostedContents: []models.HostedContent{{ID: "1", ContentType: "image/png", Content: png}},
+		}
+		got, err := op.SendMessage(ctx, "team-1", "chan-1", body)
+		require.NoError(t, err)
+		assert.Equal(t, "m-1", got.ID)
+	})
+
+	t.Run("update with hosted contents -> 400 without calling api", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, nil)
+
+		body := models.MessageBody{
+			ContentType:    models.MessageContentTypeHTML,
+			HostedContents: []models.HostedContent{{ID: "1", ContentType: "image/png", Content: png}},
+		}
+		_, err := op.UpdateMessage(ctx, "team-1", "chan-1", "m-1", body)
+		requireStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("update with subject or importance -> 400 without calling api", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, nil)
+
+		_, err := op.UpdateMessage(ctx, "team-1", "chan-1", "m-1", models.MessageBody{Content: "x", Subject: "status"})
+		requireStatus(t, err, http.StatusBadRequest)
+		_, err = op.UpdateReply(ctx, "team-1", "chan-1", "m-1", "r-1", models.MessageBody{Content: "x", Importance: models.MessageImportanceHigh})
+		requireStatus(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("lists hosted contents", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			hc := msmodels.NewChatMessageHostedContent()
+			hc.SetId(util.Ptr("hc-1"))
+			hc.SetContentType(util.Ptr("image/png"))
+			col := msmodels.NewChatMessageHostedContentCollectionResponse()
+			col.SetValue([]msmodels.ChatMessageHostedContentable{hc})
+			d.channelAPI.EXPECT().ListReplyHostedContents(gomock.Any(), "team-1", "chan-1", "m-1", "r-1").Return(col, nil).Times(1)
+		})
+
+		got, err := op.ListReplyHostedContents(ctx, "team-1", "chan-1", "m-1", "r-1")
+		require.NoError(t, err)
+		assert.Equal(t, []*models.HostedContent{{ID: "hc-1", ContentType: "image/png"}}, got)
+	})
+
+	t.Run("downloads hosted content to writer", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().GetHostedContent(gomock.Any(), "team-1", "chan-1", "m-1", "hc-1").Return(png, nil).Times(1)
+		})
+
+		var buf bytes.Buffer
+		require.NoError(t, op.DownloadHostedContent(ctx, "team-1", "chan-1", "m-1", "hc-1", &buf))
+		assert.Equal(t, png, buf.Bytes())
+	})
+
+	t.Run("download maps not found with hosted content id", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				GetReplyHostedContent(gomock.Any(), "team-1", "chan-1", "m-1", "r-1", "hc-1").
+				Return(nil, &snd.RequestError{Code: 404, Message: "gone"}).
+				Times(1)
+		})
+
+		var buf bytes.Buffer
+		err := op.DownloadReplyHostedContent(ctx, "team-1", "chan-1", "m-1", "r-1", "hc-1", &buf)
+		requireStatus(t, err, 404)
+		requireErrDataHas(t, err, resources.Message, "r-1")
+		requireErrDataHas(t, err, resources.HostedContent, "hc-1")
+		assert.Zero(t, buf.Len())
+	})
+}
+
+func TestOps_Files(t *testing.T) {
+	file := models.Attachment{ID: "att-1", ContentType: models.AttachmentContentTypeReference, ContentURL: "https://contoso/report.txt", Name: "report.txt"}
+
+	t.Run("uploads file and maps reference attachment", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			item := msmodels.NewDriveItem()
+			item.SetId(util.Ptr("item-1"))
+			item.SetETag(util.Ptr(`"{0B1E7A35-9E1C-4E5B-A7E1-6C1B0A0F4E11},1"`))
+			item.SetName(util.Ptr("report.txt"))
+			item.SetWebUrl(util.Ptr("https://contoso/report.txt"))
+			d.channelAPI.EXPECT().UploadFile(gomock.Any(), "team-1", "chan-1", "report.txt", gomock.Any(), int64(3)).Return(item, nil).Times(1)
+		})
+
+		got, err := op.UploadFile(ctx, "team-1", "chan-1", "report.txt", strings.NewReader("abc"), 3)
+		require.NoError(t, err)
+		assert.Equal(t, "0B1E7A35-9E1C-4E5B-A7E1-6C1B0A0F4E11", got.ID)
+		assert.Equal(t, models.AttachmentContentTypeReference, got.ContentType)
+		assert.Equal(t, "https://contoso/report.txt", got.ContentURL)
+	})
+
+	t.Run("upload maps api error", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				UploadFile(gomock.Any(), "team-1", "chan-1", "report.txt", gomock.Any(), int64(3)).
+				Return(nil, &snd.RequestError{Code: http.StatusNotFound, Message: "no channel"}).
+				Times(1)
+		})
+
+		_, err := op.UploadFile(ctx, "team-1", "chan-1", "report.txt", strings.NewReader("abc"), 3)
+		requireStatus(t, err, http.StatusNotFound)
+		requireErrDataHas(t, err, resources.Channel, "chan-1")
+	})
+
+	t.Run("downloads file", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().OpenFile(gomock.Any(), "https://contoso/report.txt").Return(io.NopCloser(strings.NewReader("abc")), nil).Times(1)
+		})
+
+		var buf bytes.Buffer
+		require.NoError(t, op.DownloadFile(ctx, file, &buf))
+		assert.Equal(t, "abc", buf.String())
+	})
+
+	t.Run("download of non-file attachment -> 400 without calling api", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, nil)
+
+		card := models.Attachment{ID: "card-1", ContentType: "application/vnd.microsoft.card.adaptive"}
+		err := op.DownloadFile(ctx, card, &bytes.Buffer{})
+		requireStatus(t, err, http.StatusBadRequest)
+	})
+}
+
 func TestOps_ListMembers(t *testing.T) {
 	t.Run("maps members", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
@@ -1051,11 +1436,35 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		require.Equal(t, "missing opts", err.Error())
 	})
 
+	t.Run("local search without message store -> returns error and does not call api", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SearchChannelMessages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+		})
+
+		got, err := op.SearchChannelMessages(ctx, nil, nil, &search.SearchMessagesOptions{}, &search.SearchConfig{Local: true})
+		require.Nil(t, got)
+		require.ErrorIs(t, err, search.ErrLocalSearchUnavailable)
+	})
+
+	t.Run("invalid filter -> returns error and does not call api", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SearchChannelMessages(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Times(0)
+		})
+
+		got, err := op.SearchChannelMessages(ctx, nil, nil, &search.SearchMessagesOptions{Filter: search.Or()}, nil)
+		require.Nil(t, got)
+		require.ErrorIs(t, err, search.ErrInvalidQuery)
+	})
+
 	t.Run("maps request error without resources when teamID/channelID are nil", func(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
 				SearchChannelMessages(gomock.Any(), nil, nil, gomock.Any(), gomock.Any()).
-				Return(nil, &snd.RequestError{Code: 403, Message: "nope"}, nil).
+				Return(nil, &snd.RequestError{Code: 403, Message: "nope"}).
 				Times(1)
 		})
 
@@ -1076,7 +1485,7 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
 				SearchChannelMessages(gomock.Any(), teamID, channelID, gomock.Any(), gomock.Any()).
-				Return(nil, &snd.RequestError{Code: 404, Message: "missing"}, nil).
+				Return(nil, &snd.RequestError{Code: 404, Message: "missing"}).
 				Times(1)
 		})
 
@@ -1093,7 +1502,7 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		require.ErrorAs(t, err, &nf)
 	})
 
-	t.Run("success maps messages and nextFrom", func(t *testing.T) {
+	t.Run("success maps messages, nextFrom and hit counts", func(t *testing.T) {
 		teamID := util.Ptr("team-1")
 		channelID := util.Ptr("chan-1")
 		chatID := util.Ptr("chat-1")
@@ -1108,6 +1517,11 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 				TeamID:    teamID,
 				ChannelID: channelID,
 				ChatID:    chatID,
+				Hit: iapi.SearchHit{
+					HitID:   util.Ptr("hit-1"),
+					Rank:    util.Ptr(int32(1)),
+					Summary: util.Ptr("<c0>hello</c0>"),
+				},
 			},
 			{
 				Message: testutil.NewGraphMessage(&testutil.NewMessageParams{
@@ -1123,7 +1537,12 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
 				SearchChannelMessages(gomock.Any(), teamID, channelID, gomock.Any(), gomock.Any()).
-				Return(apiResp, nil, &next).
+				Return(&iapi.SearchMessagesPage{
+					Messages:             apiResp,
+					NextFrom:             &next,
+					Total:                util.Ptr(int32(100)),
+					MoreResultsAvailable: true,
+				}, nil).
 				Times(1)
 		})
 
@@ -1134,6 +1553,8 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 
 		require.NotNil(t, got.NextFrom)
 		require.Equal(t, int32(42), *got.NextFrom)
+		require.Equal(t, int32(100), *got.Total)
+		require.True(t, got.MoreResultsAvailable)
 
 		require.Len(t, got.Messages, 2)
 
@@ -1143,6 +1564,9 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		assert.Equal(t, teamID, got.Messages[0].TeamID)
 		assert.Equal(t, channelID, got.Messages[0].ChannelID)
 		assert.Equal(t, chatID, got.Messages[0].ChatID)
+		assert.Equal(t, "hit-1", *got.Messages[0].HitID)
+		assert.Equal(t, int32(1), *got.Messages[0].Rank)
+		assert.Equal(t, "<c0>hello</c0>", *got.Messages[0].Summary)
 
 		require.NotNil(t, got.Messages[1].Message)
 		assert.Equal(t, "m2", got.Messages[1].Message.ID)
@@ -1150,6 +1574,7 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		assert.Equal(t, teamID, got.Messages[1].TeamID)
 		assert.Equal(t, channelID, got.Messages[1].ChannelID)
 		assert.Nil(t, got.Messages[1].ChatID)
+		assert.Nil(t, got.Messages[1].Summary)
 	})
 
 	t.Run("success allows nil nextFrom", func(t *testing.T) {
@@ -1170,7 +1595,7 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
 			d.channelAPI.EXPECT().
 				SearchChannelMessages(gomock.Any(), teamID, channelID, gomock.Any(), gomock.Any()).
-				Return(apiResp, nil, nil).
+				Return(&iapi.SearchMessagesPage{Messages: apiResp}, nil).
 				Times(1)
 		})
 
@@ -1179,6 +1604,8 @@ func TestOps_SearchChannelMessages(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, got)
 		require.Nil(t, got.NextFrom)
+		require.Nil(t, got.Total)
+		require.False(t, got.MoreResultsAvailable)
 		require.Len(t, got.Messages, 1)
 	})
 }
@@ -1278,3 +1705,63 @@ func TestOpsWithCache_AddMember_BlankEmail_NoSet(t *testing.T) {
 		require.Equal(t, out, got)
 	})
 }
+
+func TestOps_SyncMessages(t *testing.T) {
+	since := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	before, after := since.Add(-time.Hour), since.Add(time.Minute)
+
+	t.Run("maps changes against previous sync and returns next token", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SyncMessages(gomock.Any(), "team-1", "chan-1", "delta-1").
+				Return([]msmodels.ChatMessageable{
+					testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-1"), CreatedDateTime: &after, LastModifiedDateTime: &after}),
+					testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-0"), CreatedDateTime: &before, LastModifiedDateTime: &after, LastEditedDateTime: &after}),
+				}, "delta-2", nil).
+				Times(1)
+		})
+
+		got, next, err := op.SyncMessages(ctx, "team-1", "chan-1", formatSyncToken(since, "delta-1"))
+		require.NoError(t, err)
+		assert.Equal(t, formatSyncToken(after, "delta-2"), next)
+		require.Len(t, got.New, 1)
+		assert.Equal(t, "m-1", got.New[0].ID)
+		require.Len(t, got.Edited, 1)
+		assert.Equal(t, "m-0", got.Edited[0].ID)
+		assert.False(t, got.Reset)
+	})
+
+	t.Run("marks sync without token as reset", func(t *testing.T) {
+		for _, token := range []string{"", "https://graph.microsoft.com/delta-1"} {
+			op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+				d.channelAPI.EXPECT().
+					SyncMessages(gomock.Any(), "team-1", "chan-1", "").
+					Return([]msmodels.ChatMessageable{
+						testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-0"), CreatedDateTime: &before, LastEditedDateTime: &after}),
+					}, "delta-1", nil).
+					Times(1)
+			})
+
+			got, next, err := op.SyncMessages(ctx, "team-1", "chan-1", token)
+			require.NoError(t, err)
+			assert.Equal(t, formatSyncToken(time.Time{}, "delta-1"), next)
+			assert.True(t, got.Reset)
+			assert.Len(t, got.New, 1)
+		}
+	})
+
+	t.Run("maps api error via sender", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(d opsSUTDeps) {
+			d.channelAPI.EXPECT().
+				SyncMessages(gomock.Any(), "team-1", "chan-1", gomock.Any()).
+				Return(nil, "", &snd.RequestError{Code: 404, Message: "nope"}).
+				Times(1)
+		})
+
+		got, next, err := op.SyncMessages(ctx, "team-1", "chan-1", "")
+		require.Nil(t, got)
+		assert.Empty(t, next)
+		requireStatus(t, err, http.StatusNotFound)
+		requireErrDataHas(t, err, resources.Channel, "chan-1")
+	})
+}
@@ -2,6 +2,8 @@ package channels
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/pzsp-teams/lib/internal/cacher"
 	"github.com/pzsp-teams/lib/internal/util"
@@ -106,6 +108,90 @@ func (o *opsWithCache) SendReply(ctx context.Context, teamID, channelID, message
 	}, o.cacheHandler)
 }
 
+func (o *opsWithCache) UpdateMessage(ctx context.Context, teamID, channelID, messageID string, body models.MessageBody) (*models.Message, error) {
+	return cacher.WithErrorClear(func() (*models.Message, error) {
+		return o.chanOps.UpdateMessage(ctx, teamID, channelID, messageID, body)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) UpdateReply(ctx context.Context, teamID, channelID, messageID, replyID string, body models.MessageBody) (*models.Message, error) {
+	return cacher.WithErrorClear(func() (*models.Message, error) {
+		return o.chanOps.UpdateReply(ctx, teamID, channelID, messageID, replyID, body)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) DeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	err := o.chanOps.DeleteMessage(ctx, teamID, channelID, messageID)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) DeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error {
+	err := o.chanOps.DeleteReply(ctx, teamID, channelID, messageID, replyID)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	err := o.chanOps.UndoDeleteMessage(ctx, teamID, channelID, messageID)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) UndoDeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error {
+	err := o.chanOps.UndoDeleteReply(ctx, teamID, channelID, messageID, replyID)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	err := o.chanOps.SetReaction(ctx, teamID, channelID, messageID, reactionType)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	err := o.chanOps.UnsetReaction(ctx, teamID, channelID, messageID, reactionType)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) SetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error {
+	err := o.chanOps.SetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) UnsetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error {
+	err := o.chanOps.UnsetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
 func (o *opsWithCache) ListMessages(ctx context.Context, teamID, channelID string, opts *models.ListMessagesOptions, includeSystem bool) (*models.MessageCollection, error) {
 	return cacher.WithErrorClear(func() (*models.MessageCollection, error) {
 		return o.chanOps.ListMessages(ctx, teamID, channelID, opts, includeSystem)
@@ -142,6 +228,51 @@ func (o *opsWithCache) GetReply(ctx context.Context, teamID, channelID, messageI
 	}, o.cacheHandler)
 }
 
+func (o *opsWithCache) ListHostedContents(ctx context.Context, teamID, channelID, messageID string) ([]*models.HostedContent, error) {
+	return cacher.WithErrorClear(func() ([]*models.HostedContent, error) {
+		return o.chanOps.ListHostedContents(ctx, teamID, channelID, messageID)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) ListReplyHostedContents(ctx context.Context, teamID, channelID, messageID, replyID string) ([]*models.HostedContent, error) {
+	return cacher.WithErrorClear(func() ([]*models.HostedContent, error) {
+		return o.chanOps.ListReplyHostedContents(ctx, teamID, channelID, messageID, replyID)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) DownloadHostedContent(ctx context.Context, teamID, channelID, messageID, hostedContentID string, w io.Writer) error {
+	err := o.chanOps.DownloadHostedContent(ctx, teamID, channelID, messageID, hostedContentID, w)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) DownloadReplyHostedContent(ctx context.Context, teamID, channelID, messageID, replyID, hostedContentID string, w io.Writer) error {
+	err := o.chanOps.DownloadReplyHostedContent(ctx, teamID, channelID, messageID, replyID, hostedContentID, w)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) UploadFile(ctx context.Context, teamID, channelID, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	return cacher.WithErrorClear(func() (*models.Attachment, error) {
+		return o.chanOps.UploadFile(ctx, teamID, channelID, name, r, size)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	err := o.chanOps.DownloadFile(ctx, attachment, w)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
 func (o *opsWithCache) ListMembers(ctx context.Context, teamID, channelID string) ([]*models.Member, error) {
 	members, err := o.chanOps.ListMembers(ctx, teamID, channelID)
 	if err != nil {
@@ -217,7 +348,7 @@ func (o *opsWithCache) addMembersToCache(teamID, channelID string, members ...mo
 		if util.AnyBlank(m.Email) {
 			continue
 		}
-		key := cacher.NewChannelMemberKey(teamID, channelID, m.Email, nil)
+		key := cacher.NewChannelMemberKey(teamID, channelID, m.Email, o.cacheHandler.Pepper())
 		_ = o.cacheHandler.Cacher.Set(key, m.ID)
 	}
 }
@@ -226,7 +357,7 @@ func (o *opsWithCache) removeMemberFromCache(teamID, channelID, userRef string)
 	if util.AnyBlank(teamID, channelID, userRef) {
 		return
 	}
-	key := cacher.NewChannelMemberKey(teamID, channelID, userRef, nil)
+	key := cacher.NewChannelMemberKey(teamID, channelID, userRef, o.cacheHandler.Pepper())
 	_ = o.cacheHandler.Cacher.Invalidate(key)
 }
 
@@ -241,3 +372,24 @@ func (o *opsWithCache) SearchChannelMessages(ctx context.Context, teamID, channe
 		return o.chanOps.SearchChannelMessages(ctx, teamID, channelID, opts, searchConfig)
 	}, o.cacheHandler)
 }
+
+func (o *opsWithCache) ListMessagesModifiedSince(ctx context.Context, teamID, channelID string, since time.Time) ([]*models.Message, error) {
+	return cacher.WithErrorClear(func() ([]*models.Message, error) {
+		return o.chanOps.ListMessagesModifiedSince(ctx, teamID, channelID, since)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) SyncMessages(ctx context.Context, teamID, channelID, token string) (*models.MessageChanges, string, error) {
+	changes, next, err := o.chanOps.SyncMessages(ctx, teamID, channelID, token)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return nil, "", err
+	}
+	return changes, next, nil
+}
+
+func (o *opsWithCache) GetCurrentUser(ctx context.Context) (*models.MessageFrom, error) {
+	return cacher.WithErrorClear(func() (*models.MessageFrom, error) {
+		return o.chanOps.GetCurrentUser(ctx)
+	}, o.cacheHandler)
+}
@@ -0,0 +1,164 @@
+package channels
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pzsp-teams/lib/internal/msgstore"
+	"github.com/pzsp-teams/lib/internal/resources"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/pzsp-teams/lib/search"
+)
+
+// opsWithMessageStore reads channel messages through the local message store.
+// Local searches run against the store as well. Messages changed through the ops are written
+// back to the store or dropped from it, to be fetched again. Replies are not stored.
+// Other operations are passed to the wrapped ops.
+type opsWithMessageStore struct {
+	channelOps
+	store msgstore.Store
+}
+
+func NewOpsWithMessageStore(chanOps channelOps, store msgstore.Store) channelOps {
+	if store == nil {
+		return chanOps
+	}
+	return &opsWithMessageStore{
+		channelOps: chanOps,
+		store:      store,
+	}
+}
+
+// ListMessages serves the first page from the store once the channel was listed before,
+// fetching from Graph only messages modified since the last refresh.
+// Without Top, pages served from the store have the size of the first page read from Graph.
+// System messages and replies are never stored, so listing them always goes to Graph.
+func (o *opsWithMessageStore) ListMessages(ctx context.Context, teamID, channelID string, opts *models.ListMessagesOptions, includeSystem bool) (*models.MessageCollection, error) {
+	if includeSystem || (opts != nil && opts.ExpandReplies) {
+		return o.channelOps.ListMessages(ctx, teamID, channelID, opts, includeSystem)
+	}
+	var top *int32
+	if opts != nil {
+		top = opts.Top
+	}
+
+	key := msgstore.ChannelKey(teamID, channelID)
+	conv, err := o.store.Load(key)
+	if err != nil || conv == nil || conv.SyncedAt == nil {
+		out, err := o.channelOps.ListMessages(ctx, teamID, channelID, opts, includeSystem)
+		if err != nil {
+			return nil, err
+		}
+		_ = msgstore.RecordFirstPage(o.store, key, out)
+		return out, nil
+	}
+
+	changed, err := o.channelOps.ListMessagesModifiedSince(ctx, teamID, channelID, *conv.SyncedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.store.Sync(key, changed); err != nil {
+		return o.channelOps.ListMessages(ctx, teamID, channelID, opts, includeSystem)
+	}
+	conv, err = o.store.Load(key)
+	if err != nil || conv == nil {
+		return o.channelOps.ListMessages(ctx, teamID, channelID, opts, includeSystem)
+	}
+	return msgstore.Page(conv, top), nil
+}
+
+// ListMessagesNext continues pages served from the store with their local cursor,
+// Graph links are followed and the fetched messages are stored.
+// Following the stored link to older messages moves it to the next Graph page.
+func (o *opsWithMessageStore) ListMessagesNext(ctx context.Context, teamID, channelID, nextLink string, includeSystem bool) (*models.MessageCollection, error) {
+	if msgstore.IsCursor(nextLink) {
+		conv, err := o.store.Load(msgstore.ChannelKey(teamID, channelID))
+		if err != nil {
+			return nil, err
+		}
+		out, err := msgstore.PageAfter(conv, nextLink)
+		if err != nil {
+			return nil, snd.MapError(&snd.RequestError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}, snd.WithResource(resources.Team, teamID), snd.WithResource(resources.Channel, channelID))
+		}
+		return out, nil
+	}
+	out, err := o.channelOps.ListMessagesNext(ctx, teamID, channelID, nextLink, includeSystem)
+	if err != nil {
+		return nil, err
+	}
+	if !includeSystem {
+		key := msgstore.ChannelKey(teamID, channelID)
+		_ = o.store.Put(key, out.Messages...)
+		_ = msgstore.AdvanceNextLink(o.store, key, nextLink, out.NextLink)
+	}
+	return out, nil
+}
+
+func (o *opsWithMessageStore) GetMessage(ctx context.Context, teamID, channelID, messageID string) (*models.Message, error) {
+	key := msgstore.ChannelKey(teamID, channelID)
+	if msg, found, err := o.store.Get(key, messageID); err == nil && found {
+		return msg, nil
+	}
+	msg, err := o.channelOps.GetMessage(ctx, teamID, channelID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	_ = o.store.Put(key, msg)
+	return msg, nil
+}
+
+// UpdateMessage stores the updated message read back from Graph.
+func (o *opsWithMessageStore) UpdateMessage(ctx context.Context, teamID, channelID, messageID string, body models.MessageBody) (*models.Message, error) {
+	msg, err := o.channelOps.UpdateMessage(ctx, teamID, channelID, messageID, body)
+	if err != nil {
+		return nil, err
+	}
+	_ = o.store.Put(msgstore.ChannelKey(teamID, channelID), msg)
+	return msg, nil
+}
+
+func (o *opsWithMessageStore) DeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	return o.dropAfter(teamID, channelID, messageID, o.channelOps.DeleteMessage(ctx, teamID, channelID, messageID))
+}
+
+func (o *opsWithMessageStore) UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	return o.dropAfter(teamID, channelID, messageID, o.channelOps.UndoDeleteMessage(ctx, teamID, channelID, messageID))
+}
+
+func (o *opsWithMessageStore) SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	return o.dropAfter(teamID, channelID, messageID, o.channelOps.SetReaction(ctx, teamID, channelID, messageID, reactionType))
+}
+
+func (o *opsWithMessageStore) UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	return o.dropAfter(teamID, channelID, messageID, o.channelOps.UnsetReaction(ctx, teamID, channelID, messageID, reactionType))
+}
+
+// dropAfter drops the stored copy of a message once it was changed in Graph without reading it back.
+// The next read fetches it again, as its modification time is after the last refresh.
+func (o *opsWithMessageStore) dropAfter(teamID, channelID, messageID string, err error) error {
+	if err != nil {
+		return err
+	}
+	_ = o.store.Delete(msgstore.ChannelKey(teamID, channelID), messageID)
+	return nil
+}
+
+// SearchChannelMessages runs local searches against the store; other searches go to Graph.
+func (o *opsWithMessageStore) SearchChannelMessages(ctx context.Context, teamID, channelID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error) {
+	if opts == nil || searchConfig == nil || !searchConfig.Local {
+		return o.channelOps.SearchChannelMessages(ctx, teamID, channelID, opts, searchConfig)
+	}
+	var me *models.MessageFrom
+	if msgstore.NeedsCurrentUser(opts) {
+		var err error
+		if me, err = o.channelOps.GetCurrentUser(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return msgstore.Search(o.store, msgstore.ChannelScope(teamID, channelID), opts, me, time.Now())
+}
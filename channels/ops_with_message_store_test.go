@@ -0,0 +1,467 @@
+package channels
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/msgstore"
+	"github.com/pzsp-teams/lib/internal/testutil"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/pzsp-teams/lib/search"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newOpsWithMessageStoreSUT(t *testing.T) (channelOps, *testutil.MockchannelOps, msgstore.Store, context.Context) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	store, err := msgstore.NewMessageStore(&config.CacheConfig{
+		Mode:         config.CacheSync,
+		MessageStore: &config.MessageStoreConfig{Path: util.Ptr(filepath.Join(t.TempDir(), "messages.json"))},
+	})
+	require.NoError(t, err)
+	chanOps := testutil.NewMockchannelOps(ctrl)
+	return NewOpsWithMessageStore(chanOps, store), chanOps, store, context.Background()
+}
+
+func storedMessage(id string, created time.Time) *models.Message {
+	return &models.Message{ID: id, Content: "msg " + id, CreatedDateTime: created, LastModifiedDateTime: &created}
+}
+
+func TestNewOpsWithMessageStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOps := testutil.NewMockchannelOps(ctrl)
+
+	got := NewOpsWithMessageStore(mockOps, nil)
+	require.Same(t, mockOps, got.(*testutil.MockchannelOps))
+}
+
+func TestOpsWithMessageStore_ListMessages(t *testing.T) {
+	t.Parallel()
+
+	teamID, channelID := "team-1", "chan-1"
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("first read goes to Graph, next reads fetch only changes", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, _, ctx := newOpsWithMessageStoreSUT(t)
+
+		first := &models.MessageCollection{
+			Messages: []*models.Message{storedMessage("m1", t0), storedMessage("m2", t0.Add(time.Minute))},
+			NextLink: util.Ptr("older"),
+		}
+		chanOps.EXPECT().ListMessages(ctx, teamID, channelID, nil, false).Return(first, nil).Times(1)
+
+		out, err := sut.ListMessages(ctx, teamID, channelID, nil, false)
+		require.NoError(t, err)
+		require.Same(t, first, out)
+
+		edited := storedMessage("m1", t0)
+		edited.Content = "edited"
+		editedAt := t0.Add(time.Hour)
+		edited.LastModifiedDateTime = &editedAt
+		chanOps.EXPECT().
+			ListMessagesModifiedSince(ctx, teamID, channelID, t0.Add(time.Minute)).
+			Return([]*models.Message{edited, storedMessage("m3", t0.Add(2*time.Minute))}, nil).
+			Times(1)
+
+		out, err = sut.ListMessages(ctx, teamID, channelID, nil, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m3", "m2"}, util.MapSlices(out.Messages, func(m *models.Message) string { return m.ID }), "page size of the first read")
+		require.True(t, msgstore.IsCursor(*out.NextLink))
+
+		out, err = sut.ListMessagesNext(ctx, teamID, channelID, *out.NextLink, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m1"}, util.MapSlices(out.Messages, func(m *models.Message) string { return m.ID }))
+		require.Equal(t, "edited", out.Messages[0].Content)
+		require.Equal(t, "older", *out.NextLink)
+	})
+
+	t.Run("include system bypasses store", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		chanOps.EXPECT().ListMessages(ctx, teamID, channelID, nil, true).Return(&models.MessageCollection{
+			Messages: []*models.Message{storedMessage("m1", t0)},
+		}, nil)
+
+		_, err := sut.ListMessages(ctx, teamID, channelID, nil, true)
+		require.NoError(t, err)
+
+		conv, err := store.Load(msgstore.ChannelKey(teamID, channelID))
+		require.NoError(t, err)
+		require.Nil(t, conv)
+	})
+
+	t.Run("expand replies bypasses store", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		opts := &models.ListMessagesOptions{ExpandReplies: true}
+		chanOps.EXPECT().ListMessages(ctx, teamID, channelID, opts, false).Return(&models.MessageCollection{
+			Messages: []*models.Message{storedMessage("m1", t0)},
+		}, nil)
+
+		_, err := sut.ListMessages(ctx, teamID, channelID, opts, false)
+		require.NoError(t, err)
+
+		conv, err := store.Load(msgstore.ChannelKey(teamID, channelID))
+		require.NoError(t, err)
+		require.Nil(t, conv)
+	})
+
+	t.Run("refresh error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		require.NoError(t, store.Sync(msgstore.ChannelKey(teamID, channelID), []*models.Message{storedMessage("m1", t0)}))
+
+		chanOps.EXPECT().
+			ListMessagesModifiedSince(ctx, teamID, channelID, t0).
+			Return(nil, testutil.ReqErr(http.StatusForbidden))
+
+		_, err := sut.ListMessages(ctx, teamID, channelID, nil, false)
+		require.Error(t, err)
+	})
+}
+
+func TestOpsWithMessageStore_ListMessagesNext(t *testing.T) {
+	t.Parallel()
+
+	teamID, channelID := "team-1", "chan-1"
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ids := func(c *models.MessageCollection) []string {
+		return util.MapSlices(c.Messages, func(m *models.Message) string { return m.ID })
+	}
+
+	t.Run("warm store pages with a local cursor, then follows Graph", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		key := msgstore.ChannelKey(teamID, channelID)
+		require.NoError(t, store.Sync(key, []*models.Message{
+			storedMessage("m1", t0), storedMessage("m2", t0.Add(time.Minute)), storedMessage("m3", t0.Add(2*time.Minute)),
+		}))
+		require.NoError(t, store.SetNextLink(key, util.Ptr("https://graph.microsoft.com/older")))
+
+		chanOps.EXPECT().ListMessagesModifiedSince(ctx, teamID, channelID, t0.Add(2*time.Minute)).Return(nil, nil)
+		out, err := sut.ListMessages(ctx, teamID, channelID, &models.ListMessagesOptions{Top: util.Ptr(int32(2))}, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m3", "m2"}, ids(out))
+		require.True(t, msgstore.IsCursor(*out.NextLink))
+
+		out, err = sut.ListMessagesNext(ctx, teamID, channelID, *out.NextLink, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m1"}, ids(out))
+		require.Equal(t, "https://graph.microsoft.com/older", *out.NextLink)
+
+		chanOps.EXPECT().
+			ListMessagesNext(ctx, teamID, channelID, "https://graph.microsoft.com/older", false).
+			Return(&models.MessageCollection{Messages: []*models.Message{storedMessage("m0", t0.Add(-time.Minute))}}, nil)
+		out, err = sut.ListMessagesNext(ctx, teamID, channelID, *out.NextLink, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m0"}, ids(out))
+
+		_, found, err := store.Get(key, "m0")
+		require.NoError(t, err)
+		require.True(t, found)
+	})
+
+	t.Run("pages past followed Graph pages", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		key := msgstore.ChannelKey(teamID, channelID)
+		top := &models.ListMessagesOptions{Top: util.Ptr(int32(2))}
+		require.NoError(t, store.Sync(key, []*models.Message{storedMessage("m3", t0), storedMessage("m4", t0.Add(time.Minute))}))
+		require.NoError(t, store.SetNextLink(key, util.Ptr("older-1")))
+		chanOps.EXPECT().ListMessagesModifiedSince(ctx, teamID, channelID, t0.Add(time.Minute)).Return(nil, nil).AnyTimes()
+
+		chanOps.EXPECT().ListMessagesNext(ctx, teamID, channelID, "older-1", false).Return(&models.MessageCollection{
+			Messages: []*models.Message{storedMessage("m2", t0.Add(-time.Minute))},
+			NextLink: util.Ptr("older-2"),
+		}, nil)
+		out, err := sut.ListMessagesNext(ctx, teamID, channelID, "older-1", false)
+		require.NoError(t, err)
+		require.Equal(t, "older-2", *out.NextLink)
+
+		chanOps.EXPECT().ListMessagesNext(ctx, teamID, channelID, "older-2", false).Return(&models.MessageCollection{
+			Messages: []*models.Message{storedMessage("m1", t0.Add(-2*time.Minute))},
+		}, nil)
+		_, err = sut.ListMessagesNext(ctx, teamID, channelID, "older-2", false)
+		require.NoError(t, err)
+
+		out, err = sut.ListMessages(ctx, teamID, channelID, top, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m4", "m3"}, ids(out))
+		out, err = sut.ListMessagesNext(ctx, teamID, channelID, *out.NextLink, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m2", "m1"}, ids(out))
+		require.Nil(t, out.NextLink, "all older messages are stored")
+	})
+
+	t.Run("following an older link keeps the stored one", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		key := msgstore.ChannelKey(teamID, channelID)
+		require.NoError(t, store.Sync(key, []*models.Message{storedMessage("m2", t0)}))
+		require.NoError(t, store.SetNextLink(key, util.Ptr("older-2")))
+
+		chanOps.EXPECT().ListMessagesNext(ctx, teamID, channelID, "older-1", false).Return(&models.MessageCollection{
+			Messages: []*models.Message{storedMessage("m1", t0.Add(-time.Minute))},
+			NextLink: util.Ptr("older-2"),
+		}, nil)
+		_, err := sut.ListMessagesNext(ctx, teamID, channelID, "older-1", false)
+		require.NoError(t, err)
+
+		conv, err := store.Load(key)
+		require.NoError(t, err)
+		require.Equal(t, "older-2", *conv.NextLink)
+	})
+
+	t.Run("cursor of a cleared store -> 400", func(t *testing.T) {
+		t.Parallel()
+
+		sut, _, store, ctx := newOpsWithMessageStoreSUT(t)
+		key := msgstore.ChannelKey(teamID, channelID)
+		require.NoError(t, store.Sync(key, []*models.Message{storedMessage("m1", t0), storedMessage("m2", t0.Add(time.Minute))}))
+		conv, err := store.Load(key)
+		require.NoError(t, err)
+		page := msgstore.Page(conv, util.Ptr(int32(1)))
+		require.NoError(t, store.Clear())
+
+		_, err = sut.ListMessagesNext(ctx, teamID, channelID, *page.NextLink, false)
+		requireStatus(t, err, http.StatusBadRequest)
+	})
+}
+
+func TestOpsWithMessageStore_GetMessage(t *testing.T) {
+	t.Parallel()
+
+	sut, chanOps, _, ctx := newOpsWithMessageStoreSUT(t)
+	msg := storedMessage("m1", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	chanOps.EXPECT().GetMessage(ctx, "team-1", "chan-1", "m1").Return(msg, nil).Times(1)
+
+	got, err := sut.GetMessage(ctx, "team-1", "chan-1", "m1")
+	require.NoError(t, err)
+	require.Equal(t, msg, got)
+
+	got, err = sut.GetMessage(ctx, "team-1", "chan-1", "m1")
+	require.NoError(t, err)
+	require.Equal(t, msg.Content, got.Content)
+}
+
+func TestOpsWithMessageStore_ListMessagesNext_StoresMessages(t *testing.T) {
+	t.Parallel()
+
+	sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+	chanOps.EXPECT().ListMessagesNext(ctx, "team-1", "chan-1", "next", false).Return(&models.MessageCollection{
+		Messages: []*models.Message{storedMessage("m1", time.Now())},
+	}, nil)
+
+	_, err := sut.ListMessagesNext(ctx, "team-1", "chan-1", "next", false)
+	require.NoError(t, err)
+
+	_, found, err := store.Get(msgstore.ChannelKey("team-1", "chan-1"), "m1")
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestOpsWithMessageStore_UpdateMessage(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	key := msgstore.ChannelKey("team-1", "chan-1")
+	body := models.MessageBody{Content: "edited"}
+
+	t.Run("stores the updated message", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		require.NoError(t, store.Sync(key, []*models.Message{storedMessage("m1", t0)}))
+
+		updated := storedMessage("m1", t0)
+		updated.Content = "edited"
+		chanOps.EXPECT().UpdateMessage(ctx, "team-1", "chan-1", "m1", body).Return(updated, nil)
+
+		_, err := sut.UpdateMessage(ctx, "team-1", "chan-1", "m1", body)
+		require.NoError(t, err)
+
+		got, found, err := store.Get(key, "m1")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "edited", got.Content)
+	})
+
+	t.Run("error keeps stored message", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		require.NoError(t, store.Sync(key, []*models.Message{storedMessage("m1", t0)}))
+		chanOps.EXPECT().UpdateMessage(ctx, "team-1", "chan-1", "m1", body).Return(nil, testutil.ReqErr(http.StatusForbidden))
+
+		_, err := sut.UpdateMessage(ctx, "team-1", "chan-1", "m1", body)
+		require.Error(t, err)
+
+		got, found, err := store.Get(key, "m1")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "msg m1", got.Content)
+	})
+}
+
+func TestOpsWithMessageStore_DropsChangedMessages(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	key := msgstore.ChannelKey("team-1", "chan-1")
+
+	type tc struct {
+		name   string
+		expect func(m *testutil.MockchannelOps, ctx context.Context, err error)
+		call   func(sut channelOps, ctx context.Context) error
+	}
+	tests := []tc{
+		{
+			name: "DeleteMessage",
+			expect: func(m *testutil.MockchannelOps, ctx context.Context, err error) {
+				m.EXPECT().DeleteMessage(ctx, "team-1", "chan-1", "m1").Return(err)
+			},
+			call: func(sut channelOps, ctx context.Context) error {
+				return sut.DeleteMessage(ctx, "team-1", "chan-1", "m1")
+			},
+		},
+		{
+			name: "UndoDeleteMessage",
+			expect: func(m *testutil.MockchannelOps, ctx context.Context, err error) {
+				m.EXPECT().UndoDeleteMessage(ctx, "team-1", "chan-1", "m1").Return(err)
+			},
+			call: func(sut channelOps, ctx context.Context) error {
+				return sut.UndoDeleteMessage(ctx, "team-1", "chan-1", "m1")
+			},
+		},
+		{
+			name: "SetReaction",
+			expect: func(m *testutil.MockchannelOps, ctx context.Context, err error) {
+				m.EXPECT().SetReaction(ctx, "team-1", "chan-1", "m1", "like").Return(err)
+			},
+			call: func(sut channelOps, ctx context.Context) error {
+				return sut.SetReaction(ctx, "team-1", "chan-1", "m1", "like")
+			},
+		},
+		{
+			name: "UnsetReaction",
+			expect: func(m *testutil.MockchannelOps, ctx context.Context, err error) {
+				m.EXPECT().UnsetReaction(ctx, "team-1", "chan-1", "m1", "like").Return(err)
+			},
+			call: func(sut channelOps, ctx context.Context) error {
+				return sut.UnsetReaction(ctx, "team-1", "chan-1", "m1", "like")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" drops stored message", func(t *testing.T) {
+			t.Parallel()
+
+			sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+			require.NoError(t, store.Sync(key, []*models.Message{storedMessage("m1", t0)}))
+			tt.expect(chanOps, ctx, nil)
+
+			require.NoError(t, tt.call(sut, ctx))
+
+			_, found, err := store.Get(key, "m1")
+			require.NoError(t, err)
+			require.False(t, found)
+		})
+
+		t.Run(tt.name+" error keeps stored message", func(t *testing.T) {
+			t.Parallel()
+
+			sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+			require.NoError(t, store.Sync(key, []*models.Message{storedMessage("m1", t0)}))
+			tt.expect(chanOps, ctx, testutil.ReqErr(http.StatusForbidden))
+
+			require.Error(t, tt.call(sut, ctx))
+
+			_, found, err := store.Get(key, "m1")
+			require.NoError(t, err)
+			require.True(t, found)
+		})
+	}
+}
+
+func TestOpsWithMessageStore_SearchChannelMessages(t *testing.T) {
+	t.Parallel()
+
+	teamID, channelID := "team-1", "chan-1"
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("graph search is passed through", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, _, ctx := newOpsWithMessageStoreSUT(t)
+		opts := &search.SearchMessagesOptions{Query: util.Ptr("hello")}
+		cfg := search.DefaultSearchConfig()
+		want := &search.SearchResults{}
+		chanOps.EXPECT().SearchChannelMessages(ctx, nil, nil, opts, cfg).Return(want, nil).Times(1)
+
+		got, err := sut.SearchChannelMessages(ctx, nil, nil, opts, cfg)
+		require.NoError(t, err)
+		require.Same(t, want, got)
+	})
+
+	t.Run("local search reads stored messages", func(t *testing.T) {
+		t.Parallel()
+
+		sut, _, store, ctx := newOpsWithMessageStoreSUT(t)
+		require.NoError(t, store.Put(msgstore.ChannelKey(teamID, channelID), storedMessage("m1", t0), storedMessage("m2", t0)))
+		require.NoError(t, store.Put(msgstore.ChatKey("chat-1"), storedMessage("m3", t0)))
+
+		got, err := sut.SearchChannelMessages(ctx, nil, nil,
+			&search.SearchMessagesOptions{Query: util.Ptr("m1")}, &search.SearchConfig{Local: true})
+		require.NoError(t, err)
+		require.Len(t, got.Messages, 1)
+		require.Equal(t, "m1", got.Messages[0].Message.ID)
+		require.Equal(t, teamID, *got.Messages[0].TeamID)
+		require.Equal(t, channelID, *got.Messages[0].ChannelID)
+	})
+
+	t.Run("local search resolves the current user when needed", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, store, ctx := newOpsWithMessageStoreSUT(t)
+		mine := storedMessage("m1", t0)
+		mine.From = &models.MessageFrom{UserID: "me"}
+		require.NoError(t, store.Put(msgstore.ChannelKey(teamID, channelID), mine, storedMessage("m2", t0)))
+		chanOps.EXPECT().GetCurrentUser(ctx).Return(&models.MessageFrom{UserID: "me"}, nil).Times(1)
+
+		got, err := sut.SearchChannelMessages(ctx, util.Ptr(teamID), util.Ptr(channelID),
+			&search.SearchMessagesOptions{FromMe: true}, &search.SearchConfig{Local: true})
+		require.NoError(t, err)
+		require.Len(t, got.Messages, 1)
+		require.Equal(t, "m1", got.Messages[0].Message.ID)
+	})
+
+	t.Run("current user error is returned", func(t *testing.T) {
+		t.Parallel()
+
+		sut, chanOps, _, ctx := newOpsWithMessageStoreSUT(t)
+		chanOps.EXPECT().GetCurrentUser(ctx).Return(nil, testutil.ReqErr(http.StatusUnauthorized)).Times(1)
+
+		_, err := sut.SearchChannelMessages(ctx, nil, nil,
+			&search.SearchMessagesOptions{IsMentioned: util.Ptr(true)}, &search.SearchConfig{Local: true})
+		require.Error(t, err)
+	})
+}
@@ -3,10 +3,16 @@ package channels
 import (
 	"context"
 	"errors"
+	"io"
+	"path/filepath"
 
+	"github.com/pzsp-teams/lib/delta"
+	"github.com/pzsp-teams/lib/internal/attachments"
+	"github.com/pzsp-teams/lib/internal/mentions"
 	"github.com/pzsp-teams/lib/internal/resolver"
 	"github.com/pzsp-teams/lib/internal/resources"
 	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/markdown"
 	"github.com/pzsp-teams/lib/models"
 	"github.com/pzsp-teams/lib/search"
 )
@@ -147,6 +153,166 @@ func (s *service) SendReply(ctx context.Context, teamRef, channelRef, messageID
 	return out, nil
 }
 
+func (s *service) UpdateMessage(ctx context.Context, teamRef, channelRef, messageID string, body models.MessageBody) (*models.Message, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("UpdateMessage", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	out, err := s.ops.UpdateMessage(ctx, teamID, channelID, messageID, body)
+	if err != nil {
+		return nil, snd.Wrap("UpdateMessage", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return out, nil
+}
+
+func (s *service) UpdateReply(ctx context.Context, teamRef, channelRef, messageID, replyID string, body models.MessageBody) (*models.Message, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("UpdateReply", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	out, err := s.ops.UpdateReply(ctx, teamID, channelID, messageID, replyID, body)
+	if err != nil {
+		return nil, snd.Wrap("UpdateReply", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return out, nil
+}
+
+func (s *service) DeleteMessage(ctx context.Context, teamRef, channelRef, messageID string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("DeleteMessage", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("DeleteMessage", s.ops.DeleteMessage(ctx, teamID, channelID, messageID),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) DeleteReply(ctx context.Context, teamRef, channelRef, messageID, replyID string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("DeleteReply", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("DeleteReply", s.ops.DeleteReply(ctx, teamID, channelID, messageID, replyID),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) UndoDeleteMessage(ctx context.Context, teamRef, channelRef, messageID string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("UndoDeleteMessage", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("UndoDeleteMessage", s.ops.UndoDeleteMessage(ctx, teamID, channelID, messageID),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) UndoDeleteReply(ctx context.Context, teamRef, channelRef, messageID, replyID string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("UndoDeleteReply", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("UndoDeleteReply", s.ops.UndoDeleteReply(ctx, teamID, channelID, messageID, replyID),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) SetReaction(ctx context.Context, teamRef, channelRef, messageID, reactionType string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("SetReaction", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("SetReaction", s.ops.SetReaction(ctx, teamID, channelID, messageID, reactionType),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) UnsetReaction(ctx context.Context, teamRef, channelRef, messageID, reactionType string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("UnsetReaction", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("UnsetReaction", s.ops.UnsetReaction(ctx, teamID, channelID, messageID, reactionType),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) SetReplyReaction(ctx context.Context, teamRef, channelRef, messageID, replyID, reactionType string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("SetReplyReaction", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("SetReplyReaction", s.ops.SetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) UnsetReplyReaction(ctx context.Context, teamRef, channelRef, messageID, replyID, reactionType string) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("UnsetReplyReaction", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("UnsetReplyReaction", s.ops.UnsetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
 func (s *service) ListMessages(ctx context.Context, teamRef, channelRef string, opts *models.ListMessagesOptions, includeSystem bool, nextLink *string) (*models.MessageCollection, error) {
 	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
 	if err != nil {
@@ -243,6 +409,170 @@ func (s *service) GetReply(ctx context.Context, teamRef, channelRef, messageID,
 	return out, nil
 }
 
+func (s *service) ListHostedContents(ctx context.Context, teamRef, channelRef, messageID string) ([]*models.HostedContent, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("ListHostedContents", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	out, err := s.ops.ListHostedContents(ctx, teamID, channelID, messageID)
+	if err != nil {
+		return nil, snd.Wrap("ListHostedContents", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return out, nil
+}
+
+func (s *service) ListReplyHostedContents(ctx context.Context, teamRef, channelRef, messageID, replyID string) ([]*models.HostedContent, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("ListReplyHostedContents", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	out, err := s.ops.ListReplyHostedContents(ctx, teamID, channelID, messageID, replyID)
+	if err != nil {
+		return nil, snd.Wrap("ListReplyHostedContents", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return out, nil
+}
+
+func (s *service) DownloadHostedContent(ctx context.Context, teamRef, channelRef, messageID, hostedContentID string, w io.Writer) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("DownloadHostedContent", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("DownloadHostedContent", s.ops.DownloadHostedContent(ctx, teamID, channelID, messageID, hostedContentID, w),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) DownloadReplyHostedContent(ctx context.Context, teamRef, channelRef, messageID, replyID, hostedContentID string, w io.Writer) error {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return snd.Wrap("DownloadReplyHostedContent", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return snd.Wrap("DownloadReplyHostedContent", s.ops.DownloadReplyHostedContent(ctx, teamID, channelID, messageID, replyID, hostedContentID, w),
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	)
+}
+
+func (s *service) UploadFile(ctx context.Context, teamRef, channelRef, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("UploadFile", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	out, err := s.ops.UploadFile(ctx, teamID, channelID, name, r, size)
+	if err != nil {
+		return nil, snd.Wrap("UploadFile", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return out, nil
+}
+
+func (s *service) UploadLocalFile(ctx context.Context, teamRef, channelRef, path string) (*models.Attachment, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("UploadLocalFile", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	f, size, err := attachments.OpenLocalFile(path)
+	if err != nil {
+		return nil, snd.Wrap("UploadLocalFile", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+	defer f.Close()
+
+	out, err := s.ops.UploadFile(ctx, teamID, channelID, filepath.Base(path), f, size)
+	if err != nil {
+		return nil, snd.Wrap("UploadLocalFile", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return out, nil
+}
+
+func (s *service) ListAttachments(ctx context.Context, teamRef, channelRef, messageID string) ([]models.Attachment, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("ListAttachments", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	msg, err := s.ops.GetMessage(ctx, teamID, channelID, messageID)
+	if err != nil {
+		return nil, snd.Wrap("ListAttachments", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	return msg.Attachments, nil
+}
+
+func (s *service) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	return snd.Wrap("DownloadFile", s.ops.DownloadFile(ctx, attachment, w))
+}
+
+func (s *service) SyncMessages(ctx context.Context, teamRef, channelRef string, state delta.Store) (*models.MessageChanges, error) {
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("SyncMessages", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+
+	out, err := delta.Sync(state, delta.ChannelKey(teamID, channelID), func(token string) (*models.MessageChanges, string, error) {
+		return s.ops.SyncMessages(ctx, teamID, channelID, token)
+	})
+	if err != nil {
+		return nil, snd.Wrap("SyncMessages", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+		)
+	}
+	return out, nil
+}
+
 func (s *service) ListMembers(ctx context.Context, teamRef, channelRef string) ([]*models.Member, error) {
 	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
 	if err != nil {
@@ -366,6 +696,44 @@ func (s *service) GetMentions(ctx context.Context, teamRef, channelRef string, r
 	return out, nil
 }
 
+func (s *service) NewMarkdownMessageBody(ctx context.Context, teamRef, channelRef, src string) (*models.MessageBody, error) {
+	tokens := markdown.MentionTokens(src, markdown.MentionChannel, markdown.MentionTeam)
+	if len(tokens) == 0 {
+		body := markdown.NewMessageBody(src, nil)
+		return &body, nil
+	}
+
+	teamID, channelID, err := s.resolveTeamAndChannelID(ctx, teamRef, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("NewMarkdownMessageBody", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+			snd.NewParam(resources.MentionRef, tokens...),
+		)
+	}
+
+	ments, err := s.ops.GetMentions(ctx, teamID, teamRef, channelRef, channelID, tokens)
+	if err != nil {
+		return nil, snd.Wrap("NewMarkdownMessageBody", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+			snd.NewParam(resources.MentionRef, tokens...),
+		)
+	}
+
+	byToken, err := mentions.ByToken(tokens, ments)
+	if err != nil {
+		return nil, snd.Wrap("NewMarkdownMessageBody", err,
+			snd.NewParam(resources.TeamRef, teamRef),
+			snd.NewParam(resources.ChannelRef, channelRef),
+			snd.NewParam(resources.MentionRef, tokens...),
+		)
+	}
+
+	body := markdown.NewMessageBody(src, byToken)
+	return &body, nil
+}
+
 func (s *service) SearchMessages(ctx context.Context, teamRef, channelRef *string, opts *search.SearchMessagesOptions, cfg *search.SearchConfig) (*search.SearchResults, error) {
 	var teamIDptr, channelIDptr *string
 
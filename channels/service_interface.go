@@ -15,7 +15,9 @@ package channels
 
 import (
 	"context"
+	"io"
 
+	"github.com/pzsp-teams/lib/delta"
 	"github.com/pzsp-teams/lib/models"
 	"github.com/pzsp-teams/lib/search"
 )
@@ -46,6 +48,10 @@ type Service interface {
 	//   - Content: the text or html content of the message.
 	//   - ContentType: the type of content (text or html).
 	//   - Mentions: optional mentions to include in the message.
+	//   - Subject: optional headline of the post.
+	//   - Importance: optional importance (normal, high or urgent).
+	//   - Attachments: optional attachments, e.g. Adaptive Cards built with package adaptivecards.
+	//   - HostedContents: optional inline images, referenced as <img src="../hostedContents/{ID}/$value">.
 	SendMessage(ctx context.Context, teamRef, channelRef string, body models.MessageBody) (*models.Message, error)
 
 	// SendReply sends a reply to a specific message in a channel.
@@ -53,8 +59,45 @@ type Service interface {
 	//   - Content: the text or html content of the message.
 	//   - ContentType: the type of content (text or html).
 	//   - Mentions: optional mentions to include in the message.
+	//   - Importance: optional importance (normal, high or urgent). Replies cannot have a Subject.
+	//   - Attachments: optional attachments, e.g. Adaptive Cards built with package adaptivecards.
+	//   - HostedContents: optional inline images, referenced as <img src="../hostedContents/{ID}/$value">.
 	SendReply(ctx context.Context, teamRef, channelRef, messageID string, body models.MessageBody) (*models.Message, error)
 
+	// UpdateMessage replaces the body of a message in a channel, e.g. to report progress in place.
+	// Body replaces the content and mentions of the message; mentions are prepared as in SendMessage.
+	// Subject, Importance and HostedContents cannot be updated; a body setting them is rejected with 400.
+	// The returned message is read back after the update and carries LastEditedDateTime.
+	UpdateMessage(ctx context.Context, teamRef, channelRef, messageID string, body models.MessageBody) (*models.Message, error)
+
+	// UpdateReply replaces the body of a reply to a message in a channel, like UpdateMessage.
+	UpdateReply(ctx context.Context, teamRef, channelRef, messageID, replyID string, body models.MessageBody) (*models.Message, error)
+
+	// DeleteMessage deletes a message from a channel. Action is reversible - soft delete is performed.
+	DeleteMessage(ctx context.Context, teamRef, channelRef, messageID string) error
+
+	// DeleteReply deletes a reply to a message in a channel. Action is reversible - soft delete is performed.
+	DeleteReply(ctx context.Context, teamRef, channelRef, messageID, replyID string) error
+
+	// UndoDeleteMessage restores a message soft-deleted with DeleteMessage.
+	UndoDeleteMessage(ctx context.Context, teamRef, channelRef, messageID string) error
+
+	// UndoDeleteReply restores a reply soft-deleted with DeleteReply.
+	UndoDeleteReply(ctx context.Context, teamRef, channelRef, messageID, replyID string) error
+
+	// SetReaction adds a reaction of the current user to a message in a channel.
+	// ReactionType is the reaction emoji, e.g. "👍".
+	SetReaction(ctx context.Context, teamRef, channelRef, messageID, reactionType string) error
+
+	// UnsetReaction removes a reaction of the current user from a message in a channel.
+	UnsetReaction(ctx context.Context, teamRef, channelRef, messageID, reactionType string) error
+
+	// SetReplyReaction adds a reaction of the current user to a reply in a channel, like SetReaction.
+	SetReplyReaction(ctx context.Context, teamRef, channelRef, messageID, replyID, reactionType string) error
+
+	// UnsetReplyReaction removes a reaction of the current user from a reply in a channel.
+	UnsetReplyReaction(ctx context.Context, teamRef, channelRef, messageID, replyID, reactionType string) error
+
 	// ListMessages returns one page of messages in a channel.
 	//
 	// NextLink in the returned MessageCollection can be used to retrieve the next page of messages.
@@ -71,6 +114,43 @@ type Service interface {
 	// GetReply retrieves a specific reply to a message in a channel by its ID.
 	GetReply(ctx context.Context, teamRef, channelRef, messageID, replyID string) (*models.Message, error)
 
+	// ListHostedContents returns the hosted contents of a message in a channel, such as inline images.
+	// Content of the returned hosted contents is not set; use DownloadHostedContent to retrieve it.
+	ListHostedContents(ctx context.Context, teamRef, channelRef, messageID string) ([]*models.HostedContent, error)
+
+	// ListReplyHostedContents returns the hosted contents of a reply in a channel, like ListHostedContents.
+	ListReplyHostedContents(ctx context.Context, teamRef, channelRef, messageID, replyID string) ([]*models.HostedContent, error)
+
+	// DownloadHostedContent writes the content of a hosted content of a message in a channel to w.
+	DownloadHostedContent(ctx context.Context, teamRef, channelRef, messageID, hostedContentID string, w io.Writer) error
+
+	// DownloadReplyHostedContent writes the content of a hosted content of a reply in a channel to w.
+	DownloadReplyHostedContent(ctx context.Context, teamRef, channelRef, messageID, replyID, hostedContentID string, w io.Writer) error
+
+	// UploadFile uploads size bytes read from r as a file named name to the files folder of a channel
+	// (the channel folder in the SharePoint site of the team), replacing an existing file with the same name.
+	// Files larger than 4 MiB are uploaded in chunks through an upload session.
+	//
+	// The returned reference attachment can be sent with a message in MessageBody.Attachments.
+	UploadFile(ctx context.Context, teamRef, channelRef, name string, r io.Reader, size int64) (*models.Attachment, error)
+
+	// UploadLocalFile uploads the file at path to the files folder of a channel, like UploadFile.
+	UploadLocalFile(ctx context.Context, teamRef, channelRef, path string) (*models.Attachment, error)
+
+	// ListAttachments returns the attachments of a message in a channel, such as files and cards.
+	// Attachments of replies are returned by GetReply.
+	ListAttachments(ctx context.Context, teamRef, channelRef, messageID string) ([]models.Attachment, error)
+
+	// DownloadFile writes the content of a file attachment (ContentType "reference") of a received message to w.
+	DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error
+
+	// SyncMessages returns top-level messages created, edited or deleted in a channel since its previous sync.
+	//
+	// The sync state is kept in state (see package delta); a nil state starts over on every call.
+	// The first sync, and a sync whose state expired, report all messages of the channel as new and set Reset.
+	// Replies are not included.
+	SyncMessages(ctx context.Context, teamRef, channelRef string, state delta.Store) (*models.MessageChanges, error)
+
 	// ListMembers returns all members of a channel.
 	ListMembers(ctx context.Context, teamRef, channelRef string) ([]*models.Member, error)
 
@@ -90,6 +170,11 @@ type Service interface {
 	//   - User IDs
 	GetMentions(ctx context.Context, teamRef, channelRef string, rawMentions []string) ([]models.Mention, error)
 
+	// NewMarkdownMessageBody renders Markdown as an HTML message body for a channel, see package markdown.
+	// Mentions written as @user@example.com, @channel and @team are resolved as in GetMentions
+	// and their <at> tags are numbered automatically.
+	NewMarkdownMessageBody(ctx context.Context, teamRef, channelRef, src string) (*models.MessageBody, error)
+
 	// SearchMessagesInChannel searches for messages in a channel matching the specified query and options.
 	//
 	// If channelRef is nil, searches across all channels the user has access to.
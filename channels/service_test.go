@@ -1,10 +1,15 @@
 package channels
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pzsp-teams/lib/delta"
 	snd "github.com/pzsp-teams/lib/internal/sender"
 	"github.com/pzsp-teams/lib/internal/testutil"
 	"github.com/pzsp-teams/lib/internal/util"
@@ -463,6 +468,222 @@ func TestService_SendMessage_SendReply(t *testing.T) {
 	}
 }
 
+func TestService_UpdateMessage_UpdateReply(t *testing.T) {
+	body := models.MessageBody{Content: "done", ContentType: models.MessageContentTypeText}
+
+	t.Run("UpdateMessage resolves refs", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				UpdateMessage(gomock.Any(), "team-id", "chan-id", "msg-1", body).
+				Return(&models.Message{ID: "msg-1"}, nil).
+				Times(1)
+		})
+
+		got, err := svc.UpdateMessage(ctx, "TeamA", "ChanA", "msg-1", body)
+		require.NoError(t, err)
+		assert.Equal(t, "msg-1", got.ID)
+	})
+
+	t.Run("UpdateReply wraps ops error", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				UpdateReply(gomock.Any(), "team-id", "chan-id", "msg-1", "r-1", body).
+				Return(nil, &snd.ErrAccessForbidden{Code: 403, OriginalMessage: "nope"}).
+				Times(1)
+		})
+
+		got, err := svc.UpdateReply(ctx, "TeamA", "ChanA", "msg-1", "r-1", body)
+		require.Nil(t, got)
+		testutil.RequireReqErrCode(t, err, 403)
+	})
+}
+
+func TestService_DeleteAndUndoDeleteMessages(t *testing.T) {
+	type testCase struct {
+		name       string
+		setupMocks func(d sutDeps)
+		call       func(svc Service, ctx context.Context) error
+		assertErr  func(t *testing.T, err error)
+	}
+
+	testCases := []testCase{
+		{
+			name: "DeleteMessage resolves refs",
+			setupMocks: func(d sutDeps) {
+				expectResolveTeamAndChannel(t, d)
+				d.ops.EXPECT().DeleteMessage(gomock.Any(), "team-id", "chan-id", "msg-1").Return(nil).Times(1)
+			},
+			call: func(svc Service, ctx context.Context) error {
+				return svc.DeleteMessage(ctx, "TeamA", "ChanA", "msg-1")
+			},
+		},
+		{
+			name: "DeleteReply resolves refs",
+			setupMocks: func(d sutDeps) {
+				expectResolveTeamAndChannel(t, d)
+				d.ops.EXPECT().DeleteReply(gomock.Any(), "team-id", "chan-id", "msg-1", "r-1").Return(nil).Times(1)
+			},
+			call: func(svc Service, ctx context.Context) error {
+				return svc.DeleteReply(ctx, "TeamA", "ChanA", "msg-1", "r-1")
+			},
+		},
+		{
+			name: "UndoDeleteMessage resolves refs",
+			setupMocks: func(d sutDeps) {
+				expectResolveTeamAndChannel(t, d)
+				d.ops.EXPECT().UndoDeleteMessage(gomock.Any(), "team-id", "chan-id", "msg-1").Return(nil).Times(1)
+			},
+			call: func(svc Service, ctx context.Context) error {
+				return svc.UndoDeleteMessage(ctx, "TeamA", "ChanA", "msg-1")
+			},
+		},
+		{
+			name: "UndoDeleteReply ops error propagated",
+			setupMocks: func(d sutDeps) {
+				expectResolveTeamAndChannel(t, d)
+				d.ops.EXPECT().
+					UndoDeleteReply(gomock.Any(), "team-id", "chan-id", "msg-1", "r-1").
+					Return(&snd.ErrAccessForbidden{Code: 403, OriginalMessage: "nope"}).
+					Times(1)
+			},
+			call: func(svc Service, ctx context.Context) error {
+				return svc.UndoDeleteReply(ctx, "TeamA", "ChanA", "msg-1", "r-1")
+			},
+			assertErr: func(t *testing.T, err error) { testutil.RequireReqErrCode(t, err, 403) },
+		},
+		{
+			name: "channel resolver error",
+			setupMocks: func(d sutDeps) {
+				expectResolveTeam(t, d)
+				d.channelResolver.EXPECT().
+					ResolveChannelRefToID(gomock.Any(), "team-id", "ChanA").
+					Return("", errors.New("boom")).
+					Times(1)
+			},
+			call: func(svc Service, ctx context.Context) error {
+				return svc.DeleteReply(ctx, "TeamA", "ChanA", "msg-1", "r-1")
+			},
+			assertErr: func(t *testing.T, err error) { require.Error(t, err) },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, ctx := newSUT(t, tc.setupMocks)
+			err := tc.call(svc, ctx)
+			if tc.assertErr != nil {
+				tc.assertErr(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_Reactions(t *testing.T) {
+	t.Run("SetReaction resolves refs", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().SetReaction(gomock.Any(), "team-id", "chan-id", "msg-1", "👍").Return(nil).Times(1)
+		})
+
+		require.NoError(t, svc.SetReaction(ctx, "TeamA", "ChanA", "msg-1", "👍"))
+	})
+
+	t.Run("UnsetReplyReaction wraps ops error", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				UnsetReplyReaction(gomock.Any(), "team-id", "chan-id", "msg-1", "r-1", "👍").
+				Return(&snd.ErrAccessForbidden{Code: 403, OriginalMessage: "nope"}).
+				Times(1)
+		})
+
+		err := svc.UnsetReplyReaction(ctx, "TeamA", "ChanA", "msg-1", "r-1", "👍")
+		testutil.RequireReqErrCode(t, err, 403)
+	})
+}
+
+func TestService_HostedContents(t *testing.T) {
+	t.Run("ListHostedContents resolves refs", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				ListHostedContents(gomock.Any(), "team-id", "chan-id", "msg-1").
+				Return([]*models.HostedContent{{ID: "hc-1", ContentType: "image/png"}}, nil).
+				Times(1)
+		})
+
+		got, err := svc.ListHostedContents(ctx, "TeamA", "ChanA", "msg-1")
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+	})
+
+	t.Run("DownloadReplyHostedContent wraps ops error", func(t *testing.T) {
+		var buf bytes.Buffer
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				DownloadReplyHostedContent(gomock.Any(), "team-id", "chan-id", "msg-1", "r-1", "hc-1", &buf).
+				Return(&snd.ErrResourceNotFound{Code: 404, OriginalMessage: "gone"}).
+				Times(1)
+		})
+
+		err := svc.DownloadReplyHostedContent(ctx, "TeamA", "ChanA", "msg-1", "r-1", "hc-1", &buf)
+		testutil.RequireReqErrCode(t, err, 404)
+	})
+}
+
+func TestService_Files(t *testing.T) {
+	t.Run("UploadLocalFile uploads file under its base name", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.txt")
+		require.NoError(t, os.WriteFile(path, []byte("abc"), 0o600))
+
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				UploadFile(gomock.Any(), "team-id", "chan-id", "report.txt", gomock.Any(), int64(3)).
+				DoAndReturn(func(_ context.Context, _, _, _ string, r io.Reader, _ int64) (*models.Attachment, error) {
+					data, err := io.ReadAll(r)
+					require.NoError(t, err)
+					assert.Equal(t, "abc", string(data))
+					return &models.Attachment{ID: "att-1", ContentType: models.AttachmentContentTypeReference}, nil
+				}).
+				Times(1)
+		})
+
+		got, err := svc.UploadLocalFile(ctx, "TeamA", "ChanA", path)
+		require.NoError(t, err)
+		assert.Equal(t, "att-1", got.ID)
+	})
+
+	t.Run("UploadLocalFile reports missing file", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+		})
+
+		_, err := svc.UploadLocalFile(ctx, "TeamA", "ChanA", filepath.Join(t.TempDir(), "missing.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("ListAttachments returns message attachments", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				GetMessage(gomock.Any(), "team-id", "chan-id", "msg-1").
+				Return(&models.Message{ID: "msg-1", Attachments: []models.Attachment{{ID: "att-1"}}}, nil).
+				Times(1)
+		})
+
+		got, err := svc.ListAttachments(ctx, "TeamA", "ChanA", "msg-1")
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "att-1", got[0].ID)
+	})
+}
+
 func TestService_ListMessages_ListReplies(t *testing.T) {
 	t.Run("ListMessages passes opts through", func(t *testing.T) {
 		top := int32(5)
@@ -744,6 +965,51 @@ func TestService_GetMentions(t *testing.T) {
 	}
 }
 
+func TestService_NewMarkdownMessageBody(t *testing.T) {
+	t.Run("resolves mention tokens and numbers at tags", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				GetMentions(gomock.Any(), "team-id", "TeamA", "ChanA", "chan-id", []string{"alice@example.com", "channel"}).
+				Return([]models.Mention{
+					{Kind: models.MentionUser, TargetID: "u-1", Text: "Alice"},
+					{Kind: models.MentionChannel, TargetID: "chan-id", Text: "ChanA"},
+				}, nil).
+				Times(1)
+		})
+
+		got, err := svc.NewMarkdownMessageBody(ctx, "TeamA", "ChanA", "**Hi** @alice@example.com, see @channel and @alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, models.MessageContentTypeHTML, got.ContentType)
+		assert.Equal(t, `<p><strong>Hi</strong> <at id="0">Alice</at>, see <at id="1">ChanA</at> and <at id="2">Alice</at></p>`, got.Content)
+		require.Len(t, got.Mentions, 3)
+		assert.Equal(t, int32(2), got.Mentions[2].AtID)
+		assert.Equal(t, "u-1", got.Mentions[2].TargetID)
+	})
+
+	t.Run("without mentions refs are not resolved", func(t *testing.T) {
+		svc, ctx := newSUT(t, nil)
+
+		got, err := svc.NewMarkdownMessageBody(ctx, "TeamA", "ChanA", "- a\n- b")
+		require.NoError(t, err)
+		assert.Equal(t, "<ul><li>a</li><li>b</li></ul>", got.Content)
+		assert.Empty(t, got.Mentions)
+	})
+
+	t.Run("mention errors are propagated", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				GetMentions(gomock.Any(), "team-id", "TeamA", "ChanA", "chan-id", []string{"ghost@example.com"}).
+				Return(nil, &snd.ErrResourceNotFound{Code: 404, OriginalMessage: "no user"}).
+				Times(1)
+		})
+
+		_, err := svc.NewMarkdownMessageBody(ctx, "TeamA", "ChanA", "@ghost@example.com")
+		testutil.RequireReqErrCode(t, err, 404)
+	})
+}
+
 func TestService_SendMessage_Errors(t *testing.T) {
 	type testCase struct {
 		name       string
@@ -1578,3 +1844,88 @@ func TestService_SearchMessages(t *testing.T) {
 		testutil.RequireReqErrCode(t, err, 403)
 	})
 }
+
+func TestService_SyncMessages(t *testing.T) {
+	t.Run("continues from stored token and stores the next one", func(t *testing.T) {
+		state := delta.NewMemoryStore()
+		require.NoError(t, state.Save(delta.ChannelKey(defaultTeamID, defaultChannelID), "delta-1"))
+		want := &models.MessageChanges{Edited: []*models.Message{{ID: "m1"}}}
+
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				SyncMessages(gomock.Any(), defaultTeamID, defaultChannelID, "delta-1").
+				Return(want, "delta-2", nil).
+				Times(1)
+		})
+
+		got, err := svc.SyncMessages(ctx, defaultTeamRef, defaultChannelRef, state)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+
+		token, err := state.Load(delta.ChannelKey(defaultTeamID, defaultChannelID))
+		require.NoError(t, err)
+		assert.Equal(t, "delta-2", token)
+	})
+
+	t.Run("starts over when stored token expired", func(t *testing.T) {
+		state := delta.NewMemoryStore()
+		require.NoError(t, state.Save(delta.ChannelKey(defaultTeamID, defaultChannelID), "expired"))
+
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			gomock.InOrder(
+				d.ops.EXPECT().
+					SyncMessages(gomock.Any(), defaultTeamID, defaultChannelID, "expired").
+					Return(nil, "", snd.MapError(testutil.ReqErr(410))),
+				d.ops.EXPECT().
+					SyncMessages(gomock.Any(), defaultTeamID, defaultChannelID, "").
+					Return(&models.MessageChanges{Reset: true}, "delta-1", nil),
+			)
+		})
+
+		got, err := svc.SyncMessages(ctx, defaultTeamRef, defaultChannelRef, state)
+		require.NoError(t, err)
+		assert.True(t, got.Reset)
+
+		token, err := state.Load(delta.ChannelKey(defaultTeamID, defaultChannelID))
+		require.NoError(t, err)
+		assert.Equal(t, "delta-1", token)
+	})
+
+	t.Run("keeps stored token on error", func(t *testing.T) {
+		state := delta.NewMemoryStore()
+		require.NoError(t, state.Save(delta.ChannelKey(defaultTeamID, defaultChannelID), "delta-1"))
+
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				SyncMessages(gomock.Any(), defaultTeamID, defaultChannelID, "delta-1").
+				Return(nil, "", testutil.ReqErr(500)).
+				Times(1)
+		})
+
+		got, err := svc.SyncMessages(ctx, defaultTeamRef, defaultChannelRef, state)
+		require.Nil(t, got)
+		testutil.RequireReqErrCode(t, err, 500)
+		require.ErrorContains(t, err, "SyncMessages")
+
+		token, err := state.Load(delta.ChannelKey(defaultTeamID, defaultChannelID))
+		require.NoError(t, err)
+		assert.Equal(t, "delta-1", token)
+	})
+
+	t.Run("nil state starts over", func(t *testing.T) {
+		svc, ctx := newSUT(t, func(d sutDeps) {
+			expectResolveTeamAndChannel(t, d)
+			d.ops.EXPECT().
+				SyncMessages(gomock.Any(), defaultTeamID, defaultChannelID, "").
+				Return(&models.MessageChanges{Reset: true}, "delta-1", nil).
+				Times(1)
+		})
+
+		got, err := svc.SyncMessages(ctx, defaultTeamRef, defaultChannelRef, nil)
+		require.NoError(t, err)
+		assert.True(t, got.Reset)
+	})
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/pzsp-teams/lib/internal/adapter"
 	"github.com/pzsp-teams/lib/internal/api"
+	"github.com/pzsp-teams/lib/internal/attachments"
 	"github.com/pzsp-teams/lib/internal/mentions"
 	"github.com/pzsp-teams/lib/internal/resources"
 	snd "github.com/pzsp-teams/lib/internal/sender"
@@ -102,24 +104,65 @@ func (o *ops) ListMessages(ctx context.Context, chatID string, includeSystem boo
 }
 
 func (o *ops) SendMessage(ctx context.Context, chatID string, body models.MessageBody) (*models.Message, error) {
-	ments, err := mentions.PrepareMentions(&body)
+	atts, hosted, ments, err := attachments.PrepareBody(&body)
 	if err != nil {
 		return nil, snd.MapError(&snd.RequestError{
 			Code:    http.StatusBadRequest,
-			Message: fmt.Sprintf("Failed to prepare mentions: %v", err),
+			Message: err.Error(),
 		})
 	}
-	resp, requestErr := o.chatAPI.SendMessage(ctx, chatID, body.Content, string(body.ContentType), ments)
+	resp, requestErr := o.chatAPI.SendMessage(ctx, chatID, body.Content, string(body.ContentType), body.Subject, string(body.Importance), ments, atts, hosted)
 	if requestErr != nil {
 		return nil, snd.MapError(requestErr, snd.WithResource(resources.Chat, chatID))
 	}
 	return adapter.MapGraphMessage(resp), nil
 }
 
+// UpdateMessage replaces the body of a message. Graph answers the update with no content,
+// so the message is read back to report its lastEditedDateTime.
+func (o *ops) UpdateMessage(ctx context.Context, chatID, messageID string, body models.MessageBody) (*models.Message, error) {
+	if len(body.HostedContents) > 0 {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: "Hosted contents can only be sent with new messages",
+		}, snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+	}
+	if body.Subject != "" || body.Importance != "" {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: "Subject and importance can only be set on new messages",
+		}, snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+	}
+	atts, _, ments, err := attachments.PrepareBody(&body)
+	if err != nil {
+		return nil, snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}, snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+	}
+	requestErr := o.chatAPI.UpdateMessage(ctx, chatID, messageID, body.Content, string(body.ContentType), ments, atts)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+	}
+	return o.GetMessage(ctx, chatID, messageID)
+}
+
 func (o *ops) DeleteMessage(ctx context.Context, chatID, messageID string) error {
 	return snd.MapError(o.chatAPI.DeleteMessage(ctx, chatID, messageID), snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
 }
 
+func (o *ops) UndoDeleteMessage(ctx context.Context, chatID, messageID string) error {
+	return snd.MapError(o.chatAPI.UndoDeleteMessage(ctx, chatID, messageID), snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+}
+
+func (o *ops) SetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	return snd.MapError(o.chatAPI.SetReaction(ctx, chatID, messageID, reactionType), snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+}
+
+func (o *ops) UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	return snd.MapError(o.chatAPI.UnsetReaction(ctx, chatID, messageID, reactionType), snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+}
+
 func (o *ops) GetMessage(ctx context.Context, chatID, messageID string) (*models.Message, error) {
 	resp, requestErr := o.chatAPI.GetMessage(ctx, chatID, messageID)
 	if requestErr != nil {
@@ -128,6 +171,45 @@ func (o *ops) GetMessage(ctx context.Context, chatID, messageID string) (*models
 	return adapter.MapGraphMessage(resp), nil
 }
 
+func (o *ops) ListHostedContents(ctx context.Context, chatID, messageID string) ([]*models.HostedContent, error) {
+	resp, requestErr := o.chatAPI.ListHostedContents(ctx, chatID, messageID)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID))
+	}
+	return util.MapSlices(resp.GetValue(), adapter.MapGraphHostedContent), nil
+}
+
+func (o *ops) DownloadHostedContent(ctx context.Context, chatID, messageID, hostedContentID string, w io.Writer) error {
+	content, requestErr := o.chatAPI.GetHostedContent(ctx, chatID, messageID, hostedContentID)
+	if requestErr != nil {
+		return snd.MapError(requestErr, snd.WithResource(resources.Chat, chatID), snd.WithResource(resources.Message, messageID), snd.WithResource(resources.HostedContent, hostedContentID))
+	}
+	return attachments.WriteHostedContent(w, content)
+}
+
+func (o *ops) UploadFile(ctx context.Context, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	resp, requestErr := o.chatAPI.UploadFile(ctx, name, r, size)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	return adapter.MapGraphDriveItemAttachment(resp), nil
+}
+
+func (o *ops) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	if attachment.ContentType != models.AttachmentContentTypeReference || attachment.ContentURL == "" {
+		return snd.MapError(&snd.RequestError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("attachment %q is not a file", attachment.ID),
+		})
+	}
+	body, requestErr := o.chatAPI.OpenFile(ctx, attachment.ContentURL)
+	if requestErr != nil {
+		return snd.MapError(requestErr)
+	}
+	defer body.Close()
+	return attachments.CopyFile(w, body)
+}
+
 func (o *ops) ListChats(ctx context.Context, chatType *models.ChatType) ([]*models.Chat, error) {
 	var apiType *string
 	if chatType != nil {
@@ -215,7 +297,13 @@ func (o *ops) SearchChatMessages(ctx context.Context, chatID *string, opts *sear
 	if opts == nil {
 		return nil, errors.New("missing opts")
 	}
-	resp, requestErr, nextFrom := o.chatAPI.SearchChatMessages(ctx, chatID, opts, searchConfig)
+	if searchConfig != nil && searchConfig.Local {
+		return nil, search.ErrLocalSearchUnavailable
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	resp, requestErr := o.chatAPI.SearchChatMessages(ctx, chatID, opts, searchConfig)
 	if requestErr != nil {
 		if chatID == nil {
 			return nil, snd.MapError(requestErr)
@@ -223,16 +311,72 @@ func (o *ops) SearchChatMessages(ctx context.Context, chatID *string, opts *sear
 		return nil, snd.MapError(requestErr, snd.WithResource(resources.Chat, *chatID))
 	}
 	var results []*search.SearchResult
-	for _, msg := range resp {
+	for _, msg := range resp.Messages {
 		results = append(results, &search.SearchResult{
 			Message:   adapter.MapGraphMessage(msg.Message),
 			ChatID:    msg.ChatID,
 			TeamID:    msg.TeamID,
 			ChannelID: msg.ChannelID,
+			Summary:   msg.Hit.Summary,
+			Rank:      msg.Hit.Rank,
+			HitID:     msg.Hit.HitID,
 		})
 	}
 	return &search.SearchResults{
-		Messages: results,
-		NextFrom: nextFrom,
+		Messages:             results,
+		NextFrom:             resp.NextFrom,
+		Total:                resp.Total,
+		MoreResultsAvailable: resp.MoreResultsAvailable,
+	}, nil
+}
+
+func (o *ops) ListMessagesModifiedSince(ctx context.Context, chatID string, since time.Time) ([]*models.Message, error) {
+	resp, requestErr := o.chatAPI.ListMessagesModifiedSince(ctx, chatID, since)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Chat, chatID))
+	}
+	return util.MapSlices(resp, adapter.MapGraphMessage), nil
+}
+
+// SyncMessages lists messages modified after the time in token. Graph offers no delta query
+// for chat messages, so the token is the last modification time of the newest synced message.
+// Messages created after it are new, older ones were edited.
+func (o *ops) SyncMessages(ctx context.Context, chatID, token string) (*models.MessageChanges, string, error) {
+	since, err := time.Parse(time.RFC3339Nano, token)
+	reset := token == "" || err != nil
+	if reset {
+		since = time.Time{}
+	}
+
+	resp, requestErr := o.chatAPI.ListMessagesModifiedSince(ctx, chatID, since)
+	if requestErr != nil {
+		return nil, "", snd.MapError(requestErr, snd.WithResource(resources.Chat, chatID))
+	}
+
+	next := token
+	if reset {
+		next = ""
+	}
+	latest := since
+	for _, msg := range resp {
+		if modified := msg.GetLastModifiedDateTime(); modified != nil && modified.After(latest) {
+			latest = *modified
+			next = latest.UTC().Format(time.RFC3339Nano)
+		}
+	}
+
+	changes := adapter.MapGraphMessageChanges(resp, since)
+	changes.Reset = reset
+	return changes, next, nil
+}
+
+func (o *ops) GetCurrentUser(ctx context.Context) (*models.MessageFrom, error) {
+	resp, requestErr := o.userAPI.GetMe(ctx)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	return &models.MessageFrom{
+		UserID:      util.Deref(resp.GetId()),
+		DisplayName: util.Deref(resp.GetDisplayName()),
 	}, nil
 }
@@ -2,6 +2,7 @@ package chats
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/pzsp-teams/lib/models"
@@ -19,8 +20,16 @@ type chatOps interface {
 	UpdateGroupChatTopic(ctx context.Context, chatID, topic string) (*models.Chat, error)
 	ListMessages(ctx context.Context, chatID string, includeSystem bool) (*models.MessageCollection, error)
 	SendMessage(ctx context.Context, chatID string, body models.MessageBody) (*models.Message, error)
+	UpdateMessage(ctx context.Context, chatID, messageID string, body models.MessageBody) (*models.Message, error)
 	DeleteMessage(ctx context.Context, chatID, messageID string) error
+	UndoDeleteMessage(ctx context.Context, chatID, messageID string) error
+	SetReaction(ctx context.Context, chatID, messageID, reactionType string) error
+	UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) error
 	GetMessage(ctx context.Context, chatID, messageID string) (*models.Message, error)
+	ListHostedContents(ctx context.Context, chatID, messageID string) ([]*models.HostedContent, error)
+	DownloadHostedContent(ctx context.Context, chatID, messageID, hostedContentID string, w io.Writer) error
+	UploadFile(ctx context.Context, name string, r io.Reader, size int64) (*models.Attachment, error)
+	DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error
 	ListChats(ctx context.Context, chatType *models.ChatType) ([]*models.Chat, error)
 	ListAllMessages(ctx context.Context, startTime, endTime *time.Time, top *int32) ([]*models.Message, error)
 	ListPinnedMessages(ctx context.Context, chatID string) ([]*models.Message, error)
@@ -29,4 +38,7 @@ type chatOps interface {
 	GetMentions(ctx context.Context, chatID string, isGroup bool, rawMentions []string) ([]models.Mention, error)
 	ListMessagesNext(ctx context.Context, chatID, nextLink string, includeSystem bool) (*models.MessageCollection, error)
 	SearchChatMessages(ctx context.Context, chatID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error)
+	ListMessagesModifiedSince(ctx context.Context, chatID string, since time.Time) ([]*models.Message, error)
+	SyncMessages(ctx context.Context, chatID, token string) (*models.MessageChanges, string, error)
+	GetCurrentUser(ctx context.Context) (*models.MessageFrom, error)
 }
@@ -0,0 +1,268 @@
+package chats
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/testutil"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newOpsSUT(t *testing.T, setup func(chatAPI *testutil.MockChatAPI)) (chatOps, context.Context) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	chatAPI := testutil.NewMockChatAPI(ctrl)
+	if setup != nil {
+		setup(chatAPI)
+	}
+	return NewOps(chatAPI, testutil.NewMockUserAPI(ctrl)), context.Background()
+}
+
+func TestOps_SyncMessages(t *testing.T) {
+	since := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	newer := since.Add(time.Minute)
+	newest := since.Add(2 * time.Minute)
+
+	t.Run("continues from token time and advances to newest modification", func(t *testing.T) {
+		older := since.Add(-time.Hour)
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().
+				ListMessagesModifiedSince(gomock.Any(), "chat-1", since).
+				Return([]msmodels.ChatMessageable{
+					testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-2"), CreatedDateTime: &newest, LastModifiedDateTime: &newest}),
+					testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-1"), CreatedDateTime: &newer, LastModifiedDateTime: &newer}),
+					testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-0"), CreatedDateTime: &older, LastModifiedDateTime: &newer, LastEditedDateTime: &newer}),
+				}, nil).
+				Times(1)
+		})
+
+		got, next, err := op.SyncMessages(ctx, "chat-1", since.Format(time.RFC3339Nano))
+		require.NoError(t, err)
+		assert.Equal(t, newest.Format(time.RFC3339Nano), next)
+		assert.Len(t, got.New, 2)
+		require.Len(t, got.Edited, 1)
+		assert.Equal(t, "m-0", got.Edited[0].ID)
+		assert.False(t, got.Reset)
+	})
+
+	t.Run("keeps token when nothing changed", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().ListMessagesModifiedSince(gomock.Any(), "chat-1", since).Return(nil, nil).Times(1)
+		})
+
+		token := since.Format(time.RFC3339Nano)
+		_, next, err := op.SyncMessages(ctx, "chat-1", token)
+		require.NoError(t, err)
+		assert.Equal(t, token, next)
+	})
+
+	t.Run("starts over without a valid token", func(t *testing.T) {
+		for _, token := range []string{"", "not-a-time"} {
+			op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+				chatAPI.EXPECT().ListMessagesModifiedSince(gomock.Any(), "chat-1", time.Time{}).Return(nil, nil).Times(1)
+			})
+
+			got, next, err := op.SyncMessages(ctx, "chat-1", token)
+			require.NoError(t, err)
+			assert.True(t, got.Reset)
+			assert.Empty(t, next)
+		}
+	})
+}
+
+func TestOps_UpdateMessage(t *testing.T) {
+	edited := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("patches body and reads message back", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			gomock.InOrder(
+				chatAPI.EXPECT().
+					UpdateMessage(gomock.Any(), "chat-1", "m-1", "<p>done</p>", "html", gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _, _, _ string, ments []msmodels.ChatMessageMentionable, _ []msmodels.ChatMessageAttachmentable) *snd.RequestError {
+						assert.Empty(t, ments)
+						return nil
+					}).
+					Times(1),
+				chatAPI.EXPECT().
+					GetMessage(gomock.Any(), "chat-1", "m-1").
+					Return(testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("m-1"), LastEditedDateTime: &edited}), nil).
+					Times(1),
+			)
+		})
+
+		body := models.MessageBody{Content: "<p>done</p>", ContentType: models.MessageContentTypeHTML}
+		got, err := op.UpdateMessage(ctx, "chat-1", "m-1", body)
+		require.NoError(t, err)
+		require.NotNil(t, got.LastEditedDateTime)
+		assert.Equal(t, edited, *got.LastEditedDateTime)
+	})
+
+	t.Run("maps api error", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().
+				UpdateMessage(gomock.Any(), "chat-1", "m-1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(&snd.RequestError{Code: 404, Message: "gone"}).
+				Times(1)
+		})
+
+		got, err := op.UpdateMessage(ctx, "chat-1", "m-1", models.MessageBody{Content: "x"})
+		require.Nil(t, got)
+		code, ok := snd.StatusCode(err)
+		require.True(t, ok)
+		assert.Equal(t, 404, code)
+	})
+
+	t.Run("importance -> 400 without calling api", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, nil)
+
+		_, err := op.UpdateMessage(ctx, "chat-1", "m-1", models.MessageBody{Content: "x", Importance: models.MessageImportanceUrgent})
+		code, ok := snd.StatusCode(err)
+		require.True(t, ok)
+		assert.Equal(t, 400, code)
+	})
+}
+
+func TestOps_UndoDeleteMessage(t *testing.T) {
+	t.Run("calls undo endpoint", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().UndoDeleteMessage(gomock.Any(), "chat-1", "m-1").Return(nil).Times(1)
+		})
+
+		require.NoError(t, op.UndoDeleteMessage(ctx, "chat-1", "m-1"))
+	})
+
+	t.Run("maps api error", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().
+				UndoDeleteMessage(gomock.Any(), "chat-1", "m-1").
+				Return(&snd.RequestError{Code: 404, Message: "gone"}).
+				Times(1)
+		})
+
+		err := op.UndoDeleteMessage(ctx, "chat-1", "m-1")
+		var nf *snd.ErrResourceNotFound
+		require.ErrorAs(t, err, &nf)
+	})
+}
+
+func TestOps_DownloadHostedContent(t *testing.T) {
+	t.Run("writes content", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().GetHostedContent(gomock.Any(), "chat-1", "m-1", "hc-1").Return([]byte("img"), nil).Times(1)
+		})
+
+		var buf bytes.Buffer
+		require.NoError(t, op.DownloadHostedContent(ctx, "chat-1", "m-1", "hc-1", &buf))
+		assert.Equal(t, "img", buf.String())
+	})
+
+	t.Run("reports write errors", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().GetHostedContent(gomock.Any(), "chat-1", "m-1", "hc-1").Return([]byte("img"), nil).Times(1)
+		})
+
+		err := op.DownloadHostedContent(ctx, "chat-1", "m-1", "hc-1", failingWriter{})
+		require.ErrorIs(t, err, errWriteFailed)
+	})
+
+	t.Run("maps api error", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().
+				GetHostedContent(gomock.Any(), "chat-1", "m-1", "hc-1").
+				Return(nil, &snd.RequestError{Code: 404, Message: "gone"}).
+				Times(1)
+		})
+
+		err := op.DownloadHostedContent(ctx, "chat-1", "m-1", "hc-1", &bytes.Buffer{})
+		var nf *snd.ErrResourceNotFound
+		require.ErrorAs(t, err, &nf)
+	})
+}
+
+func TestOps_Files(t *testing.T) {
+	t.Run("uploads file and maps reference attachment", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			item := msmodels.NewDriveItem()
+			item.SetId(util.Ptr("item-1"))
+			item.SetName(util.Ptr("report.txt"))
+			item.SetWebUrl(util.Ptr("https://contoso-my/report.txt"))
+			chatAPI.EXPECT().UploadFile(gomock.Any(), "report.txt", gomock.Any(), int64(3)).Return(item, nil).Times(1)
+		})
+
+		got, err := op.UploadFile(ctx, "report.txt", strings.NewReader("abc"), 3)
+		require.NoError(t, err)
+		assert.Equal(t, "item-1", got.ID)
+		assert.Equal(t, models.AttachmentContentTypeReference, got.ContentType)
+	})
+
+	t.Run("downloads file", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().OpenFile(gomock.Any(), "https://contoso-my/report.txt").Return(io.NopCloser(strings.NewReader("abc")), nil).Times(1)
+		})
+
+		var buf bytes.Buffer
+		att := models.Attachment{ID: "att-1", ContentType: models.AttachmentContentTypeReference, ContentURL: "https://contoso-my/report.txt"}
+		require.NoError(t, op.DownloadFile(ctx, att, &buf))
+		assert.Equal(t, "abc", buf.String())
+	})
+
+	t.Run("maps api error", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().
+				OpenFile(gomock.Any(), "https://contoso-my/report.txt").
+				Return(nil, &snd.RequestError{Code: 403, Message: "denied"}).
+				Times(1)
+		})
+
+		att := models.Attachment{ID: "att-1", ContentType: models.AttachmentContentTypeReference, ContentURL: "https://contoso-my/report.txt"}
+		err := op.DownloadFile(ctx, att, &bytes.Buffer{})
+		code, ok := snd.StatusCode(err)
+		require.True(t, ok)
+		assert.Equal(t, 403, code)
+	})
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
+
+func TestOps_Reactions(t *testing.T) {
+	t.Run("calls reaction endpoints", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().SetReaction(gomock.Any(), "chat-1", "m-1", "👍").Return(nil).Times(1)
+			chatAPI.EXPECT().UnsetReaction(gomock.Any(), "chat-1", "m-1", "👍").Return(nil).Times(1)
+		})
+
+		require.NoError(t, op.SetReaction(ctx, "chat-1", "m-1", "👍"))
+		require.NoError(t, op.UnsetReaction(ctx, "chat-1", "m-1", "👍"))
+	})
+
+	t.Run("maps api error", func(t *testing.T) {
+		op, ctx := newOpsSUT(t, func(chatAPI *testutil.MockChatAPI) {
+			chatAPI.EXPECT().
+				UnsetReaction(gomock.Any(), "chat-1", "m-1", "👍").
+				Return(&snd.RequestError{Code: 404, Message: "gone"}).
+				Times(1)
+		})
+
+		err := op.UnsetReaction(ctx, "chat-1", "m-1", "👍")
+		var nf *snd.ErrResourceNotFound
+		require.ErrorAs(t, err, &nf)
+	})
+}
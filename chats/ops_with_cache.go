@@ -2,6 +2,7 @@ package chats
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/pzsp-teams/lib/internal/cacher"
@@ -126,6 +127,12 @@ func (o *opsWithCache) SendMessage(ctx context.Context, chatID string, body mode
 	}, o.cacheHandler)
 }
 
+func (o *opsWithCache) UpdateMessage(ctx context.Context, chatID, messageID string, body models.MessageBody) (*models.Message, error) {
+	return cacher.WithErrorClear(func() (*models.Message, error) {
+		return o.chatOps.UpdateMessage(ctx, chatID, messageID, body)
+	}, o.cacheHandler)
+}
+
 func (o *opsWithCache) DeleteMessage(ctx context.Context, chatID, messageID string) error {
 	err := o.chatOps.DeleteMessage(ctx, chatID, messageID)
 	if err != nil {
@@ -135,12 +142,69 @@ func (o *opsWithCache) DeleteMessage(ctx context.Context, chatID, messageID stri
 	return nil
 }
 
+func (o *opsWithCache) UndoDeleteMessage(ctx context.Context, chatID, messageID string) error {
+	err := o.chatOps.UndoDeleteMessage(ctx, chatID, messageID)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) SetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	err := o.chatOps.SetReaction(ctx, chatID, messageID, reactionType)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	err := o.chatOps.UnsetReaction(ctx, chatID, messageID, reactionType)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
 func (o *opsWithCache) GetMessage(ctx context.Context, chatID, messageID string) (*models.Message, error) {
 	return cacher.WithErrorClear(func() (*models.Message, error) {
 		return o.chatOps.GetMessage(ctx, chatID, messageID)
 	}, o.cacheHandler)
 }
 
+func (o *opsWithCache) ListHostedContents(ctx context.Context, chatID, messageID string) ([]*models.HostedContent, error) {
+	return cacher.WithErrorClear(func() ([]*models.HostedContent, error) {
+		return o.chatOps.ListHostedContents(ctx, chatID, messageID)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) DownloadHostedContent(ctx context.Context, chatID, messageID, hostedContentID string, w io.Writer) error {
+	err := o.chatOps.DownloadHostedContent(ctx, chatID, messageID, hostedContentID, w)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
+func (o *opsWithCache) UploadFile(ctx context.Context, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	return cacher.WithErrorClear(func() (*models.Attachment, error) {
+		return o.chatOps.UploadFile(ctx, name, r, size)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	err := o.chatOps.DownloadFile(ctx, attachment, w)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return err
+	}
+	return nil
+}
+
 func (o *opsWithCache) ListChats(ctx context.Context, chatType *models.ChatType) ([]*models.Chat, error) {
 	chats, err := o.chatOps.ListChats(ctx, chatType)
 	if err != nil {
@@ -203,7 +267,7 @@ func (o *opsWithCache) addChatsToCache(chats ...cacheChat) {
 			if item.userID == nil || util.AnyBlank(item.chat.ID, *item.userID) {
 				continue
 			}
-			key := cacher.NewOneOnOneChatKey(*item.userID, nil)
+			key := cacher.NewOneOnOneChatKey(*item.userID, o.cacheHandler.Pepper())
 			_ = o.cacheHandler.Cacher.Set(key, item.chat.ID)
 		}
 		if item.chat.Type == models.ChatTypeGroup {
@@ -224,7 +288,7 @@ func (o *opsWithCache) addMembersToCache(chatID string, members ...models.Member
 		if util.AnyBlank(member.Email) {
 			continue
 		}
-		key := cacher.NewGroupChatMemberKey(chatID, member.Email, nil)
+		key := cacher.NewGroupChatMemberKey(chatID, member.Email, o.cacheHandler.Pepper())
 		_ = o.cacheHandler.Cacher.Set(key, member.ID)
 	}
 }
@@ -233,7 +297,7 @@ func (o *opsWithCache) removeMemberFromCache(chatID, userRef string) {
 	if util.AnyBlank(chatID, userRef) {
 		return
 	}
-	key := cacher.NewGroupChatMemberKey(chatID, userRef, nil)
+	key := cacher.NewGroupChatMemberKey(chatID, userRef, o.cacheHandler.Pepper())
 	_ = o.cacheHandler.Cacher.Invalidate(key)
 }
 
@@ -242,3 +306,24 @@ func (o *opsWithCache) SearchChatMessages(ctx context.Context, chatID *string, o
 		return o.chatOps.SearchChatMessages(ctx, chatID, opts, searchConfig)
 	}, o.cacheHandler)
 }
+
+func (o *opsWithCache) ListMessagesModifiedSince(ctx context.Context, chatID string, since time.Time) ([]*models.Message, error) {
+	return cacher.WithErrorClear(func() ([]*models.Message, error) {
+		return o.chatOps.ListMessagesModifiedSince(ctx, chatID, since)
+	}, o.cacheHandler)
+}
+
+func (o *opsWithCache) SyncMessages(ctx context.Context, chatID, token string) (*models.MessageChanges, string, error) {
+	changes, next, err := o.chatOps.SyncMessages(ctx, chatID, token)
+	if err != nil {
+		o.cacheHandler.OnError(err)
+		return nil, "", err
+	}
+	return changes, next, nil
+}
+
+func (o *opsWithCache) GetCurrentUser(ctx context.Context) (*models.MessageFrom, error) {
+	return cacher.WithErrorClear(func() (*models.MessageFrom, error) {
+		return o.chatOps.GetCurrentUser(ctx)
+	}, o.cacheHandler)
+}
@@ -0,0 +1,159 @@
+package chats
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pzsp-teams/lib/internal/msgstore"
+	"github.com/pzsp-teams/lib/internal/resources"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/pzsp-teams/lib/search"
+)
+
+// opsWithMessageStore reads chat messages through the local message store.
+// Local searches run against the store as well. Messages changed through the ops are written
+// back to the store or dropped from it, to be fetched again. Other operations are passed to the wrapped ops.
+type opsWithMessageStore struct {
+	chatOps
+	store msgstore.Store
+}
+
+func NewOpsWithMessageStore(chatOps chatOps, store msgstore.Store) chatOps {
+	if store == nil {
+		return chatOps
+	}
+	return &opsWithMessageStore{
+		chatOps: chatOps,
+		store:   store,
+	}
+}
+
+// ListMessages serves the first page from the store once the chat was listed before,
+// fetching from Graph only messages modified since the last refresh.
+// Pages served from the store have the size of the first page read from Graph.
+// System messages are never stored, so listing them always goes to Graph.
+func (o *opsWithMessageStore) ListMessages(ctx context.Context, chatID string, includeSystem bool) (*models.MessageCollection, error) {
+	if includeSystem {
+		return o.chatOps.ListMessages(ctx, chatID, includeSystem)
+	}
+
+	key := msgstore.ChatKey(chatID)
+	conv, err := o.store.Load(key)
+	if err != nil || conv == nil || conv.SyncedAt == nil {
+		out, err := o.chatOps.ListMessages(ctx, chatID, includeSystem)
+		if err != nil {
+			return nil, err
+		}
+		_ = msgstore.RecordFirstPage(o.store, key, out)
+		return out, nil
+	}
+
+	changed, err := o.chatOps.ListMessagesModifiedSince(ctx, chatID, *conv.SyncedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.store.Sync(key, changed); err != nil {
+		return o.chatOps.ListMessages(ctx, chatID, includeSystem)
+	}
+	conv, err = o.store.Load(key)
+	if err != nil || conv == nil {
+		return o.chatOps.ListMessages(ctx, chatID, includeSystem)
+	}
+	return msgstore.Page(conv, nil), nil
+}
+
+// ListMessagesNext continues pages served from the store with their local cursor,
+// Graph links are followed and the fetched messages are stored.
+// Following the stored link to older messages moves it to the next Graph page.
+func (o *opsWithMessageStore) ListMessagesNext(ctx context.Context, chatID, nextLink string, includeSystem bool) (*models.MessageCollection, error) {
+	if msgstore.IsCursor(nextLink) {
+		conv, err := o.store.Load(msgstore.ChatKey(chatID))
+		if err != nil {
+			return nil, err
+		}
+		out, err := msgstore.PageAfter(conv, nextLink)
+		if err != nil {
+			return nil, snd.MapError(&snd.RequestError{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}, snd.WithResource(resources.Chat, chatID))
+		}
+		return out, nil
+	}
+	out, err := o.chatOps.ListMessagesNext(ctx, chatID, nextLink, includeSystem)
+	if err != nil {
+		return nil, err
+	}
+	if !includeSystem {
+		key := msgstore.ChatKey(chatID)
+		_ = o.store.Put(key, out.Messages...)
+		_ = msgstore.AdvanceNextLink(o.store, key, nextLink, out.NextLink)
+	}
+	return out, nil
+}
+
+func (o *opsWithMessageStore) GetMessage(ctx context.Context, chatID, messageID string) (*models.Message, error) {
+	key := msgstore.ChatKey(chatID)
+	if msg, found, err := o.store.Get(key, messageID); err == nil && found {
+		return msg, nil
+	}
+	msg, err := o.chatOps.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	_ = o.store.Put(key, msg)
+	return msg, nil
+}
+
+// UpdateMessage stores the updated message read back from Graph.
+func (o *opsWithMessageStore) UpdateMessage(ctx context.Context, chatID, messageID string, body models.MessageBody) (*models.Message, error) {
+	msg, err := o.chatOps.UpdateMessage(ctx, chatID, messageID, body)
+	if err != nil {
+		return nil, err
+	}
+	_ = o.store.Put(msgstore.ChatKey(chatID), msg)
+	return msg, nil
+}
+
+func (o *opsWithMessageStore) DeleteMessage(ctx context.Context, chatID, messageID string) error {
+	return o.dropAfter(chatID, messageID, o.chatOps.DeleteMessage(ctx, chatID, messageID))
+}
+
+func (o *opsWithMessageStore) UndoDeleteMessage(ctx context.Context, chatID, messageID string) error {
+	return o.dropAfter(chatID, messageID, o.chatOps.UndoDeleteMessage(ctx, chatID, messageID))
+}
+
+func (o *opsWithMessageStore) SetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	return o.dropAfter(chatID, messageID, o.chatOps.SetReaction(ctx, chatID, messageID, reactionType))
+}
+
+func (o *opsWithMessageStore) UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	return o.dropAfter(chatID, messageID, o.chatOps.UnsetReaction(ctx, chatID, messageID, reactionType))
+}
+
+// dropAfter drops the stored copy of a message once it was changed in Graph without reading it back.
+// The next read fetches it again, as its modification time is after the last refresh.
+func (o *opsWithMessageStore) dropAfter(chatID, messageID string, err error) error {
+	if err != nil {
+		return err
+	}
+	_ = o.store.Delete(msgstore.ChatKey(chatID), messageID)
+	return nil
+}
+
+// SearchChatMessages runs local searches against the store; other searches go to Graph.
+func (o *opsWithMessageStore) SearchChatMessages(ctx context.Context, chatID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error) {
+	if opts == nil || searchConfig == nil || !searchConfig.Local {
+		return o.chatOps.SearchChatMessages(ctx, chatID, opts, searchConfig)
+	}
+	var me *models.MessageFrom
+	if msgstore.NeedsCurrentUser(opts) {
+		var err error
+		if me, err = o.chatOps.GetCurrentUser(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return msgstore.Search(o.store, msgstore.ChatScope(chatID), opts, me, time.Now())
+}
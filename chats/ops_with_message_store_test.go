@@ -0,0 +1,107 @@
+package chats
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/msgstore"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/testutil"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newOpsWithMessageStoreSUT(t *testing.T) (chatOps, *testutil.MockchatOps, msgstore.Store, context.Context) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	store, err := msgstore.NewMessageStore(&config.CacheConfig{
+		Mode:         config.CacheSync,
+		MessageStore: &config.MessageStoreConfig{Path: util.Ptr(filepath.Join(t.TempDir(), "messages.json"))},
+	})
+	require.NoError(t, err)
+	ops := testutil.NewMockchatOps(ctrl)
+	return NewOpsWithMessageStore(ops, store), ops, store, context.Background()
+}
+
+func TestOpsWithMessageStore_ListMessages(t *testing.T) {
+	t.Parallel()
+
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	message := func(id string, created time.Time) *models.Message {
+		return &models.Message{ID: id, CreatedDateTime: created, LastModifiedDateTime: &created}
+	}
+	ids := func(c *models.MessageCollection) []string {
+		return util.MapSlices(c.Messages, func(m *models.Message) string { return m.ID })
+	}
+
+	t.Run("store pages have the size of the first Graph page", func(t *testing.T) {
+		t.Parallel()
+
+		sut, ops, _, ctx := newOpsWithMessageStoreSUT(t)
+		ops.EXPECT().ListMessages(ctx, "chat-1", false).Return(&models.MessageCollection{
+			Messages: []*models.Message{message("m3", t0.Add(2*time.Minute)), message("m2", t0.Add(time.Minute))},
+			NextLink: util.Ptr("older"),
+		}, nil)
+		_, err := sut.ListMessages(ctx, "chat-1", false)
+		require.NoError(t, err)
+
+		ops.EXPECT().ListMessagesNext(ctx, "chat-1", "older", false).Return(&models.MessageCollection{
+			Messages: []*models.Message{message("m1", t0)},
+		}, nil)
+		_, err = sut.ListMessagesNext(ctx, "chat-1", "older", false)
+		require.NoError(t, err)
+
+		ops.EXPECT().ListMessagesModifiedSince(ctx, "chat-1", t0.Add(2*time.Minute)).
+			Return([]*models.Message{message("m4", t0.Add(3*time.Minute))}, nil)
+		out, err := sut.ListMessages(ctx, "chat-1", false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m4", "m3"}, ids(out))
+		require.True(t, msgstore.IsCursor(*out.NextLink))
+
+		out, err = sut.ListMessagesNext(ctx, "chat-1", *out.NextLink, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{"m2", "m1"}, ids(out))
+		require.Nil(t, out.NextLink)
+	})
+
+	t.Run("cursor of a cleared store -> 400", func(t *testing.T) {
+		t.Parallel()
+
+		sut, _, store, ctx := newOpsWithMessageStoreSUT(t)
+		key := msgstore.ChatKey("chat-1")
+		require.NoError(t, store.Sync(key, []*models.Message{message("m1", t0), message("m2", t0.Add(time.Minute))}))
+		conv, err := store.Load(key)
+		require.NoError(t, err)
+		page := msgstore.Page(conv, util.Ptr(int32(1)))
+		require.NoError(t, store.Clear())
+
+		_, err = sut.ListMessagesNext(ctx, "chat-1", *page.NextLink, false)
+		code, ok := snd.StatusCode(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("single Graph page keeps the default page size", func(t *testing.T) {
+		t.Parallel()
+
+		sut, ops, store, ctx := newOpsWithMessageStoreSUT(t)
+		ops.EXPECT().ListMessages(ctx, "chat-1", false).Return(&models.MessageCollection{
+			Messages: []*models.Message{message("m1", t0)},
+		}, nil)
+		_, err := sut.ListMessages(ctx, "chat-1", false)
+		require.NoError(t, err)
+
+		conv, err := store.Load(msgstore.ChatKey("chat-1"))
+		require.NoError(t, err)
+		require.Zero(t, conv.PageSize)
+	})
+}
@@ -3,11 +3,17 @@ package chats
 import (
 	"context"
 	"fmt"
+	"io"
+	"path/filepath"
 	"time"
 
+	"github.com/pzsp-teams/lib/delta"
+	"github.com/pzsp-teams/lib/internal/attachments"
+	"github.com/pzsp-teams/lib/internal/mentions"
 	"github.com/pzsp-teams/lib/internal/resolver"
 	"github.com/pzsp-teams/lib/internal/resources"
 	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/markdown"
 	"github.com/pzsp-teams/lib/models"
 	"github.com/pzsp-teams/lib/search"
 )
@@ -183,6 +189,25 @@ func (s *service) ListMessages(ctx context.Context, chatRef ChatRef, includeSyst
 	return resp, nil
 }
 
+func (s *service) SyncMessages(ctx context.Context, chatRef ChatRef, state delta.Store) (*models.MessageChanges, error) {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return nil, snd.Wrap("SyncMessages", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	out, err := delta.Sync(state, delta.ChatKey(chatID), func(token string) (*models.MessageChanges, string, error) {
+		return s.chatOps.SyncMessages(ctx, chatID, token)
+	})
+	if err != nil {
+		return nil, snd.Wrap("SyncMessages", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+	return out, nil
+}
+
 func (s *service) SendMessage(ctx context.Context, chatRef ChatRef, body models.MessageBody) (*models.Message, error) {
 	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
 	if err != nil {
@@ -207,6 +232,30 @@ func (s *service) SendMessage(ctx context.Context, chatRef ChatRef, body models.
 	return resp, nil
 }
 
+func (s *service) UpdateMessage(ctx context.Context, chatRef ChatRef, messageID string, body models.MessageBody) (*models.Message, error) {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return nil, snd.Wrap("UpdateMessage", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	if err := validateChatMentions(chatRef, body.Mentions); err != nil {
+		return nil, snd.Wrap("UpdateMessage", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	resp, err := s.chatOps.UpdateMessage(ctx, chatID, messageID, body)
+	if err != nil {
+		return nil, snd.Wrap("UpdateMessage", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	return resp, nil
+}
+
 func (s *service) DeleteMessage(ctx context.Context, chatRef ChatRef, messageID string) error {
 	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
 	if err != nil {
@@ -225,6 +274,60 @@ func (s *service) DeleteMessage(ctx context.Context, chatRef ChatRef, messageID
 	return nil
 }
 
+func (s *service) UndoDeleteMessage(ctx context.Context, chatRef ChatRef, messageID string) error {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return snd.Wrap("UndoDeleteMessage", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	err = s.chatOps.UndoDeleteMessage(ctx, chatID, messageID)
+	if err != nil {
+		return snd.Wrap("UndoDeleteMessage", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	return nil
+}
+
+func (s *service) SetReaction(ctx context.Context, chatRef ChatRef, messageID, reactionType string) error {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return snd.Wrap("SetReaction", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	err = s.chatOps.SetReaction(ctx, chatID, messageID, reactionType)
+	if err != nil {
+		return snd.Wrap("SetReaction", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	return nil
+}
+
+func (s *service) UnsetReaction(ctx context.Context, chatRef ChatRef, messageID, reactionType string) error {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return snd.Wrap("UnsetReaction", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	err = s.chatOps.UnsetReaction(ctx, chatID, messageID, reactionType)
+	if err != nil {
+		return snd.Wrap("UnsetReaction", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	return nil
+}
+
 func (s *service) GetMessage(ctx context.Context, chatRef ChatRef, messageID string) (*models.Message, error) {
 	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
 	if err != nil {
@@ -243,6 +346,88 @@ func (s *service) GetMessage(ctx context.Context, chatRef ChatRef, messageID str
 	return resp, nil
 }
 
+func (s *service) ListHostedContents(ctx context.Context, chatRef ChatRef, messageID string) ([]*models.HostedContent, error) {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return nil, snd.Wrap("ListHostedContents", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	resp, err := s.chatOps.ListHostedContents(ctx, chatID, messageID)
+	if err != nil {
+		return nil, snd.Wrap("ListHostedContents", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	return resp, nil
+}
+
+func (s *service) DownloadHostedContent(ctx context.Context, chatRef ChatRef, messageID, hostedContentID string, w io.Writer) error {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return snd.Wrap("DownloadHostedContent", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	err = s.chatOps.DownloadHostedContent(ctx, chatID, messageID, hostedContentID, w)
+	if err != nil {
+		return snd.Wrap("DownloadHostedContent", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	return nil
+}
+
+func (s *service) UploadFile(ctx context.Context, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	out, err := s.chatOps.UploadFile(ctx, name, r, size)
+	if err != nil {
+		return nil, snd.Wrap("UploadFile", err)
+	}
+
+	return out, nil
+}
+
+func (s *service) UploadLocalFile(ctx context.Context, path string) (*models.Attachment, error) {
+	f, size, err := attachments.OpenLocalFile(path)
+	if err != nil {
+		return nil, snd.Wrap("UploadLocalFile", err)
+	}
+	defer f.Close()
+
+	out, err := s.chatOps.UploadFile(ctx, filepath.Base(path), f, size)
+	if err != nil {
+		return nil, snd.Wrap("UploadLocalFile", err)
+	}
+
+	return out, nil
+}
+
+func (s *service) ListAttachments(ctx context.Context, chatRef ChatRef, messageID string) ([]models.Attachment, error) {
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return nil, snd.Wrap("ListAttachments", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	msg, err := s.chatOps.GetMessage(ctx, chatID, messageID)
+	if err != nil {
+		return nil, snd.Wrap("ListAttachments", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	return msg.Attachments, nil
+}
+
+func (s *service) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	return snd.Wrap("DownloadFile", s.chatOps.DownloadFile(ctx, attachment, w))
+}
+
 func (s *service) ListChats(ctx context.Context, chatType *models.ChatType) ([]*models.Chat, error) {
 	resp, err := s.chatOps.ListChats(ctx, chatType)
 	if err != nil {
@@ -340,6 +525,47 @@ func (s *service) GetMentions(ctx context.Context, chatRef ChatRef, rawMentions
 	return resp, nil
 }
 
+func (s *service) NewMarkdownMessageBody(ctx context.Context, chatRef ChatRef, src string) (*models.MessageBody, error) {
+	tokens := markdown.MentionTokens(src, markdown.MentionEveryone)
+	if len(tokens) == 0 {
+		body := markdown.NewMessageBody(src, nil)
+		return &body, nil
+	}
+
+	chatID, err := s.resolveChatIDFromRef(ctx, chatRef)
+	if err != nil {
+		return nil, snd.Wrap("NewMarkdownMessageBody", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	isGroup, err := isGroupChatRef(chatRef)
+	if err != nil {
+		return nil, snd.Wrap("NewMarkdownMessageBody", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+		)
+	}
+
+	ments, err := s.chatOps.GetMentions(ctx, chatID, isGroup, tokens)
+	if err != nil {
+		return nil, snd.Wrap("NewMarkdownMessageBody", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+			snd.NewParam(resources.MentionRef, tokens...),
+		)
+	}
+
+	byToken, err := mentions.ByToken(tokens, ments)
+	if err != nil {
+		return nil, snd.Wrap("NewMarkdownMessageBody", err,
+			snd.NewParam(resources.ChatRef, chatRef.get()),
+			snd.NewParam(resources.MentionRef, tokens...),
+		)
+	}
+
+	body := markdown.NewMessageBody(src, byToken)
+	return &body, nil
+}
+
 func (s *service) SearchMessages(ctx context.Context, chatRef ChatRef, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error) {
 	var chatID *string
 	var err error
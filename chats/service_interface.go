@@ -14,8 +14,10 @@ package chats
 
 import (
 	"context"
+	"io"
 	"time"
 
+	"github.com/pzsp-teams/lib/delta"
 	"github.com/pzsp-teams/lib/models"
 	"github.com/pzsp-teams/lib/search"
 )
@@ -51,19 +53,71 @@ type Service interface {
 	// NextLink in the returned MessageCollection can be used to retrieve the next page of messages.
 	ListMessages(ctx context.Context, chatRef ChatRef, includeSystem bool, nextLink *string) (*models.MessageCollection, error)
 
+	// SyncMessages returns messages created, edited or deleted in a chat since its previous sync.
+	//
+	// The sync state is kept in state (see package delta); a nil state starts over on every call.
+	// The first sync, and a sync whose state expired, report all messages of the chat as new and set Reset.
+	//
+	// Unlike channels, chat messages have no delta query in Graph. Each sync lists the chat messages
+	// modified after the newest change seen by the previous sync, so the state is a timestamp and never expires.
+	SyncMessages(ctx context.Context, chatRef ChatRef, state delta.Store) (*models.MessageChanges, error)
+
 	// SendMessage sends a message to a chat.
 	// Body parameter is the body of the message. It includes:
 	//   - Content: the text or html content of the message.
 	//   - ContentType: the type of content (text or html).
 	//   - Mentions: optional mentions to include in the message.
+	//   - Importance: optional importance (normal, high or urgent). Chat messages cannot have a Subject.
+	//   - Attachments: optional attachments, e.g. Adaptive Cards built with package adaptivecards.
+	//   - HostedContents: optional inline images, referenced as <img src="../hostedContents/{ID}/$value">.
 	SendMessage(ctx context.Context, chatRef ChatRef, body models.MessageBody) (*models.Message, error)
 
+	// UpdateMessage replaces the body of a message in a chat, e.g. to report progress in place.
+	// Body replaces the content and mentions of the message; mentions are validated as in SendMessage.
+	// Subject, Importance and HostedContents cannot be updated; a body setting them is rejected with 400.
+	// The returned message is read back after the update and carries LastEditedDateTime.
+	UpdateMessage(ctx context.Context, chatRef ChatRef, messageID string, body models.MessageBody) (*models.Message, error)
+
 	// DeleteMessage deletes a message from a chat. Action is reversible - soft delete is performed.
 	DeleteMessage(ctx context.Context, chatRef ChatRef, messageID string) error
 
+	// UndoDeleteMessage restores a message soft-deleted with DeleteMessage.
+	UndoDeleteMessage(ctx context.Context, chatRef ChatRef, messageID string) error
+
+	// SetReaction adds a reaction of the current user to a message in a chat.
+	// ReactionType is the reaction emoji, e.g. "👍".
+	SetReaction(ctx context.Context, chatRef ChatRef, messageID, reactionType string) error
+
+	// UnsetReaction removes a reaction of the current user from a message in a chat.
+	UnsetReaction(ctx context.Context, chatRef ChatRef, messageID, reactionType string) error
+
 	// GetMessage retrieves a specific message from a chat by its ID.
 	GetMessage(ctx context.Context, chatRef ChatRef, messageID string) (*models.Message, error)
 
+	// ListHostedContents returns the hosted contents of a message in a chat, such as inline images.
+	// Content of the returned hosted contents is not set; use DownloadHostedContent to retrieve it.
+	ListHostedContents(ctx context.Context, chatRef ChatRef, messageID string) ([]*models.HostedContent, error)
+
+	// DownloadHostedContent writes the content of a hosted content of a message in a chat to w.
+	DownloadHostedContent(ctx context.Context, chatRef ChatRef, messageID, hostedContentID string, w io.Writer) error
+
+	// UploadFile uploads size bytes read from r as a file named name to the "Microsoft Teams Chat Files" folder
+	// in the OneDrive of the signed-in user, replacing an existing file with the same name.
+	// Files larger than 4 MiB are uploaded in chunks through an upload session.
+	//
+	// The returned reference attachment can be sent with a message in MessageBody.Attachments.
+	// The file is not shared with the chat members; they need access to it to open the attachment.
+	UploadFile(ctx context.Context, name string, r io.Reader, size int64) (*models.Attachment, error)
+
+	// UploadLocalFile uploads the file at path to the OneDrive of the signed-in user, like UploadFile.
+	UploadLocalFile(ctx context.Context, path string) (*models.Attachment, error)
+
+	// ListAttachments returns the attachments of a message in a chat, such as files and cards.
+	ListAttachments(ctx context.Context, chatRef ChatRef, messageID string) ([]models.Attachment, error)
+
+	// DownloadFile writes the content of a file attachment (ContentType "reference") of a received message to w.
+	DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error
+
 	// ListChats returns all chats, optionally filtered by chat type.
 	ListChats(ctx context.Context, chatType *models.ChatType) ([]*models.Chat, error)
 
@@ -87,6 +141,11 @@ type Service interface {
 	//   - User IDs
 	GetMentions(ctx context.Context, chatRef ChatRef, rawMentions []string) ([]models.Mention, error)
 
+	// NewMarkdownMessageBody renders Markdown as an HTML message body for a chat, see package markdown.
+	// Mentions written as @user@example.com and @everyone (group chats only) are resolved as in GetMentions
+	// and their <at> tags are numbered automatically.
+	NewMarkdownMessageBody(ctx context.Context, chatRef ChatRef, src string) (*models.MessageBody, error)
+
 	// SearchMessages searches for messages in a chat matching the specified query and options.
 	//
 	// If chatRef is nil, searches across all chats the user has access to.
@@ -6,6 +6,8 @@
 //   - Authentication (via MSAL and Graph Token Providers).
 //   - Dependency Injection (wiring APIs, Caches, and Resolvers).
 //   - Caching strategies (transparently wrapping operations with caching layers).
+//   - Optional local message store (reading messages through a local copy refreshed incrementally).
+//   - Change-notification subscriptions renewed in the background.
 //
 // Usage:
 // Initialize the Client using NewClient for a standard setup.
@@ -13,6 +15,8 @@
 //   - NewTeamServiceFromGraphClient for Teams service.
 //   - NewChannelServiceFromGraphClient for Channels service.
 //   - NewChatServiceFromGraphClient for Chats service.
+//   - NewEntityServiceFromGraphClient for searches of files, people and events.
+//   - NewSubscriptionServiceFromGraphClient for change-notification subscriptions.
 //
 // Always ensure to call Close() upon application shutdown to flush any background
 // cache operations.
@@ -29,17 +33,25 @@ import (
 	"github.com/pzsp-teams/lib/internal/api"
 	"github.com/pzsp-teams/lib/internal/auth"
 	"github.com/pzsp-teams/lib/internal/cacher"
+	"github.com/pzsp-teams/lib/internal/msgstore"
 	"github.com/pzsp-teams/lib/internal/resolver"
+	"github.com/pzsp-teams/lib/search/entities"
+	"github.com/pzsp-teams/lib/search/saved"
+	"github.com/pzsp-teams/lib/subscriptions"
 	"github.com/pzsp-teams/lib/teams"
 )
 
 // Client is the central hub for interacting with the Microsoft Teams ecosystem.
-// It aggregates access to specific domains: Channels, Teams, and Chats, hiding
-// the complexity of underlying Graph API calls and caching mechanisms.
+// It aggregates access to specific domains: Channels, Teams, Chats, searches of
+// files, people and events (Entities), saved message searches (SavedSearches) and
+// change-notification subscriptions (Subscriptions), hiding the complexity of underlying Graph API calls and caching mechanisms.
 type Client struct {
-	Channels channels.Service
-	Teams    teams.Service
-	Chats    chats.Service
+	Channels      channels.Service
+	Teams         teams.Service
+	Chats         chats.Service
+	Entities      entities.Service
+	SavedSearches saved.Manager
+	Subscriptions subscriptions.Service
 }
 
 // graphClient is a package-level singleton to hold the authenticated Graph client.
@@ -86,8 +98,12 @@ func NewClientFromGraphClient(graphClient *graph.GraphServiceClient, senderCfg *
 	channelAPI := api.GetChannelAPI(graphClient, senderCfg, searchAPI)
 	chatAPI := api.GetChatAPI(graphClient, senderCfg, searchAPI)
 	userAPI := api.GetUserAPI(graphClient, senderCfg)
+	entityAPI := api.GetEntityAPI(graphClient, senderCfg, searchAPI)
 
-	cacheHandler := cacher.GetCacheHandler(cacheCfg)
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// TODO: make resolvers with cache decorators, the same as services
 	teamResolver := resolver.GetTeamResolver(teamsAPI, cacheHandler)
@@ -103,14 +119,33 @@ func NewClientFromGraphClient(graphClient *graph.GraphServiceClient, senderCfg *
 		teamOps = teams.NewOpsWithCache(teamOps, cacheHandler)
 		chatOps = chats.NewOpsWithCache(chatOps, cacheHandler)
 	}
+	messageStore, err := msgstore.GetMessageStore(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	channelOps = channels.NewOpsWithMessageStore(channelOps, messageStore)
+	chatOps = chats.NewOpsWithMessageStore(chatOps, messageStore)
 	channelSvc := channels.NewService(channelOps, teamResolver, channelResolver)
 	teamSvc := teams.NewService(teamOps, teamResolver)
 	chatSvc := chats.NewService(chatOps, chatResolver)
+	entitySvc := entities.NewService(entities.NewOps(entityAPI))
+	savedSearches, err := saved.NewManager(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	subscriptionSvc := subscriptions.NewService(
+		subscriptions.NewOps(api.GetSubscriptionAPI(graphClient, senderCfg)),
+		teamResolver, channelResolver, chatResolver,
+		newSubscriptionStore(cacheCfg, nil), nil,
+	)
 
 	return &Client{
-		Channels: channelSvc,
-		Teams:    teamSvc,
-		Chats:    chatSvc,
+		Channels:      channelSvc,
+		Teams:         teamSvc,
+		Chats:         chatSvc,
+		Entities:      entitySvc,
+		SavedSearches: savedSearches,
+		Subscriptions: subscriptionSvc,
 	}, nil
 }
 
@@ -126,7 +161,10 @@ func NewChannelServiceFromGraphClient(ctx context.Context, authCfg *config.AuthC
 	userAPI := api.GetUserAPI(cl, senderCfg)
 	teamAPI := api.GetTeamAPI(cl, senderCfg)
 
-	cacheHandler := cacher.GetCacheHandler(cacheCfg)
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
 	teamResolver := resolver.GetTeamResolver(teamAPI, cacheHandler)
 	channelResolver := resolver.GetChannelResolver(channelAPI, cacheHandler)
 
@@ -134,6 +172,11 @@ func NewChannelServiceFromGraphClient(ctx context.Context, authCfg *config.AuthC
 	if cacheHandler != nil {
 		channelOps = channels.NewOpsWithCache(channelOps, cacheHandler)
 	}
+	messageStore, err := msgstore.GetMessageStore(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	channelOps = channels.NewOpsWithMessageStore(channelOps, messageStore)
 	channelSvc := channels.NewService(channelOps, teamResolver, channelResolver)
 
 	return channelSvc, nil
@@ -148,7 +191,10 @@ func NewTeamServiceFromGraphClient(ctx context.Context, authCfg *config.AuthConf
 	}
 	teamAPI := api.GetTeamAPI(cl, senderCfg)
 
-	cacheHandler := cacher.GetCacheHandler(cacheCfg)
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
 	teamResolver := resolver.GetTeamResolver(teamAPI, cacheHandler)
 
 	teamOps := teams.NewOps(teamAPI)
@@ -171,18 +217,85 @@ func NewChatServiceFromGraphClient(ctx context.Context, authCfg *config.AuthConf
 	chatAPI := api.GetChatAPI(cl, senderCfg, searchAPI)
 	userAPI := api.GetUserAPI(cl, senderCfg)
 
-	cacheHandler := cacher.GetCacheHandler(cacheCfg)
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
 	chatResolver := resolver.GetChatResolver(chatAPI, cacheHandler)
 
 	chatOps := chats.NewOps(chatAPI, userAPI)
 	if cacheHandler != nil {
 		chatOps = chats.NewOpsWithCache(chatOps, cacheHandler)
 	}
+	messageStore, err := msgstore.GetMessageStore(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	chatOps = chats.NewOpsWithMessageStore(chatOps, messageStore)
 	chatSvc := chats.NewService(chatOps, chatResolver)
 
 	return chatSvc, nil
 }
 
+// NewEntityServiceFromGraphClient creates a standalone service for searching files, people and events.
+// Use this if you do not need the full Client wrapper and only want to search entities other than messages.
+func NewEntityServiceFromGraphClient(ctx context.Context, authCfg *config.AuthConfig, senderCfg *config.SenderConfig) (entities.Service, error) {
+	cl, err := getGraphClient(authCfg)
+	if err != nil {
+		return nil, err
+	}
+	searchAPI := api.GetSearchAPI(cl, senderCfg)
+	entityAPI := api.GetEntityAPI(cl, senderCfg, searchAPI)
+
+	return entities.NewService(entities.NewOps(entityAPI)), nil
+}
+
+// NewSubscriptionServiceFromGraphClient creates a standalone service for change-notification subscriptions.
+// Use this if you do not need the full Client wrapper, or to configure subscriptions with subCfg.
+func NewSubscriptionServiceFromGraphClient(
+	ctx context.Context,
+	authCfg *config.AuthConfig,
+	senderCfg *config.SenderConfig,
+	cacheCfg *config.CacheConfig,
+	subCfg *config.SubscriptionConfig,
+) (subscriptions.Service, error) {
+	cl, err := getGraphClient(authCfg)
+	if err != nil {
+		return nil, err
+	}
+	searchAPI := api.GetSearchAPI(cl, senderCfg)
+	teamAPI := api.GetTeamAPI(cl, senderCfg)
+	channelAPI := api.GetChannelAPI(cl, senderCfg, searchAPI)
+	chatAPI := api.GetChatAPI(cl, senderCfg, searchAPI)
+
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	teamResolver := resolver.GetTeamResolver(teamAPI, cacheHandler)
+	channelResolver := resolver.GetChannelResolver(channelAPI, cacheHandler)
+	chatResolver := resolver.GetChatResolver(chatAPI, cacheHandler)
+
+	subscriptionOps := subscriptions.NewOps(api.GetSubscriptionAPI(cl, senderCfg))
+	return subscriptions.NewService(
+		subscriptionOps, teamResolver, channelResolver, chatResolver,
+		newSubscriptionStore(cacheCfg, subCfg), subCfg,
+	), nil
+}
+
+// newSubscriptionStore returns the file store of subscriptions configured in subCfg,
+// falling back to the default location derived from cacheCfg.
+func newSubscriptionStore(cacheCfg *config.CacheConfig, subCfg *config.SubscriptionConfig) subscriptions.Store {
+	if subCfg != nil && subCfg.StatePath != nil {
+		return subscriptions.NewFileStore(*subCfg.StatePath)
+	}
+	var cachePath *string
+	if cacheCfg != nil {
+		cachePath = cacheCfg.Path
+	}
+	return subscriptions.NewFileStore(subscriptions.DefaultStatePath(cachePath))
+}
+
 // Close ensures a graceful shutdown of the library.
 // It waits for any pending background operations (such as asynchronous cache updates)
 // to complete before returning, preventing data loss or race conditions.
@@ -22,9 +22,80 @@ const (
 	CacheProviderJSONFile CacheProvider = "JSON_FILE"
 )
 
+// CacheProtection defines how cache keys and values are protected at rest.
+//
+// Protection uses the pepper configured in PepperConfig, creating a protected cache fails when it is missing.
+type CacheProtection string
+
+const (
+	// CacheProtectionNone stores team names, channel names and chat topics in plain text.
+	// Only user references are hashed.
+	CacheProtectionNone CacheProtection = "NONE"
+
+	// CacheProtectionHash hashes every cache key with the pepper.
+	// Values are resolved Graph IDs and are kept in plain text.
+	CacheProtectionHash CacheProtection = "HASH"
+
+	// CacheProtectionEncrypt encrypts every cache key and value with a key derived from the pepper.
+	CacheProtectionEncrypt CacheProtection = "ENCRYPT"
+)
+
+// PepperSource defines where the pepper is read from.
+type PepperSource string
+
+const (
+	// PepperSourceKeyring reads the pepper from the system keyring.
+	PepperSourceKeyring PepperSource = "KEYRING"
+
+	// PepperSourceEnv reads the pepper from an environment variable.
+	PepperSourceEnv PepperSource = "ENV"
+
+	// PepperSourceFile reads the pepper from a file.
+	PepperSourceFile PepperSource = "FILE"
+
+	// PepperSourceCallback obtains the pepper from a user-provided function.
+	PepperSourceCallback PepperSource = "CALLBACK"
+)
+
+// PepperConfig holds configuration of the pepper used to hash and encrypt cache entries.
+type PepperConfig struct {
+	Source PepperSource
+
+	// EnvVar is the variable read with PepperSourceEnv. Defaults to PZSP_TEAMS_PEPPER.
+	EnvVar string
+
+	// FilePath is the file read with PepperSourceFile.
+	FilePath string
+
+	// Callback is called with PepperSourceCallback.
+	Callback func() (string, error)
+
+	// Strict fails creation of the cache when no pepper is available,
+	// instead of hashing user references with a built-in pepper.
+	// Protected caches (CacheProtectionHash, CacheProtectionEncrypt) always require a pepper.
+	Strict bool
+}
+
+// MessageStoreConfig holds configuration of the local message store,
+// which keeps bodies of listed and fetched messages for offline reading.
+//
+// Messages are stored in plain text, so the store cannot be enabled together with
+// CacheProtectionHash or CacheProtectionEncrypt; creating a client fails in that case.
+type MessageStoreConfig struct {
+	// Path of the store file. Defaults to messages.json next to the cache file.
+	Path *string
+}
+
 // CacheConfig holds configuration for caching.
+//
+// Empty Protection is equivalent to CacheProtectionNone.
+// Nil Pepper reads the pepper from the system keyring without strict mode.
+// Nil MessageStore disables the local message store.
 type CacheConfig struct {
-	Mode     CacheMode
-	Provider CacheProvider
-	Path     *string
+	Mode         CacheMode
+	Provider     CacheProvider
+	Path         *string
+	Protection   CacheProtection
+	Pepper       *PepperConfig
+	MessageStore *MessageStoreConfig
 }
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// SubscriptionConfig holds configuration of change-notification subscriptions.
+//
+// Zero values use the defaults described on the fields.
+type SubscriptionConfig struct {
+	// StatePath is the file keeping subscriptions created by the library across restarts.
+	// Defaults to subscriptions.json next to the cache file, or in the user cache directory.
+	StatePath *string
+
+	// Lifetime is the time until expiry requested when creating or renewing a subscription.
+	// Defaults to 55 minutes; Graph allows at most 60 minutes for chat and channel messages.
+	Lifetime time.Duration
+
+	// RenewBefore is how long before expiry a subscription is renewed in the background.
+	// Defaults to 10 minutes.
+	RenewBefore time.Duration
+}
@@ -0,0 +1,122 @@
+// Package delta provides storage of sync state for incremental message syncs
+// (channels.Service.SyncMessages and chats.Service.SyncMessages).
+//
+// Concepts:
+//   - A sync returns messages created, edited or deleted since the previous sync of the same conversation.
+//   - The position of the previous sync is kept as an opaque token in a Store, under a key
+//     identifying the conversation (see ChannelKey and ChatKey).
+//   - Without a stored token (first sync, or after the token expired) the sync starts over
+//     and reports all messages of the conversation as new.
+//   - Sync loads the token, runs a single sync and saves the token of the next one.
+//   - Use NewFileStore to keep tokens across restarts, NewMemoryStore for the lifetime of the process,
+//     or implement Store to keep them elsewhere (e.g. next to the synced messages in a database).
+package delta
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store keeps sync tokens by conversation key.
+type Store interface {
+	// Load returns the token stored under key, or "" when there is none.
+	Load(key string) (string, error)
+	// Save stores token under key.
+	Save(key, token string) error
+}
+
+// ChannelKey returns the key of sync state of a channel.
+func ChannelKey(teamID, channelID string) string {
+	return "channel/" + teamID + "/" + channelID
+}
+
+// ChatKey returns the key of sync state of a chat.
+func ChatKey(chatID string) string {
+	return "chat/" + chatID
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// NewMemoryStore creates a Store keeping tokens in memory only.
+func NewMemoryStore() Store {
+	return &memoryStore{tokens: make(map[string]string)}
+}
+
+func (s *memoryStore) Load(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[key], nil
+}
+
+func (s *memoryStore) Save(key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[key] = token
+	return nil
+}
+
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a Store keeping tokens in a JSON file, readable by its owner only.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) Load(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return tokens[key], nil
+}
+
+func (s *fileStore) Save(key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *fileStore) read() (map[string]string, error) {
+	tokens := make(map[string]string)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
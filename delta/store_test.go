@@ -0,0 +1,48 @@
+package delta
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "delta.json")
+	tests := []struct {
+		name   string
+		store  Store
+		reopen func() Store
+	}{
+		{name: "memory", store: NewMemoryStore()},
+		{name: "file", store: NewFileStore(path), reopen: func() Store { return NewFileStore(path) }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.store.Load(ChatKey("c1"))
+			require.NoError(t, err)
+			assert.Empty(t, got)
+
+			require.NoError(t, tc.store.Save(ChatKey("c1"), "token-1"))
+			require.NoError(t, tc.store.Save(ChannelKey("t1", "c1"), "token-2"))
+			require.NoError(t, tc.store.Save(ChatKey("c1"), "token-3"))
+
+			store := tc.store
+			if tc.reopen != nil {
+				store = tc.reopen()
+			}
+			got, err = store.Load(ChatKey("c1"))
+			require.NoError(t, err)
+			assert.Equal(t, "token-3", got)
+			got, err = store.Load(ChannelKey("t1", "c1"))
+			require.NoError(t, err)
+			assert.Equal(t, "token-2", got)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package delta
+
+import (
+	"fmt"
+	"net/http"
+
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/models"
+)
+
+// SyncFunc runs a single sync from token ("" starts over) and returns the changes with the token of the next sync.
+type SyncFunc func(token string) (*models.MessageChanges, string, error)
+
+// Sync runs sync from the token stored under key and stores the token of the next sync.
+// When the stored token has expired (410 Gone), the sync starts over. A nil state starts over on every call.
+func Sync(state Store, key string, sync SyncFunc) (*models.MessageChanges, error) {
+	if state == nil {
+		state = NewMemoryStore()
+	}
+	token, err := state.Load(key)
+	if err != nil {
+		return nil, fmt.Errorf("loading sync state: %w", err)
+	}
+
+	changes, next, err := sync(token)
+	if code, ok := snd.StatusCode(err); ok && code == http.StatusGone && token != "" {
+		changes, next, err = sync("")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := state.Save(key, next); err != nil {
+		return nil, fmt.Errorf("saving sync state: %w", err)
+	}
+	return changes, nil
+}
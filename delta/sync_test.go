@@ -0,0 +1,87 @@
+package delta
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	key := ChatKey("chat-1")
+
+	t.Run("continues from stored token and saves the next one", func(t *testing.T) {
+		t.Parallel()
+
+		state := NewMemoryStore()
+		require.NoError(t, state.Save(key, "t1"))
+
+		var tokens []string
+		_, err := Sync(state, key, func(token string) (*models.MessageChanges, string, error) {
+			tokens = append(tokens, token)
+			return &models.MessageChanges{}, "t2", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t1"}, tokens)
+
+		next, err := state.Load(key)
+		require.NoError(t, err)
+		assert.Equal(t, "t2", next)
+	})
+
+	t.Run("expired token starts over", func(t *testing.T) {
+		t.Parallel()
+
+		state := NewMemoryStore()
+		require.NoError(t, state.Save(key, "expired"))
+
+		var tokens []string
+		got, err := Sync(state, key, func(token string) (*models.MessageChanges, string, error) {
+			tokens = append(tokens, token)
+			if token != "" {
+				return nil, "", &snd.RequestError{Code: http.StatusGone, Message: "gone"}
+			}
+			return &models.MessageChanges{Reset: true}, "fresh", nil
+		})
+		require.NoError(t, err)
+		assert.True(t, got.Reset)
+		assert.Equal(t, []string{"expired", ""}, tokens)
+
+		next, err := state.Load(key)
+		require.NoError(t, err)
+		assert.Equal(t, "fresh", next)
+	})
+
+	t.Run("error keeps stored token", func(t *testing.T) {
+		t.Parallel()
+
+		state := NewMemoryStore()
+		require.NoError(t, state.Save(key, "t1"))
+		boom := errors.New("boom")
+
+		_, err := Sync(state, key, func(string) (*models.MessageChanges, string, error) {
+			return nil, "", boom
+		})
+		require.ErrorIs(t, err, boom)
+
+		token, err := state.Load(key)
+		require.NoError(t, err)
+		assert.Equal(t, "t1", token)
+	})
+
+	t.Run("nil state", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Sync(nil, key, func(token string) (*models.MessageChanges, string, error) {
+			assert.Empty(t, token)
+			return &models.MessageChanges{}, "t1", nil
+		})
+		require.NoError(t, err)
+	})
+}
@@ -5,6 +5,9 @@
 package adapter
 
 import (
+	"strings"
+	"time"
+
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
 	"github.com/pzsp-teams/lib/internal/util"
 	"github.com/pzsp-teams/lib/models"
@@ -73,6 +76,11 @@ func MapGraphMessage(graphMessage msmodels.ChatMessageable) *models.Message {
 				UserID:      util.Deref(user.GetId()),
 				DisplayName: util.Deref(user.GetDisplayName()),
 			}
+		} else if app := graphFrom.GetApplication(); app != nil {
+			from = &models.MessageFrom{
+				ApplicationID: util.Deref(app.GetId()),
+				DisplayName:   util.Deref(app.GetDisplayName()),
+			}
 		}
 	}
 
@@ -80,14 +88,150 @@ func MapGraphMessage(graphMessage msmodels.ChatMessageable) *models.Message {
 		replyCount = len(replies)
 	}
 
-	return &models.Message{
-		ID:              util.Deref(graphMessage.GetId()),
-		Content:         content,
-		ContentType:     contentType,
-		CreatedDateTime: util.Deref(graphMessage.GetCreatedDateTime()),
-		From:            from,
-		ReplyCount:      replyCount,
+	message := &models.Message{
+		ID:                   util.Deref(graphMessage.GetId()),
+		ReplyToID:            util.Deref(graphMessage.GetReplyToId()),
+		Subject:              util.Deref(graphMessage.GetSubject()),
+		Content:              content,
+		ContentType:          contentType,
+		CreatedDateTime:      util.Deref(graphMessage.GetCreatedDateTime()),
+		LastModifiedDateTime: graphMessage.GetLastModifiedDateTime(),
+		LastEditedDateTime:   graphMessage.GetLastEditedDateTime(),
+		DeletedDateTime:      graphMessage.GetDeletedDateTime(),
+		From:                 from,
+		ReplyCount:           replyCount,
+		Mentions:             mapGraphMentions(graphMessage.GetMentions()),
+		Reactions:            mapGraphReactions(graphMessage.GetReactions()),
+		Attachments:          mapGraphAttachments(graphMessage.GetAttachments()),
+		WebURL:               util.Deref(graphMessage.GetWebUrl()),
+		ChatID:               util.Deref(graphMessage.GetChatId()),
+	}
+	if messageType := graphMessage.GetMessageType(); messageType != nil {
+		message.MessageType = models.MessageType(messageType.String())
+	}
+	if importance := graphMessage.GetImportance(); importance != nil {
+		message.Importance = models.MessageImportance(importance.String())
+	}
+	if channel := graphMessage.GetChannelIdentity(); channel != nil {
+		message.TeamID = util.Deref(channel.GetTeamId())
+		message.ChannelID = util.Deref(channel.GetChannelId())
+	}
+	return message
+}
+
+// MapGraphMessageChanges sorts messages changed since the previous sync into new, edited and deleted ones.
+// Messages created after since are new, older ones are edited. A zero since (the sync started over)
+// reports all messages which are not deleted as new.
+func MapGraphMessageChanges(graphMessages []msmodels.ChatMessageable, since time.Time) *models.MessageChanges {
+	changes := &models.MessageChanges{}
+	for _, graphMessage := range graphMessages {
+		if graphMessage == nil {
+			continue
+		}
+		msg := MapGraphMessage(graphMessage)
+		created := graphMessage.GetCreatedDateTime()
+		switch {
+		case graphMessage.GetDeletedDateTime() != nil:
+			changes.Deleted = append(changes.Deleted, msg)
+		case since.IsZero() || created == nil || created.After(since):
+			changes.New = append(changes.New, msg)
+		default:
+			changes.Edited = append(changes.Edited, msg)
+		}
+	}
+	return changes
+}
+
+func mapGraphAttachments(graphAttachments []msmodels.ChatMessageAttachmentable) []models.Attachment {
+	if len(graphAttachments) == 0 {
+		return nil
+	}
+	return util.MapSlices(graphAttachments, func(graphAttachment msmodels.ChatMessageAttachmentable) models.Attachment {
+		return models.Attachment{
+			ID:           util.Deref(graphAttachment.GetId()),
+			ContentType:  util.Deref(graphAttachment.GetContentType()),
+			ContentURL:   util.Deref(graphAttachment.GetContentUrl()),
+			Content:      util.Deref(graphAttachment.GetContent()),
+			Name:         util.Deref(graphAttachment.GetName()),
+			ThumbnailURL: util.Deref(graphAttachment.GetThumbnailUrl()),
+			TeamsAppID:   util.Deref(graphAttachment.GetTeamsAppId()),
+		}
+	})
+}
+
+// legacyReactions maps reaction names used by Graph before emoji reactions to their emoji.
+var legacyReactions = map[string]string{
+	"like":      "👍",
+	"heart":     "❤️",
+	"laugh":     "😆",
+	"surprised": "😮",
+	"sad":       "😢",
+	"angry":     "😡",
+}
+
+func mapGraphReactions(graphReactions []msmodels.ChatMessageReactionable) []models.Reaction {
+	if len(graphReactions) == 0 {
+		return nil
+	}
+	return util.MapSlices(graphReactions, mapGraphReaction)
+}
+
+func mapGraphReaction(graphReaction msmodels.ChatMessageReactionable) models.Reaction {
+	reactionType := util.Deref(graphReaction.GetReactionType())
+	if emoji, ok := legacyReactions[reactionType]; ok {
+		reactionType = emoji
+	}
+	reaction := models.Reaction{
+		ReactionType:    reactionType,
+		DisplayName:     util.Deref(graphReaction.GetDisplayName()),
+		CreatedDateTime: util.Deref(graphReaction.GetCreatedDateTime()),
+	}
+	if identity := graphReaction.GetUser(); identity != nil {
+		if user := identity.GetUser(); user != nil {
+			reaction.User = &models.MessageFrom{
+				UserID:      util.Deref(user.GetId()),
+				DisplayName: util.Deref(user.GetDisplayName()),
+			}
+		}
+	}
+	return reaction
+}
+
+func mapGraphMentions(graphMentions []msmodels.ChatMessageMentionable) []models.Mention {
+	if len(graphMentions) == 0 {
+		return nil
+	}
+	return util.MapSlices(graphMentions, mapGraphMention)
+}
+
+func mapGraphMention(graphMention msmodels.ChatMessageMentionable) models.Mention {
+	mention := models.Mention{
+		AtID: util.Deref(graphMention.GetId()),
+		Text: util.Deref(graphMention.GetMentionText()),
+	}
+	mentioned := graphMention.GetMentioned()
+	if mentioned == nil {
+		return mention
+	}
+	if user := mentioned.GetUser(); user != nil {
+		mention.Kind = models.MentionUser
+		mention.TargetID = util.Deref(user.GetId())
+		return mention
 	}
+	if conv := mentioned.GetConversation(); conv != nil {
+		mention.TargetID = util.Deref(conv.GetId())
+		if t := conv.GetConversationIdentityType(); t != nil {
+			switch *t {
+			case msmodels.TEAM_TEAMWORKCONVERSATIONIDENTITYTYPE:
+				mention.Kind = models.MentionTeam
+			case msmodels.CHANNEL_TEAMWORKCONVERSATIONIDENTITYTYPE:
+				mention.Kind = models.MentionChannel
+			case msmodels.CHAT_TEAMWORKCONVERSATIONIDENTITYTYPE:
+				mention.Kind = models.MentionEveryone
+			}
+		}
+	}
+	return mention
 }
 
 // MapGraphPinnedMessage maps a Microsoft Graph PinnedChatMessageInfoable to simplified Message model.
@@ -98,6 +242,39 @@ func MapGraphPinnedMessage(graphPinned msmodels.PinnedChatMessageInfoable) *mode
 	return MapGraphMessage(graphPinned.GetMessage())
 }
 
+// MapGraphHostedContent maps a Microsoft Graph ChatMessageHostedContentable to simplified HostedContent model.
+func MapGraphHostedContent(graphHostedContent msmodels.ChatMessageHostedContentable) *models.HostedContent {
+	if graphHostedContent == nil {
+		return nil
+	}
+	return &models.HostedContent{
+		ID:          util.Deref(graphHostedContent.GetId()),
+		ContentType: util.Deref(graphHostedContent.GetContentType()),
+		Content:     graphHostedContent.GetContentBytes(),
+	}
+}
+
+// MapGraphDriveItemAttachment maps a Microsoft Graph DriveItemable to a reference Attachment pointing at the file.
+// Teams identifies file attachments by the GUID of the eTag of the drive item.
+func MapGraphDriveItemAttachment(graphItem msmodels.DriveItemable) *models.Attachment {
+	if graphItem == nil {
+		return nil
+	}
+	id := util.Deref(graphItem.GetId())
+	if eTag := util.Deref(graphItem.GetETag()); eTag != "" {
+		guid, _, _ := strings.Cut(strings.Trim(eTag, `"`), ",")
+		if guid = strings.Trim(guid, "{}"); guid != "" {
+			id = guid
+		}
+	}
+	return &models.Attachment{
+		ID:          id,
+		ContentType: models.AttachmentContentTypeReference,
+		ContentURL:  util.Deref(graphItem.GetWebUrl()),
+		Name:        util.Deref(graphItem.GetName()),
+	}
+}
+
 // MapGraphMember maps a Microsoft Graph ConversationMemberable to simplified Member model.
 func MapGraphMember(graphMember msmodels.ConversationMemberable) *models.Member {
 	if graphMember == nil {
@@ -156,3 +333,28 @@ func MapGraphChat(graphChat msmodels.Chatable) *models.Chat {
 		Topic:    graphChat.GetTopic(),
 	}
 }
+
+// MapGraphSubscription maps a Microsoft Graph Subscriptionable to simplified Subscription model.
+func MapGraphSubscription(graphSubscription msmodels.Subscriptionable) *models.Subscription {
+	if graphSubscription == nil {
+		return nil
+	}
+
+	var changeTypes []models.ChangeType
+	for _, ct := range strings.Split(util.Deref(graphSubscription.GetChangeType()), ",") {
+		if ct = strings.TrimSpace(ct); ct != "" {
+			changeTypes = append(changeTypes, models.ChangeType(ct))
+		}
+	}
+
+	return &models.Subscription{
+		ID:                       util.Deref(graphSubscription.GetId()),
+		Resource:                 util.Deref(graphSubscription.GetResource()),
+		ChangeTypes:              changeTypes,
+		NotificationURL:          util.Deref(graphSubscription.GetNotificationUrl()),
+		LifecycleNotificationURL: util.Deref(graphSubscription.GetLifecycleNotificationUrl()),
+		ClientState:              util.Deref(graphSubscription.GetClientState()),
+		IncludeResourceData:      util.Deref(graphSubscription.GetIncludeResourceData()),
+		ExpirationDateTime:       util.Deref(graphSubscription.GetExpirationDateTime()),
+	}
+}
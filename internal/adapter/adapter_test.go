@@ -157,21 +157,25 @@ func TestMapGraphMessage(t *testing.T) {
 		{
 			"Text content message",
 			&testutil.NewMessageParams{
-				ID:              util.Ptr("message-id"),
-				Content:         util.Ptr("Hello, world!"),
-				ContentType:     util.Ptr(msmodels.TEXT_BODYTYPE),
-				CreatedDateTime: util.Ptr(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)),
-				FromUserID:      util.Ptr("user-id"),
-				FromDisplayName: util.Ptr("John Doe"),
-				ReplyCount:      util.Ptr(3),
+				ID:                   util.Ptr("message-id"),
+				Content:              util.Ptr("Hello, world!"),
+				ContentType:          util.Ptr(msmodels.TEXT_BODYTYPE),
+				CreatedDateTime:      util.Ptr(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)),
+				LastModifiedDateTime: util.Ptr(time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC)),
+				LastEditedDateTime:   util.Ptr(time.Date(2024, 1, 3, 7, 0, 0, 0, time.UTC)),
+				FromUserID:           util.Ptr("user-id"),
+				FromDisplayName:      util.Ptr("John Doe"),
+				ReplyCount:           util.Ptr(3),
 			},
 			&models.Message{
-				ID:              "message-id",
-				Content:         "Hello, world!",
-				ContentType:     models.MessageContentTypeText,
-				CreatedDateTime: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
-				From:            &models.MessageFrom{UserID: "user-id", DisplayName: "John Doe"},
-				ReplyCount:      3,
+				ID:                   "message-id",
+				Content:              "Hello, world!",
+				ContentType:          models.MessageContentTypeText,
+				CreatedDateTime:      time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+				LastModifiedDateTime: util.Ptr(time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC)),
+				LastEditedDateTime:   util.Ptr(time.Date(2024, 1, 3, 7, 0, 0, 0, time.UTC)),
+				From:                 &models.MessageFrom{UserID: "user-id", DisplayName: "John Doe"},
+				ReplyCount:           3,
 			},
 		},
 		{
@@ -221,6 +225,7 @@ func TestMapGraphMessage(t *testing.T) {
 		assert.Equal(t, tc.result.Content, message.Content)
 		assert.Equal(t, tc.result.ContentType, message.ContentType)
 		assert.Equal(t, tc.result.CreatedDateTime, message.CreatedDateTime)
+		assert.Equal(t, tc.result.LastEditedDateTime, message.LastEditedDateTime)
 
 		if tc.result.From == nil || message.From == nil {
 			assert.Equal(t, tc.result.From, message.From)
@@ -233,6 +238,114 @@ func TestMapGraphMessage(t *testing.T) {
 	}
 }
 
+func TestMapGraphMessage_Mentions(t *testing.T) {
+	user := msmodels.NewChatMessageMention()
+	user.SetId(util.Ptr(int32(0)))
+	user.SetMentionText(util.Ptr("John"))
+	userIdentity := msmodels.NewIdentity()
+	userIdentity.SetId(util.Ptr("user-id"))
+	userSet := msmodels.NewChatMessageMentionedIdentitySet()
+	userSet.SetUser(userIdentity)
+	user.SetMentioned(userSet)
+
+	channel := msmodels.NewChatMessageMention()
+	channel.SetId(util.Ptr(int32(1)))
+	channel.SetMentionText(util.Ptr("General"))
+	conv := msmodels.NewTeamworkConversationIdentity()
+	conv.SetId(util.Ptr("channel-id"))
+	conv.SetConversationIdentityType(util.Ptr(msmodels.CHANNEL_TEAMWORKCONVERSATIONIDENTITYTYPE))
+	convSet := msmodels.NewChatMessageMentionedIdentitySet()
+	convSet.SetConversation(conv)
+	channel.SetMentioned(convSet)
+
+	graphMessage := testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("message-id")})
+	graphMessage.SetMentions([]msmodels.ChatMessageMentionable{user, channel})
+
+	assert.Equal(t, []models.Mention{
+		{Kind: models.MentionUser, AtID: 0, Text: "John", TargetID: "user-id"},
+		{Kind: models.MentionChannel, AtID: 1, Text: "General", TargetID: "channel-id"},
+	}, MapGraphMessage(graphMessage).Mentions)
+	assert.Nil(t, MapGraphMessage(testutil.NewGraphMessage(&testutil.NewMessageParams{})).Mentions)
+}
+
+func TestMapGraphMessage_Reactions(t *testing.T) {
+	reactedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	newReaction := func(reactionType, userID string) msmodels.ChatMessageReactionable {
+		reaction := msmodels.NewChatMessageReaction()
+		reaction.SetReactionType(util.Ptr(reactionType))
+		reaction.SetCreatedDateTime(&reactedAt)
+		user := msmodels.NewIdentity()
+		user.SetId(util.Ptr(userID))
+		identity := msmodels.NewChatMessageReactionIdentitySet()
+		identity.SetUser(user)
+		reaction.SetUser(identity)
+		return reaction
+	}
+
+	graphMessage := testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("message-id")})
+	graphMessage.SetReactions([]msmodels.ChatMessageReactionable{
+		newReaction("👍", "user-1"),
+		newReaction("like", "user-2"),
+		newReaction("🎉", "user-1"),
+	})
+
+	assert.Equal(t, []models.Reaction{
+		{ReactionType: "👍", User: &models.MessageFrom{UserID: "user-1"}, CreatedDateTime: reactedAt},
+		{ReactionType: "👍", User: &models.MessageFrom{UserID: "user-2"}, CreatedDateTime: reactedAt},
+		{ReactionType: "🎉", User: &models.MessageFrom{UserID: "user-1"}, CreatedDateTime: reactedAt},
+	}, MapGraphMessage(graphMessage).Reactions)
+	assert.Nil(t, MapGraphMessage(testutil.NewGraphMessage(&testutil.NewMessageParams{})).Reactions)
+}
+
+func TestMapGraphMessage_RichFields(t *testing.T) {
+	deleted := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	graphMessage := testutil.NewGraphMessage(&testutil.NewMessageParams{ID: util.Ptr("reply-id")})
+	graphMessage.SetReplyToId(util.Ptr("message-id"))
+	graphMessage.SetSubject(util.Ptr("Release"))
+	graphMessage.SetImportance(util.Ptr(msmodels.URGENT_CHATMESSAGEIMPORTANCE))
+	graphMessage.SetMessageType(util.Ptr(msmodels.SYSTEMEVENTMESSAGE_CHATMESSAGETYPE))
+	graphMessage.SetDeletedDateTime(&deleted)
+	graphMessage.SetWebUrl(util.Ptr("https://teams.microsoft.com/l/message/1"))
+
+	channel := msmodels.NewChannelIdentity()
+	channel.SetTeamId(util.Ptr("team-id"))
+	channel.SetChannelId(util.Ptr("channel-id"))
+	graphMessage.SetChannelIdentity(channel)
+
+	app := msmodels.NewIdentity()
+	app.SetId(util.Ptr("app-id"))
+	app.SetDisplayName(util.Ptr("Build Bot"))
+	from := msmodels.NewChatMessageFromIdentitySet()
+	from.SetApplication(app)
+	graphMessage.SetFrom(from)
+
+	attachment := msmodels.NewChatMessageAttachment()
+	attachment.SetId(util.Ptr("att-id"))
+	attachment.SetContentType(util.Ptr("reference"))
+	attachment.SetContentUrl(util.Ptr("https://contoso.sharepoint.com/report.pdf"))
+	attachment.SetName(util.Ptr("report.pdf"))
+	graphMessage.SetAttachments([]msmodels.ChatMessageAttachmentable{attachment})
+
+	message := MapGraphMessage(graphMessage)
+	assert.Equal(t, "message-id", message.ReplyToID)
+	assert.Equal(t, "Release", message.Subject)
+	assert.Equal(t, models.MessageImportanceUrgent, message.Importance)
+	assert.Equal(t, models.MessageTypeSystemEvent, message.MessageType)
+	assert.Equal(t, &deleted, message.DeletedDateTime)
+	assert.Equal(t, "https://teams.microsoft.com/l/message/1", message.WebURL)
+	assert.Equal(t, "team-id", message.TeamID)
+	assert.Equal(t, "channel-id", message.ChannelID)
+	assert.Empty(t, message.ChatID)
+	assert.Equal(t, &models.MessageFrom{ApplicationID: "app-id", DisplayName: "Build Bot"}, message.From)
+	assert.Equal(t, []models.Attachment{{
+		ID:          "att-id",
+		ContentType: "reference",
+		ContentURL:  "https://contoso.sharepoint.com/report.pdf",
+		Name:        "report.pdf",
+	}}, message.Attachments)
+}
+
 func TestMapGraphPinnedMessage(t *testing.T) {
 	type testCase struct {
 		name   string
@@ -427,3 +540,101 @@ func TestMapGraphChat(t *testing.T) {
 		}
 	}
 }
+
+func TestMapGraphMessageChanges(t *testing.T) {
+	since := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	before, after := since.Add(-time.Hour), since.Add(time.Hour)
+	newMessage := func(id string, created time.Time, edited, deleted bool) msmodels.ChatMessageable {
+		msg := msmodels.NewChatMessage()
+		msg.SetId(util.Ptr(id))
+		msg.SetCreatedDateTime(&created)
+		if edited {
+			msg.SetLastEditedDateTime(&after)
+		}
+		if deleted {
+			msg.SetDeletedDateTime(&after)
+		}
+		return msg
+	}
+	messages := []msmodels.ChatMessageable{
+		newMessage("new", after, false, false),
+		nil,
+		newMessage("new-edited", after, true, false),
+		newMessage("edited", before, true, false),
+		newMessage("reacted", before, false, false),
+		newMessage("deleted", before, true, true),
+	}
+	ids := func(msgs []*models.Message) []string {
+		return util.MapSlices(msgs, func(m *models.Message) string { return m.ID })
+	}
+
+	got := MapGraphMessageChanges(messages, since)
+	assert.Equal(t, []string{"new", "new-edited"}, ids(got.New))
+	assert.Equal(t, []string{"edited", "reacted"}, ids(got.Edited))
+	assert.Equal(t, []string{"deleted"}, ids(got.Deleted))
+	assert.False(t, got.Reset)
+
+	got = MapGraphMessageChanges(messages, time.Time{})
+	assert.Equal(t, []string{"new", "new-edited", "edited", "reacted"}, ids(got.New))
+	assert.Empty(t, got.Edited)
+	assert.Equal(t, []string{"deleted"}, ids(got.Deleted))
+}
+
+func TestMapGraphSubscription(t *testing.T) {
+	assert.Nil(t, MapGraphSubscription(nil))
+
+	expires := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sub := msmodels.NewSubscription()
+	sub.SetId(util.Ptr("sub-1"))
+	sub.SetResource(util.Ptr("/chats/chat-1/messages"))
+	sub.SetChangeType(util.Ptr("created, updated"))
+	sub.SetNotificationUrl(util.Ptr("https://example.com/notify"))
+	sub.SetLifecycleNotificationUrl(util.Ptr("https://example.com/lifecycle"))
+	sub.SetClientState(util.Ptr("secret"))
+	sub.SetIncludeResourceData(util.Ptr(true))
+	sub.SetExpirationDateTime(&expires)
+
+	assert.Equal(t, &models.Subscription{
+		ID:                       "sub-1",
+		Resource:                 "/chats/chat-1/messages",
+		ChangeTypes:              []models.ChangeType{models.ChangeTypeCreated, models.ChangeTypeUpdated},
+		NotificationURL:          "https://example.com/notify",
+		LifecycleNotificationURL: "https://example.com/lifecycle",
+		ClientState:              "secret",
+		IncludeResourceData:      true,
+		ExpirationDateTime:       expires,
+	}, MapGraphSubscription(sub))
+	assert.Equal(t, &models.Subscription{}, MapGraphSubscription(msmodels.NewSubscription()))
+}
+
+func TestMapGraphHostedContent(t *testing.T) {
+	assert.Nil(t, MapGraphHostedContent(nil))
+
+	hc := msmodels.NewChatMessageHostedContent()
+	hc.SetId(util.Ptr("hc-1"))
+	hc.SetContentType(util.Ptr("image/png"))
+
+	assert.Equal(t, &models.HostedContent{ID: "hc-1", ContentType: "image/png"}, MapGraphHostedContent(hc))
+	assert.Equal(t, &models.HostedContent{}, MapGraphHostedContent(msmodels.NewChatMessageHostedContent()))
+}
+
+func TestMapGraphDriveItemAttachment(t *testing.T) {
+	assert.Nil(t, MapGraphDriveItemAttachment(nil))
+
+	item := msmodels.NewDriveItem()
+	item.SetId(util.Ptr("01ABC"))
+	item.SetETag(util.Ptr(`"{8C3CFD4E-1B7E-4F3A-9A8B-0D1C2E3F4A5B},3"`))
+	item.SetName(util.Ptr("report.pdf"))
+	item.SetWebUrl(util.Ptr("https://contoso.sharepoint.com/sites/Team/Shared%20Documents/General/report.pdf"))
+
+	assert.Equal(t, &models.Attachment{
+		ID:          "8C3CFD4E-1B7E-4F3A-9A8B-0D1C2E3F4A5B",
+		ContentType: models.AttachmentContentTypeReference,
+		ContentURL:  "https://contoso.sharepoint.com/sites/Team/Shared%20Documents/General/report.pdf",
+		Name:        "report.pdf",
+	}, MapGraphDriveItemAttachment(item))
+
+	noETag := msmodels.NewDriveItem()
+	noETag.SetId(util.Ptr("01ABC"))
+	assert.Equal(t, "01ABC", MapGraphDriveItemAttachment(noETag).ID)
+}
@@ -0,0 +1,117 @@
+package adapter
+
+import (
+	"time"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+)
+
+// graphDateTimeLayout is the layout of date-times in Graph dateTimeTimeZone values.
+const graphDateTimeLayout = "2006-01-02T15:04:05.9999999"
+
+// MapGraphFile maps a Microsoft Graph DriveItemable to simplified File model.
+func MapGraphFile(graphItem msmodels.DriveItemable) *search.File {
+	if graphItem == nil {
+		return nil
+	}
+
+	var driveID string
+	if ref := graphItem.GetParentReference(); ref != nil {
+		driveID = util.Deref(ref.GetDriveId())
+	}
+
+	var modifiedBy string
+	if by := graphItem.GetLastModifiedBy(); by != nil && by.GetUser() != nil {
+		modifiedBy = util.Deref(by.GetUser().GetDisplayName())
+	}
+
+	return &search.File{
+		ID:                   util.Deref(graphItem.GetId()),
+		Name:                 util.Deref(graphItem.GetName()),
+		WebURL:               util.Deref(graphItem.GetWebUrl()),
+		Size:                 graphItem.GetSize(),
+		DriveID:              driveID,
+		LastModifiedDateTime: graphItem.GetLastModifiedDateTime(),
+		LastModifiedBy:       modifiedBy,
+	}
+}
+
+// MapGraphPerson maps a Microsoft Graph Personable to simplified Person model.
+func MapGraphPerson(graphPerson msmodels.Personable) *search.Person {
+	if graphPerson == nil {
+		return nil
+	}
+
+	var email string
+	for _, address := range graphPerson.GetScoredEmailAddresses() {
+		if address != nil && address.GetAddress() != nil {
+			email = *address.GetAddress()
+			break
+		}
+	}
+
+	return &search.Person{
+		ID:                util.Deref(graphPerson.GetId()),
+		DisplayName:       util.Deref(graphPerson.GetDisplayName()),
+		Email:             email,
+		UserPrincipalName: util.Deref(graphPerson.GetUserPrincipalName()),
+		JobTitle:          util.Deref(graphPerson.GetJobTitle()),
+		Department:        util.Deref(graphPerson.GetDepartment()),
+	}
+}
+
+// MapGraphEvent maps a Microsoft Graph Eventable to simplified Event model.
+func MapGraphEvent(graphEvent msmodels.Eventable) *search.Event {
+	if graphEvent == nil {
+		return nil
+	}
+
+	var organizer string
+	if o := graphEvent.GetOrganizer(); o != nil && o.GetEmailAddress() != nil {
+		organizer = util.Deref(o.GetEmailAddress().GetAddress())
+		if organizer == "" {
+			organizer = util.Deref(o.GetEmailAddress().GetName())
+		}
+	}
+
+	var location string
+	if l := graphEvent.GetLocation(); l != nil {
+		location = util.Deref(l.GetDisplayName())
+	}
+
+	var joinURL string
+	if m := graphEvent.GetOnlineMeeting(); m != nil {
+		joinURL = util.Deref(m.GetJoinUrl())
+	}
+
+	return &search.Event{
+		ID:              util.Deref(graphEvent.GetId()),
+		Subject:         util.Deref(graphEvent.GetSubject()),
+		Start:           mapGraphDateTime(graphEvent.GetStart()),
+		End:             mapGraphDateTime(graphEvent.GetEnd()),
+		Organizer:       organizer,
+		Location:        location,
+		WebLink:         util.Deref(graphEvent.GetWebLink()),
+		IsOnlineMeeting: util.Deref(graphEvent.GetIsOnlineMeeting()),
+		JoinURL:         joinURL,
+	}
+}
+
+// mapGraphDateTime parses a date-time in its time zone; unknown time zones fall back to UTC.
+func mapGraphDateTime(dt msmodels.DateTimeTimeZoneable) *time.Time {
+	if dt == nil || dt.GetDateTime() == nil {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(util.Deref(dt.GetTimeZone()))
+	if err != nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(graphDateTimeLayout, *dt.GetDateTime(), loc)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
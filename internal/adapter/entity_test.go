@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"testing"
+	"time"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapGraphFile(t *testing.T) {
+	assert.Nil(t, MapGraphFile(nil))
+
+	modified := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	ref := msmodels.NewItemReference()
+	ref.SetDriveId(util.Ptr("drive-1"))
+	user := msmodels.NewIdentity()
+	user.SetDisplayName(util.Ptr("Jane"))
+	by := msmodels.NewIdentitySet()
+	by.SetUser(user)
+
+	item := msmodels.NewDriveItem()
+	item.SetId(util.Ptr("file-1"))
+	item.SetName(util.Ptr("plan.xlsx"))
+	item.SetWebUrl(util.Ptr("https://example.com/plan.xlsx"))
+	item.SetSize(util.Ptr(int64(42)))
+	item.SetParentReference(ref)
+	item.SetLastModifiedBy(by)
+	item.SetLastModifiedDateTime(&modified)
+
+	assert.Equal(t, &search.File{
+		ID:                   "file-1",
+		Name:                 "plan.xlsx",
+		WebURL:               "https://example.com/plan.xlsx",
+		Size:                 util.Ptr(int64(42)),
+		DriveID:              "drive-1",
+		LastModifiedDateTime: &modified,
+		LastModifiedBy:       "Jane",
+	}, MapGraphFile(item))
+	assert.Equal(t, &search.File{}, MapGraphFile(msmodels.NewDriveItem()))
+}
+
+func TestMapGraphPerson(t *testing.T) {
+	assert.Nil(t, MapGraphPerson(nil))
+
+	address := msmodels.NewScoredEmailAddress()
+	address.SetAddress(util.Ptr("jane@example.com"))
+	person := msmodels.NewPerson()
+	person.SetId(util.Ptr("p1"))
+	person.SetDisplayName(util.Ptr("Jane"))
+	person.SetScoredEmailAddresses([]msmodels.ScoredEmailAddressable{msmodels.NewScoredEmailAddress(), address})
+	person.SetUserPrincipalName(util.Ptr("jane@example.com"))
+	person.SetJobTitle(util.Ptr("Engineer"))
+	person.SetDepartment(util.Ptr("R&D"))
+
+	assert.Equal(t, &search.Person{
+		ID:                "p1",
+		DisplayName:       "Jane",
+		Email:             "jane@example.com",
+		UserPrincipalName: "jane@example.com",
+		JobTitle:          "Engineer",
+		Department:        "R&D",
+	}, MapGraphPerson(person))
+}
+
+func TestMapGraphEvent(t *testing.T) {
+	assert.Nil(t, MapGraphEvent(nil))
+
+	dateTime := func(value, tz string) msmodels.DateTimeTimeZoneable {
+		dt := msmodels.NewDateTimeTimeZone()
+		dt.SetDateTime(util.Ptr(value))
+		dt.SetTimeZone(util.Ptr(tz))
+		return dt
+	}
+	email := msmodels.NewEmailAddress()
+	email.SetName(util.Ptr("Jane"))
+	organizer := msmodels.NewRecipient()
+	organizer.SetEmailAddress(email)
+	location := msmodels.NewLocation()
+	location.SetDisplayName(util.Ptr("Room 1"))
+	meeting := msmodels.NewOnlineMeetingInfo()
+	meeting.SetJoinUrl(util.Ptr("https://teams.example.com/join"))
+
+	event := msmodels.NewEvent()
+	event.SetId(util.Ptr("e1"))
+	event.SetSubject(util.Ptr("Standup"))
+	event.SetStart(dateTime("2026-03-02T09:00:00.0000000", "Europe/Warsaw"))
+	event.SetEnd(dateTime("2026-03-02T09:15:00.0000000", "Unknown Standard Time"))
+	event.SetOrganizer(organizer)
+	event.SetLocation(location)
+	event.SetWebLink(util.Ptr("https://outlook.example.com/e1"))
+	event.SetIsOnlineMeeting(util.Ptr(true))
+	event.SetOnlineMeeting(meeting)
+
+	got := MapGraphEvent(event)
+	require.NotNil(t, got)
+	assert.Equal(t, "e1", got.ID)
+	assert.Equal(t, "Standup", got.Subject)
+	assert.True(t, got.Start.Equal(time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)))
+	assert.True(t, got.End.Equal(time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC)))
+	assert.Equal(t, "Jane", got.Organizer)
+	assert.Equal(t, "Room 1", got.Location)
+	assert.Equal(t, "https://outlook.example.com/e1", got.WebLink)
+	assert.True(t, got.IsOnlineMeeting)
+	assert.Equal(t, "https://teams.example.com/join", got.JoinURL)
+
+	event.SetStart(dateTime("not a date", "UTC"))
+	assert.Nil(t, MapGraphEvent(event).Start)
+}
@@ -6,6 +6,8 @@ package api
 
 import (
 	"context"
+	"io"
+	"time"
 
 	graph "github.com/microsoftgraph/msgraph-sdk-go"
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/pzsp-teams/lib/config"
 	"github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/util"
 	"github.com/pzsp-teams/lib/search"
 )
 
@@ -22,19 +25,37 @@ type ChannelAPI interface {
 	CreateStandardChannel(ctx context.Context, teamID string, channel msmodels.Channelable) (msmodels.Channelable, *sender.RequestError)
 	CreatePrivateChannelWithMembers(ctx context.Context, teamID, displayName string, memberIDs, ownersID []string) (msmodels.Channelable, *sender.RequestError)
 	DeleteChannel(ctx context.Context, teamID, channelID string) *sender.RequestError
-	SendMessage(ctx context.Context, teamID, channelID, content, contentType string, mentions []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *sender.RequestError)
-	SendReply(ctx context.Context, teamID, channelID, messageID, content, contentType string, mentions []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *sender.RequestError)
-	ListMessages(ctx context.Context, teamID, channelID string, top *int32, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
+	SendMessage(ctx context.Context, teamID, channelID, content, contentType, subject, importance string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable, hostedContents []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *sender.RequestError)
+	SendReply(ctx context.Context, teamID, channelID, messageID, content, contentType, subject, importance string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable, hostedContents []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *sender.RequestError)
+	UpdateMessage(ctx context.Context, teamID, channelID, messageID, content, contentType string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable) *sender.RequestError
+	UpdateReply(ctx context.Context, teamID, channelID, messageID, replyID, content, contentType string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable) *sender.RequestError
+	DeleteMessage(ctx context.Context, teamID, channelID, messageID string) *sender.RequestError
+	DeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) *sender.RequestError
+	UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) *sender.RequestError
+	UndoDeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) *sender.RequestError
+	SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) *sender.RequestError
+	UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) *sender.RequestError
+	SetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) *sender.RequestError
+	UnsetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) *sender.RequestError
+	ListMessages(ctx context.Context, teamID, channelID string, top *int32, expandReplies, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
 	GetMessage(ctx context.Context, teamID, channelID, messageID string) (msmodels.ChatMessageable, *sender.RequestError)
 	ListReplies(ctx context.Context, teamID, channelID, messageID string, top *int32, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
 	GetReply(ctx context.Context, teamID, channelID, messageID, replyID string) (msmodels.ChatMessageable, *sender.RequestError)
+	ListHostedContents(ctx context.Context, teamID, channelID, messageID string) (msmodels.ChatMessageHostedContentCollectionResponseable, *sender.RequestError)
+	ListReplyHostedContents(ctx context.Context, teamID, channelID, messageID, replyID string) (msmodels.ChatMessageHostedContentCollectionResponseable, *sender.RequestError)
+	GetHostedContent(ctx context.Context, teamID, channelID, messageID, hostedContentID string) ([]byte, *sender.RequestError)
+	GetReplyHostedContent(ctx context.Context, teamID, channelID, messageID, replyID, hostedContentID string) ([]byte, *sender.RequestError)
+	UploadFile(ctx context.Context, teamID, channelID, name string, r io.Reader, size int64) (msmodels.DriveItemable, *sender.RequestError)
+	OpenFile(ctx context.Context, contentURL string) (io.ReadCloser, *sender.RequestError)
 	ListMembers(ctx context.Context, teamID, channelID string) (msmodels.ConversationMemberCollectionResponseable, *sender.RequestError)
 	AddMember(ctx context.Context, teamID, channelID, userRef string, roles []string) (msmodels.ConversationMemberable, *sender.RequestError)
 	UpdateMemberRoles(ctx context.Context, teamID, channelID, memberID string, roles []string) (msmodels.ConversationMemberable, *sender.RequestError)
 	RemoveMember(ctx context.Context, teamID, channelID, memberID string) *sender.RequestError
 	ListMessagesNext(ctx context.Context, teamID, channelID, nextLink string, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
 	ListRepliesNext(ctx context.Context, teamID, channelID, messageID, nextLink string, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
-	SearchChannelMessages(ctx context.Context, teamID, channelID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) ([]*SearchMessage, *sender.RequestError, *int32)
+	SearchChannelMessages(ctx context.Context, teamID, channelID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*SearchMessagesPage, *sender.RequestError)
+	ListMessagesModifiedSince(ctx context.Context, teamID, channelID string, since time.Time) ([]msmodels.ChatMessageable, *sender.RequestError)
+	SyncMessages(ctx context.Context, teamID, channelID, deltaLink string) ([]msmodels.ChatMessageable, string, *sender.RequestError)
 }
 
 type channelAPI struct {
@@ -160,11 +181,10 @@ func (c *channelAPI) DeleteChannel(ctx context.Context, teamID, channelID string
 	return err
 }
 
-func (c *channelAPI) SendMessage(ctx context.Context, teamID, channelID, content, contentType string, mentions []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *sender.RequestError) {
-	message := msmodels.NewChatMessage()
-	message.SetBody(messageToGraph(content, contentType))
-	if len(mentions) > 0 {
-		message.SetMentions(mentions)
+func (c *channelAPI) SendMessage(ctx context.Context, teamID, channelID, content, contentType, subject, importance string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable, hostedContents []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *sender.RequestError) {
+	message, reqErr := newGraphMessage(content, contentType, subject, importance, mentions, attachments, hostedContents, true)
+	if reqErr != nil {
+		return nil, reqErr
 	}
 
 	call := func(ctx context.Context) (sender.Response, error) {
@@ -190,11 +210,10 @@ func (c *channelAPI) SendMessage(ctx context.Context, teamID, channelID, content
 	return out, nil
 }
 
-func (c *channelAPI) SendReply(ctx context.Context, teamID, channelID, messageID, content, contentType string, mentions []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *sender.RequestError) {
-	reply := msmodels.NewChatMessage()
-	reply.SetBody(messageToGraph(content, contentType))
-	if len(mentions) > 0 {
-		reply.SetMentions(mentions)
+func (c *channelAPI) SendReply(ctx context.Context, teamID, channelID, messageID, content, contentType, subject, importance string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable, hostedContents []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *sender.RequestError) {
+	reply, reqErr := newGraphMessage(content, contentType, subject, importance, mentions, attachments, hostedContents, false)
+	if reqErr != nil {
+		return nil, reqErr
 	}
 
 	call := func(ctx context.Context) (sender.Response, error) {
@@ -222,12 +241,209 @@ func (c *channelAPI) SendReply(ctx context.Context, teamID, channelID, messageID
 	return out, nil
 }
 
-func (c *channelAPI) ListMessages(ctx context.Context, teamID, channelID string, top *int32, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError) {
+func (c *channelAPI) UpdateMessage(ctx context.Context, teamID, channelID, messageID, content, contentType string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable) *sender.RequestError {
+	message := messageUpdateToGraph(content, contentType, mentions, attachments)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Patch(ctx, message, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) UpdateReply(ctx context.Context, teamID, channelID, messageID, replyID, content, contentType string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable) *sender.RequestError {
+	reply := messageUpdateToGraph(content, contentType, mentions, attachments)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Replies().
+			ByChatMessageId1(replyID).
+			Patch(ctx, reply, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) DeleteMessage(ctx context.Context, teamID, channelID, messageID string) *sender.RequestError {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			SoftDelete().
+			Post(ctx, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) DeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) *sender.RequestError {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Replies().
+			ByChatMessageId1(replyID).
+			SoftDelete().
+			Post(ctx, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) *sender.RequestError {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			UndoSoftDelete().
+			Post(ctx, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) UndoDeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) *sender.RequestError {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Replies().
+			ByChatMessageId1(replyID).
+			UndoSoftDelete().
+			Post(ctx, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) *sender.RequestError {
+	body := graphteams.NewItemChannelsItemMessagesItemSetReactionPostRequestBody()
+	body.SetReactionType(&reactionType)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			SetReaction().
+			Post(ctx, body, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) *sender.RequestError {
+	body := graphteams.NewItemChannelsItemMessagesItemUnsetReactionPostRequestBody()
+	body.SetReactionType(&reactionType)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			UnsetReaction().
+			Post(ctx, body, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) SetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) *sender.RequestError {
+	body := graphteams.NewItemChannelsItemMessagesItemRepliesItemSetReactionPostRequestBody()
+	body.SetReactionType(&reactionType)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Replies().
+			ByChatMessageId1(replyID).
+			SetReaction().
+			Post(ctx, body, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) UnsetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) *sender.RequestError {
+	body := graphteams.NewItemChannelsItemMessagesItemRepliesItemUnsetReactionPostRequestBody()
+	body.SetReactionType(&reactionType)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Replies().
+			ByChatMessageId1(replyID).
+			UnsetReaction().
+			Post(ctx, body, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *channelAPI) ListMessages(ctx context.Context, teamID, channelID string, top *int32, expandReplies, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError) {
 	call := func(ctx context.Context) (sender.Response, error) {
 		queryParameters := &graphteams.ItemChannelsItemMessagesRequestBuilderGetQueryParameters{}
 		if top != nil {
 			queryParameters.Top = top
 		}
+		if expandReplies {
+			queryParameters.Expand = []string{"replies"}
+		}
 		return c.client.
 			Teams().
 			ByTeamId(teamID).
@@ -345,6 +561,152 @@ func (c *channelAPI) GetReply(ctx context.Context, teamID, channelID, messageID,
 	return out, nil
 }
 
+func (c *channelAPI) ListHostedContents(ctx context.Context, teamID, channelID, messageID string) (msmodels.ChatMessageHostedContentCollectionResponseable, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			HostedContents().
+			Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.ChatMessageHostedContentCollectionResponseable)
+	if !ok {
+		return nil, newTypeError("ChatMessageHostedContentCollectionResponseable")
+	}
+
+	return out, nil
+}
+
+func (c *channelAPI) ListReplyHostedContents(ctx context.Context, teamID, channelID, messageID, replyID string) (msmodels.ChatMessageHostedContentCollectionResponseable, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Replies().
+			ByChatMessageId1(replyID).
+			HostedContents().
+			Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.ChatMessageHostedContentCollectionResponseable)
+	if !ok {
+		return nil, newTypeError("ChatMessageHostedContentCollectionResponseable")
+	}
+
+	return out, nil
+}
+
+func (c *channelAPI) GetHostedContent(ctx context.Context, teamID, channelID, messageID, hostedContentID string) ([]byte, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			HostedContents().
+			ByChatMessageHostedContentId(hostedContentID).
+			Content().
+			Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.([]byte)
+	if !ok {
+		return nil, newTypeError("[]byte")
+	}
+
+	return out, nil
+}
+
+func (c *channelAPI) GetReplyHostedContent(ctx context.Context, teamID, channelID, messageID, replyID, hostedContentID string) ([]byte, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			Messages().
+			ByChatMessageId(messageID).
+			Replies().
+			ByChatMessageId1(replyID).
+			HostedContents().
+			ByChatMessageHostedContentId(hostedContentID).
+			Content().
+			Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.([]byte)
+	if !ok {
+		return nil, newTypeError("[]byte")
+	}
+
+	return out, nil
+}
+
+// UploadFile uploads a file to the files folder of a channel, which is kept in the SharePoint site of the team.
+func (c *channelAPI) UploadFile(ctx context.Context, teamID, channelID, name string, r io.Reader, size int64) (msmodels.DriveItemable, *sender.RequestError) {
+	if reqErr := validateFileName(name); reqErr != nil {
+		return nil, reqErr
+	}
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Teams().
+			ByTeamId(teamID).
+			Channels().
+			ByChannelId(channelID).
+			FilesFolder().
+			Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, ok := resp.(msmodels.DriveItemable)
+	if !ok || folder.GetId() == nil || folder.GetParentReference() == nil || folder.GetParentReference().GetDriveId() == nil {
+		return nil, newTypeError("DriveItemable with drive reference")
+	}
+
+	path := channelFileItemPath(*folder.GetParentReference().GetDriveId(), *folder.GetId(), name)
+	return uploadFile(ctx, c.client, c.senderCfg, path, r, size)
+}
+
+func (c *channelAPI) OpenFile(ctx context.Context, contentURL string) (io.ReadCloser, *sender.RequestError) {
+	return openFile(ctx, c.client, c.senderCfg, contentURL)
+}
+
 func (c *channelAPI) ListMembers(ctx context.Context, teamID, channelID string) (msmodels.ConversationMemberCollectionResponseable, *sender.RequestError) {
 	call := func(ctx context.Context) (sender.Response, error) {
 		return c.client.
@@ -501,7 +863,7 @@ func (c *channelAPI) SearchChannelMessages(
 	teamID, channelID *string,
 	opts *search.SearchMessagesOptions,
 	searchConfig *search.SearchConfig,
-) ([]*SearchMessage, *sender.RequestError, *int32) {
+) (*SearchMessagesPage, *sender.RequestError) {
 	keep := func(e SearchEntity) bool {
 		if e.TeamID == nil || e.ChannelID == nil {
 			return false
@@ -521,3 +883,88 @@ func (c *channelAPI) SearchChannelMessages(
 
 	return enrichMessages(ctx, c.searchAPI, opts, keep, fetch, searchConfig)
 }
+
+// ListMessagesModifiedSince returns top-level channel messages created, edited or deleted after since,
+// following all pages of the delta response. System event messages are skipped.
+func (c *channelAPI) ListMessagesModifiedSince(ctx context.Context, teamID, channelID string, since time.Time) ([]msmodels.ChatMessageable, *sender.RequestError) {
+	filter := modifiedSinceFilter(since)
+	builder := c.client.
+		Teams().
+		ByTeamId(teamID).
+		Channels().
+		ByChannelId(channelID).
+		Messages().
+		Delta()
+
+	var out []msmodels.ChatMessageable
+	var nextLink *string
+	for {
+		call := func(ctx context.Context) (sender.Response, error) {
+			if nextLink != nil {
+				return builder.WithUrl(*nextLink).GetAsDeltaGetResponse(ctx, nil)
+			}
+			return builder.GetAsDeltaGetResponse(ctx, &graphteams.ItemChannelsItemMessagesDeltaRequestBuilderGetRequestConfiguration{
+				QueryParameters: &graphteams.ItemChannelsItemMessagesDeltaRequestBuilderGetQueryParameters{
+					Filter: &filter,
+				},
+			})
+		}
+
+		resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		page, ok := resp.(graphteams.ItemChannelsItemMessagesDeltaGetResponseable)
+		if !ok {
+			return nil, newTypeError("ItemChannelsItemMessagesDeltaGetResponseable")
+		}
+		out = append(out, dropSystemEvents(page.GetValue())...)
+
+		nextLink = page.GetOdataNextLink()
+		if nextLink == nil {
+			return out, nil
+		}
+	}
+}
+
+// SyncMessages runs the delta query of top-level channel messages from deltaLink, or from the start
+// when deltaLink is empty, following all pages. It returns the changed messages and the delta link
+// of the next sync. System event messages are skipped.
+func (c *channelAPI) SyncMessages(ctx context.Context, teamID, channelID, deltaLink string) ([]msmodels.ChatMessageable, string, *sender.RequestError) {
+	builder := c.client.
+		Teams().
+		ByTeamId(teamID).
+		Channels().
+		ByChannelId(channelID).
+		Messages().
+		Delta()
+
+	var out []msmodels.ChatMessageable
+	link := deltaLink
+	for {
+		call := func(ctx context.Context) (sender.Response, error) {
+			if link != "" {
+				return builder.WithUrl(link).GetAsDeltaGetResponse(ctx, nil)
+			}
+			return builder.GetAsDeltaGetResponse(ctx, nil)
+		}
+
+		resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+		if err != nil {
+			return nil, "", err
+		}
+
+		page, ok := resp.(graphteams.ItemChannelsItemMessagesDeltaGetResponseable)
+		if !ok {
+			return nil, "", newTypeError("ItemChannelsItemMessagesDeltaGetResponseable")
+		}
+		out = append(out, dropSystemEvents(page.GetValue())...)
+
+		if next := page.GetOdataNextLink(); next != nil {
+			link = *next
+			continue
+		}
+		return out, util.Deref(page.GetOdataDeltaLink()), nil
+	}
+}
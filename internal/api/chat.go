@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	graph "github.com/microsoftgraph/msgraph-sdk-go"
@@ -33,15 +34,24 @@ type ChatAPI interface {
 	GroupChatAPI
 	ListMessages(ctx context.Context, chatID string, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
 	ListChats(ctx context.Context, chatType *string) (msmodels.ChatCollectionResponseable, *sender.RequestError)
-	SendMessage(ctx context.Context, chatID, content, contentType string, mentions []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *sender.RequestError)
+	SendMessage(ctx context.Context, chatID, content, contentType, subject, importance string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable, hostedContents []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *sender.RequestError)
+	UpdateMessage(ctx context.Context, chatID, messageID, content, contentType string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable) *sender.RequestError
 	DeleteMessage(ctx context.Context, chatID, messageID string) *sender.RequestError
+	UndoDeleteMessage(ctx context.Context, chatID, messageID string) *sender.RequestError
+	SetReaction(ctx context.Context, chatID, messageID, reactionType string) *sender.RequestError
+	UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) *sender.RequestError
 	GetMessage(ctx context.Context, chatID, messageID string) (msmodels.ChatMessageable, *sender.RequestError)
+	ListHostedContents(ctx context.Context, chatID, messageID string) (msmodels.ChatMessageHostedContentCollectionResponseable, *sender.RequestError)
+	GetHostedContent(ctx context.Context, chatID, messageID, hostedContentID string) ([]byte, *sender.RequestError)
+	UploadFile(ctx context.Context, name string, r io.Reader, size int64) (msmodels.DriveItemable, *sender.RequestError)
+	OpenFile(ctx context.Context, contentURL string) (io.ReadCloser, *sender.RequestError)
 	ListAllMessages(ctx context.Context, startTime, endTime *time.Time, top *int32) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
 	ListPinnedMessages(ctx context.Context, chatID string) (msmodels.PinnedChatMessageInfoCollectionResponseable, *sender.RequestError)
 	PinMessage(ctx context.Context, chatID, messageID string) *sender.RequestError
 	UnpinMessage(ctx context.Context, chatID, pinnedID string) *sender.RequestError
 	ListMessagesNext(ctx context.Context, chatID, nextLink string, includeSystem bool) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError)
-	SearchChatMessages(ctx context.Context, chatID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) ([]*SearchMessage, *sender.RequestError, *int32)
+	SearchChatMessages(ctx context.Context, chatID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*SearchMessagesPage, *sender.RequestError)
+	ListMessagesModifiedSince(ctx context.Context, chatID string, since time.Time) ([]msmodels.ChatMessageable, *sender.RequestError)
 }
 
 type chatsAPI struct {
@@ -304,13 +314,12 @@ func (c *chatsAPI) ListMessages(ctx context.Context, chatID string, includeSyste
 	return out, nil
 }
 
-func (c *chatsAPI) SendMessage(ctx context.Context, chatID, content, contentType string, mentions []msmodels.ChatMessageMentionable) (msmodels.ChatMessageable, *sender.RequestError) {
-	msg := msmodels.NewChatMessage()
-	msg.SetBody(messageToGraph(content, contentType))
-
-	if len(mentions) > 0 {
-		msg.SetMentions(mentions)
+func (c *chatsAPI) SendMessage(ctx context.Context, chatID, content, contentType, subject, importance string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable, hostedContents []msmodels.ChatMessageHostedContentable) (msmodels.ChatMessageable, *sender.RequestError) {
+	msg, reqErr := newGraphMessage(content, contentType, subject, importance, mentions, attachments, hostedContents, false)
+	if reqErr != nil {
+		return nil, reqErr
 	}
+
 	call := func(ctx context.Context) (sender.Response, error) {
 		return c.client.Chats().ByChatId(chatID).Messages().Post(ctx, msg, nil)
 	}
@@ -327,6 +336,17 @@ func (c *chatsAPI) SendMessage(ctx context.Context, chatID, content, contentType
 	return out, nil
 }
 
+func (c *chatsAPI) UpdateMessage(ctx context.Context, chatID, messageID, content, contentType string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable) *sender.RequestError {
+	msg := messageUpdateToGraph(content, contentType, mentions, attachments)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.Chats().ByChatId(chatID).Messages().ByChatMessageId(messageID).Patch(ctx, msg, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
 func (c *chatsAPI) DeleteMessage(ctx context.Context, chatID, messageID string) *sender.RequestError {
 	call := func(ctx context.Context) (sender.Response, error) {
 		return nil, c.client.
@@ -343,6 +363,46 @@ func (c *chatsAPI) DeleteMessage(ctx context.Context, chatID, messageID string)
 	return err
 }
 
+func (c *chatsAPI) UndoDeleteMessage(ctx context.Context, chatID, messageID string) *sender.RequestError {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.
+			Me().
+			Chats().
+			ByChatId(chatID).
+			Messages().
+			ByChatMessageId(messageID).
+			UndoSoftDelete().
+			Post(ctx, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *chatsAPI) SetReaction(ctx context.Context, chatID, messageID, reactionType string) *sender.RequestError {
+	body := graphchats.NewItemMessagesItemSetReactionPostRequestBody()
+	body.SetReactionType(&reactionType)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.Chats().ByChatId(chatID).Messages().ByChatMessageId(messageID).SetReaction().Post(ctx, body, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
+func (c *chatsAPI) UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) *sender.RequestError {
+	body := graphchats.NewItemMessagesItemUnsetReactionPostRequestBody()
+	body.SetReactionType(&reactionType)
+
+	call := func(ctx context.Context) (sender.Response, error) {
+		return nil, c.client.Chats().ByChatId(chatID).Messages().ByChatMessageId(messageID).UnsetReaction().Post(ctx, body, nil)
+	}
+
+	_, err := sender.SendRequest(ctx, call, c.senderCfg)
+	return err
+}
+
 func (c *chatsAPI) GetMessage(ctx context.Context, chatID, messageID string) (msmodels.ChatMessageable, *sender.RequestError) {
 	call := func(ctx context.Context) (sender.Response, error) {
 		return c.client.
@@ -366,6 +426,68 @@ func (c *chatsAPI) GetMessage(ctx context.Context, chatID, messageID string) (ms
 	return out, nil
 }
 
+func (c *chatsAPI) ListHostedContents(ctx context.Context, chatID, messageID string) (msmodels.ChatMessageHostedContentCollectionResponseable, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Chats().
+			ByChatId(chatID).
+			Messages().
+			ByChatMessageId(messageID).
+			HostedContents().
+			Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.ChatMessageHostedContentCollectionResponseable)
+	if !ok {
+		return nil, newTypeError("ChatMessageHostedContentCollectionResponseable")
+	}
+
+	return out, nil
+}
+
+func (c *chatsAPI) GetHostedContent(ctx context.Context, chatID, messageID, hostedContentID string) ([]byte, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return c.client.
+			Chats().
+			ByChatId(chatID).
+			Messages().
+			ByChatMessageId(messageID).
+			HostedContents().
+			ByChatMessageHostedContentId(hostedContentID).
+			Content().
+			Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.([]byte)
+	if !ok {
+		return nil, newTypeError("[]byte")
+	}
+
+	return out, nil
+}
+
+// UploadFile uploads a file to the chat files folder in the OneDrive of the current user, where Teams keeps files shared in chats.
+func (c *chatsAPI) UploadFile(ctx context.Context, name string, r io.Reader, size int64) (msmodels.DriveItemable, *sender.RequestError) {
+	if reqErr := validateFileName(name); reqErr != nil {
+		return nil, reqErr
+	}
+	return uploadFile(ctx, c.client, c.senderCfg, chatFileItemPath(name), r, size)
+}
+
+func (c *chatsAPI) OpenFile(ctx context.Context, contentURL string) (io.ReadCloser, *sender.RequestError) {
+	return openFile(ctx, c.client, c.senderCfg, contentURL)
+}
+
 func (c *chatsAPI) ListAllMessages(ctx context.Context, startTime, endTime *time.Time, top *int32) (msmodels.ChatMessageCollectionResponseable, *sender.RequestError) {
 	requestParameters := &graphusers.ItemChatsGetAllMessagesRequestBuilderGetQueryParameters{
 		Top: top,
@@ -492,7 +614,7 @@ func (c *chatsAPI) SearchChatMessages(
 	chatID *string,
 	opts *search.SearchMessagesOptions,
 	searchConfig *search.SearchConfig,
-) ([]*SearchMessage, *sender.RequestError, *int32) {
+) (*SearchMessagesPage, *sender.RequestError) {
 	keep := func(e SearchEntity) bool {
 		if e.ChatID == nil {
 			return false
@@ -512,3 +634,45 @@ func (c *chatsAPI) SearchChatMessages(
 
 	return enrichMessages(ctx, c.searchAPI, opts, keep, fetch, searchConfig)
 }
+
+// ListMessagesModifiedSince returns chat messages created, edited or deleted after since,
+// newest first, following all result pages. System event messages are skipped.
+func (c *chatsAPI) ListMessagesModifiedSince(ctx context.Context, chatID string, since time.Time) ([]msmodels.ChatMessageable, *sender.RequestError) {
+	filter := modifiedSinceFilter(since)
+	builder := c.client.
+		Chats().
+		ByChatId(chatID).
+		Messages()
+
+	var out []msmodels.ChatMessageable
+	var nextLink *string
+	for {
+		call := func(ctx context.Context) (sender.Response, error) {
+			if nextLink != nil {
+				return builder.WithUrl(*nextLink).Get(ctx, nil)
+			}
+			return builder.Get(ctx, &graphchats.ItemMessagesRequestBuilderGetRequestConfiguration{
+				QueryParameters: &graphchats.ItemMessagesRequestBuilderGetQueryParameters{
+					Filter:  &filter,
+					Orderby: []string{"lastModifiedDateTime desc"},
+				},
+			})
+		}
+
+		resp, err := sender.SendRequest(ctx, call, c.senderCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		page, ok := resp.(msmodels.ChatMessageCollectionResponseable)
+		if !ok {
+			return nil, newTypeError("ChatMessageCollectionResponseable")
+		}
+		out = append(out, filterOutSystemEvents(page)...)
+
+		nextLink = page.GetOdataNextLink()
+		if nextLink == nil {
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	jsonserialization "github.com/microsoft/kiota-serialization-json-go"
+	graph "github.com/microsoftgraph/msgraph-sdk-go"
+	graphdrives "github.com/microsoftgraph/msgraph-sdk-go/drives"
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/sender"
+)
+
+const (
+	// simpleUploadLimit is the largest file uploaded with a single request; larger files go through an upload session.
+	simpleUploadLimit = 4 * 1024 * 1024
+	// uploadChunkSize is the size of upload session chunks; Graph requires a multiple of 320 KiB.
+	uploadChunkSize = 10 * 320 * 1024
+	// chatFilesFolder is the OneDrive folder in which Teams keeps files shared in chats.
+	chatFilesFolder     = "Microsoft Teams Chat Files"
+	conflictBehaviorKey = "@microsoft.graph.conflictBehavior"
+	downloadURLKey      = "@microsoft.graph.downloadUrl"
+	maxErrorBodyBytes   = 4096
+	// cancelTimeout bounds the request cancelling a failed upload session.
+	cancelTimeout        = 10 * time.Second
+	invalidFileNameChars = `/\:*?"<>|`
+)
+
+// fileHTTPClient sends requests to pre-authenticated upload and download URLs, which must not carry the Graph token.
+var fileHTTPClient = http.DefaultClient
+
+func validateFileName(name string) *sender.RequestError {
+	if strings.TrimSpace(name) == "" || name == "." || name == ".." {
+		return &sender.RequestError{Code: http.StatusBadRequest, Message: "file name cannot be empty"}
+	}
+	if strings.ContainsAny(name, invalidFileNameChars) {
+		return &sender.RequestError{Code: http.StatusBadRequest, Message: fmt.Sprintf("file name %q contains invalid characters", name)}
+	}
+	return nil
+}
+
+// uploadFile uploads size bytes of r to the drive item addressed by itemPath, e.g. "/drives/{id}/items/{id}:/{name}:".
+// An existing file with the same name is replaced.
+func uploadFile(ctx context.Context, client *graph.GraphServiceClient, senderCfg *config.SenderConfig, itemPath string, r io.Reader, size int64) (msmodels.DriveItemable, *sender.RequestError) {
+	if size < 0 {
+		return nil, &sender.RequestError{Code: http.StatusBadRequest, Message: "file size cannot be negative"}
+	}
+	baseURL := client.GetAdapter().GetBaseUrl()
+
+	if size <= simpleUploadLimit {
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, &sender.RequestError{Code: http.StatusBadRequest, Message: "reading file: " + err.Error()}
+		}
+		call := func(ctx context.Context) (sender.Response, error) {
+			return client.
+				Drives().
+				ByDriveId("").
+				Items().
+				ByDriveItemId("").
+				Content().
+				WithUrl(baseURL+itemPath+"/content").
+				Put(ctx, data, nil)
+		}
+		resp, err := sender.SendRequest(ctx, call, senderCfg)
+		if err != nil {
+			return nil, err
+		}
+		out, ok := resp.(msmodels.DriveItemable)
+		if !ok {
+			return nil, newTypeError("DriveItemable")
+		}
+		return out, nil
+	}
+
+	props := msmodels.NewDriveItemUploadableProperties()
+	props.SetAdditionalData(map[string]any{conflictBehaviorKey: "replace"})
+	body := graphdrives.NewItemItemsItemCreateUploadSessionPostRequestBody()
+	body.SetItem(props)
+	call := func(ctx context.Context) (sender.Response, error) {
+		return client.
+			Drives().
+			ByDriveId("").
+			Items().
+			ByDriveItemId("").
+			CreateUploadSession().
+			WithUrl(baseURL+itemPath+"/createUploadSession").
+			Post(ctx, body, nil)
+	}
+	resp, err := sender.SendRequest(ctx, call, senderCfg)
+	if err != nil {
+		return nil, err
+	}
+	session, ok := resp.(msmodels.UploadSessionable)
+	if !ok || session.GetUploadUrl() == nil {
+		return nil, newTypeError("UploadSessionable")
+	}
+
+	return uploadChunks(ctx, fileHTTPClient, *session.GetUploadUrl(), r, size, uploadChunkSize, senderCfg)
+}
+
+// chunkUploader sends the chunks of a file to an upload session.
+type chunkUploader struct {
+	httpClient *http.Client
+	uploadURL  string
+	size       int64
+	timeout    time.Duration
+	attempts   int
+	delay      time.Duration
+}
+
+// uploadChunks uploads r to an upload session chunk by chunk. Transient chunk failures are retried
+// as configured in senderCfg. The session is cancelled when an upload fails.
+func uploadChunks(ctx context.Context, httpClient *http.Client, uploadURL string, r io.Reader, size, chunkSize int64, senderCfg *config.SenderConfig) (msmodels.DriveItemable, *sender.RequestError) {
+	u := &chunkUploader{
+		httpClient: httpClient,
+		uploadURL:  uploadURL,
+		size:       size,
+		timeout:    time.Duration(senderCfg.Timeout) * time.Second,
+		attempts:   max(senderCfg.MaxRetries, 1),
+		delay:      time.Duration(senderCfg.NextRetryDelay) * time.Second,
+	}
+
+	buf := make([]byte, chunkSize)
+	for offset := int64(0); offset < size; {
+		n := min(chunkSize, size-offset)
+		if _, err := io.ReadFull(r, buf[:n]); err != nil {
+			u.cancel(ctx)
+			return nil, &sender.RequestError{Code: http.StatusBadRequest, Message: "reading file: " + err.Error()}
+		}
+
+		status, body, reqErr := u.put(ctx, buf[:n], offset)
+		if reqErr != nil {
+			u.cancel(ctx)
+			return nil, reqErr
+		}
+		offset += n
+
+		if status == http.StatusOK || status == http.StatusCreated {
+			if offset < size {
+				return nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: "upload session completed before the whole file was sent"}
+			}
+			return parseDriveItem(body)
+		}
+	}
+	return nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: "upload session did not return the uploaded file"}
+}
+
+// put sends chunk, which starts at offset in the file, retrying transient failures.
+// The upload continues from the next byte the session expects, so bytes it already received
+// are not sent again, both after a failure and after a chunk was received only in part.
+func (u *chunkUploader) put(ctx context.Context, chunk []byte, offset int64) (int, []byte, *sender.RequestError) {
+	end := offset + int64(len(chunk))
+	sent := offset
+	for attempt := 1; ; {
+		status, body, reqErr, transient := putChunk(ctx, u.httpClient, u.uploadURL, chunk[sent-offset:], sent, u.size, u.timeout)
+		if reqErr == nil {
+			if next, ok := nextExpectedOffset(body); ok && status == http.StatusAccepted && next > sent && next < end {
+				sent = next
+				continue
+			}
+			return status, body, nil
+		}
+		// A failure is not retried once the whole upload was cancelled or timed out.
+		if !transient || attempt >= u.attempts || ctx.Err() != nil {
+			return 0, nil, reqErr
+		}
+		attempt++
+
+		select {
+		case <-ctx.Done():
+			return 0, nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: ctx.Err().Error()}
+		case <-time.After(u.delay):
+		}
+
+		next, ok := u.nextExpected(ctx)
+		switch {
+		case !ok:
+		case next >= end:
+			return http.StatusAccepted, nil, nil
+		case next >= offset:
+			sent = next
+		default:
+			return 0, nil, &sender.RequestError{Code: http.StatusConflict, Message: fmt.Sprintf("upload session expects byte %d, which was already sent", next)}
+		}
+	}
+}
+
+// nextExpected asks the upload session for the next byte it expects.
+func (u *chunkUploader) nextExpected(ctx context.Context) (int64, bool) {
+	ctx, cancel := withOptionalTimeout(ctx, u.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.uploadURL, nil)
+	if err != nil {
+		return 0, false
+	}
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, false
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, false
+	}
+	return nextExpectedOffset(body)
+}
+
+// cancel deletes the upload session. It is sent even when ctx is already done, but never waits longer than cancelTimeout.
+func (u *chunkUploader) cancel(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.uploadURL, nil)
+	if err != nil {
+		return
+	}
+	if resp, err := u.httpClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// putChunk sends a chunk starting at offset. Transient reports whether the failure may pass when the chunk is sent again.
+func putChunk(ctx context.Context, httpClient *http.Client, uploadURL string, chunk []byte, offset, size int64, timeout time.Duration) (status int, body []byte, reqErr *sender.RequestError, transient bool) {
+	ctx, cancel := withOptionalTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(chunk))
+	if err != nil {
+		return 0, nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: err.Error()}, false
+	}
+	last := offset + int64(len(chunk)) - 1
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, last, size))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: err.Error()}, true
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, nil, newHTTPError(resp), resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	}
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: err.Error()}, true
+	}
+	return resp.StatusCode, body, nil, false
+}
+
+// nextExpectedOffset returns the start of the first range in nextExpectedRanges of an upload session response.
+func nextExpectedOffset(body []byte) (int64, bool) {
+	var session struct {
+		NextExpectedRanges []string `json:"nextExpectedRanges"`
+	}
+	if err := json.Unmarshal(body, &session); err != nil || len(session.NextExpectedRanges) == 0 {
+		return 0, false
+	}
+	start, _, _ := strings.Cut(session.NextExpectedRanges[0], "-")
+	next, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return next, true
+}
+
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func parseDriveItem(body []byte) (msmodels.DriveItemable, *sender.RequestError) {
+	node, err := jsonserialization.NewJsonParseNode(body)
+	if err != nil {
+		return nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	parsed, err := node.GetObjectValue(msmodels.CreateDriveItemFromDiscriminatorValue)
+	if err != nil {
+		return nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	out, ok := parsed.(msmodels.DriveItemable)
+	if !ok {
+		return nil, newTypeError("DriveItemable")
+	}
+	return out, nil
+}
+
+// openFile resolves a sharing or web URL of a file, e.g. the content URL of a reference attachment,
+// and opens its content. The caller must close the returned reader.
+func openFile(ctx context.Context, client *graph.GraphServiceClient, senderCfg *config.SenderConfig, contentURL string) (io.ReadCloser, *sender.RequestError) {
+	if contentURL == "" {
+		return nil, &sender.RequestError{Code: http.StatusBadRequest, Message: "file URL cannot be empty"}
+	}
+	itemURL := client.GetAdapter().GetBaseUrl() + "/shares/" + encodeSharingURL(contentURL) + "/driveItem"
+	call := func(ctx context.Context) (sender.Response, error) {
+		return client.
+			Shares().
+			BySharedDriveItemId("").
+			DriveItem().
+			WithUrl(itemURL).
+			Get(ctx, nil)
+	}
+	resp, err := sender.SendRequest(ctx, call, senderCfg)
+	if err != nil {
+		return nil, err
+	}
+	item, ok := resp.(msmodels.DriveItemable)
+	if !ok {
+		return nil, newTypeError("DriveItemable")
+	}
+	downloadURL, _ := item.GetAdditionalData()[downloadURLKey].(*string)
+	if downloadURL == nil || *downloadURL == "" {
+		return nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: "drive item has no download URL, it may be a folder"}
+	}
+	return openDownload(ctx, fileHTTPClient, *downloadURL)
+}
+
+func openDownload(ctx context.Context, httpClient *http.Client, downloadURL string) (io.ReadCloser, *sender.RequestError) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, &sender.RequestError{Code: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, newHTTPError(resp)
+	}
+	return resp.Body, nil
+}
+
+// encodeSharingURL encodes a URL as a sharing token accepted by the shares endpoint.
+func encodeSharingURL(u string) string {
+	return "u!" + base64.RawURLEncoding.EncodeToString([]byte(u))
+}
+
+func newHTTPError(resp *http.Response) *sender.RequestError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	msg := strings.TrimSpace(string(body))
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+	return &sender.RequestError{Code: resp.StatusCode, Message: msg}
+}
+
+func channelFileItemPath(driveID, folderID, name string) string {
+	return fmt.Sprintf("/drives/%s/items/%s:/%s:", url.PathEscape(driveID), url.PathEscape(folderID), url.PathEscape(name))
+}
+
+func chatFileItemPath(name string) string {
+	return fmt.Sprintf("/me/drive/root:/%s/%s:", url.PathEscape(chatFilesFolder), url.PathEscape(name))
+}
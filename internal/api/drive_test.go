@@ -0,0 +1,268 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	noRetries   = &config.SenderConfig{MaxRetries: 1, Timeout: 1}
+	withRetries = &config.SenderConfig{MaxRetries: 3, Timeout: 1}
+)
+
+type chunkRequest struct {
+	method       string
+	contentRange string
+	body         string
+}
+
+func newUploadServer(t *testing.T, respond func(i int, w http.ResponseWriter)) (*httptest.Server, func() []chunkRequest) {
+	t.Helper()
+
+	var (
+		mu   sync.Mutex
+		reqs []chunkRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, chunkRequest{method: r.Method, contentRange: r.Header.Get("Content-Range"), body: string(body)})
+		i := len(reqs) - 1
+		mu.Unlock()
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		respond(i, w)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []chunkRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]chunkRequest(nil), reqs...)
+	}
+}
+
+func TestUploadChunks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends chunks with ranges and parses uploaded item", func(t *testing.T) {
+		t.Parallel()
+
+		srv, requests := newUploadServer(t, func(i int, w http.ResponseWriter) {
+			if i < 2 {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"nextExpectedRanges":["0-"]}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"item-1","name":"report.txt","webUrl":"https://contoso/report.txt"}`))
+		})
+
+		item, reqErr := uploadChunks(context.Background(), srv.Client(), srv.URL, strings.NewReader("abcdefgh"), 8, 3, noRetries)
+		require.Nil(t, reqErr)
+		require.Equal(t, "item-1", *item.GetId())
+		require.Equal(t, "report.txt", *item.GetName())
+
+		require.Equal(t, []chunkRequest{
+			{method: http.MethodPut, contentRange: "bytes 0-2/8", body: "abc"},
+			{method: http.MethodPut, contentRange: "bytes 3-5/8", body: "def"},
+			{method: http.MethodPut, contentRange: "bytes 6-7/8", body: "gh"},
+		}, requests())
+	})
+
+	t.Run("failed chunk cancels session", func(t *testing.T) {
+		t.Parallel()
+
+		srv, requests := newUploadServer(t, func(_ int, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusInsufficientStorage)
+			_, _ = w.Write([]byte("quota exceeded"))
+		})
+
+		item, reqErr := uploadChunks(context.Background(), srv.Client(), srv.URL, strings.NewReader("abcdef"), 6, 3, noRetries)
+		require.Nil(t, item)
+		require.NotNil(t, reqErr)
+		require.Equal(t, http.StatusInsufficientStorage, reqErr.Code)
+		require.Equal(t, "quota exceeded", reqErr.Message)
+
+		reqs := requests()
+		require.Len(t, reqs, 2)
+		require.Equal(t, http.MethodDelete, reqs[1].method)
+	})
+
+	t.Run("short reader cancels session", func(t *testing.T) {
+		t.Parallel()
+
+		srv, requests := newUploadServer(t, func(_ int, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		_, reqErr := uploadChunks(context.Background(), srv.Client(), srv.URL, strings.NewReader("abcd"), 6, 3, noRetries)
+		require.NotNil(t, reqErr)
+		require.Equal(t, http.StatusBadRequest, reqErr.Code)
+
+		reqs := requests()
+		require.Len(t, reqs, 2)
+		require.Equal(t, http.MethodDelete, reqs[1].method)
+	})
+
+	t.Run("session never completes", func(t *testing.T) {
+		t.Parallel()
+
+		srv, _ := newUploadServer(t, func(_ int, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		_, reqErr := uploadChunks(context.Background(), srv.Client(), srv.URL, strings.NewReader("abc"), 3, 3, noRetries)
+		require.NotNil(t, reqErr)
+		require.Equal(t, http.StatusUnprocessableEntity, reqErr.Code)
+	})
+
+	t.Run("transient failure resumes from next expected byte", func(t *testing.T) {
+		t.Parallel()
+
+		srv, requests := newUploadServer(t, func(i int, w http.ResponseWriter) {
+			switch i {
+			case 1:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case 2:
+				_, _ = w.Write([]byte(`{"nextExpectedRanges":["4-7"]}`))
+			case 4:
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":"item-1"}`))
+			default:
+				w.WriteHeader(http.StatusAccepted)
+			}
+		})
+
+		item, reqErr := uploadChunks(context.Background(), srv.Client(), srv.URL, strings.NewReader("abcdefgh"), 8, 3, withRetries)
+		require.Nil(t, reqErr)
+		require.Equal(t, "item-1", *item.GetId())
+
+		require.Equal(t, []chunkRequest{
+			{method: http.MethodPut, contentRange: "bytes 0-2/8", body: "abc"},
+			{method: http.MethodPut, contentRange: "bytes 3-5/8", body: "def"},
+			{method: http.MethodGet, body: ""},
+			{method: http.MethodPut, contentRange: "bytes 4-5/8", body: "ef"},
+			{method: http.MethodPut, contentRange: "bytes 6-7/8", body: "gh"},
+		}, requests())
+	})
+
+	t.Run("partly received chunk is completed", func(t *testing.T) {
+		t.Parallel()
+
+		srv, requests := newUploadServer(t, func(i int, w http.ResponseWriter) {
+			if i == 0 {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{"nextExpectedRanges":["2-"]}`))
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"item-1"}`))
+		})
+
+		_, reqErr := uploadChunks(context.Background(), srv.Client(), srv.URL, strings.NewReader("abc"), 3, 3, noRetries)
+		require.Nil(t, reqErr)
+		require.Equal(t, []chunkRequest{
+			{method: http.MethodPut, contentRange: "bytes 0-2/3", body: "abc"},
+			{method: http.MethodPut, contentRange: "bytes 2-2/3", body: "c"},
+		}, requests())
+	})
+
+	t.Run("client error is not retried", func(t *testing.T) {
+		t.Parallel()
+
+		srv, requests := newUploadServer(t, func(_ int, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadRequest)
+		})
+
+		_, reqErr := uploadChunks(context.Background(), srv.Client(), srv.URL, strings.NewReader("abc"), 3, 3, withRetries)
+		require.NotNil(t, reqErr)
+		require.Equal(t, http.StatusBadRequest, reqErr.Code)
+
+		reqs := requests()
+		require.Len(t, reqs, 2)
+		require.Equal(t, http.MethodDelete, reqs[1].method)
+	})
+
+	t.Run("cancelled upload still cancels session", func(t *testing.T) {
+		t.Parallel()
+
+		srv, requests := newUploadServer(t, func(_ int, w http.ResponseWriter) {
+			w.WriteHeader(http.StatusAccepted)
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, reqErr := uploadChunks(ctx, srv.Client(), srv.URL, strings.NewReader("abc"), 3, 3, withRetries)
+		require.NotNil(t, reqErr)
+		require.Equal(t, []chunkRequest{{method: http.MethodDelete, body: ""}}, requests())
+	})
+}
+
+func TestOpenDownload(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("file content"))
+	}))
+	t.Cleanup(srv.Close)
+
+	body, reqErr := openDownload(context.Background(), srv.Client(), srv.URL+"/file")
+	require.Nil(t, reqErr)
+	defer body.Close()
+	var buf bytes.Buffer
+	_, err := io.Copy(&buf, body)
+	require.NoError(t, err)
+	require.Equal(t, "file content", buf.String())
+
+	_, reqErr = openDownload(context.Background(), srv.Client(), srv.URL+"/missing")
+	require.NotNil(t, reqErr)
+	require.Equal(t, http.StatusNotFound, reqErr.Code)
+}
+
+func TestEncodeSharingURL(t *testing.T) {
+	t.Parallel()
+
+	u := "https://contoso.sharepoint.com/sites/team/Shared Documents/General/report.txt"
+	got := encodeSharingURL(u)
+
+	require.True(t, strings.HasPrefix(got, "u!"))
+	require.NotContains(t, got, "=")
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(got, "u!"))
+	require.NoError(t, err)
+	require.Equal(t, u, string(decoded))
+}
+
+func TestValidateFileName(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, validateFileName("report 2025.txt"))
+	for _, name := range []string{"", "  ", ".", "..", "a/b.txt", `a\b.txt`, "a:b", "what?.txt"} {
+		reqErr := validateFileName(name)
+		require.NotNil(t, reqErr, name)
+		require.Equal(t, http.StatusBadRequest, reqErr.Code, name)
+	}
+}
+
+func TestFileItemPaths(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "/drives/b%21drive/items/folder-1:/my%20report.txt:", channelFileItemPath("b!drive", "folder-1", "my report.txt"))
+	require.Equal(t, "/me/drive/root:/Microsoft%20Teams%20Chat%20Files/a%23b.txt:", chatFileItemPath("a#b.txt"))
+}
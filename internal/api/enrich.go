@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
 	"golang.org/x/sync/errgroup"
@@ -10,6 +11,8 @@ import (
 	"github.com/pzsp-teams/lib/search"
 )
 
+const defaultSearchPageSize int32 = 25
+
 type entityFilter func(SearchEntity) bool
 
 type messageFetcher func(ctx context.Context, e SearchEntity) (msmodels.ChatMessageable, *sender.RequestError)
@@ -33,11 +36,39 @@ func cloneSearchOpts(in *search.SearchMessagesOptions) *search.SearchMessagesOpt
 	return &out
 }
 
+// pinTimeRange replaces the interval of the options with its exact range at now,
+// so all search pages requested for one result page cover the same range.
+func pinTimeRange(opts *search.SearchMessagesOptions, now time.Time) *sender.RequestError {
+	if opts.Interval == nil {
+		return nil
+	}
+	start, end, err := opts.TimeRange(now)
+	if err != nil {
+		return &sender.RequestError{Message: err.Error()}
+	}
+	opts.StartTime, opts.EndTime, opts.Interval = start, end, nil
+	return nil
+}
+
 type task struct {
 	idx int
 	e   SearchEntity
 }
 
+// SearchMessagesPage is a page of search hits enriched with full messages.
+type SearchMessagesPage struct {
+	Messages []*SearchMessage
+	// NextFrom is the offset of the first hit not consumed by this page.
+	NextFrom *int32
+	// Total is the number of hits reported by Graph, before hits outside the requested scope are dropped.
+	Total *int32
+	// MoreResultsAvailable reports whether hits after NextFrom exist.
+	MoreResultsAvailable bool
+}
+
+// enrichMessages fills a page of SearchPage.Size messages (25 by default), requesting further
+// /search/query pages while hits are dropped by keep or no longer exist, up to searchCfg.MaxPages requests.
+// In lite mode messages are not fetched; the partial messages from the search response are returned instead.
 func enrichMessages(
 	ctx context.Context,
 	searchAPI SearchAPI,
@@ -45,66 +76,153 @@ func enrichMessages(
 	keep entityFilter,
 	fetch messageFetcher,
 	searchCfg *search.SearchConfig,
-) ([]*SearchMessage, *sender.RequestError, *int32) {
+) (*SearchMessagesPage, *sender.RequestError) {
 	if searchCfg == nil {
 		searchCfg = search.DefaultSearchConfig()
 	}
 	localOpts := cloneSearchOpts(opts)
+	if reqErr := pinTimeRange(localOpts, time.Now()); reqErr != nil {
+		return nil, reqErr
+	}
+	want := searchPageSize(localOpts)
+	localOpts.SearchPage = &search.SearchPage{From: calcNextSearchFrom(localOpts, 0), Size: &want}
+
+	out := &SearchMessagesPage{Messages: []*SearchMessage{}, NextFrom: localOpts.SearchPage.From}
+	seen := make(map[string]struct{})
+	for range maxSearchPages(searchCfg) {
+		resp, reqErr := searchAPI.SearchMessages(ctx, localOpts)
+		if reqErr != nil {
+			return nil, reqErr
+		}
+		entities := extractMessages(resp)
+		total, more := extractHitsInfo(resp)
+		if out.Total == nil {
+			out.Total = total
+		}
 
-	resp, reqErr := searchAPI.SearchMessages(ctx, localOpts)
-	if reqErr != nil {
-		return nil, reqErr, nil
+		tasks, consumed := takeTasks(prepareTasks(entities, keep), seen, int(want)-len(out.Messages), len(entities))
+		if searchCfg.Lite {
+			out.Messages = append(out.Messages, liteMessages(tasks)...)
+		} else {
+			msgs, reqErr := fetchMessages(ctx, tasks, fetch, searchCfg.MaxWorkers)
+			if reqErr != nil {
+				return nil, reqErr
+			}
+			out.Messages = append(out.Messages, msgs...)
+		}
+
+		localOpts.SearchPage.From = calcNextSearchFrom(localOpts, consumed)
+		out.NextFrom = localOpts.SearchPage.From
+		out.MoreResultsAvailable = consumed < len(entities) || (more && len(entities) > 0)
+		if !out.MoreResultsAvailable || len(out.Messages) >= int(want) {
+			break
+		}
 	}
+	return out, nil
+}
 
-	entities := extractMessages(resp)
-	if len(entities) == 0 {
-		return []*SearchMessage{}, nil, nil
+func maxSearchPages(cfg *search.SearchConfig) int {
+	if cfg.MaxPages > 0 {
+		return cfg.MaxPages
 	}
+	return search.DefaultMaxPages
+}
 
-	tasks := prepareTasks(entities, keep)
+func searchPageSize(opts *search.SearchMessagesOptions) int32 {
+	if opts.SearchPage != nil && opts.SearchPage.Size != nil && *opts.SearchPage.Size > 0 {
+		return *opts.SearchPage.Size
+	}
+	return defaultSearchPageSize
+}
 
-	if len(tasks) == 0 {
-		nextFrom := calcNextSearchFrom(localOpts, len(entities))
-		return []*SearchMessage{}, nil, nextFrom
+// takeTasks takes up to need tasks not seen before and returns them together with
+// the number of hits of the page consumed by them.
+func takeTasks(tasks []task, seen map[string]struct{}, need, hits int) ([]task, int) {
+	taken := make([]task, 0, min(need, len(tasks)))
+	for _, t := range tasks {
+		if len(taken) == need {
+			return taken, t.idx
+		}
+		if _, ok := seen[*t.e.MessageID]; ok {
+			continue
+		}
+		seen[*t.e.MessageID] = struct{}{}
+		taken = append(taken, t)
 	}
+	return taken, hits
+}
 
+// fetchMessages fetches full messages of the tasks concurrently, preserving their order.
+// Messages deleted since they were indexed are skipped.
+func fetchMessages(ctx context.Context, tasks []task, fetch messageFetcher, maxWorkers int) ([]*SearchMessage, *sender.RequestError) {
+	return fetchAll(ctx, tasks, maxWorkers, func(ctx context.Context, t task) (*SearchMessage, *sender.RequestError) {
+		msg, err := fetch(ctx, t.e)
+		if err != nil {
+			return nil, err
+		}
+		return newSearchMessage(t.e, msg), nil
+	})
+}
+
+// fetchAll runs fetch for every item with at most maxWorkers calls at once, preserving the order of items.
+// Items which no longer exist (404) are skipped.
+func fetchAll[T, R any](ctx context.Context, items []T, maxWorkers int, fetch func(context.Context, T) (R, *sender.RequestError)) ([]R, *sender.RequestError) {
 	g, gctx := errgroup.WithContext(ctx)
-	g.SetLimit(searchCfg.MaxWorkers)
-	results := make([]*SearchMessage, len(entities))
+	g.SetLimit(maxWorkers)
+	results := make([]R, len(items))
+	found := make([]bool, len(items))
 
-	for _, t := range tasks {
+	for i, item := range items {
 		g.Go(func() error {
-			msg, err := fetch(gctx, t.e)
+			res, err := fetch(gctx, item)
 			if err != nil {
 				if err.StatusCode() == 404 {
 					return nil
 				}
 				return err
 			}
-			results[t.idx] = &SearchMessage{
-				Message:   msg,
-				ChannelID: t.e.ChannelID,
-				TeamID:    t.e.TeamID,
-				ChatID:    t.e.ChatID,
-			}
+			results[i], found[i] = res, true
 			return nil
 		})
 	}
 
 	if err := g.Wait(); err != nil {
 		if reqErr, ok := err.(*sender.RequestError); ok {
-			return nil, reqErr, nil
+			return nil, reqErr
+		}
+		return nil, &sender.RequestError{Message: err.Error()}
+	}
+	out := make([]R, 0, len(items))
+	for i, res := range results {
+		if found[i] {
+			out = append(out, res)
 		}
-		return nil, &sender.RequestError{Message: err.Error()}, nil
 	}
+	return out, nil
+}
+
+// liteMessages builds results from the partial messages of the search response.
+func liteMessages(tasks []task) []*SearchMessage {
 	out := make([]*SearchMessage, 0, len(tasks))
-	for _, sm := range results {
-		if sm != nil {
-			out = append(out, sm)
+	for _, t := range tasks {
+		msg := t.e.Resource
+		if msg == nil {
+			msg = msmodels.NewChatMessage()
+			msg.SetId(t.e.MessageID)
 		}
+		out = append(out, newSearchMessage(t.e, msg))
+	}
+	return out
+}
+
+func newSearchMessage(e SearchEntity, msg msmodels.ChatMessageable) *SearchMessage {
+	return &SearchMessage{
+		Message:   msg,
+		ChannelID: e.ChannelID,
+		TeamID:    e.TeamID,
+		ChatID:    e.ChatID,
+		Hit:       e.Hit,
 	}
-	nextFrom := calcNextSearchFrom(localOpts, len(entities))
-	return out, nil, nextFrom
 }
 
 func prepareTasks(entities []SearchEntity, keep entityFilter) []task {
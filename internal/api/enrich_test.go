@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	graphsearch "github.com/microsoftgraph/msgraph-sdk-go/search"
+	"github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSearchAPI serves hits from a fixed list, honouring the requested page.
+type fakeSearchAPI struct {
+	hits       []msmodels.SearchHitable
+	calls      []int32
+	entityType msmodels.EntityType
+	query      string
+	opts       []search.SearchMessagesOptions
+}
+
+func (f *fakeSearchAPI) SearchMessages(ctx context.Context, opts *search.SearchMessagesOptions) (graphsearch.QueryPostResponseable, *sender.RequestError) {
+	f.opts = append(f.opts, *opts)
+	return f.SearchEntities(ctx, msmodels.CHATMESSAGE_ENTITYTYPE, "", opts.SearchPage, opts.Sort)
+}
+
+func (f *fakeSearchAPI) SearchEntities(_ context.Context, entityType msmodels.EntityType, query string, page *search.SearchPage, _ search.SortOrder) (graphsearch.QueryPostResponseable, *sender.RequestError) {
+	from, size := util.Deref(page.From), util.Deref(page.Size)
+	f.calls = append(f.calls, from)
+	f.entityType, f.query = entityType, query
+
+	end := min(int(from+size), len(f.hits))
+	hc := newHitsContainer()
+	if int(from) < end {
+		hc.SetHits(f.hits[from:end])
+	}
+	hc.SetTotal(util.Ptr(int32(len(f.hits))))
+	hc.SetMoreResultsAvailable(util.Ptr(end < len(f.hits)))
+	return newQueryPostResponse(newSearchResponse(hc)), nil
+}
+
+func chatHits(ids ...string) []msmodels.SearchHitable {
+	out := make([]msmodels.SearchHitable, 0, len(ids))
+	for _, id := range ids {
+		chat := "chat-1"
+		if id[0] == 'x' {
+			chat = "chat-2"
+		}
+		out = append(out, newHit(newResource(id, map[string]any{"chatId": chat})))
+	}
+	return out
+}
+
+func TestEnrichMessages(t *testing.T) {
+	t.Parallel()
+
+	keep := func(e SearchEntity) bool { return *e.ChatID == "chat-1" }
+	fetch := func(_ context.Context, e SearchEntity) (msmodels.ChatMessageable, *sender.RequestError) {
+		if *e.MessageID == "gone" {
+			return nil, &sender.RequestError{Code: http.StatusNotFound}
+		}
+		m := msmodels.NewChatMessage()
+		m.SetId(e.MessageID)
+		return m, nil
+	}
+	ids := func(p *SearchMessagesPage) []string {
+		return util.MapSlices(p.Messages, func(m *SearchMessage) string { return *m.Message.GetId() })
+	}
+	page := func(from, size int32) *search.SearchMessagesOptions {
+		return &search.SearchMessagesOptions{SearchPage: &search.SearchPage{From: &from, Size: &size}}
+	}
+
+	tests := []struct {
+		name      string
+		hits      []msmodels.SearchHitable
+		opts      *search.SearchMessagesOptions
+		cfg       *search.SearchConfig
+		want      []string
+		wantNext  int32
+		wantMore  bool
+		wantCalls []int32
+	}{
+		{
+			name:      "fills the page across provider pages",
+			hits:      chatHits("x1", "x2", "m1", "x3", "gone", "m2", "m3", "m4"),
+			opts:      page(0, 2),
+			want:      []string{"m1", "m2"},
+			wantNext:  6,
+			wantMore:  true,
+			wantCalls: []int32{0, 2, 4, 5},
+		},
+		{
+			name:      "continues from next offset without repeating hits",
+			hits:      chatHits("x1", "x2", "m1", "x3", "gone", "m2", "m3", "m4"),
+			opts:      page(6, 2),
+			want:      []string{"m3", "m4"},
+			wantNext:  8,
+			wantMore:  false,
+			wantCalls: []int32{6},
+		},
+		{
+			name:      "stops when no more results are available",
+			hits:      chatHits("m1", "x1", "x2"),
+			opts:      page(0, 5),
+			want:      []string{"m1"},
+			wantNext:  3,
+			wantMore:  false,
+			wantCalls: []int32{0},
+		},
+		{
+			name:      "stops after max pages",
+			hits:      chatHits("x1", "x2", "x3", "x4", "m1"),
+			opts:      page(0, 1),
+			cfg:       &search.SearchConfig{MaxWorkers: 2, MaxPages: 2},
+			want:      []string{},
+			wantNext:  2,
+			wantMore:  true,
+			wantCalls: []int32{0, 1},
+		},
+		{
+			name:      "stops in the middle of a page once it is full",
+			hits:      chatHits("m1", "m2", "m3"),
+			opts:      page(0, 3),
+			cfg:       &search.SearchConfig{MaxWorkers: 2},
+			want:      []string{"m1", "m2", "m3"},
+			wantNext:  3,
+			wantMore:  false,
+			wantCalls: []int32{0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			api := &fakeSearchAPI{hits: tc.hits}
+			got, err := enrichMessages(context.Background(), api, tc.opts, keep, fetch, tc.cfg)
+			require.Nil(t, err)
+			require.Equal(t, tc.want, ids(got))
+			require.Equal(t, tc.wantNext, *got.NextFrom)
+			require.Equal(t, tc.wantMore, got.MoreResultsAvailable)
+			require.Equal(t, int32(len(tc.hits)), *got.Total)
+			require.Equal(t, tc.wantCalls, api.calls)
+		})
+	}
+}
+
+func TestEnrichMessages_PartialPageKeepsRemainingHits(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeSearchAPI{hits: chatHits("m1", "m2", "m3", "m4")}
+	fetch := func(_ context.Context, e SearchEntity) (msmodels.ChatMessageable, *sender.RequestError) {
+		m := msmodels.NewChatMessage()
+		m.SetId(e.MessageID)
+		return m, nil
+	}
+	from, size := int32(0), int32(3)
+	opts := &search.SearchMessagesOptions{SearchPage: &search.SearchPage{From: &from, Size: &size}}
+
+	got, err := enrichMessages(context.Background(), api, opts, nil, fetch, nil)
+	require.Nil(t, err)
+	require.Len(t, got.Messages, 3)
+	require.Equal(t, int32(3), *got.NextFrom)
+	require.True(t, got.MoreResultsAvailable)
+	require.Equal(t, int32(0), *opts.SearchPage.From, "caller options are not modified")
+}
+
+func TestEnrichMessages_Lite(t *testing.T) {
+	t.Parallel()
+
+	hits := chatHits("m1", "x1", "m2")
+	for i, h := range hits {
+		h.SetRank(util.Ptr(int32(i + 1)))
+		h.SetSummary(util.Ptr("<c0>hit</c0> " + *h.GetResource().GetId()))
+		h.SetHitId(h.GetResource().GetId())
+	}
+	api := &fakeSearchAPI{hits: hits}
+	fetch := func(context.Context, SearchEntity) (msmodels.ChatMessageable, *sender.RequestError) {
+		t.Fatal("lite mode must not fetch messages")
+		return nil, nil
+	}
+	keep := func(e SearchEntity) bool { return *e.ChatID == "chat-1" }
+
+	got, err := enrichMessages(context.Background(), api, &search.SearchMessagesOptions{}, keep, fetch, &search.SearchConfig{Lite: true})
+	require.Nil(t, err)
+	require.Len(t, got.Messages, 2)
+
+	second := got.Messages[1]
+	require.Equal(t, "m2", *second.Message.GetId())
+	require.Equal(t, "chat-1", *second.ChatID)
+	require.Equal(t, "<c0>hit</c0> m2", *second.Hit.Summary)
+	require.Equal(t, int32(3), *second.Hit.Rank)
+	require.Equal(t, "m2", *second.Hit.HitID)
+}
+
+func TestEnrichMessages_PinsIntervalAcrossPages(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeSearchAPI{hits: chatHits("m1", "x1", "x2", "m2")}
+	keep := func(e SearchEntity) bool { return *e.ChatID == "chat-1" }
+	opts := &search.SearchMessagesOptions{Interval: util.Ptr(search.ThisWeek), SearchPage: &search.SearchPage{Size: util.Ptr(int32(2))}}
+
+	got, err := enrichMessages(context.Background(), api, opts, keep, nil, &search.SearchConfig{Lite: true})
+	require.Nil(t, err)
+	require.Len(t, got.Messages, 2)
+	require.Len(t, api.opts, 2)
+	for _, o := range api.opts {
+		require.Nil(t, o.Interval)
+		require.Equal(t, api.opts[0].StartTime, o.StartTime)
+		require.Equal(t, api.opts[0].EndTime, o.EndTime)
+	}
+	require.Equal(t, time.Monday, api.opts[0].StartTime.Weekday())
+	require.Equal(t, time.UTC, api.opts[0].StartTime.Location())
+	require.NotNil(t, opts.Interval, "caller options must not change")
+}
+
+func TestNewSortProperty(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, newSortProperty(search.SortByRelevance))
+
+	asc := newSortProperty(search.SortByCreatedAsc)
+	require.Equal(t, "createdDateTime", *asc.GetName())
+	require.False(t, *asc.GetIsDescending())
+
+	desc := newSortProperty(search.SortByCreatedDesc)
+	require.Equal(t, "createdDateTime", *desc.GetName())
+	require.True(t, *desc.GetIsDescending())
+}
+
+func TestAddMeToOpts(t *testing.T) {
+	t.Parallel()
+
+	opts := &search.SearchMessagesOptions{
+		FromMe:  true,
+		NotToMe: true,
+		From:    []string{"alice@example.com"},
+		NotTo:   make([]string, 0, 4),
+	}
+
+	for range 2 {
+		got := addMeToOpts(opts, "me@example.com")
+		require.Equal(t, []string{"alice@example.com", "me@example.com"}, got.From)
+		require.Equal(t, []string{"me@example.com"}, got.NotTo)
+		require.Empty(t, got.NotFrom)
+		require.Empty(t, got.To)
+	}
+	require.Equal(t, []string{"alice@example.com"}, opts.From)
+	require.Empty(t, opts.NotTo)
+
+	got := addMeToOpts(opts, "")
+	require.Equal(t, opts.From, got.From)
+	require.Empty(t, got.NotTo)
+}
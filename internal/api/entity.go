@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	graph "github.com/microsoftgraph/msgraph-sdk-go"
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	graphsearch "github.com/microsoftgraph/msgraph-sdk-go/search"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+)
+
+// EntityHit is a search hit of a file, person or event.
+type EntityHit[T any] struct {
+	Item T
+	Hit  SearchHit
+}
+
+// EntityPage is a page of entity search hits.
+type EntityPage[T any] struct {
+	Hits                 []*EntityHit[T]
+	NextFrom             *int32
+	Total                *int32
+	MoreResultsAvailable bool
+}
+
+// EntityAPI searches entities other than chat messages.
+type EntityAPI interface {
+	// SearchFiles searches drive items, e.g. files shared in channels.
+	SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*EntityPage[msmodels.DriveItemable], *sender.RequestError)
+	// SearchPeople searches people relevant to the current user.
+	SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*EntityPage[msmodels.Personable], *sender.RequestError)
+	// SearchEvents searches events of the current user's calendar.
+	SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*EntityPage[msmodels.Eventable], *sender.RequestError)
+}
+
+type entityAPI struct {
+	client    *graph.GraphServiceClient
+	senderCfg *config.SenderConfig
+	searchAPI SearchAPI
+}
+
+func NewEntity(client *graph.GraphServiceClient, senderCfg *config.SenderConfig, searchAPI SearchAPI) EntityAPI {
+	return &entityAPI{client: client, senderCfg: senderCfg, searchAPI: searchAPI}
+}
+
+func (e *entityAPI) SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*EntityPage[msmodels.DriveItemable], *sender.RequestError) {
+	return searchEntities(ctx, e.searchAPI, msmodels.DRIVEITEM_ENTITYTYPE, opts, searchConfig, e.getDriveItem)
+}
+
+func (e *entityAPI) SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*EntityPage[msmodels.Personable], *sender.RequestError) {
+	return searchEntities[msmodels.Personable](ctx, e.searchAPI, msmodels.PERSON_ENTITYTYPE, opts, searchConfig, nil)
+}
+
+func (e *entityAPI) SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*EntityPage[msmodels.Eventable], *sender.RequestError) {
+	return searchEntities(ctx, e.searchAPI, msmodels.EVENT_ENTITYTYPE, opts, searchConfig, e.getEvent)
+}
+
+func (e *entityAPI) getDriveItem(ctx context.Context, item msmodels.DriveItemable) (msmodels.DriveItemable, *sender.RequestError) {
+	ref := item.GetParentReference()
+	if item.GetId() == nil || ref == nil || ref.GetDriveId() == nil {
+		return item, nil
+	}
+	call := func(ctx context.Context) (sender.Response, error) {
+		return e.client.Drives().ByDriveId(*ref.GetDriveId()).Items().ByDriveItemId(*item.GetId()).Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, e.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.DriveItemable)
+	if !ok {
+		return nil, newTypeError("DriveItemable")
+	}
+	return out, nil
+}
+
+func (e *entityAPI) getEvent(ctx context.Context, item msmodels.Eventable) (msmodels.Eventable, *sender.RequestError) {
+	if item.GetId() == nil {
+		return item, nil
+	}
+	call := func(ctx context.Context) (sender.Response, error) {
+		return e.client.Me().Events().ByEventId(*item.GetId()).Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, e.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.Eventable)
+	if !ok {
+		return nil, newTypeError("Eventable")
+	}
+	return out, nil
+}
+
+// searchEntities runs a single search page for the entity type. Unless in lite mode, entities are
+// replaced with their full versions fetched concurrently; entities which no longer exist are skipped.
+func searchEntities[T any](
+	ctx context.Context,
+	searchAPI SearchAPI,
+	entityType msmodels.EntityType,
+	opts *search.SearchEntitiesOptions,
+	searchCfg *search.SearchConfig,
+	fetch func(ctx context.Context, item T) (T, *sender.RequestError),
+) (*EntityPage[T], *sender.RequestError) {
+	if searchCfg == nil {
+		searchCfg = search.DefaultSearchConfig()
+	}
+	queryString, err := entityQueryString(opts)
+	if err != nil {
+		return nil, &sender.RequestError{Message: err.Error()}
+	}
+	resp, reqErr := searchAPI.SearchEntities(ctx, entityType, queryString, opts.SearchPage, search.SortByRelevance)
+	if reqErr != nil {
+		return nil, reqErr
+	}
+	hits, returned := extractEntityHits[T](resp)
+	total, more := extractHitsInfo(resp)
+
+	if fetch != nil && !searchCfg.Lite {
+		hits, reqErr = fetchAll(ctx, hits, searchCfg.MaxWorkers, func(ctx context.Context, h *EntityHit[T]) (*EntityHit[T], *sender.RequestError) {
+			item, err := fetch(ctx, h.Item)
+			if err != nil {
+				return nil, err
+			}
+			return &EntityHit[T]{Item: item, Hit: h.Hit}, nil
+		})
+		if reqErr != nil {
+			return nil, reqErr
+		}
+	}
+
+	return &EntityPage[T]{
+		Hits:                 hits,
+		NextFrom:             calcNextSearchFrom(&search.SearchMessagesOptions{SearchPage: opts.SearchPage}, returned),
+		Total:                total,
+		MoreResultsAvailable: more && returned > 0,
+	}, nil
+}
+
+func entityQueryString(opts *search.SearchEntitiesOptions) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+	query := strings.TrimSpace(util.Deref(opts.Query))
+	if opts.Filter == nil {
+		return query, nil
+	}
+	filter, _ := search.Compile(opts.Filter)
+	if query == "" {
+		return filter, nil
+	}
+	return query + " (" + filter + ")", nil
+}
+
+// extractEntityHits returns hits whose resource is of type T, with the number of all returned hits.
+func extractEntityHits[T any](resp graphsearch.QueryPostResponseable) ([]*EntityHit[T], int) {
+	var out []*EntityHit[T]
+	returned := 0
+	if resp == nil {
+		return nil, 0
+	}
+	for _, sr := range resp.GetValue() {
+		if sr == nil {
+			continue
+		}
+		for _, hc := range sr.GetHitsContainers() {
+			if hc == nil {
+				continue
+			}
+			for _, hit := range hc.GetHits() {
+				if hit == nil {
+					continue
+				}
+				returned++
+				item, ok := hit.GetResource().(T)
+				if !ok {
+					continue
+				}
+				out = append(out, &EntityHit[T]{
+					Item: item,
+					Hit: SearchHit{
+						HitID:   hit.GetHitId(),
+						Rank:    hit.GetRank(),
+						Summary: hit.GetSummary(),
+					},
+				})
+			}
+		}
+	}
+	return out, returned
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+)
+
+func eventHits(ids ...string) []msmodels.SearchHitable {
+	out := make([]msmodels.SearchHitable, 0, len(ids))
+	for _, id := range ids {
+		ev := msmodels.NewEvent()
+		ev.SetId(util.Ptr(id))
+		h := newHit(ev)
+		h.SetHitId(util.Ptr("hit-" + id))
+		out = append(out, h)
+	}
+	return out
+}
+
+func TestSearchEntities(t *testing.T) {
+	t.Parallel()
+
+	page := &search.SearchPage{From: util.Ptr(int32(1)), Size: util.Ptr(int32(2))}
+	hits := append(eventHits("e1", "e2", "e3"), newHit(newResource("m1", nil)))
+
+	tests := []struct {
+		name      string
+		opts      *search.SearchEntitiesOptions
+		cfg       *search.SearchConfig
+		wantQuery string
+		wantIDs   []string
+		wantNext  int32
+	}{
+		{
+			name:      "fetches full entities and skips missing ones",
+			opts:      &search.SearchEntitiesOptions{Query: util.Ptr(" standup "), SearchPage: page},
+			cfg:       search.DefaultSearchConfig(),
+			wantQuery: "standup",
+			wantIDs:   []string{"e2"},
+			wantNext:  3,
+		},
+		{
+			name:      "lite mode keeps partial entities",
+			opts:      &search.SearchEntitiesOptions{Query: util.Ptr("standup"), Filter: search.Subject("Daily"), SearchPage: page},
+			cfg:       &search.SearchConfig{MaxWorkers: 1, Lite: true},
+			wantQuery: `standup (Subject:"Daily")`,
+			wantIDs:   []string{"e2", "e3"},
+			wantNext:  3,
+		},
+		{
+			name:      "filter only, hits of other types are dropped",
+			opts:      &search.SearchEntitiesOptions{Filter: search.Term("x"), SearchPage: &search.SearchPage{From: util.Ptr(int32(2)), Size: util.Ptr(int32(5))}},
+			cfg:       &search.SearchConfig{MaxWorkers: 1, Lite: true},
+			wantQuery: "x",
+			wantIDs:   []string{"e3"},
+			wantNext:  4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fake := &fakeSearchAPI{hits: hits}
+			fetch := func(_ context.Context, ev msmodels.Eventable) (msmodels.Eventable, *sender.RequestError) {
+				if *ev.GetId() == "e3" {
+					return nil, &sender.RequestError{Code: http.StatusNotFound}
+				}
+				full := msmodels.NewEvent()
+				full.SetId(ev.GetId())
+				full.SetSubject(util.Ptr("Daily"))
+				return full, nil
+			}
+
+			got, err := searchEntities(context.Background(), fake, msmodels.EVENT_ENTITYTYPE, tc.opts, tc.cfg, fetch)
+			require.Nil(t, err)
+			require.Equal(t, msmodels.EVENT_ENTITYTYPE, fake.entityType)
+			require.Equal(t, tc.wantQuery, fake.query)
+
+			var ids []string
+			for _, h := range got.Hits {
+				ids = append(ids, *h.Item.GetId())
+				require.Equal(t, "hit-"+*h.Item.GetId(), *h.Hit.HitID)
+			}
+			require.Equal(t, tc.wantIDs, ids)
+			require.Equal(t, tc.wantNext, *got.NextFrom)
+			require.Equal(t, int32(len(hits)), *got.Total)
+		})
+	}
+}
+
+func TestSearchEntities_InvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeSearchAPI{}
+	opts := &search.SearchEntitiesOptions{Filter: search.And()}
+	got, err := searchEntities[msmodels.Personable](context.Background(), fake, msmodels.PERSON_ENTITYTYPE, opts, nil, nil)
+	require.Nil(t, got)
+	require.NotNil(t, err)
+	require.Empty(t, fake.calls)
+}
@@ -58,6 +58,59 @@ func messageToGraph(content, contentType string) msmodels.ItemBodyable {
 	return body
 }
 
+// newGraphMessage builds a message to post, validating its subject and importance.
+// Subject is only allowed on top-level channel posts.
+func newGraphMessage(content, contentType, subject, importance string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable, hostedContents []msmodels.ChatMessageHostedContentable, allowSubject bool) (msmodels.ChatMessageable, *sender.RequestError) {
+	message := msmodels.NewChatMessage()
+	message.SetBody(messageToGraph(content, contentType))
+	if len(mentions) > 0 {
+		message.SetMentions(mentions)
+	}
+	if len(attachments) > 0 {
+		message.SetAttachments(attachments)
+	}
+	if len(hostedContents) > 0 {
+		message.SetHostedContents(hostedContents)
+	}
+
+	if subject != "" {
+		if !allowSubject {
+			return nil, &sender.RequestError{Code: http.StatusBadRequest, Message: "subject is only supported on channel posts"}
+		}
+		message.SetSubject(&subject)
+	}
+
+	switch importance {
+	case "":
+	case "normal", "high", "urgent":
+		parsed, err := msmodels.ParseChatMessageImportance(importance)
+		if err != nil {
+			return nil, &sender.RequestError{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		message.SetImportance(parsed.(*msmodels.ChatMessageImportance))
+	default:
+		return nil, &sender.RequestError{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid importance %q: expected normal, high or urgent", importance)}
+	}
+
+	return message, nil
+}
+
+// messageUpdateToGraph builds a PATCH payload replacing the body, mentions and attachments of a message.
+func messageUpdateToGraph(content, contentType string, mentions []msmodels.ChatMessageMentionable, attachments []msmodels.ChatMessageAttachmentable) msmodels.ChatMessageable {
+	message := msmodels.NewChatMessage()
+	message.SetBody(messageToGraph(content, contentType))
+	// Empty lists are sent too, so mentions and attachments of the previous body are dropped.
+	if mentions == nil {
+		mentions = []msmodels.ChatMessageMentionable{}
+	}
+	message.SetMentions(mentions)
+	if attachments == nil {
+		attachments = []msmodels.ChatMessageAttachmentable{}
+	}
+	message.SetAttachments(attachments)
+	return message
+}
+
 func newTypeError(expected string) *sender.RequestError {
 	return &sender.RequestError{
 		Code:    http.StatusUnprocessableEntity,
@@ -76,7 +129,10 @@ func isSystemEvent(m msmodels.ChatMessageable) bool {
 }
 
 func filterOutSystemEvents(messages msmodels.ChatMessageCollectionResponseable) []msmodels.ChatMessageable {
-	vals := messages.GetValue()
+	return dropSystemEvents(messages.GetValue())
+}
+
+func dropSystemEvents(vals []msmodels.ChatMessageable) []msmodels.ChatMessageable {
 	if vals == nil {
 		return nil
 	}
@@ -90,6 +146,10 @@ func filterOutSystemEvents(messages msmodels.ChatMessageCollectionResponseable)
 	return filtered
 }
 
+func modifiedSinceFilter(since time.Time) string {
+	return "lastModifiedDateTime gt " + since.UTC().Format(time.RFC3339Nano)
+}
+
 var (
 	reTeamIDQuoted = regexp.MustCompile(`/teams\('([^']+)'\)`)
 	reTeamIDSlash  = regexp.MustCompile(`/teams/([^/?]+)`)
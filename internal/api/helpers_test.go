@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	abstractions "github.com/microsoft/kiota-abstractions-go"
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
@@ -185,6 +186,54 @@ func TestMessageToGraph(t *testing.T) {
 	}
 }
 
+func TestNewGraphMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		subject        string
+		importance     string
+		allowSubject   bool
+		wantErr        bool
+		wantSubject    *string
+		wantImportance *msmodels.ChatMessageImportance
+	}{
+		{name: "plain message", allowSubject: true},
+		{
+			name:           "channel post with subject and urgency",
+			subject:        "Incident",
+			importance:     "urgent",
+			allowSubject:   true,
+			wantSubject:    util.Ptr("Incident"),
+			wantImportance: util.Ptr(msmodels.URGENT_CHATMESSAGEIMPORTANCE),
+		},
+		{name: "high importance", importance: "high", wantImportance: util.Ptr(msmodels.HIGH_CHATMESSAGEIMPORTANCE)},
+		{name: "subject where not allowed", subject: "Incident", wantErr: true},
+		{name: "unknown importance", importance: "critical", allowSubject: true, wantErr: true},
+		{name: "future importance value", importance: "unknownFutureValue", allowSubject: true, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newGraphMessage("hi", "text", tc.subject, tc.importance, nil, nil, nil, tc.allowSubject)
+			if tc.wantErr {
+				require.Nil(t, got)
+				require.NotNil(t, err)
+				require.Equal(t, http.StatusBadRequest, err.Code)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, "hi", *got.GetBody().GetContent())
+			require.Equal(t, tc.wantSubject, got.GetSubject())
+			require.Equal(t, tc.wantImportance, got.GetImportance())
+			require.Nil(t, got.GetMentions())
+		})
+	}
+}
+
 func TestNewTypeError(t *testing.T) {
 	t.Parallel()
 
@@ -804,3 +853,10 @@ func TestMessageToGraph_ContentTypeIsCaseSensitive(t *testing.T) {
 	require.NotNil(t, body.GetContentType())
 	require.Equal(t, msmodels.TEXT_BODYTYPE, *body.GetContentType())
 }
+
+func TestModifiedSinceFilter(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2025, 3, 4, 5, 6, 7, 800000000, time.FixedZone("CET", 3600))
+	require.Equal(t, "lastModifiedDateTime gt 2025-03-04T04:06:07.8Z", modifiedSinceFilter(since))
+}
@@ -9,14 +9,23 @@ import (
 )
 
 // ParseQuery constructs the final search query string based on the provided options.
-func ParseQuery(s *search.SearchMessagesOptions) string {
+// Interval is resolved to an exact range at now, in the time zone of the options.
+// It fails when the filter expression or the interval of the options is invalid.
+func ParseQuery(s *search.SearchMessagesOptions, now time.Time) (string, error) {
 	query := util.Deref(s.Query)
+	if s.Filter != nil {
+		filter, err := search.Compile(s.Filter)
+		if err != nil {
+			return "", err
+		}
+		query += " (" + filter + ")"
+	}
 
 	if len(s.From) > 0 {
-		query += ` from:("` + strings.Join(s.From, `" OR "`) + `")`
+		query += ` from:(` + quoteAll(s.From) + `)`
 	}
 	if len(s.NotFrom) > 0 {
-		query += ` NOT from:("` + strings.Join(s.NotFrom, `" OR "`) + `")`
+		query += ` NOT from:(` + quoteAll(s.NotFrom) + `)`
 	}
 	if s.IsRead != nil {
 		if *s.IsRead {
@@ -33,25 +42,29 @@ func ParseQuery(s *search.SearchMessagesOptions) string {
 		}
 	}
 	if len(s.To) > 0 {
-		query += ` to:("` + strings.Join(s.To, `" OR "`) + `")`
+		query += ` to:(` + quoteAll(s.To) + `)`
 	}
 	if len(s.NotTo) > 0 {
-		query += ` NOT to:("` + strings.Join(s.NotTo, `" OR "`) + `")`
+		query += ` NOT to:(` + quoteAll(s.NotTo) + `)`
 	}
-	if s.Interval != nil {
-		query += ` sent:` + `"` + string(*s.Interval) + `"`
-		return query
+	start, end, err := s.TimeRange(now)
+	if err != nil {
+		return "", err
 	}
-	if s.StartTime != nil && s.EndTime != nil {
-		query += ` sent:` + `"` + s.StartTime.Format(time.RFC3339) + `..` + s.EndTime.Format(time.RFC3339) + `"`
-		return query
+	if start != nil && end != nil {
+		query += ` sent:` + `"` + start.Format(time.RFC3339) + `..` + end.Format(time.RFC3339) + `"`
+		return query, nil
 	}
-	if s.StartTime != nil {
-		query += ` sent>=` + `"` + s.StartTime.Format(time.RFC3339) + `"`
+	if start != nil {
+		query += ` sent>=` + `"` + start.Format(time.RFC3339) + `"`
 	}
-	if s.EndTime != nil {
-		query += ` sent<=` + `"` + s.EndTime.Format(time.RFC3339) + `"`
+	if end != nil {
+		query += ` sent<=` + `"` + end.Format(time.RFC3339) + `"`
 	}
 
-	return query
+	return query, nil
+}
+
+func quoteAll(values []string) string {
+	return strings.Join(util.MapSlices(values, search.Quote), " OR ")
 }
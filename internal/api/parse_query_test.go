@@ -21,6 +21,9 @@ func TestSearchMessagesOptions_ParseQuery_TableDriven(t *testing.T) {
 
 	start := mustTimeRFC3339(t, "2026-01-09T00:00:00Z")
 	end := mustTimeRFC3339(t, "2026-01-10T23:59:59Z")
+	now := mustTimeRFC3339(t, "2026-01-14T23:30:00Z")
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
 
 	tests := []struct {
 		name string
@@ -97,7 +100,7 @@ func TestSearchMessagesOptions_ParseQuery_TableDriven(t *testing.T) {
 			want: ` to:("x@x.com") NOT to:("y@y.com" OR "z@z.com")`,
 		},
 		{
-			name: "interval has priority and returns immediately",
+			name: "interval has priority and is resolved at now",
 			in: search.SearchMessagesOptions{
 				Query:     util.Ptr("foo"),
 				From:      []string{"a@x.com"},
@@ -105,7 +108,23 @@ func TestSearchMessagesOptions_ParseQuery_TableDriven(t *testing.T) {
 				StartTime: &start,
 				EndTime:   &end,
 			},
-			want: `foo from:("a@x.com") sent:"today"`,
+			want: `foo from:("a@x.com") sent:"2026-01-14T00:00:00Z..2026-01-15T00:00:00Z"`,
+		},
+		{
+			name: "interval is resolved in the time zone",
+			in: search.SearchMessagesOptions{
+				Interval: util.Ptr(search.Today),
+				TimeZone: warsaw,
+			},
+			want: ` sent:"2026-01-15T00:00:00+01:00..2026-01-16T00:00:00+01:00"`,
+		},
+		{
+			name: "start is sent in the time zone",
+			in: search.SearchMessagesOptions{
+				StartTime: &start,
+				TimeZone:  warsaw,
+			},
+			want: ` sent>="2026-01-09T01:00:00+01:00"`,
 		},
 		{
 			name: "start and end -> sent range and returns immediately",
@@ -147,13 +166,37 @@ func TestSearchMessagesOptions_ParseQuery_TableDriven(t *testing.T) {
 			},
 			want: `foo from:("a@x.com" OR "b@x.com") NOT from:("c@x.com") IsRead:"true" IsMentioned:"false" to:("t@x.com") NOT to:("nt1@x.com" OR "nt2@x.com") sent:"2026-01-09T00:00:00Z..2026-01-10T23:59:59Z"`,
 		},
+		{
+			name: "values with quotes are escaped",
+			in: search.SearchMessagesOptions{
+				From: []string{`a"b@x.com`},
+			},
+			want: ` from:("a\"b@x.com")`,
+		},
+		{
+			name: "filter is ANDed in parentheses",
+			in: search.SearchMessagesOptions{
+				Query:  util.Ptr("foo"),
+				Filter: search.Or(search.Term("a"), search.HasAttachment(true)),
+				IsRead: util.Ptr(true),
+			},
+			want: `foo (a OR HasAttachment:"true") IsRead:"true"`,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := ParseQuery(&tt.in)
+			got, err := ParseQuery(&tt.in, now)
+			require.NoError(t, err)
 			require.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func TestParseQuery_InvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseQuery(&search.SearchMessagesOptions{Filter: search.And()}, time.Now())
+	require.ErrorIs(t, err, search.ErrInvalidQuery)
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"strings"
+	"sync"
+	"time"
 
 	graph "github.com/microsoftgraph/msgraph-sdk-go"
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/pzsp-teams/lib/config"
 	"github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/util"
 	"github.com/pzsp-teams/lib/search"
 )
 
@@ -18,6 +21,16 @@ type SearchEntity struct {
 	TeamID    *string
 	MessageID *string
 	ChatID    *string
+	Hit       SearchHit
+	// Resource is the partial message returned in the search response.
+	Resource msmodels.ChatMessageable
+}
+
+// SearchHit holds the search metadata of a hit.
+type SearchHit struct {
+	HitID   *string
+	Rank    *int32
+	Summary *string
 }
 
 type SearchMessage struct {
@@ -25,16 +38,23 @@ type SearchMessage struct {
 	ChannelID *string
 	TeamID    *string
 	ChatID    *string
+	Hit       SearchHit
 }
 
 type SearchAPI interface {
 	// SearchMessages runs POST /search/query with entityTypes=["chatMessage"].
 	SearchMessages(ctx context.Context, searchRequest *search.SearchMessagesOptions) (graphsearch.QueryPostResponseable, *sender.RequestError)
+	// SearchEntities runs POST /search/query for a single entity type with a ready query string.
+	SearchEntities(ctx context.Context, entityType msmodels.EntityType, queryString string, page *search.SearchPage, sort search.SortOrder) (graphsearch.QueryPostResponseable, *sender.RequestError)
 }
 
 type searchAPI struct {
 	client    *graph.GraphServiceClient
 	senderCfg *config.SenderConfig
+
+	meMu sync.Mutex
+	// me is the principal name of the signed-in user, resolved on the first search about "me".
+	me *string
 }
 
 func NewSearch(client *graph.GraphServiceClient, senderCfg *config.SenderConfig) SearchAPI {
@@ -42,33 +62,48 @@ func NewSearch(client *graph.GraphServiceClient, senderCfg *config.SenderConfig)
 }
 
 func (s *searchAPI) SearchMessages(ctx context.Context, searchRequest *search.SearchMessagesOptions) (graphsearch.QueryPostResponseable, *sender.RequestError) {
+	if err := searchRequest.Validate(); err != nil {
+		return nil, &sender.RequestError{Message: err.Error()}
+	}
+	if searchRequest.NotFromMe || searchRequest.NotToMe || searchRequest.FromMe || searchRequest.ToMe {
+		me, err := s.principalName(ctx)
+		if err != nil {
+			return nil, err
+		}
+		searchRequest = addMeToOpts(searchRequest, me)
+	}
+
+	parsed, err := ParseQuery(searchRequest, time.Now())
+	if err != nil {
+		return nil, &sender.RequestError{Message: err.Error()}
+	}
+	return s.SearchEntities(ctx, msmodels.CHATMESSAGE_ENTITYTYPE, strings.TrimSpace(parsed), searchRequest.SearchPage, searchRequest.Sort)
+}
+
+func (s *searchAPI) SearchEntities(ctx context.Context, entityType msmodels.EntityType, queryString string, page *search.SearchPage, sort search.SortOrder) (graphsearch.QueryPostResponseable, *sender.RequestError) {
 	call := func(ctx context.Context) (sender.Response, error) {
 		body := graphsearch.NewQueryPostRequestBody()
 
 		req := msmodels.NewSearchRequest()
-		req.SetEntityTypes([]msmodels.EntityType{msmodels.CHATMESSAGE_ENTITYTYPE})
+		req.SetEntityTypes([]msmodels.EntityType{entityType})
 
 		q := msmodels.NewSearchQuery()
-		queryString := strings.TrimSpace(ParseQuery(searchRequest))
 		q.SetQueryString(&queryString)
 		req.SetQuery(q)
 
-		if searchRequest.SearchPage != nil && searchRequest.SearchPage.From != nil {
-			req.SetFrom(searchRequest.SearchPage.From)
+		if page != nil && page.From != nil {
+			req.SetFrom(page.From)
+		}
+		if page != nil && page.Size != nil {
+			req.SetSize(page.Size)
 		}
-		if searchRequest.SearchPage != nil && searchRequest.SearchPage.Size != nil {
-			req.SetSize(searchRequest.SearchPage.Size)
+		if sortProperty := newSortProperty(sort); sortProperty != nil {
+			req.SetSortProperties([]msmodels.SortPropertyable{sortProperty})
 		}
 
 		body.SetRequests([]msmodels.SearchRequestable{req})
 		return s.client.Search().Query().PostAsQueryPostResponse(ctx, body, nil)
 	}
-	if searchRequest.NotFromMe || searchRequest.NotToMe || searchRequest.FromMe || searchRequest.ToMe {
-		err := s.addMeToOpts(ctx, searchRequest)
-		if err != nil {
-			return nil, err
-		}
-	}
 
 	resp, err := sender.SendRequest(ctx, call, s.senderCfg)
 	if err != nil {
@@ -82,30 +117,55 @@ func (s *searchAPI) SearchMessages(ctx context.Context, searchRequest *search.Se
 	return out, nil
 }
 
-func (s *searchAPI) addMeToOpts(ctx context.Context, opts *search.SearchMessagesOptions) *sender.RequestError {
-	me, err := GetMe(ctx, s.client, s.senderCfg)
-	if err != nil {
-		return err
+// newSortProperty maps the sort order to a Graph sort property; relevance order needs none.
+func newSortProperty(sort search.SortOrder) msmodels.SortPropertyable {
+	if sort != search.SortByCreatedAsc && sort != search.SortByCreatedDesc {
+		return nil
 	}
-	if opts.NotFromMe {
+	descending := sort == search.SortByCreatedDesc
+	p := msmodels.NewSortProperty()
+	p.SetName(util.Ptr("createdDateTime"))
+	p.SetIsDescending(&descending)
+	return p
+}
+
+// principalName returns the principal name of the signed-in user, fetching it only once.
+func (s *searchAPI) principalName(ctx context.Context) (string, *sender.RequestError) {
+	s.meMu.Lock()
+	defer s.meMu.Unlock()
+
+	if s.me == nil {
+		me, err := GetMe(ctx, s.client, s.senderCfg)
+		if err != nil {
+			return "", err
+		}
+		upn := ""
 		if me != nil && me.GetUserPrincipalName() != nil {
-			opts.NotFrom = append(opts.NotFrom, strings.TrimSpace(*me.GetUserPrincipalName()))
+			upn = strings.TrimSpace(*me.GetUserPrincipalName())
 		}
+		s.me = &upn
+	}
+	return *s.me, nil
+}
+
+// addMeToOpts returns a copy of opts with "me" filters turned into filters on the user me.
+// The caller's opts are left untouched, so they can be reused for the next search page.
+func addMeToOpts(opts *search.SearchMessagesOptions, me string) *search.SearchMessagesOptions {
+	out := cloneSearchOpts(opts)
+	if me == "" {
+		return out
+	}
+	if opts.NotFromMe {
+		out.NotFrom = append(out.NotFrom, me)
 	}
 	if opts.NotToMe {
-		if me != nil && me.GetUserPrincipalName() != nil {
-			opts.NotTo = append(opts.NotTo, strings.TrimSpace(*me.GetUserPrincipalName()))
-		}
+		out.NotTo = append(out.NotTo, me)
 	}
 	if opts.FromMe {
-		if me != nil && me.GetUserPrincipalName() != nil {
-			opts.From = append(opts.From, strings.TrimSpace(*me.GetUserPrincipalName()))
-		}
+		out.From = append(out.From, me)
 	}
 	if opts.ToMe {
-		if me != nil && me.GetUserPrincipalName() != nil {
-			opts.To = append(opts.To, strings.TrimSpace(*me.GetUserPrincipalName()))
-		}
+		out.To = append(out.To, me)
 	}
-	return nil
+	return out
 }
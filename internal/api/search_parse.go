@@ -32,11 +32,18 @@ func extractMessages(resp graphsearch.QueryPostResponseable) []SearchEntity {
 					continue
 				}
 
+				msg, _ := resource.(msmodels.ChatMessageable)
 				out = append(out, SearchEntity{
 					MessageID: msgID,
 					TeamID:    teamID,
 					ChannelID: channelID,
 					ChatID:    chatID,
+					Hit: SearchHit{
+						HitID:   hit.GetHitId(),
+						Rank:    hit.GetRank(),
+						Summary: hit.GetSummary(),
+					},
+					Resource: msg,
 				})
 			}
 		}
@@ -45,6 +52,34 @@ func extractMessages(resp graphsearch.QueryPostResponseable) []SearchEntity {
 	return out
 }
 
+// extractHitsInfo returns the total number of hits and whether more of them are available.
+func extractHitsInfo(resp graphsearch.QueryPostResponseable) (total *int32, more bool) {
+	if resp == nil {
+		return nil, false
+	}
+	for _, sr := range resp.GetValue() {
+		if sr == nil {
+			continue
+		}
+		for _, hc := range sr.GetHitsContainers() {
+			if hc == nil {
+				continue
+			}
+			if t := hc.GetTotal(); t != nil {
+				sum := *t
+				if total != nil {
+					sum += *total
+				}
+				total = &sum
+			}
+			if m := hc.GetMoreResultsAvailable(); m != nil && *m {
+				more = true
+			}
+		}
+	}
+	return total, more
+}
+
 func asStringPtr(v any) *string {
 	switch t := v.(type) {
 	case nil:
@@ -6,11 +6,13 @@ import (
 )
 
 var (
-	channelSingleton ChannelAPI
-	chatSingleton    ChatAPI
-	teamSingleton    TeamAPI
-	userSingleton    UserAPI
-	searchSingleton  SearchAPI
+	channelSingleton      ChannelAPI
+	chatSingleton         ChatAPI
+	teamSingleton         TeamAPI
+	userSingleton         UserAPI
+	searchSingleton       SearchAPI
+	entitySingleton       EntityAPI
+	subscriptionSingleton SubscriptionAPI
 )
 
 func GetChannelAPI(c *graph.GraphServiceClient, sCfg *config.SenderConfig, searchAPI SearchAPI) ChannelAPI {
@@ -47,3 +49,17 @@ func GetSearchAPI(c *graph.GraphServiceClient, sCfg *config.SenderConfig) Search
 	}
 	return searchSingleton
 }
+
+func GetEntityAPI(c *graph.GraphServiceClient, sCfg *config.SenderConfig, searchAPI SearchAPI) EntityAPI {
+	if entitySingleton == nil {
+		entitySingleton = NewEntity(c, sCfg, searchAPI)
+	}
+	return entitySingleton
+}
+
+func GetSubscriptionAPI(c *graph.GraphServiceClient, sCfg *config.SenderConfig) SubscriptionAPI {
+	if subscriptionSingleton == nil {
+		subscriptionSingleton = NewSubscription(c, sCfg)
+	}
+	return subscriptionSingleton
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+
+	graph "github.com/microsoftgraph/msgraph-sdk-go"
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/sender"
+)
+
+type SubscriptionAPI interface {
+	Create(ctx context.Context, subscription msmodels.Subscriptionable) (msmodels.Subscriptionable, *sender.RequestError)
+	Update(ctx context.Context, subscriptionID string, patch msmodels.Subscriptionable) (msmodels.Subscriptionable, *sender.RequestError)
+	List(ctx context.Context) (msmodels.SubscriptionCollectionResponseable, *sender.RequestError)
+	Delete(ctx context.Context, subscriptionID string) *sender.RequestError
+}
+
+type subscriptionAPI struct {
+	client    *graph.GraphServiceClient
+	senderCfg *config.SenderConfig
+}
+
+func NewSubscription(client *graph.GraphServiceClient, senderCfg *config.SenderConfig) SubscriptionAPI {
+	return &subscriptionAPI{client: client, senderCfg: senderCfg}
+}
+
+func (s *subscriptionAPI) Create(ctx context.Context, subscription msmodels.Subscriptionable) (msmodels.Subscriptionable, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return s.client.Subscriptions().Post(ctx, subscription, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, s.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.Subscriptionable)
+	if !ok {
+		return nil, newTypeError("Subscriptionable")
+	}
+	return out, nil
+}
+
+func (s *subscriptionAPI) Update(ctx context.Context, subscriptionID string, patch msmodels.Subscriptionable) (msmodels.Subscriptionable, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return s.client.Subscriptions().BySubscriptionId(subscriptionID).Patch(ctx, patch, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, s.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.Subscriptionable)
+	if !ok {
+		return nil, newTypeError("Subscriptionable")
+	}
+	return out, nil
+}
+
+func (s *subscriptionAPI) List(ctx context.Context) (msmodels.SubscriptionCollectionResponseable, *sender.RequestError) {
+	call := func(ctx context.Context) (sender.Response, error) {
+		return s.client.Subscriptions().Get(ctx, nil)
+	}
+
+	resp, err := sender.SendRequest(ctx, call, s.senderCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, ok := resp.(msmodels.SubscriptionCollectionResponseable)
+	if !ok {
+		return nil, newTypeError("SubscriptionCollectionResponseable")
+	}
+	return out, nil
+}
+
+func (s *subscriptionAPI) Delete(ctx context.Context, subscriptionID string) *sender.RequestError {
+	call := func(ctx context.Context) (sender.Response, error) {
+		err := s.client.Subscriptions().BySubscriptionId(subscriptionID).Delete(ctx, nil)
+		return nil, err
+	}
+
+	_, err := sender.SendRequest(ctx, call, s.senderCfg)
+	return err
+}
@@ -16,6 +16,7 @@ import (
 
 type UserAPI interface {
 	GetUserByEmailOrUPN(ctx context.Context, emailOrUPN string) (msmodels.Userable, *sender.RequestError)
+	GetMe(ctx context.Context) (msmodels.Userable, *sender.RequestError)
 }
 
 type userAPI struct {
@@ -47,6 +48,10 @@ func (u *userAPI) GetUserByEmailOrUPN(ctx context.Context, emailOrUPN string) (m
 	return nil, reqErr
 }
 
+func (u *userAPI) GetMe(ctx context.Context) (msmodels.Userable, *sender.RequestError) {
+	return GetMe(ctx, u.client, u.senderCfg)
+}
+
 func (u *userAPI) getUserByKey(ctx context.Context, key string) (msmodels.Userable, *sender.RequestError) {
 	cfg := &graphusers.UserItemRequestBuilderGetRequestConfiguration{
 		QueryParameters: &graphusers.UserItemRequestBuilderGetQueryParameters{
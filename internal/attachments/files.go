@@ -0,0 +1,33 @@
+package attachments
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// CopyFile copies a downloaded file attachment to w.
+func CopyFile(w io.Writer, r io.Reader) error {
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("failed to download file: %w", err)
+	}
+	return nil
+}
+
+// OpenLocalFile opens a local file to be uploaded and returns it with its size.
+func OpenLocalFile(path string) (*os.File, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, 0, fmt.Errorf("failed to open file: %s is a directory", path)
+	}
+	return f, info.Size(), nil
+}
@@ -0,0 +1,53 @@
+package attachments
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFile(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, CopyFile(&buf, strings.NewReader("report")))
+	require.Equal(t, "report", buf.String())
+
+	err := CopyFile(failingWriter{}, strings.NewReader("report"))
+	require.ErrorContains(t, err, "failed to download file")
+}
+
+func TestOpenLocalFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.txt")
+	require.NoError(t, os.WriteFile(path, []byte("report"), 0o600))
+
+	t.Run("opens file with its size", func(t *testing.T) {
+		t.Parallel()
+
+		f, size, err := OpenLocalFile(path)
+		require.NoError(t, err)
+		defer f.Close()
+		require.Equal(t, int64(6), size)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := OpenLocalFile(filepath.Join(dir, "missing.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := OpenLocalFile(dir)
+		require.ErrorContains(t, err, "is a directory")
+	})
+}
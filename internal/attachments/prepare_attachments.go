@@ -0,0 +1,97 @@
+// Package attachments prepares message attachments, such as Adaptive Cards, and hosted contents, such as inline images, for Microsoft Graph.
+// PrepareBody prepares a whole message body, together with its mentions.
+package attachments
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/models"
+)
+
+var attachmentTag = regexp.MustCompile(`<attachment\s+id="([^"]*)"`)
+
+// PrepareAttachments validates the attachments of body and maps them to Graph attachments.
+//
+// Graph requires every attachment to be referenced from the HTML body with an <attachment id="..."> tag;
+// missing tags are appended to the content. A text body is switched to HTML when it is empty,
+// so a message can consist of attachments only.
+func PrepareAttachments(body *models.MessageBody) ([]msmodels.ChatMessageAttachmentable, error) {
+	if body == nil || len(body.Attachments) == 0 {
+		if body != nil && attachmentTag.MatchString(body.Content) {
+			return nil, fmt.Errorf("content contains <attachment> tags but attachments list is empty")
+		}
+		return nil, nil
+	}
+	if body.ContentType != models.MessageContentTypeHTML {
+		if strings.TrimSpace(body.Content) != "" {
+			return nil, fmt.Errorf("attachments can only be used with HTML content type")
+		}
+		body.ContentType = models.MessageContentTypeHTML
+	}
+
+	seen := make(map[string]struct{}, len(body.Attachments))
+	out := make([]msmodels.ChatMessageAttachmentable, 0, len(body.Attachments))
+	for i, att := range body.Attachments {
+		if err := validateAttachment(att, seen); err != nil {
+			return nil, fmt.Errorf("attachment[%d]: %w", i, err)
+		}
+		out = append(out, mapToGraphAttachment(att))
+	}
+
+	for _, match := range attachmentTag.FindAllStringSubmatch(body.Content, -1) {
+		if _, ok := seen[match[1]]; !ok {
+			return nil, fmt.Errorf("content references unknown attachment %q", match[1])
+		}
+	}
+	for _, att := range body.Attachments {
+		if !strings.Contains(body.Content, fmt.Sprintf(`<attachment id="%s"`, att.ID)) {
+			body.Content += fmt.Sprintf(`<attachment id="%s"></attachment>`, att.ID)
+		}
+	}
+	return out, nil
+}
+
+func validateAttachment(att models.Attachment, seen map[string]struct{}) error {
+	if att.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if strings.ContainsAny(att.ID, `"<>`) {
+		return fmt.Errorf("id %q contains invalid characters", att.ID)
+	}
+	if _, ok := seen[att.ID]; ok {
+		return fmt.Errorf("duplicate id %q", att.ID)
+	}
+	seen[att.ID] = struct{}{}
+	if att.ContentType == "" {
+		return fmt.Errorf("content type is required")
+	}
+	if att.Content == "" && att.ContentURL == "" {
+		return fmt.Errorf("content or content URL is required")
+	}
+	return nil
+}
+
+func mapToGraphAttachment(att models.Attachment) msmodels.ChatMessageAttachmentable {
+	out := msmodels.NewChatMessageAttachment()
+	out.SetId(&att.ID)
+	out.SetContentType(&att.ContentType)
+	if att.Content != "" {
+		out.SetContent(&att.Content)
+	}
+	if att.ContentURL != "" {
+		out.SetContentUrl(&att.ContentURL)
+	}
+	if att.Name != "" {
+		out.SetName(&att.Name)
+	}
+	if att.ThumbnailURL != "" {
+		out.SetThumbnailUrl(&att.ThumbnailURL)
+	}
+	if att.TeamsAppID != "" {
+		out.SetTeamsAppId(&att.TeamsAppID)
+	}
+	return out
+}
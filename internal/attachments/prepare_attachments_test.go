@@ -0,0 +1,114 @@
+package attachments
+
+import (
+	"testing"
+
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+func card(id string) models.Attachment {
+	return models.Attachment{
+		ID:          id,
+		ContentType: "application/vnd.microsoft.card.adaptive",
+		Content:     `{"type":"AdaptiveCard"}`,
+	}
+}
+
+func TestPrepareAttachments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		body            models.MessageBody
+		wantContent     string
+		wantContentType models.MessageContentType
+		wantIDs         []string
+		wantErrContains string
+	}{
+		{
+			name:            "no attachments -> nothing to do",
+			body:            models.MessageBody{Content: "hi", ContentType: models.MessageContentTypeText},
+			wantContent:     "hi",
+			wantContentType: models.MessageContentTypeText,
+		},
+		{
+			name:            "missing tag is appended",
+			body:            models.MessageBody{Content: "<p>hi</p>", ContentType: models.MessageContentTypeHTML, Attachments: []models.Attachment{card("a1")}},
+			wantContent:     `<p>hi</p><attachment id="a1"></attachment>`,
+			wantContentType: models.MessageContentTypeHTML,
+			wantIDs:         []string{"a1"},
+		},
+		{
+			name:            "existing tag is kept",
+			body:            models.MessageBody{Content: `<attachment id="a1"></attachment><p>hi</p>`, ContentType: models.MessageContentTypeHTML, Attachments: []models.Attachment{card("a1")}},
+			wantContent:     `<attachment id="a1"></attachment><p>hi</p>`,
+			wantContentType: models.MessageContentTypeHTML,
+			wantIDs:         []string{"a1"},
+		},
+		{
+			name:            "empty text body is switched to HTML",
+			body:            models.MessageBody{Attachments: []models.Attachment{card("a1"), card("a2")}},
+			wantContent:     `<attachment id="a1"></attachment><attachment id="a2"></attachment>`,
+			wantContentType: models.MessageContentTypeHTML,
+			wantIDs:         []string{"a1", "a2"},
+		},
+		{
+			name:            "text content with attachments",
+			body:            models.MessageBody{Content: "hi", ContentType: models.MessageContentTypeText, Attachments: []models.Attachment{card("a1")}},
+			wantErrContains: "HTML content type",
+		},
+		{
+			name:            "tag without attachments",
+			body:            models.MessageBody{Content: `<attachment id="a1"></attachment>`, ContentType: models.MessageContentTypeHTML},
+			wantErrContains: "attachments list is empty",
+		},
+		{
+			name:            "tag references unknown attachment",
+			body:            models.MessageBody{Content: `<attachment id="x"></attachment>`, ContentType: models.MessageContentTypeHTML, Attachments: []models.Attachment{card("a1")}},
+			wantErrContains: `unknown attachment "x"`,
+		},
+		{
+			name:            "missing id",
+			body:            models.MessageBody{ContentType: models.MessageContentTypeHTML, Attachments: []models.Attachment{card("")}},
+			wantErrContains: "attachment[0]: id is required",
+		},
+		{
+			name:            "duplicate id",
+			body:            models.MessageBody{ContentType: models.MessageContentTypeHTML, Attachments: []models.Attachment{card("a1"), card("a1")}},
+			wantErrContains: `attachment[1]: duplicate id "a1"`,
+		},
+		{
+			name:            "missing content",
+			body:            models.MessageBody{ContentType: models.MessageContentTypeHTML, Attachments: []models.Attachment{{ID: "a1", ContentType: "reference"}}},
+			wantErrContains: "content or content URL is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := tt.body
+			out, err := PrepareAttachments(&body)
+
+			if tt.wantErrContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErrContains)
+				require.Nil(t, out)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantContent, body.Content)
+			require.Equal(t, tt.wantContentType, body.ContentType)
+			require.Len(t, out, len(tt.wantIDs))
+			for i, id := range tt.wantIDs {
+				require.Equal(t, id, *out[i].GetId())
+				require.Equal(t, "application/vnd.microsoft.card.adaptive", *out[i].GetContentType())
+				require.Equal(t, `{"type":"AdaptiveCard"}`, *out[i].GetContent())
+				require.Nil(t, out[i].GetContentUrl())
+			}
+		})
+	}
+}
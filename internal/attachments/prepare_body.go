@@ -0,0 +1,29 @@
+package attachments
+
+import (
+	"fmt"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/internal/mentions"
+	"github.com/pzsp-teams/lib/models"
+)
+
+// PrepareBody prepares the attachments, hosted contents and mentions of body for Graph.
+// The returned error names the part of the body which could not be prepared.
+func PrepareBody(body *models.MessageBody) (
+	atts []msmodels.ChatMessageAttachmentable,
+	hosted []msmodels.ChatMessageHostedContentable,
+	ments []msmodels.ChatMessageMentionable,
+	err error,
+) {
+	if atts, err = PrepareAttachments(body); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to prepare attachments: %w", err)
+	}
+	if hosted, err = PrepareHostedContents(body); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to prepare hosted contents: %w", err)
+	}
+	if ments, err = mentions.PrepareMentions(body); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to prepare mentions: %w", err)
+	}
+	return atts, hosted, ments, nil
+}
@@ -0,0 +1,59 @@
+package attachments
+
+import (
+	"testing"
+
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareBody(t *testing.T) {
+	t.Parallel()
+
+	t.Run("prepares all parts", func(t *testing.T) {
+		t.Parallel()
+
+		body := &models.MessageBody{
+			Content:        `<p>cpu <at id="0">Alice</at></p>`,
+			ContentType:    models.MessageContentTypeHTML,
+			Attachments:    []models.Attachment{card("a1")},
+			HostedContents: []models.HostedContent{image("1")},
+			Mentions:       []models.Mention{{Kind: models.MentionUser, TargetID: "6f1c2b6e-1d6a-4a51-9d33-2f7b2b5a1c11", Text: "Alice", AtID: 0}},
+		}
+		atts, hosted, ments, err := PrepareBody(body)
+		require.NoError(t, err)
+		require.Len(t, atts, 1)
+		require.Len(t, hosted, 1)
+		require.Len(t, ments, 1)
+	})
+
+	tests := []struct {
+		name            string
+		body            models.MessageBody
+		wantErrContains string
+	}{
+		{
+			name:            "attachments",
+			body:            models.MessageBody{Content: `<attachment id="a1"></attachment>`, ContentType: models.MessageContentTypeHTML},
+			wantErrContains: "failed to prepare attachments",
+		},
+		{
+			name:            "hosted contents",
+			body:            models.MessageBody{Content: `<img src="../hostedContents/1/$value">`, ContentType: models.MessageContentTypeHTML},
+			wantErrContains: "failed to prepare hosted contents",
+		},
+		{
+			name:            "mentions",
+			body:            models.MessageBody{Content: "hi", ContentType: models.MessageContentTypeText, Mentions: []models.Mention{{Kind: models.MentionUser, TargetID: "u1", Text: "Alice"}}},
+			wantErrContains: "failed to prepare mentions",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+" error is named", func(t *testing.T) {
+			t.Parallel()
+
+			_, _, _, err := PrepareBody(&tc.body)
+			require.ErrorContains(t, err, tc.wantErrContains)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package attachments
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/models"
+)
+
+const temporaryIDKey = "@microsoft.graph.temporaryId"
+
+var (
+	hostedContentRef = regexp.MustCompile(`\.\./hostedContents/([^/"]*)/\$value`)
+	temporaryID      = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// PrepareHostedContents validates the hosted contents of body and maps them to Graph hosted contents
+// identified by their temporary IDs.
+//
+// Graph requires every hosted content to be referenced from the HTML body; missing references are appended
+// as <img src="../hostedContents/{ID}/$value"> tags. A text body is switched to HTML when it is empty.
+func PrepareHostedContents(body *models.MessageBody) ([]msmodels.ChatMessageHostedContentable, error) {
+	if body == nil || len(body.HostedContents) == 0 {
+		if body != nil && hostedContentRef.MatchString(body.Content) {
+			return nil, fmt.Errorf("content references hosted contents but hosted contents list is empty")
+		}
+		return nil, nil
+	}
+	if body.ContentType != models.MessageContentTypeHTML {
+		if strings.TrimSpace(body.Content) != "" {
+			return nil, fmt.Errorf("hosted contents can only be used with HTML content type")
+		}
+		body.ContentType = models.MessageContentTypeHTML
+	}
+
+	seen := make(map[string]struct{}, len(body.HostedContents))
+	out := make([]msmodels.ChatMessageHostedContentable, 0, len(body.HostedContents))
+	for i, hc := range body.HostedContents {
+		if err := validateHostedContent(hc, seen); err != nil {
+			return nil, fmt.Errorf("hostedContent[%d]: %w", i, err)
+		}
+		out = append(out, mapToGraphHostedContent(hc))
+	}
+
+	for _, match := range hostedContentRef.FindAllStringSubmatch(body.Content, -1) {
+		if _, ok := seen[match[1]]; !ok {
+			return nil, fmt.Errorf("content references unknown hosted content %q", match[1])
+		}
+	}
+	for _, hc := range body.HostedContents {
+		ref := hostedContentSrc(hc.ID)
+		if !strings.Contains(body.Content, ref) {
+			body.Content += fmt.Sprintf(`<img src="%s">`, ref)
+		}
+	}
+	return out, nil
+}
+
+// hostedContentSrc returns the reference to a hosted content with the given temporary ID,
+// to be used as the src of an <img> tag in a message body.
+func hostedContentSrc(id string) string {
+	return "../hostedContents/" + id + "/$value"
+}
+
+func validateHostedContent(hc models.HostedContent, seen map[string]struct{}) error {
+	if hc.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if !temporaryID.MatchString(hc.ID) {
+		return fmt.Errorf("id %q may only contain letters, digits, '-' and '_'", hc.ID)
+	}
+	if _, ok := seen[hc.ID]; ok {
+		return fmt.Errorf("duplicate id %q", hc.ID)
+	}
+	seen[hc.ID] = struct{}{}
+	if hc.ContentType == "" {
+		return fmt.Errorf("content type is required")
+	}
+	if len(hc.Content) == 0 {
+		return fmt.Errorf("content is required")
+	}
+	return nil
+}
+
+func mapToGraphHostedContent(hc models.HostedContent) msmodels.ChatMessageHostedContentable {
+	out := msmodels.NewChatMessageHostedContent()
+	out.SetContentBytes(hc.Content)
+	out.SetContentType(&hc.ContentType)
+	out.SetAdditionalData(map[string]any{temporaryIDKey: hc.ID})
+	return out
+}
+
+// WriteHostedContent writes the downloaded bytes of a hosted content to w.
+func WriteHostedContent(w io.Writer, content []byte) error {
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("failed to write hosted content: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package attachments
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+func image(id string) models.HostedContent {
+	return models.HostedContent{ID: id, ContentType: "image/png", Content: []byte{0x89, 'P', 'N', 'G'}}
+}
+
+func TestPrepareHostedContents(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		body            models.MessageBody
+		wantContent     string
+		wantContentType models.MessageContentType
+		wantIDs         []string
+		wantErrContains string
+	}{
+		{
+			name:            "no hosted contents -> nothing to do",
+			body:            models.MessageBody{Content: "hi", ContentType: models.MessageContentTypeText},
+			wantContent:     "hi",
+			wantContentType: models.MessageContentTypeText,
+		},
+		{
+			name:            "referenced image is kept",
+			body:            models.MessageBody{Content: `<p>cpu</p><img src="../hostedContents/1/$value">`, ContentType: models.MessageContentTypeHTML, HostedContents: []models.HostedContent{image("1")}},
+			wantContent:     `<p>cpu</p><img src="../hostedContents/1/$value">`,
+			wantContentType: models.MessageContentTypeHTML,
+			wantIDs:         []string{"1"},
+		},
+		{
+			name:            "missing reference is appended",
+			body:            models.MessageBody{Content: "<p>cpu</p>", ContentType: models.MessageContentTypeHTML, HostedContents: []models.HostedContent{image("1")}},
+			wantContent:     `<p>cpu</p><img src="../hostedContents/1/$value">`,
+			wantContentType: models.MessageContentTypeHTML,
+			wantIDs:         []string{"1"},
+		},
+		{
+			name:            "empty text body is switched to HTML",
+			body:            models.MessageBody{HostedContents: []models.HostedContent{image("1"), image("2")}},
+			wantContent:     `<img src="../hostedContents/1/$value"><img src="../hostedContents/2/$value">`,
+			wantContentType: models.MessageContentTypeHTML,
+			wantIDs:         []string{"1", "2"},
+		},
+		{
+			name:            "text content with hosted contents",
+			body:            models.MessageBody{Content: "hi", ContentType: models.MessageContentTypeText, HostedContents: []models.HostedContent{image("1")}},
+			wantErrContains: "HTML content type",
+		},
+		{
+			name:            "reference without hosted contents",
+			body:            models.MessageBody{Content: `<img src="../hostedContents/1/$value">`, ContentType: models.MessageContentTypeHTML},
+			wantErrContains: "hosted contents list is empty",
+		},
+		{
+			name:            "reference to unknown hosted content",
+			body:            models.MessageBody{Content: `<img src="../hostedContents/2/$value">`, ContentType: models.MessageContentTypeHTML, HostedContents: []models.HostedContent{image("1")}},
+			wantErrContains: `unknown hosted content "2"`,
+		},
+		{
+			name:            "invalid id",
+			body:            models.MessageBody{ContentType: models.MessageContentTypeHTML, HostedContents: []models.HostedContent{image("a/b")}},
+			wantErrContains: `hostedContent[0]: id "a/b"`,
+		},
+		{
+			name:            "duplicate id",
+			body:            models.MessageBody{ContentType: models.MessageContentTypeHTML, HostedContents: []models.HostedContent{image("1"), image("1")}},
+			wantErrContains: `hostedContent[1]: duplicate id "1"`,
+		},
+		{
+			name:            "missing content",
+			body:            models.MessageBody{ContentType: models.MessageContentTypeHTML, HostedContents: []models.HostedContent{{ID: "1", ContentType: "image/png"}}},
+			wantErrContains: "content is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := tt.body
+			out, err := PrepareHostedContents(&body)
+
+			if tt.wantErrContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErrContains)
+				require.Nil(t, out)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantContent, body.Content)
+			require.Equal(t, tt.wantContentType, body.ContentType)
+			require.Len(t, out, len(tt.wantIDs))
+			for i, id := range tt.wantIDs {
+				require.Equal(t, id, out[i].GetAdditionalData()[temporaryIDKey])
+				require.Equal(t, "image/png", *out[i].GetContentType())
+				require.Equal(t, []byte{0x89, 'P', 'N', 'G'}, out[i].GetContentBytes())
+			}
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWriteHostedContent(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteHostedContent(&buf, []byte("png")))
+	require.Equal(t, "png", buf.String())
+
+	err := WriteHostedContent(failingWriter{}, []byte("png"))
+	require.ErrorContains(t, err, "failed to write hosted content: disk full")
+}
@@ -1,7 +1,8 @@
 // Package cacher contains caching utilities for the library, including:
 //   - the Cacher interface,
 //   - a JSON file-backed cacher,
-//   - key builders for teams, channels, chats and members.
+//   - a decorator hashing or encrypting keys and values with the pepper,
+//   - key builders for teams, channels, chats, members and saved searches.
 package cacher
 
 type Cacher interface {
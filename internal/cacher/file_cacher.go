@@ -13,6 +13,10 @@ type jSONFileCacher struct {
 	file   string
 	cache  map[string]json.RawMessage
 	loaded bool
+
+	hits          uint64
+	misses        uint64
+	invalidations uint64
 }
 
 func newJSONFileCacher(path string) Cacher {
@@ -36,12 +40,14 @@ func (c *jSONFileCacher) Get(key string) (value any, found bool, err error) {
 func (c *jSONFileCacher) getFromCache(key string) (value any, found bool, err error) {
 	data, ok := c.cache[key]
 	if !ok {
+		c.misses++
 		return nil, false, nil
 	}
 	var result []string
 	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, false, err
 	}
+	c.hits++
 	return result, true, nil
 }
 
@@ -100,6 +106,9 @@ func (c *jSONFileCacher) Invalidate(key string) error {
 	if err := c.ensureLoadedLocked(); err != nil {
 		return err
 	}
+	if _, ok := c.cache[key]; ok {
+		c.invalidations++
+	}
 	delete(c.cache, key)
 	return c.persistLocked()
 }
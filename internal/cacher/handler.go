@@ -1,11 +1,14 @@
 package cacher
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/pepper"
 	"github.com/pzsp-teams/lib/internal/sender"
 	"github.com/pzsp-teams/lib/internal/util"
 )
@@ -13,6 +16,26 @@ import (
 type CacheHandler struct {
 	Cacher Cacher
 	Runner util.TaskRunner
+
+	mu     sync.RWMutex
+	pepper *string
+}
+
+// Pepper returns the pepper hashing user references in cache keys. It is read from the configured
+// source once, when the handler is created, and nil when no pepper is available for an unprotected cache.
+func (h *CacheHandler) Pepper() *string {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.pepper
+}
+
+func (h *CacheHandler) setPepper(pep string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pepper = &pep
 }
 
 func (h *CacheHandler) OnError(err error) {
@@ -42,9 +65,24 @@ func shouldClearCache(err error) bool {
 	return false
 }
 
-func NewCacheHandler(cfg *config.CacheConfig) *CacheHandler {
+// NewCacheHandler creates the handler of the cache configured in cfg, or nil when cache is disabled.
+//
+// An error is returned when the cache is protected or the pepper is strict, but no pepper is available.
+func NewCacheHandler(cfg *config.CacheConfig) (*CacheHandler, error) {
 	if cfg.Mode == config.CacheDisabled {
-		return nil
+		return nil, nil
+	}
+
+	protected := cfg.Protection != "" && cfg.Protection != config.CacheProtectionNone
+	var pep *string
+	value, err := pepper.Get(NewPepperSource(cfg.Pepper))
+	switch {
+	case err == nil:
+		pep = &value
+	case cfg.Pepper != nil && cfg.Pepper.Strict:
+		return nil, fmt.Errorf("strict pepper: %w", err)
+	case protected:
+		return nil, fmt.Errorf("cache protection %s requires a pepper: %w", cfg.Protection, err)
 	}
 
 	if cfg.Path == nil {
@@ -62,9 +100,38 @@ func NewCacheHandler(cfg *config.CacheConfig) *CacheHandler {
 		cacher = newJSONFileCacher(*cfg.Path)
 	}
 
+	if protected {
+		protectedCacher, err := newProtectedCacher(cacher, cfg.Protection, *pep)
+		if err != nil {
+			return nil, err
+		}
+		cacher = protectedCacher
+	}
+
 	return &CacheHandler{
 		Cacher: cacher,
 		Runner: runner,
+		pepper: pep,
+	}, nil
+}
+
+// NewPepperSource returns the pepper source configured in cfg. Nil cfg reads the system keyring.
+func NewPepperSource(cfg *config.PepperConfig) pepper.Source {
+	if cfg == nil {
+		return pepper.KeyringSource{}
+	}
+	switch cfg.Source {
+	case config.PepperSourceEnv:
+		return pepper.EnvSource{Name: cfg.EnvVar}
+	case config.PepperSourceFile:
+		return pepper.FileSource{Path: cfg.FilePath}
+	case config.PepperSourceCallback:
+		if cfg.Callback == nil {
+			return pepper.FuncSource(func() (string, error) { return "", pepper.ErrPepperNotSet })
+		}
+		return pepper.FuncSource(cfg.Callback)
+	default:
+		return pepper.KeyringSource{}
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/pepper"
 	"github.com/pzsp-teams/lib/internal/sender"
 	testutil "github.com/pzsp-teams/lib/internal/testutil"
 	"github.com/pzsp-teams/lib/internal/util"
@@ -276,7 +277,8 @@ func TestNewCacheHandler(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			h := NewCacheHandler(tc.cfg)
+			h, err := NewCacheHandler(tc.cfg)
+			require.NoError(t, err)
 			tc.assertions(t, tc.cfg, h)
 		})
 	}
@@ -306,3 +308,121 @@ func Test_defaultCachePath_UsesUserCacheDirWhenAvailable(t *testing.T) {
 		"unexpected cache file name: %s", p,
 	)
 }
+
+func TestNewCacheHandler_PepperConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	missing := func() (string, error) { return "", pepper.ErrPepperNotSet }
+
+	present := func() (string, error) { return "pep", nil }
+
+	tests := []struct {
+		name       string
+		pepper     *config.PepperConfig
+		protection config.CacheProtection
+		wantErr    bool
+	}{
+		{
+			name:    "strict without pepper fails",
+			pepper:  &config.PepperConfig{Source: config.PepperSourceCallback, Callback: missing, Strict: true},
+			wantErr: true,
+		},
+		{
+			name:    "strict without callback fails",
+			pepper:  &config.PepperConfig{Source: config.PepperSourceCallback, Strict: true},
+			wantErr: true,
+		},
+		{
+			name:   "non-strict without pepper keeps cache",
+			pepper: &config.PepperConfig{Source: config.PepperSourceCallback, Callback: missing},
+		},
+		{
+			name:   "strict with pepper keeps cache",
+			pepper: &config.PepperConfig{Source: config.PepperSourceCallback, Callback: present, Strict: true},
+		},
+		{
+			name:       "hash protection without pepper fails",
+			pepper:     &config.PepperConfig{Source: config.PepperSourceCallback, Callback: missing},
+			protection: config.CacheProtectionHash,
+			wantErr:    true,
+		},
+		{
+			name:       "encrypt protection without pepper fails",
+			pepper:     &config.PepperConfig{Source: config.PepperSourceCallback, Callback: missing},
+			protection: config.CacheProtectionEncrypt,
+			wantErr:    true,
+		},
+		{
+			name:       "encrypt protection with pepper keeps cache",
+			pepper:     &config.PepperConfig{Source: config.PepperSourceCallback, Callback: present},
+			protection: config.CacheProtectionEncrypt,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := NewCacheHandler(&config.CacheConfig{
+				Mode:       config.CacheSync,
+				Provider:   config.CacheProviderJSONFile,
+				Path:       util.Ptr(path),
+				Protection: tc.protection,
+				Pepper:     tc.pepper,
+			})
+			if tc.wantErr {
+				require.ErrorIs(t, err, pepper.ErrPepperNotSet)
+				assert.Nil(t, h)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, h)
+		})
+	}
+}
+
+func TestNewCacheHandler_PepperIsResolvedOnce(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(callback func() (string, error)) *CacheHandler {
+		h, err := NewCacheHandler(&config.CacheConfig{
+			Mode:     config.CacheSync,
+			Provider: config.CacheProviderJSONFile,
+			Path:     util.Ptr(filepath.Join(t.TempDir(), "cache.json")),
+			Pepper:   &config.PepperConfig{Source: config.PepperSourceCallback, Callback: callback},
+		})
+		require.NoError(t, err)
+		return h
+	}
+
+	value := "pep-a"
+	a := newHandler(func() (string, error) { return value, nil })
+	b := newHandler(func() (string, error) { return "pep-b", nil })
+	value = ""
+
+	require.Equal(t, util.Ptr("pep-a"), a.Pepper(), "pepper is kept when the source disappears")
+	require.Equal(t, util.Ptr("pep-b"), b.Pepper(), "handlers do not share the pepper source")
+
+	missing := newHandler(func() (string, error) { return "", pepper.ErrPepperNotSet })
+	assert.Nil(t, missing.Pepper())
+	assert.Nil(t, (*CacheHandler)(nil).Pepper())
+}
+
+func TestNewPepperSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  *config.PepperConfig
+		want pepper.Source
+	}{
+		{name: "nil is keyring", cfg: nil, want: pepper.KeyringSource{}},
+		{name: "default is keyring", cfg: &config.PepperConfig{}, want: pepper.KeyringSource{}},
+		{name: "env", cfg: &config.PepperConfig{Source: config.PepperSourceEnv, EnvVar: "X"}, want: pepper.EnvSource{Name: "X"}},
+		{name: "file", cfg: &config.PepperConfig{Source: config.PepperSourceFile, FilePath: "/p"}, want: pepper.FileSource{Path: "/p"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, NewPepperSource(tc.cfg))
+		})
+	}
+}
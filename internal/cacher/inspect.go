@@ -0,0 +1,185 @@
+package cacher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pzsp-teams/lib/config"
+)
+
+const exportFormatVersion = 1
+
+// Stats holds usage counters and a summary of the cache contents.
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Invalidations uint64
+	EntriesByType map[KeyType]int
+	FileSize      int64
+}
+
+// Entry is a single cache entry with its key split into type and reference part.
+type Entry struct {
+	Type   KeyType  `json:"type"`
+	Key    string   `json:"key"`
+	Values []string `json:"values"`
+}
+
+// Inspector is implemented by cachers which can report their usage and contents.
+type Inspector interface {
+	Stats() (*Stats, error)
+	Entries() ([]Entry, error)
+	Export(w io.Writer) error
+	Import(r io.Reader) error
+}
+
+// exportDocument is the format of cache exports. Protection is empty for plain exports,
+// otherwise keys and values of the entries are stored in their protected form.
+type exportDocument struct {
+	Version    int                    `json:"version"`
+	Protection config.CacheProtection `json:"protection,omitempty"`
+	Entries    []Entry                `json:"entries"`
+}
+
+func writeExport(w io.Writer, doc exportDocument) error {
+	doc.Version = exportFormatVersion
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func readExport(r io.Reader) (exportDocument, error) {
+	var doc exportDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return exportDocument{}, fmt.Errorf("decoding cache export: %w", err)
+	}
+	if doc.Version != exportFormatVersion {
+		return exportDocument{}, fmt.Errorf("unsupported cache export version %d", doc.Version)
+	}
+	if doc.Protection == "" {
+		doc.Protection = config.CacheProtectionNone
+	}
+	return doc, nil
+}
+
+// ParseKey splits a key created by one of the key builders into its type and reference part.
+func ParseKey(key string) (keyType KeyType, ref string, ok bool) {
+	if !strings.HasPrefix(key, "$") {
+		return "", "", false
+	}
+	end := strings.Index(key[1:], "$:")
+	if end < 0 {
+		return "", "", false
+	}
+	return KeyType(key[1 : end+1]), key[end+3:], true
+}
+
+func joinKey(t KeyType, ref string) string {
+	return fmt.Sprintf("$%s$:%s", t, ref)
+}
+
+func (c *jSONFileCacher) Stats() (*Stats, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.ensureLoadedLocked(); err != nil {
+		return nil, err
+	}
+	stats := &Stats{
+		Hits:          c.hits,
+		Misses:        c.misses,
+		Invalidations: c.invalidations,
+		EntriesByType: make(map[KeyType]int),
+	}
+	for key := range c.cache {
+		keyType, _, ok := ParseKey(key)
+		if !ok || keyType == Meta {
+			continue
+		}
+		stats.EntriesByType[keyType]++
+	}
+	info, err := os.Stat(c.file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		stats.FileSize = info.Size()
+	}
+	return stats, nil
+}
+
+func (c *jSONFileCacher) Entries() ([]Entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.ensureLoadedLocked(); err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(c.cache))
+	for key, raw := range c.cache {
+		keyType, ref, ok := ParseKey(key)
+		if !ok || keyType == Meta {
+			continue
+		}
+		var values []string
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, fmt.Errorf("decoding cache entry %q: %w", key, err)
+		}
+		entries = append(entries, Entry{Type: keyType, Key: ref, Values: values})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
+
+func (c *jSONFileCacher) Export(w io.Writer) error {
+	entries, err := c.Entries()
+	if err != nil {
+		return err
+	}
+	return writeExport(w, exportDocument{Entries: entries})
+}
+
+func (c *jSONFileCacher) Import(r io.Reader) error {
+	doc, err := readExport(r)
+	if err != nil {
+		return err
+	}
+	if doc.Protection != config.CacheProtectionNone {
+		return fmt.Errorf("cache export is protected with %s, import it into a cache with the same protection", doc.Protection)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.ensureLoadedLocked(); err != nil {
+		return err
+	}
+	for _, entry := range doc.Entries {
+		if entry.Type == "" {
+			return fmt.Errorf("cache export entry %q has no type", entry.Key)
+		}
+		key := joinKey(entry.Type, entry.Key)
+		var values []string
+		if raw, exists := c.cache[key]; exists {
+			_ = json.Unmarshal(raw, &values)
+		}
+		for _, v := range entry.Values {
+			if !slices.Contains(values, v) {
+				values = append(values, v)
+			}
+		}
+		record, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+		c.cache[key] = record
+	}
+	return c.persistLocked()
+}
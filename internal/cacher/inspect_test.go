@@ -0,0 +1,160 @@
+package cacher
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newInspector(t *testing.T) (Cacher, Inspector, string) {
+	t.Helper()
+	path := tempFilePath(t)
+	c := newJSONFileCacher(path)
+	inspector, ok := c.(Inspector)
+	require.True(t, ok, "expected Inspector, got %T", c)
+	return c, inspector, path
+}
+
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		key      string
+		wantType KeyType
+		wantRef  string
+		wantOK   bool
+	}{
+		{name: "team key", key: NewTeamKey("Team A"), wantType: Team, wantRef: "Team A", wantOK: true},
+		{name: "channel key keeps colons in ref", key: NewChannelKey("team-id", "a:b"), wantType: Channel, wantRef: "team-id:a:b", wantOK: true},
+		{name: "member key", key: NewTeamMemberKey("team-id", "user", util.Ptr("p")), wantType: TeamMember, wantOK: true},
+		{name: "no prefix", key: "team:abc", wantOK: false},
+		{name: "no separator", key: "$team", wantOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotType, gotRef, ok := ParseKey(tc.key)
+			require.Equal(t, tc.wantOK, ok)
+			if !tc.wantOK {
+				return
+			}
+			require.Equal(t, tc.wantType, gotType)
+			if tc.wantRef != "" {
+				require.Equal(t, tc.wantRef, gotRef)
+			}
+		})
+	}
+}
+
+func TestJSONFileCacher_Stats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counts hits, misses, invalidations and entries per type", func(t *testing.T) {
+		t.Parallel()
+
+		c, inspector, path := newInspector(t)
+
+		mustSet(t, c, NewTeamKey("Team A"), "team-1")
+		mustSet(t, c, NewTeamKey("Team B"), "team-2")
+		mustSet(t, c, NewChannelKey("team-1", "General"), "chan-1")
+
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+		requireCacheMiss(t, c, NewTeamKey("Team C"))
+		require.NoError(t, c.Invalidate(NewTeamKey("Team B")))
+		require.NoError(t, c.Invalidate(NewTeamKey("missing")))
+
+		stats, err := inspector.Stats()
+		require.NoError(t, err)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+
+		require.Equal(t, uint64(1), stats.Hits)
+		require.Equal(t, uint64(1), stats.Misses)
+		require.Equal(t, uint64(1), stats.Invalidations)
+		require.Equal(t, map[KeyType]int{Team: 1, Channel: 1}, stats.EntriesByType)
+		require.Equal(t, info.Size(), stats.FileSize)
+	})
+
+	t.Run("file does not exist -> zero size", func(t *testing.T) {
+		t.Parallel()
+
+		_, inspector, _ := newInspector(t)
+
+		stats, err := inspector.Stats()
+		require.NoError(t, err)
+		require.Zero(t, stats.FileSize)
+		require.Empty(t, stats.EntriesByType)
+	})
+}
+
+func TestJSONFileCacher_Entries(t *testing.T) {
+	t.Parallel()
+
+	c, inspector, _ := newInspector(t)
+	pep := "pepper"
+
+	mustSet(t, c, NewTeamMemberKey("team-1", "alice@example.com", &pep), "member-1")
+	mustSet(t, c, NewTeamKey("Team A"), "team-1")
+	mustSet(t, c, NewChannelKey("team-1", "General"), "chan-1")
+
+	entries, err := inspector.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	require.Equal(t, Entry{Type: Channel, Key: "team-1:General", Values: []string{"chan-1"}}, entries[0])
+	require.Equal(t, Entry{Type: Team, Key: "Team A", Values: []string{"team-1"}}, entries[1])
+	require.Equal(t, TeamMember, entries[2].Type)
+	require.NotContains(t, entries[2].Key, "alice@example.com")
+}
+
+func TestJSONFileCacher_ExportImport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round-trip merges entries", func(t *testing.T) {
+		t.Parallel()
+
+		src, srcInspector, _ := newInspector(t)
+		mustSet(t, src, NewTeamKey("Team A"), "team-1")
+		mustSet(t, src, NewChannelKey("team-1", "General"), "chan-1")
+
+		var buf bytes.Buffer
+		require.NoError(t, srcInspector.Export(&buf))
+
+		dst, dstInspector, path := newInspector(t)
+		mustSet(t, dst, NewTeamKey("Team A"), "team-0")
+
+		require.NoError(t, dstInspector.Import(&buf))
+
+		requireFileHasKey(t, path, NewTeamKey("Team A"), []string{"team-0", "team-1"})
+		requireFileHasKey(t, path, NewChannelKey("team-1", "General"), []string{"chan-1"})
+	})
+
+	t.Run("unsupported version -> error", func(t *testing.T) {
+		t.Parallel()
+
+		_, inspector, _ := newInspector(t)
+		require.Error(t, inspector.Import(strings.NewReader(`{"version": 99, "entries": []}`)))
+	})
+
+	t.Run("entry without type -> error", func(t *testing.T) {
+		t.Parallel()
+
+		_, inspector, _ := newInspector(t)
+		require.Error(t, inspector.Import(strings.NewReader(`{"version": 1, "entries": [{"key": "x", "values": ["1"]}]}`)))
+	})
+
+	t.Run("invalid document -> error", func(t *testing.T) {
+		t.Parallel()
+
+		_, inspector, _ := newInspector(t)
+		require.Error(t, inspector.Import(strings.NewReader("not-json")))
+	})
+}
@@ -1,10 +1,8 @@
 package cacher
 
 import (
-	"fmt"
 	"strings"
 
-	"github.com/pzsp-teams/lib/internal/pepper"
 	"github.com/pzsp-teams/lib/internal/util"
 )
 
@@ -18,13 +16,15 @@ const (
 	DirectChat      KeyType = "direct-chat"
 	GroupChatMember KeyType = "group-chat-member"
 	TeamMember      KeyType = "team-member"
+	SavedSearch     KeyType = "saved-search"
+	Meta            KeyType = "meta"
 )
 
 func formatKey(t KeyType, parts ...string) string {
 	for i, p := range parts {
 		parts[i] = strings.TrimSpace(p)
 	}
-	return fmt.Sprintf("$%s$:%s", t, strings.Join(parts, ":"))
+	return joinKey(t, strings.Join(parts, ":"))
 }
 
 func NewTeamKey(name string) string {
@@ -55,12 +55,17 @@ func NewTeamMemberKey(teamID, userRef string, pep *string) string {
 	return formatKey(TeamMember, teamID, hashRef(userRef, pep))
 }
 
+func NewSavedSearchKey(name string) string {
+	return formatKey(SavedSearch, name)
+}
+
+// fallbackPepper hashes user references when no pepper is passed, i.e. for unprotected caches
+// without a configured pepper (see CacheHandler.Pepper). Protected caches and strict peppers never use it.
+const fallbackPepper = "default-pepper"
+
 func hashRef(ref string, pep *string) string {
 	if pep == nil {
-		p, err := pepper.GetPepper()
-		if err != nil {
-			p = "default-pepper"
-		}
+		p := fallbackPepper
 		pep = &p
 	}
 	return util.HashWithPepper(*pep, strings.TrimSpace(ref))
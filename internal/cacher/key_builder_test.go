@@ -29,6 +29,13 @@ func TestKeyBuilders_Format(t *testing.T) {
 			},
 			want: "$team$:my-team",
 		},
+		{
+			name: "NewSavedSearchKey formats key",
+			got: func() string {
+				return NewSavedSearchKey(" incident-123 ")
+			},
+			want: "$saved-search$:incident-123",
+		},
 		{
 			name: "NewChannelKey formats key",
 			got: func() string {
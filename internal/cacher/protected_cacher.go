@@ -0,0 +1,534 @@
+package cacher
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/pepper"
+	"github.com/pzsp-teams/lib/internal/util"
+)
+
+const (
+	protectionMetaRef = "protection"
+	encKeyLabel       = "pzsp-teams-cache:enc"
+	macKeyLabel       = "pzsp-teams-cache:mac"
+)
+
+// ErrIrreversibleProtection is returned when hashed cache entries would have to be turned back into plain text.
+var ErrIrreversibleProtection = errors.New("hashed cache entries cannot be recovered, clear the cache instead")
+
+// ErrCacheCleared is returned when cache entries could neither be rewritten nor restored, the cache was cleared instead.
+var ErrCacheCleared = errors.New("cache entries could not be rewritten and the cache was cleared")
+
+// protector transforms the reference part of cache keys and cache values
+// according to the configured protection mode.
+//
+// Encryption is deterministic (the nonce is derived from the plain text),
+// so equal keys map to equal ciphertexts and lookups keep working.
+type protector struct {
+	mode   config.CacheProtection
+	pepper string
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+func newProtector(mode config.CacheProtection, pep string) (*protector, error) {
+	p := &protector{mode: mode, pepper: pep}
+	switch mode {
+	case config.CacheProtectionNone, config.CacheProtectionHash:
+		return p, nil
+	case config.CacheProtectionEncrypt:
+		encKey := sha256.Sum256([]byte(encKeyLabel + "::" + pep))
+		macKey := sha256.Sum256([]byte(macKeyLabel + "::" + pep))
+		block, err := aes.NewCipher(encKey[:])
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		p.aead = aead
+		p.macKey = macKey[:]
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown cache protection %q", mode)
+	}
+}
+
+func (p *protector) encodeKey(key string) string {
+	keyType, ref, ok := ParseKey(key)
+	if !ok || keyType == Meta {
+		return key
+	}
+	switch p.mode {
+	case config.CacheProtectionHash:
+		return joinKey(keyType, util.HashWithPepper(p.pepper, ref))
+	case config.CacheProtectionEncrypt:
+		return joinKey(keyType, p.encrypt(ref))
+	default:
+		return key
+	}
+}
+
+func (p *protector) decodeKey(key string) (string, error) {
+	keyType, ref, ok := ParseKey(key)
+	if !ok || keyType == Meta {
+		return key, nil
+	}
+	switch p.mode {
+	case config.CacheProtectionHash:
+		return "", ErrIrreversibleProtection
+	case config.CacheProtectionEncrypt:
+		plain, err := p.decrypt(ref)
+		if err != nil {
+			return "", err
+		}
+		return joinKey(keyType, plain), nil
+	default:
+		return key, nil
+	}
+}
+
+func (p *protector) encodeValue(value string) string {
+	if p.mode != config.CacheProtectionEncrypt {
+		return value
+	}
+	return p.encrypt(value)
+}
+
+func (p *protector) decodeValue(value string) (string, error) {
+	if p.mode != config.CacheProtectionEncrypt {
+		return value, nil
+	}
+	return p.decrypt(value)
+}
+
+func (p *protector) encrypt(plain string) string {
+	mac := hmac.New(sha256.New, p.macKey)
+	mac.Write([]byte(plain))
+	nonce := mac.Sum(nil)[:p.aead.NonceSize()]
+	sealed := p.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+func (p *protector) decrypt(encoded string) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("decoding protected cache data: %w", err)
+	}
+	nonceSize := p.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("protected cache data too short")
+	}
+	plain, err := p.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypting protected cache data: %w", err)
+	}
+	return string(plain), nil
+}
+
+func (p *protector) decodeEntry(e Entry) (Entry, error) {
+	key, err := p.decodeKey(joinKey(e.Type, e.Key))
+	if err != nil {
+		return Entry{}, err
+	}
+	_, ref, _ := ParseKey(key)
+	values := make([]string, 0, len(e.Values))
+	for _, v := range e.Values {
+		plain, err := p.decodeValue(v)
+		if err != nil {
+			return Entry{}, err
+		}
+		values = append(values, plain)
+	}
+	return Entry{Type: e.Type, Key: ref, Values: values}, nil
+}
+
+func (p *protector) encodeEntry(e Entry) Entry {
+	_, ref, _ := ParseKey(p.encodeKey(joinKey(e.Type, e.Key)))
+	values := make([]string, 0, len(e.Values))
+	for _, v := range e.Values {
+		values = append(values, p.encodeValue(v))
+	}
+	return Entry{Type: e.Type, Key: ref, Values: values}
+}
+
+// protectedCacher is a Cacher decorator which hashes or encrypts keys and values
+// before they reach the underlying cacher.
+type protectedCacher struct {
+	mu            sync.RWMutex
+	inner         Cacher
+	protector     *protector
+	markerWritten bool
+}
+
+func newProtectedCacher(inner Cacher, mode config.CacheProtection, pep string) (Cacher, error) {
+	p, err := newProtector(mode, pep)
+	if err != nil {
+		return nil, err
+	}
+	return &protectedCacher{inner: inner, protector: p}, nil
+}
+
+func (c *protectedCacher) Get(key string) (value any, found bool, err error) {
+	p := c.current()
+	value, found, err = c.inner.Get(p.encodeKey(key))
+	if err != nil || !found {
+		return value, found, err
+	}
+	ids, ok := value.([]string)
+	if !ok {
+		return nil, false, fmt.Errorf("protectedCacher.Get: expected []string, got %T", value)
+	}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		plain, err := p.decodeValue(id)
+		if err != nil {
+			return nil, false, err
+		}
+		out = append(out, plain)
+	}
+	return out, true, nil
+}
+
+func (c *protectedCacher) Set(key string, value any) error {
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("protectedCacher.Set: expected string, got %T", value)
+	}
+	if err := c.ensureMarker(); err != nil {
+		return err
+	}
+	p := c.current()
+	return c.inner.Set(p.encodeKey(key), p.encodeValue(str))
+}
+
+func (c *protectedCacher) Invalidate(key string) error {
+	return c.inner.Invalidate(c.current().encodeKey(key))
+}
+
+func (c *protectedCacher) Clear() error {
+	c.resetMarker()
+	return c.inner.Clear()
+}
+
+func (c *protectedCacher) Stats() (*Stats, error) {
+	inspector, err := asInspector(c.inner)
+	if err != nil {
+		return nil, err
+	}
+	return inspector.Stats()
+}
+
+// Entries lists entries with keys and values decoded where possible.
+// Hashed keys are listed as they are stored.
+func (c *protectedCacher) Entries() ([]Entry, error) {
+	inspector, err := asInspector(c.inner)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := inspector.Entries()
+	if err != nil {
+		return nil, err
+	}
+	p := c.current()
+	if p.mode != config.CacheProtectionEncrypt {
+		return entries, nil
+	}
+	for i, e := range entries {
+		decoded, err := p.decodeEntry(e)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = decoded
+	}
+	return entries, nil
+}
+
+// Export writes entries in their protected form, so the export can only be read back with the same pepper.
+func (c *protectedCacher) Export(w io.Writer) error {
+	inspector, err := asInspector(c.inner)
+	if err != nil {
+		return err
+	}
+	entries, err := inspector.Entries()
+	if err != nil {
+		return err
+	}
+	return writeExport(w, exportDocument{Protection: c.current().mode, Entries: entries})
+}
+
+// Import merges entries of an export into the cache. Plain exports are protected on import,
+// exports of caches with the same protection have to be written with the same pepper.
+func (c *protectedCacher) Import(r io.Reader) error {
+	inspector, err := asInspector(c.inner)
+	if err != nil {
+		return err
+	}
+	doc, err := readExport(r)
+	if err != nil {
+		return err
+	}
+	p := c.current()
+	entries := doc.Entries
+	switch doc.Protection {
+	case p.mode:
+	case config.CacheProtectionNone:
+		entries = make([]Entry, 0, len(doc.Entries))
+		for _, e := range doc.Entries {
+			entries = append(entries, p.encodeEntry(e))
+		}
+	case config.CacheProtectionHash:
+		return ErrIrreversibleProtection
+	default:
+		return fmt.Errorf("cache export is protected with %s, import it into a cache with the same protection", doc.Protection)
+	}
+	if err := c.ensureMarker(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := writeExport(&buf, exportDocument{Entries: entries}); err != nil {
+		return err
+	}
+	return inspector.Import(&buf)
+}
+
+func (c *protectedCacher) current() *protector {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.protector
+}
+
+func (c *protectedCacher) replace(p *protector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.protector = p
+	c.markerWritten = false
+}
+
+func (c *protectedCacher) resetMarker() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.markerWritten = false
+}
+
+func (c *protectedCacher) ensureMarker() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.markerWritten {
+		return nil
+	}
+	if err := writeProtectionMarker(c.inner, c.protector.mode); err != nil {
+		return err
+	}
+	c.markerWritten = true
+	return nil
+}
+
+func asInspector(c Cacher) (Inspector, error) {
+	inspector, ok := c.(Inspector)
+	if !ok {
+		return nil, fmt.Errorf("cacher %T does not support inspection", c)
+	}
+	return inspector, nil
+}
+
+func protectionMarkerKey() string {
+	return joinKey(Meta, protectionMetaRef)
+}
+
+func readProtectionMarker(c Cacher) (config.CacheProtection, error) {
+	value, found, err := c.Get(protectionMarkerKey())
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return config.CacheProtectionNone, nil
+	}
+	modes, ok := value.([]string)
+	if !ok || len(modes) != 1 {
+		return "", fmt.Errorf("invalid cache protection marker %v", value)
+	}
+	return config.CacheProtection(modes[0]), nil
+}
+
+func writeProtectionMarker(c Cacher, mode config.CacheProtection) error {
+	current, err := readProtectionMarker(c)
+	if err != nil {
+		return err
+	}
+	if current == mode {
+		return nil
+	}
+	if err := c.Invalidate(protectionMarkerKey()); err != nil {
+		return err
+	}
+	if mode == config.CacheProtectionNone {
+		return nil
+	}
+	return c.Set(protectionMarkerKey(), string(mode))
+}
+
+// Migrate rewrites all entries stored by the handler's cacher from the protection they were written with
+// to the protection the cacher is configured with. Entries written without any protection are
+// recognized by the absence of the protection marker.
+//
+// Hashed entries cannot be turned back into plain text or re-encrypted, for such caches
+// ErrIrreversibleProtection is returned.
+func Migrate(h *CacheHandler) error {
+	if pc, ok := h.Cacher.(*protectedCacher); ok {
+		return migrateWithPepper(pc, pc.current().pepper)
+	}
+	source, err := readProtectionMarker(h.Cacher)
+	if err != nil || source == config.CacheProtectionNone {
+		return err
+	}
+	pep := h.Pepper()
+	if pep == nil {
+		return fmt.Errorf("migrating %s cache entries requires a pepper: %w", source, pepper.ErrPepperNotSet)
+	}
+	return migrateWithPepper(h.Cacher, *pep)
+}
+
+func migrateWithPepper(c Cacher, pep string) error {
+	inner, target := c, config.CacheProtectionNone
+	pc, isProtected := c.(*protectedCacher)
+	if isProtected {
+		inner, target = pc.inner, pc.current().mode
+		defer pc.resetMarker()
+	}
+
+	source, err := readProtectionMarker(inner)
+	if err != nil {
+		return err
+	}
+	if source == target {
+		return nil
+	}
+	if source == config.CacheProtectionHash {
+		return ErrIrreversibleProtection
+	}
+
+	from, err := newProtector(source, pep)
+	if err != nil {
+		return err
+	}
+	to, err := newProtector(target, pep)
+	if err != nil {
+		return err
+	}
+	return rewriteEntries(inner, from, to, target, nil)
+}
+
+// Rotate re-keys entries written with the handler's pepper so they can be read with newPep,
+// which the handler uses from then on.
+//
+// Entries keyed by peppered user hashes (and all entries of hashed caches) cannot be re-keyed,
+// they are dropped and resolved again on next use. If the entries cannot be rewritten,
+// the old ones are restored and the handler keeps its pepper.
+func Rotate(h *CacheHandler, newPep string) error {
+	// entries of unprotected caches do not depend on the pepper
+	oldPep := ""
+	if pc, ok := h.Cacher.(*protectedCacher); ok {
+		oldPep = pc.current().pepper
+	}
+	if err := rotateWithPepper(h.Cacher, oldPep, newPep); err != nil {
+		return err
+	}
+	h.setPepper(newPep)
+	return nil
+}
+
+func rotateWithPepper(c Cacher, oldPep, newPep string) error {
+	inner, mode := c, config.CacheProtectionNone
+	pc, isProtected := c.(*protectedCacher)
+	if isProtected {
+		inner, mode = pc.inner, pc.current().mode
+	}
+
+	to, err := newProtector(mode, newPep)
+	if err != nil {
+		return err
+	}
+	if mode == config.CacheProtectionHash {
+		err = inner.Clear()
+	} else {
+		var from *protector
+		if from, err = newProtector(mode, oldPep); err == nil {
+			err = rewriteEntries(inner, from, to, mode, isPepperIndependent)
+		}
+	}
+	if !isProtected {
+		return err
+	}
+	if err != nil {
+		pc.resetMarker()
+		return err
+	}
+	pc.replace(to)
+	return nil
+}
+
+func isPepperIndependent(e Entry) bool {
+	switch e.Type {
+	case DirectChat, GroupChatMember, ChannelMember, TeamMember:
+		return false
+	default:
+		return true
+	}
+}
+
+func rewriteEntries(inner Cacher, from, to *protector, target config.CacheProtection, keep func(Entry) bool) error {
+	inspector, err := asInspector(inner)
+	if err != nil {
+		return err
+	}
+	entries, err := inspector.Entries()
+	if err != nil {
+		return err
+	}
+	rewritten := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if keep != nil && !keep(e) {
+			continue
+		}
+		plain, err := from.decodeEntry(e)
+		if err != nil {
+			return err
+		}
+		rewritten = append(rewritten, to.encodeEntry(plain))
+	}
+
+	if err := replaceEntries(inner, inspector, rewritten, target); err != nil {
+		if restoreErr := replaceEntries(inner, inspector, entries, from.mode); restoreErr != nil {
+			return errors.Join(ErrCacheCleared, err, restoreErr, inner.Clear())
+		}
+		return err
+	}
+	return nil
+}
+
+// replaceEntries replaces all entries of the cacher with the given ones, written with the given protection.
+func replaceEntries(inner Cacher, inspector Inspector, entries []Entry, mode config.CacheProtection) error {
+	var buf bytes.Buffer
+	if err := writeExport(&buf, exportDocument{Entries: entries}); err != nil {
+		return err
+	}
+	if err := inner.Clear(); err != nil {
+		return err
+	}
+	if err := inspector.Import(&buf); err != nil {
+		return err
+	}
+	return writeProtectionMarker(inner, mode)
+}
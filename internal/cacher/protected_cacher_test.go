@@ -0,0 +1,343 @@
+package cacher
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/stretchr/testify/require"
+)
+
+const testPepper = "test-pepper"
+
+func newProtected(t *testing.T, path string, mode config.CacheProtection) Cacher {
+	t.Helper()
+	c, err := newProtectedCacher(newJSONFileCacher(path), mode, testPepper)
+	require.NoError(t, err)
+	return c
+}
+
+func requireFileNotContains(t *testing.T, path string, secrets ...string) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	for _, s := range secrets {
+		require.NotContains(t, string(raw), s)
+	}
+}
+
+func TestNewProtector_UnknownMode(t *testing.T) {
+	t.Parallel()
+
+	_, err := newProtector("ROT13", testPepper)
+	require.Error(t, err)
+}
+
+func TestProtectedCacher_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		mode        config.CacheProtection
+		wantHiddenV bool
+	}{
+		{name: "hash hides keys", mode: config.CacheProtectionHash},
+		{name: "encrypt hides keys and values", mode: config.CacheProtectionEncrypt, wantHiddenV: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := tempFilePath(t)
+			c := newProtected(t, path, tc.mode)
+			key := NewTeamKey("Project Apollo")
+
+			mustSet(t, c, key, "team-id-1")
+			mustSet(t, c, key, "team-id-1")
+			mustSet(t, c, NewChannelKey("team-id-1", "Secret Channel"), "chan-id-1")
+
+			requireCacheHitWithIDs(t, c, key, []string{"team-id-1"})
+			requireFileNotContains(t, path, "Project Apollo", "Secret Channel")
+			if tc.wantHiddenV {
+				requireFileNotContains(t, path, "team-id-1", "chan-id-1")
+			}
+
+			require.NoError(t, c.Invalidate(key))
+			requireCacheMiss(t, c, key)
+
+			stats, err := c.(Inspector).Stats()
+			require.NoError(t, err)
+			require.Equal(t, map[KeyType]int{Channel: 1}, stats.EntriesByType)
+		})
+	}
+}
+
+func TestProtectedCacher_DifferentPepper_Misses(t *testing.T) {
+	t.Parallel()
+
+	path := tempFilePath(t)
+	mustSet(t, newProtected(t, path, config.CacheProtectionEncrypt), NewTeamKey("A"), "id-1")
+
+	other, err := newProtectedCacher(newJSONFileCacher(path), config.CacheProtectionEncrypt, "other-pepper")
+	require.NoError(t, err)
+	requireCacheMiss(t, other, NewTeamKey("A"))
+}
+
+func TestProtectedCacher_Entries(t *testing.T) {
+	t.Parallel()
+
+	path := tempFilePath(t)
+	c := newProtected(t, path, config.CacheProtectionEncrypt)
+	mustSet(t, c, NewTeamKey("Team A"), "team-1")
+
+	entries, err := c.(Inspector).Entries()
+	require.NoError(t, err)
+	require.Equal(t, []Entry{{Type: Team, Key: "Team A", Values: []string{"team-1"}}}, entries)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.(Inspector).Export(&buf))
+	require.NotContains(t, buf.String(), "Team A")
+}
+
+func TestProtectedCacher_Import(t *testing.T) {
+	t.Parallel()
+
+	t.Run("plain export is encrypted on import", func(t *testing.T) {
+		t.Parallel()
+
+		plain := newJSONFileCacher(tempFilePath(t))
+		mustSet(t, plain, NewTeamKey("Team A"), "team-1")
+		mustSet(t, plain, NewChannelKey("team-1", "General"), "chan-1")
+		var buf bytes.Buffer
+		require.NoError(t, plain.(Inspector).Export(&buf))
+
+		path := tempFilePath(t)
+		c := newProtected(t, path, config.CacheProtectionEncrypt)
+		require.NoError(t, c.(Inspector).Import(&buf))
+
+		requireFileNotContains(t, path, "Team A", "General", "team-1", "chan-1")
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+		requireCacheHitWithIDs(t, c, NewChannelKey("team-1", "General"), []string{"chan-1"})
+	})
+
+	t.Run("encrypted export round-trips", func(t *testing.T) {
+		t.Parallel()
+
+		src := newProtected(t, tempFilePath(t), config.CacheProtectionEncrypt)
+		mustSet(t, src, NewTeamKey("Team A"), "team-1")
+		var buf bytes.Buffer
+		require.NoError(t, src.(Inspector).Export(&buf))
+
+		dst := newProtected(t, tempFilePath(t), config.CacheProtectionEncrypt)
+		require.NoError(t, dst.(Inspector).Import(&buf))
+		requireCacheHitWithIDs(t, dst, NewTeamKey("Team A"), []string{"team-1"})
+	})
+
+	t.Run("hashed export cannot be encrypted", func(t *testing.T) {
+		t.Parallel()
+
+		src := newProtected(t, tempFilePath(t), config.CacheProtectionHash)
+		mustSet(t, src, NewTeamKey("Team A"), "team-1")
+		var buf bytes.Buffer
+		require.NoError(t, src.(Inspector).Export(&buf))
+
+		dst := newProtected(t, tempFilePath(t), config.CacheProtectionEncrypt)
+		require.ErrorIs(t, dst.(Inspector).Import(&buf), ErrIrreversibleProtection)
+	})
+
+	t.Run("protected export is rejected by plain cache", func(t *testing.T) {
+		t.Parallel()
+
+		src := newProtected(t, tempFilePath(t), config.CacheProtectionEncrypt)
+		mustSet(t, src, NewTeamKey("Team A"), "team-1")
+		var buf bytes.Buffer
+		require.NoError(t, src.(Inspector).Export(&buf))
+
+		require.Error(t, newJSONFileCacher(tempFilePath(t)).(Inspector).Import(&buf))
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("plain -> encrypted", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		plain := newJSONFileCacher(path)
+		mustSet(t, plain, NewTeamKey("Team A"), "team-1")
+		mustSet(t, plain, NewGroupChatKey("Confidential topic"), "chat-1")
+
+		c := newProtected(t, path, config.CacheProtectionEncrypt)
+		require.NoError(t, migrateWithPepper(c, testPepper))
+
+		requireFileNotContains(t, path, "Team A", "Confidential topic", "team-1")
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+		requireCacheHitWithIDs(t, c, NewGroupChatKey("Confidential topic"), []string{"chat-1"})
+
+		require.NoError(t, migrateWithPepper(c, testPepper), "migration is idempotent")
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+	})
+
+	t.Run("encrypted -> plain", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		mustSet(t, newProtected(t, path, config.CacheProtectionEncrypt), NewTeamKey("Team A"), "team-1")
+
+		plain := newJSONFileCacher(path)
+		require.NoError(t, migrateWithPepper(plain, testPepper))
+
+		requireFileHasKey(t, path, NewTeamKey("Team A"), []string{"team-1"})
+		requireFileMissingKey(t, path, protectionMarkerKey())
+	})
+
+	t.Run("plain -> hashed", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		mustSet(t, newJSONFileCacher(path), NewTeamKey("Team A"), "team-1")
+
+		c := newProtected(t, path, config.CacheProtectionHash)
+		require.NoError(t, migrateWithPepper(c, testPepper))
+
+		requireFileNotContains(t, path, "Team A")
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+	})
+
+	t.Run("hashed -> plain returns error", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		mustSet(t, newProtected(t, path, config.CacheProtectionHash), NewTeamKey("Team A"), "team-1")
+
+		err := migrateWithPepper(newJSONFileCacher(path), testPepper)
+		require.ErrorIs(t, err, ErrIrreversibleProtection)
+	})
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+
+	const newPepper = "new-pepper"
+
+	t.Run("plain cache keeps names and drops user hashes", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		c := newJSONFileCacher(path)
+		oldPep := testPepper
+		mustSet(t, c, NewTeamKey("Team A"), "team-1")
+		mustSet(t, c, NewTeamMemberKey("team-1", "alice@example.com", &oldPep), "member-1")
+
+		require.NoError(t, rotateWithPepper(c, testPepper, newPepper))
+
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+		requireFileMissingKey(t, path, NewTeamMemberKey("team-1", "alice@example.com", &oldPep))
+	})
+
+	t.Run("encrypted cache is re-encrypted with the new pepper", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		c := newProtected(t, path, config.CacheProtectionEncrypt)
+		mustSet(t, c, NewTeamKey("Team A"), "team-1")
+
+		require.NoError(t, rotateWithPepper(c, testPepper, newPepper))
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+
+		old := newProtected(t, path, config.CacheProtectionEncrypt)
+		requireCacheMiss(t, old, NewTeamKey("Team A"))
+
+		fresh, err := newProtectedCacher(newJSONFileCacher(path), config.CacheProtectionEncrypt, newPepper)
+		require.NoError(t, err)
+		requireCacheHitWithIDs(t, fresh, NewTeamKey("Team A"), []string{"team-1"})
+	})
+
+	t.Run("handler uses the new pepper", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		oldPep := testPepper
+		h := &CacheHandler{Cacher: newProtected(t, path, config.CacheProtectionEncrypt), pepper: &oldPep}
+		mustSet(t, h.Cacher, NewTeamKey("Team A"), "team-1")
+
+		require.NoError(t, Rotate(h, newPepper))
+		require.Equal(t, newPepper, *h.Pepper())
+		requireCacheHitWithIDs(t, h.Cacher, NewTeamKey("Team A"), []string{"team-1"})
+	})
+
+	t.Run("hashed cache is cleared", func(t *testing.T) {
+		t.Parallel()
+
+		path := tempFilePath(t)
+		c := newProtected(t, path, config.CacheProtectionHash)
+		mustSet(t, c, NewTeamKey("Team A"), "team-1")
+
+		require.NoError(t, rotateWithPepper(c, testPepper, newPepper))
+		requireCacheMiss(t, c, NewTeamKey("Team A"))
+
+		mustSet(t, c, NewTeamKey("Team A"), "team-1")
+		requireCacheHitWithIDs(t, c, NewTeamKey("Team A"), []string{"team-1"})
+	})
+}
+
+var errImport = errors.New("import failed")
+
+// failingImportCacher fails the given number of imports before passing them to the file cacher.
+type failingImportCacher struct {
+	*jSONFileCacher
+	failures int
+}
+
+func (c *failingImportCacher) Import(r io.Reader) error {
+	if c.failures > 0 {
+		c.failures--
+		return errImport
+	}
+	return c.jSONFileCacher.Import(r)
+}
+
+func TestRotate_ImportFails(t *testing.T) {
+	t.Parallel()
+
+	const newPepper = "new-pepper"
+
+	newHandler := func(t *testing.T, failures int) *CacheHandler {
+		t.Helper()
+		inner := &failingImportCacher{jSONFileCacher: newJSONFileCacher(tempFilePath(t)).(*jSONFileCacher), failures: failures}
+		c, err := newProtectedCacher(inner, config.CacheProtectionEncrypt, testPepper)
+		require.NoError(t, err)
+		oldPep := testPepper
+		h := &CacheHandler{Cacher: c, pepper: &oldPep}
+		mustSet(t, h.Cacher, NewTeamKey("Team A"), "team-1")
+		return h
+	}
+
+	t.Run("old entries and pepper are kept", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHandler(t, 1)
+		err := Rotate(h, newPepper)
+		require.ErrorIs(t, err, errImport)
+		require.NotErrorIs(t, err, ErrCacheCleared)
+
+		require.Equal(t, testPepper, *h.Pepper())
+		requireCacheHitWithIDs(t, h.Cacher, NewTeamKey("Team A"), []string{"team-1"})
+	})
+
+	t.Run("cache is cleared when restoring fails", func(t *testing.T) {
+		t.Parallel()
+
+		h := newHandler(t, 2)
+		err := Rotate(h, newPepper)
+		require.ErrorIs(t, err, ErrCacheCleared)
+
+		require.Equal(t, testPepper, *h.Pepper())
+		requireCacheMiss(t, h.Cacher, NewTeamKey("Team A"))
+	})
+}
@@ -4,9 +4,13 @@ import "github.com/pzsp-teams/lib/config"
 
 var Singleton *CacheHandler
 
-func GetCacheHandler(cfg *config.CacheConfig) *CacheHandler {
+func GetCacheHandler(cfg *config.CacheConfig) (*CacheHandler, error) {
 	if Singleton == nil {
-		Singleton = NewCacheHandler(cfg)
+		handler, err := NewCacheHandler(cfg)
+		if err != nil {
+			return nil, err
+		}
+		Singleton = handler
 	}
-	return Singleton
+	return Singleton, nil
 }
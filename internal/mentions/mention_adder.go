@@ -67,3 +67,15 @@ func (a *MentionAdder) AddUserMention(ctx context.Context, userRef string, userA
 	a.Add(models.MentionUser, id, dn)
 	return nil
 }
+
+// ByToken pairs mention tokens with the mentions resolved from them, which GetMentions returns in order.
+func ByToken(tokens []string, ments []models.Mention) (map[string]models.Mention, error) {
+	if len(tokens) != len(ments) {
+		return nil, fmt.Errorf("resolved %d mentions for %d mention references", len(ments), len(tokens))
+	}
+	out := make(map[string]models.Mention, len(tokens))
+	for i, token := range tokens {
+		out[token] = ments[i]
+	}
+	return out, nil
+}
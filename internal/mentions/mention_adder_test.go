@@ -299,3 +299,19 @@ func TestMentionAdder_AddUserMention(t *testing.T) {
 		})
 	}
 }
+
+func TestByToken(t *testing.T) {
+	t.Parallel()
+
+	ments := []models.Mention{
+		{Kind: models.MentionUser, TargetID: "u-1", Text: "Alice"},
+		{Kind: models.MentionEveryone, TargetID: "chat-1", Text: "Everyone"},
+	}
+
+	got, err := ByToken([]string{"alice@example.com", "everyone"}, ments)
+	require.NoError(t, err)
+	require.Equal(t, map[string]models.Mention{"alice@example.com": ments[0], "everyone": ments[1]}, got)
+
+	_, err = ByToken([]string{"alice@example.com"}, ments)
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package mentions
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
@@ -9,6 +10,9 @@ import (
 	"github.com/pzsp-teams/lib/models"
 )
 
+// atTagStart matches the start of an <at> tag but not of other tags such as <attachment>.
+var atTagStart = regexp.MustCompile(`<at[\s>]`)
+
 type convCfg struct {
 	identityType msmodels.TeamworkConversationIdentityType
 	validate     func(string) bool
@@ -43,7 +47,7 @@ func validateAtTags(body *models.MessageBody) error {
 	if body.ContentType != models.MessageContentTypeHTML {
 		return nil
 	}
-	if strings.Contains(body.Content, "<at") && len(body.Mentions) == 0 {
+	if atTagStart.MatchString(body.Content) && len(body.Mentions) == 0 {
 		return fmt.Errorf("content contains <at> tags but mentions list is empty")
 	}
 	seen := map[int32]struct{}{}
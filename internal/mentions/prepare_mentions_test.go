@@ -169,6 +169,15 @@ func Test_validateAtTags(t *testing.T) {
 			},
 			want: "mentions list is empty",
 		},
+		{
+			name: "<attachment> tag without mentions -> ok",
+			body: &models.MessageBody{
+				ContentType: models.MessageContentTypeHTML,
+				Content:     `<attachment id="card-1"></attachment>`,
+				Mentions:    nil,
+			},
+			want: "",
+		},
 		{
 			name: "duplicate AtID -> error",
 			body: &models.MessageBody{
@@ -0,0 +1,33 @@
+package msgstore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// Sorted returns messages of the conversation, newest first.
+func (c *Conversation) Sorted() []*models.Message {
+	if c == nil {
+		return nil
+	}
+	out := make([]*models.Message, 0, len(c.Messages))
+	for _, msg := range c.Messages {
+		out = append(out, msg)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].CreatedDateTime.Equal(out[j].CreatedDateTime) {
+			return out[i].ID > out[j].ID
+		}
+		return out[i].CreatedDateTime.After(out[j].CreatedDateTime)
+	})
+	return out
+}
+
+func lastModified(msg *models.Message) time.Time {
+	if msg.LastModifiedDateTime != nil {
+		return *msg.LastModifiedDateTime
+	}
+	return msg.CreatedDateTime
+}
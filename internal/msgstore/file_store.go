@@ -0,0 +1,228 @@
+package msgstore
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+type jSONFileStore struct {
+	mu            sync.Mutex
+	file          string
+	conversations map[string]*Conversation
+	index         *index
+	loaded        bool
+}
+
+func newJSONFileStore(path string) Store {
+	return &jSONFileStore{
+		file: path,
+	}
+}
+
+func (s *jSONFileStore) Get(conversation, messageID string) (*models.Message, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return nil, false, err
+	}
+	conv, ok := s.conversations[conversation]
+	if !ok {
+		return nil, false, nil
+	}
+	msg, ok := conv.Messages[messageID]
+	if !ok || msg == nil {
+		return nil, false, nil
+	}
+	local := *msg
+	return &local, true, nil
+}
+
+func (s *jSONFileStore) Load(conversation string) (*Conversation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return nil, err
+	}
+	conv, ok := s.conversations[conversation]
+	if !ok {
+		return nil, nil
+	}
+	out := &Conversation{
+		Messages: make(map[string]*models.Message, len(conv.Messages)),
+		SyncedAt: conv.SyncedAt,
+		NextLink: conv.NextLink,
+		PageSize: conv.PageSize,
+	}
+	for id, msg := range conv.Messages {
+		local := *msg
+		out.Messages[id] = &local
+	}
+	return out, nil
+}
+
+func (s *jSONFileStore) Put(conversation string, messages ...*models.Message) error {
+	return s.put(conversation, messages, false)
+}
+
+func (s *jSONFileStore) Delete(conversation string, messageIDs ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return err
+	}
+	conv, ok := s.conversations[conversation]
+	if !ok {
+		return nil
+	}
+	for _, id := range messageIDs {
+		delete(conv.Messages, id)
+		s.index.remove(ref{conversation: conversation, messageID: id})
+	}
+	return s.persistLocked()
+}
+
+func (s *jSONFileStore) Sync(conversation string, messages []*models.Message) error {
+	return s.put(conversation, messages, true)
+}
+
+func (s *jSONFileStore) put(conversation string, messages []*models.Message, advance bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return err
+	}
+	conv := s.conversationLocked(conversation)
+	for _, msg := range messages {
+		if msg == nil || msg.ID == "" {
+			continue
+		}
+		local := *msg
+		conv.Messages[msg.ID] = &local
+		s.index.add(conversation, &local)
+		if !advance {
+			continue
+		}
+		modified := lastModified(&local)
+		if conv.SyncedAt == nil || modified.After(*conv.SyncedAt) {
+			conv.SyncedAt = &modified
+		}
+	}
+	return s.persistLocked()
+}
+
+func (s *jSONFileStore) SetNextLink(conversation string, nextLink *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return err
+	}
+	s.conversationLocked(conversation).NextLink = nextLink
+	return s.persistLocked()
+}
+
+func (s *jSONFileStore) SetPageSize(conversation string, size int32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return err
+	}
+	s.conversationLocked(conversation).PageSize = size
+	return s.persistLocked()
+}
+
+func (s *jSONFileStore) Conversations() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(s.conversations))
+	for key := range s.conversations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *jSONFileStore) Search(terms []string) ([]Hit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.ensureLoadedLocked(); err != nil {
+		return nil, err
+	}
+	refs := s.index.lookup(terms)
+	out := make([]Hit, 0, len(refs))
+	for _, r := range refs {
+		msg := s.conversations[r.conversation].Messages[r.messageID]
+		local := *msg
+		out = append(out, Hit{Conversation: r.conversation, Message: &local})
+	}
+	return out, nil
+}
+
+func (s *jSONFileStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conversations = make(map[string]*Conversation)
+	s.index = newIndex()
+	s.loaded = true
+	return s.persistLocked()
+}
+
+func (s *jSONFileStore) conversationLocked(conversation string) *Conversation {
+	conv, ok := s.conversations[conversation]
+	if !ok {
+		conv = &Conversation{Messages: make(map[string]*models.Message)}
+		s.conversations[conversation] = conv
+	}
+	return conv
+}
+
+func (s *jSONFileStore) ensureLoadedLocked() error {
+	if s.loaded {
+		return nil
+	}
+	data, err := os.ReadFile(s.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.conversations = make(map[string]*Conversation)
+			s.index = newIndex()
+			s.loaded = true
+			return nil
+		}
+		return err
+	}
+	s.conversations = make(map[string]*Conversation)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.conversations); err != nil {
+			return err
+		}
+	}
+	s.index = newIndex()
+	for key, conv := range s.conversations {
+		if conv.Messages == nil {
+			conv.Messages = make(map[string]*models.Message)
+		}
+		for id, msg := range conv.Messages {
+			if msg == nil {
+				delete(conv.Messages, id)
+				continue
+			}
+			s.index.add(key, msg)
+		}
+	}
+	s.loaded = true
+	return nil
+}
+
+func (s *jSONFileStore) persistLocked() error {
+	data, err := json.MarshalIndent(s.conversations, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.file, data, 0o600)
+}
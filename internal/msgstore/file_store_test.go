@@ -0,0 +1,269 @@
+package msgstore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+func tempStore(t *testing.T) (Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "messages.json")
+	return newJSONFileStore(path), path
+}
+
+func newMessage(id string, created time.Time, modified *time.Time) *models.Message {
+	return &models.Message{
+		ID:                   id,
+		Content:              "content " + id,
+		ContentType:          models.MessageContentTypeText,
+		CreatedDateTime:      created,
+		LastModifiedDateTime: modified,
+	}
+}
+
+var baseTime = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestJSONFileStore_PutGet(t *testing.T) {
+	t.Parallel()
+
+	s, path := tempStore(t)
+	key := ChannelKey("team-1", "chan-1")
+
+	_, found, err := s.Get(key, "m1")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, s.Put(key, newMessage("m1", baseTime, nil), nil, &models.Message{}))
+
+	got, found, err := s.Get(key, "m1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "content m1", got.Content)
+
+	conv, err := s.Load(key)
+	require.NoError(t, err)
+	require.Len(t, conv.Messages, 1)
+	require.Nil(t, conv.SyncedAt, "Put does not advance sync time")
+
+	reloaded := newJSONFileStore(path)
+	got, found, err = reloaded.Get(key, "m1")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "content m1", got.Content)
+}
+
+func TestJSONFileStore_Delete(t *testing.T) {
+	t.Parallel()
+
+	s, _ := tempStore(t)
+	key := ChatKey("chat-1")
+	require.NoError(t, s.Put(key, newMessage("m1", baseTime, nil), newMessage("m2", baseTime, nil)))
+
+	require.NoError(t, s.Delete(key, "m1", "missing"))
+	require.NoError(t, s.Delete(ChatKey("other"), "m2"))
+
+	_, found, err := s.Get(key, "m1")
+	require.NoError(t, err)
+	require.False(t, found)
+	_, found, err = s.Get(key, "m2")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	hits, err := s.Search([]string{"m1"})
+	require.NoError(t, err)
+	require.Empty(t, hits)
+}
+
+func TestJSONFileStore_Sync(t *testing.T) {
+	t.Parallel()
+
+	s, _ := tempStore(t)
+	key := ChatKey("chat-1")
+
+	edited := baseTime.Add(time.Hour)
+	require.NoError(t, s.Sync(key, []*models.Message{
+		newMessage("m1", baseTime, &edited),
+		newMessage("m2", baseTime.Add(time.Minute), nil),
+	}))
+
+	conv, err := s.Load(key)
+	require.NoError(t, err)
+	require.Equal(t, edited, *conv.SyncedAt)
+
+	require.NoError(t, s.Sync(key, []*models.Message{newMessage("m0", baseTime.Add(-time.Hour), nil)}))
+	conv, err = s.Load(key)
+	require.NoError(t, err)
+	require.Equal(t, edited, *conv.SyncedAt, "older messages do not move sync time back")
+	require.Len(t, conv.Messages, 3)
+}
+
+func TestJSONFileStore_LoadReturnsCopy(t *testing.T) {
+	t.Parallel()
+
+	s, _ := tempStore(t)
+	key := ChatKey("chat-1")
+	require.NoError(t, s.Put(key, newMessage("m1", baseTime, nil)))
+
+	conv, err := s.Load(key)
+	require.NoError(t, err)
+	conv.Messages["m1"].Content = "changed"
+
+	got, _, err := s.Get(key, "m1")
+	require.NoError(t, err)
+	require.Equal(t, "content m1", got.Content)
+
+	missing, err := s.Load(ChatKey("other"))
+	require.NoError(t, err)
+	require.Nil(t, missing)
+}
+
+func TestJSONFileStore_ConversationsAndClear(t *testing.T) {
+	t.Parallel()
+
+	s, _ := tempStore(t)
+	require.NoError(t, s.Put(ChatKey("b"), newMessage("m1", baseTime, nil)))
+	require.NoError(t, s.SetNextLink(ChannelKey("t", "a"), util.Ptr("next")))
+
+	keys, err := s.Conversations()
+	require.NoError(t, err)
+	require.Equal(t, []string{"channel:t:a", "chat:b"}, keys)
+
+	require.NoError(t, s.Clear())
+	keys, err = s.Conversations()
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+func TestJSONFileStore_InvalidFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := newJSONFileStore(dir)
+
+	_, _, err := s.Get(ChatKey("c"), "m")
+	require.Error(t, err)
+}
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+
+	conv := &Conversation{
+		Messages: map[string]*models.Message{
+			"old": newMessage("old", baseTime, nil),
+			"new": newMessage("new", baseTime.Add(time.Hour), nil),
+			"mid": newMessage("mid", baseTime.Add(time.Minute), nil),
+		},
+		NextLink: util.Ptr("next"),
+	}
+
+	t.Run("all messages newest first with next link", func(t *testing.T) {
+		t.Parallel()
+
+		page := Page(conv, nil)
+		require.Equal(t, []string{"new", "mid", "old"}, util.MapSlices(page.Messages, func(m *models.Message) string { return m.ID }))
+		require.Equal(t, "next", *page.NextLink)
+	})
+
+	t.Run("top pages through stored messages with a local cursor", func(t *testing.T) {
+		t.Parallel()
+
+		ids := func(c *models.MessageCollection) []string {
+			return util.MapSlices(c.Messages, func(m *models.Message) string { return m.ID })
+		}
+
+		page := Page(conv, util.Ptr(int32(2)))
+		require.Equal(t, []string{"new", "mid"}, ids(page))
+		require.NotNil(t, page.NextLink)
+		require.True(t, IsCursor(*page.NextLink))
+
+		page, err := PageAfter(conv, *page.NextLink)
+		require.NoError(t, err)
+		require.Equal(t, []string{"old"}, ids(page))
+		require.Equal(t, "next", *page.NextLink)
+	})
+
+	t.Run("without top pages have the recorded page size", func(t *testing.T) {
+		t.Parallel()
+
+		sized := *conv
+		sized.PageSize = 2
+		page := Page(&sized, nil)
+		require.Len(t, page.Messages, 2)
+		require.True(t, IsCursor(*page.NextLink))
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := PageAfter(conv, "msgstore:???")
+		require.ErrorIs(t, err, ErrInvalidCursor)
+
+		page := Page(conv, util.Ptr(int32(1)))
+		_, err = PageAfter(nil, *page.NextLink)
+		require.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestNewMessageStore(t *testing.T) {
+	t.Parallel()
+
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+
+	tests := []struct {
+		name     string
+		cfg      *config.CacheConfig
+		wantNil  bool
+		wantErr  error
+		wantPath string
+	}{
+		{name: "nil config", cfg: nil, wantNil: true},
+		{name: "cache disabled", cfg: &config.CacheConfig{Mode: config.CacheDisabled, MessageStore: &config.MessageStoreConfig{}}, wantNil: true},
+		{name: "store not configured", cfg: &config.CacheConfig{Mode: config.CacheSync}, wantNil: true},
+		{
+			name:     "default path next to cache",
+			cfg:      &config.CacheConfig{Mode: config.CacheSync, Path: &cachePath, MessageStore: &config.MessageStoreConfig{}},
+			wantPath: filepath.Join(filepath.Dir(cachePath), "messages.json"),
+		},
+		{
+			name:     "configured path",
+			cfg:      &config.CacheConfig{Mode: config.CacheSync, MessageStore: &config.MessageStoreConfig{Path: util.Ptr("messages.json")}},
+			wantPath: "messages.json",
+		},
+		{
+			name:    "cache protection",
+			cfg:     &config.CacheConfig{Mode: config.CacheSync, Protection: config.CacheProtectionEncrypt, MessageStore: &config.MessageStoreConfig{}},
+			wantErr: ErrProtectedStore,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var before *string
+			if tc.cfg != nil && tc.cfg.MessageStore != nil {
+				before = tc.cfg.MessageStore.Path
+			}
+
+			s, err := NewMessageStore(tc.cfg)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tc.wantNil {
+				require.Nil(t, s)
+				return
+			}
+			require.NotNil(t, s)
+			require.Equal(t, tc.wantPath, s.(*jSONFileStore).file)
+			require.Same(t, before, tc.cfg.MessageStore.Path, "config is not changed")
+		})
+	}
+}
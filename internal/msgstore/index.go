@@ -0,0 +1,160 @@
+package msgstore
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// ref identifies a stored message.
+type ref struct {
+	conversation string
+	messageID    string
+}
+
+// index is an in-memory inverted index of words used in stored messages.
+type index struct {
+	postings map[string]map[ref]struct{}
+	words    map[ref][]string
+}
+
+func newIndex() *index {
+	return &index{
+		postings: make(map[string]map[ref]struct{}),
+		words:    make(map[ref][]string),
+	}
+}
+
+func (ix *index) add(conversation string, msg *models.Message) {
+	r := ref{conversation: conversation, messageID: msg.ID}
+	ix.remove(r)
+
+	words := uniqueWords(tokenize(plainText(msg)))
+	ix.words[r] = words
+	for _, w := range words {
+		refs, ok := ix.postings[w]
+		if !ok {
+			refs = make(map[ref]struct{})
+			ix.postings[w] = refs
+		}
+		refs[r] = struct{}{}
+	}
+}
+
+func (ix *index) remove(r ref) {
+	for _, w := range ix.words[r] {
+		delete(ix.postings[w], r)
+		if len(ix.postings[w]) == 0 {
+			delete(ix.postings, w)
+		}
+	}
+	delete(ix.words, r)
+}
+
+// lookup returns messages containing all the terms, or all indexed messages when there are none.
+// A term ending with "*" matches any word starting with it.
+func (ix *index) lookup(terms []string) []ref {
+	var matched map[ref]struct{}
+	for _, term := range terms {
+		for _, refs := range ix.termRefs(term) {
+			matched = intersect(matched, refs)
+		}
+		if matched != nil && len(matched) == 0 {
+			return nil
+		}
+	}
+
+	if matched == nil {
+		out := make([]ref, 0, len(ix.words))
+		for r := range ix.words {
+			out = append(out, r)
+		}
+		return out
+	}
+	out := make([]ref, 0, len(matched))
+	for r := range matched {
+		out = append(out, r)
+	}
+	return out
+}
+
+// termRefs returns one set of messages per word of the term; a message must be in all of them.
+func (ix *index) termRefs(term string) []map[ref]struct{} {
+	prefix := strings.HasSuffix(term, "*")
+	words := tokenize(strings.TrimSuffix(term, "*"))
+	out := make([]map[ref]struct{}, 0, len(words))
+	for i, w := range words {
+		if prefix && i == len(words)-1 {
+			out = append(out, ix.prefixRefs(w))
+			continue
+		}
+		refs := ix.postings[w]
+		if refs == nil {
+			refs = map[ref]struct{}{}
+		}
+		out = append(out, refs)
+	}
+	return out
+}
+
+func (ix *index) prefixRefs(prefix string) map[ref]struct{} {
+	out := make(map[ref]struct{})
+	for w, refs := range ix.postings {
+		if !strings.HasPrefix(w, prefix) {
+			continue
+		}
+		for r := range refs {
+			out[r] = struct{}{}
+		}
+	}
+	return out
+}
+
+func intersect(acc, refs map[ref]struct{}) map[ref]struct{} {
+	if acc == nil {
+		out := make(map[ref]struct{}, len(refs))
+		for r := range refs {
+			out[r] = struct{}{}
+		}
+		return out
+	}
+	for r := range acc {
+		if _, ok := refs[r]; !ok {
+			delete(acc, r)
+		}
+	}
+	return acc
+}
+
+// plainText returns the message content without HTML markup.
+func plainText(msg *models.Message) string {
+	if msg.ContentType != models.MessageContentTypeHTML {
+		return msg.Content
+	}
+	return html.UnescapeString(tagPattern.ReplaceAllString(msg.Content, " "))
+}
+
+// tokenize splits text into lower-cased words made of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func uniqueWords(words []string) []string {
+	seen := make(map[string]struct{}, len(words))
+	out := make([]string, 0, len(words))
+	for _, w := range words {
+		if _, ok := seen[w]; ok {
+			continue
+		}
+		seen[w] = struct{}{}
+		out = append(out, w)
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package msgstore
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// DefaultPageSize limits pages served from the store when the page size of Graph is not known.
+const DefaultPageSize int32 = 20
+
+// cursorPrefix starts NextLinks of pages served from the store, telling them apart from Graph links.
+const cursorPrefix = "msgstore:"
+
+// ErrInvalidCursor is returned when a page cannot be continued from the store,
+// e.g. because the store was cleared in the meantime. Listing has to start over.
+var ErrInvalidCursor = errors.New("invalid message store cursor, list messages again")
+
+// cursor points past the last message of a page served from the store.
+type cursor struct {
+	Top       int32     `json:"top"`
+	CreatedAt time.Time `json:"createdAt"`
+	ID        string    `json:"id"`
+}
+
+// Page builds a message collection from the stored conversation, newest messages first.
+//
+// Without top, pages have the size of the first page read from Graph (see RecordFirstPage),
+// or DefaultPageSize if it is not known. When more messages are stored, NextLink is a local cursor
+// to be passed to PageAfter (see IsCursor). After the last stored message, NextLink points
+// to messages older than any stored ones.
+func Page(conv *Conversation, top *int32) *models.MessageCollection {
+	limit := DefaultPageSize
+	if top != nil {
+		limit = *top
+	} else if conv.PageSize > 0 {
+		limit = conv.PageSize
+	}
+	return page(conv, conv.Sorted(), limit)
+}
+
+// RecordFirstPage stores the first page of a conversation read from Graph
+// together with its link to older messages and, if more pages follow, its size.
+func RecordFirstPage(s Store, conversation string, first *models.MessageCollection) error {
+	if err := s.Sync(conversation, first.Messages); err != nil {
+		return err
+	}
+	if err := s.SetNextLink(conversation, first.NextLink); err != nil {
+		return err
+	}
+	if first.NextLink == nil || len(first.Messages) == 0 {
+		return nil
+	}
+	return s.SetPageSize(conversation, int32(len(first.Messages)))
+}
+
+// PageAfter continues listing the stored conversation from a cursor returned by Page or PageAfter.
+// Messages stored in the meantime are skipped if they are newer than the cursor.
+func PageAfter(conv *Conversation, nextLink string) (*models.MessageCollection, error) {
+	cur, ok := parseCursor(nextLink)
+	if !ok || conv == nil {
+		return nil, ErrInvalidCursor
+	}
+	messages := conv.Sorted()
+	start := sort.Search(len(messages), func(i int) bool {
+		m := messages[i]
+		return m.CreatedDateTime.Before(cur.CreatedAt) || (m.CreatedDateTime.Equal(cur.CreatedAt) && m.ID < cur.ID)
+	})
+	return page(conv, messages[start:], cur.Top), nil
+}
+
+// AdvanceNextLink replaces the stored link to older messages once the page it points to was fetched
+// from Graph, so pages served from the store afterwards continue past that page.
+// Following any other link leaves the stored one unchanged.
+func AdvanceNextLink(s Store, conversation, followed string, next *string) error {
+	conv, err := s.Load(conversation)
+	if err != nil || conv == nil || conv.NextLink == nil || *conv.NextLink != followed {
+		return err
+	}
+	return s.SetNextLink(conversation, next)
+}
+
+// IsCursor reports whether nextLink is a local cursor of the store rather than a Graph link.
+func IsCursor(nextLink string) bool {
+	return strings.HasPrefix(nextLink, cursorPrefix)
+}
+
+func page(conv *Conversation, messages []*models.Message, top int32) *models.MessageCollection {
+	nextLink := conv.NextLink
+	if top > 0 && len(messages) > int(top) {
+		messages = messages[:top]
+		last := messages[len(messages)-1]
+		link := newCursor(cursor{Top: top, CreatedAt: last.CreatedDateTime, ID: last.ID})
+		nextLink = &link
+	}
+	return &models.MessageCollection{
+		Messages: messages,
+		NextLink: nextLink,
+	}
+}
+
+func newCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return cursorPrefix + base64.RawURLEncoding.EncodeToString(data)
+}
+
+func parseCursor(nextLink string) (cursor, bool) {
+	if !IsCursor(nextLink) {
+		return cursor{}, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(nextLink, cursorPrefix))
+	if err != nil {
+		return cursor{}, false
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return cursor{}, false
+	}
+	return c, true
+}
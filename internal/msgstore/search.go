@@ -0,0 +1,316 @@
+package msgstore
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/pzsp-teams/lib/search"
+)
+
+const defaultSearchPageSize = 25
+
+// ErrFilterNotSupported is returned when search options with a filter expression are run locally.
+var ErrFilterNotSupported = errors.New("filter expressions are not supported by local search")
+
+// Scope decides whether messages of a conversation take part in a search.
+type Scope func(conversation string) bool
+
+// ChannelScope limits a search to channels, optionally of a single team or a single channel.
+func ChannelScope(teamID, channelID *string) Scope {
+	return func(conversation string) bool {
+		t, c, ok := parseChannelKey(conversation)
+		if !ok {
+			return false
+		}
+		return (teamID == nil || *teamID == t) && (channelID == nil || *channelID == c)
+	}
+}
+
+// ChatScope limits a search to chats, optionally to a single chat.
+func ChatScope(chatID *string) Scope {
+	return func(conversation string) bool {
+		id, ok := parseChatKey(conversation)
+		return ok && (chatID == nil || *chatID == id)
+	}
+}
+
+// NeedsCurrentUser reports whether the options refer to the current user,
+// which must then be passed to Search.
+func NeedsCurrentUser(opts *search.SearchMessagesOptions) bool {
+	return opts.IsMentioned != nil || opts.FromMe || opts.NotFromMe || opts.ToMe || opts.NotToMe
+}
+
+// Search runs the search options against messages kept in the store.
+//
+// The query matches messages containing all of its words; quoted phrases must appear verbatim,
+// words prefixed with "-" or preceded by NOT must not appear, and a trailing "*" matches word prefixes.
+// Email addresses are not stored locally, so From and NotFrom match senders by user ID or display name,
+// while To and NotTo match mentioned users or conversations by ID or mention text.
+// ToMe and IsMentioned both select messages mentioning the current user. IsRead is ignored
+// and filter expressions are rejected with ErrFilterNotSupported.
+//
+// Interval is resolved in opts.TimeZone at now. Hits are ordered newest first,
+// or oldest first with SortByCreatedAsc, and paged with opts.SearchPage.
+func Search(store Store, scope Scope, opts *search.SearchMessagesOptions, me *models.MessageFrom, now time.Time) (*search.SearchResults, error) {
+	if opts.Filter != nil {
+		return nil, ErrFilterNotSupported
+	}
+	q := parseSearchQuery(util.Deref(opts.Query))
+	hits, err := store.Search(q.terms)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := newSearchFilter(opts, me, now)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]Hit, 0, len(hits))
+	for _, hit := range hits {
+		if scope != nil && !scope(hit.Conversation) {
+			continue
+		}
+		if !q.matches(hit.Message) || !f.matches(hit.Message) {
+			continue
+		}
+		matched = append(matched, hit)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := matched[i].Message, matched[j].Message
+		if opts.Sort == search.SortByCreatedAsc {
+			a, b = b, a
+		}
+		if a.CreatedDateTime.Equal(b.CreatedDateTime) {
+			return a.ID > b.ID
+		}
+		return a.CreatedDateTime.After(b.CreatedDateTime)
+	})
+
+	return pageHits(matched, opts.SearchPage), nil
+}
+
+func pageHits(hits []Hit, page *search.SearchPage) *search.SearchResults {
+	from, size := int32(0), int32(defaultSearchPageSize)
+	if page != nil && page.From != nil && *page.From > 0 {
+		from = *page.From
+	}
+	if page != nil && page.Size != nil && *page.Size > 0 {
+		size = *page.Size
+	}
+
+	total := int32(len(hits))
+	out := &search.SearchResults{Messages: []*search.SearchResult{}, Total: &total}
+	if int(from) >= len(hits) {
+		return out
+	}
+	end := min(int(from+size), len(hits))
+	for _, hit := range hits[from:end] {
+		out.Messages = append(out.Messages, newSearchResult(hit))
+	}
+	if end < len(hits) {
+		next := int32(end)
+		out.NextFrom = &next
+		out.MoreResultsAvailable = true
+	}
+	return out
+}
+
+func newSearchResult(hit Hit) *search.SearchResult {
+	res := &search.SearchResult{Message: hit.Message}
+	if teamID, channelID, ok := parseChannelKey(hit.Conversation); ok {
+		res.TeamID = util.Ptr(teamID)
+		res.ChannelID = util.Ptr(channelID)
+	} else if chatID, ok := parseChatKey(hit.Conversation); ok {
+		res.ChatID = util.Ptr(chatID)
+	}
+	return res
+}
+
+// searchQuery is a parsed full-text query.
+type searchQuery struct {
+	terms    []string
+	phrases  [][]string
+	excluded [][]string
+}
+
+func parseSearchQuery(query string) searchQuery {
+	var q searchQuery
+	negate := false
+	for _, part := range splitQuery(query) {
+		text := part.text
+		if !part.quoted {
+			if text == "AND" {
+				continue
+			}
+			if text == "NOT" {
+				negate = true
+				continue
+			}
+			if strings.HasPrefix(text, "-") {
+				negate, text = true, text[1:]
+			}
+			if text == "" {
+				continue
+			}
+		}
+
+		words := tokenize(text)
+		switch {
+		case len(words) == 0:
+		case negate:
+			q.excluded = append(q.excluded, words)
+		case part.quoted:
+			q.terms = append(q.terms, words...)
+			q.phrases = append(q.phrases, words)
+		default:
+			q.terms = append(q.terms, strings.ToLower(text))
+		}
+		negate = false
+	}
+	return q
+}
+
+type queryPart struct {
+	text   string
+	quoted bool
+}
+
+func splitQuery(query string) []queryPart {
+	var out []queryPart
+	for i, chunk := range strings.Split(query, `"`) {
+		if i%2 == 1 {
+			out = append(out, queryPart{text: chunk, quoted: true})
+			continue
+		}
+		for _, field := range strings.Fields(chunk) {
+			out = append(out, queryPart{text: field})
+		}
+	}
+	return out
+}
+
+// matches checks the parts of the query the index cannot answer: phrases and excluded words.
+func (q searchQuery) matches(msg *models.Message) bool {
+	if len(q.phrases) == 0 && len(q.excluded) == 0 {
+		return true
+	}
+	words := tokenize(plainText(msg))
+	text := " " + strings.Join(words, " ") + " "
+	for _, phrase := range q.phrases {
+		if !strings.Contains(text, " "+strings.Join(phrase, " ")+" ") {
+			return false
+		}
+	}
+	for _, phrase := range q.excluded {
+		if strings.Contains(text, " "+strings.Join(phrase, " ")+" ") {
+			return false
+		}
+	}
+	return true
+}
+
+// searchFilter holds the non-text criteria of the search options.
+type searchFilter struct {
+	opts       *search.SearchMessagesOptions
+	me         *models.MessageFrom
+	start, end *time.Time
+}
+
+func newSearchFilter(opts *search.SearchMessagesOptions, me *models.MessageFrom, now time.Time) (*searchFilter, error) {
+	start, end, err := opts.TimeRange(now)
+	if err != nil {
+		return nil, err
+	}
+	return &searchFilter{opts: opts, me: me, start: start, end: end}, nil
+}
+
+func (f *searchFilter) matches(msg *models.Message) bool {
+	opts := f.opts
+	if f.start != nil && msg.CreatedDateTime.Before(*f.start) {
+		return false
+	}
+	if f.end != nil && !msg.CreatedDateTime.Before(*f.end) {
+		return false
+	}
+	if len(opts.From) > 0 && !sentBy(msg, opts.From...) {
+		return false
+	}
+	if len(opts.NotFrom) > 0 && sentBy(msg, opts.NotFrom...) {
+		return false
+	}
+	if len(opts.To) > 0 && !addressedTo(msg, opts.To...) {
+		return false
+	}
+	if len(opts.NotTo) > 0 && addressedTo(msg, opts.NotTo...) {
+		return false
+	}
+	return f.matchesMe(msg)
+}
+
+func (f *searchFilter) matchesMe(msg *models.Message) bool {
+	if f.me == nil {
+		return !NeedsCurrentUser(f.opts)
+	}
+	fromMe := sentBy(msg, f.me.UserID)
+	mentionsMe := mentionsUser(msg, f.me.UserID)
+	switch {
+	case f.opts.FromMe && !fromMe, f.opts.NotFromMe && fromMe:
+		return false
+	case f.opts.ToMe && !mentionsMe, f.opts.NotToMe && mentionsMe:
+		return false
+	case f.opts.IsMentioned != nil && *f.opts.IsMentioned != mentionsMe:
+		return false
+	}
+	return true
+}
+
+func sentBy(msg *models.Message, refs ...string) bool {
+	if msg.From == nil {
+		return false
+	}
+	for _, r := range refs {
+		r = strings.TrimSpace(r)
+		if r != "" && (strings.EqualFold(r, msg.From.UserID) || strings.EqualFold(r, msg.From.DisplayName)) {
+			return true
+		}
+	}
+	return false
+}
+
+func addressedTo(msg *models.Message, refs ...string) bool {
+	for _, m := range msg.Mentions {
+		for _, r := range refs {
+			r = strings.TrimSpace(r)
+			if r != "" && (strings.EqualFold(r, m.TargetID) || strings.EqualFold(r, m.Text)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func mentionsUser(msg *models.Message, userID string) bool {
+	for _, m := range msg.Mentions {
+		if m.Kind == models.MentionUser && m.TargetID != "" && m.TargetID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func parseChannelKey(conversation string) (teamID, channelID string, ok bool) {
+	parts := strings.SplitN(conversation, ":", 3)
+	if len(parts) != 3 || parts[0] != "channel" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+func parseChatKey(conversation string) (string, bool) {
+	id, ok := strings.CutPrefix(conversation, "chat:")
+	return id, ok
+}
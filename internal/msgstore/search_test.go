@@ -0,0 +1,183 @@
+package msgstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/pzsp-teams/lib/search"
+	"github.com/stretchr/testify/require"
+)
+
+func searchFixture(t *testing.T) Store {
+	t.Helper()
+
+	s, _ := tempStore(t)
+	chanKey := ChannelKey("team-1", "19:chan@thread.tacv2")
+	otherKey := ChannelKey("team-2", "19:other@thread.tacv2")
+	chatKey := ChatKey("19:chat@thread.v2")
+
+	require.NoError(t, s.Put(chanKey,
+		&models.Message{
+			ID: "c1", Content: "<p>Deployment of the <b>new</b> release</p>", ContentType: models.MessageContentTypeHTML,
+			CreatedDateTime: baseTime, From: &models.MessageFrom{UserID: "u-alice", DisplayName: "Alice"},
+		},
+		&models.Message{
+			ID: "c2", Content: "release notes are ready", ContentType: models.MessageContentTypeText,
+			CreatedDateTime: baseTime.Add(time.Hour), From: &models.MessageFrom{UserID: "u-bob", DisplayName: "Bob"},
+			Mentions: []models.Mention{{Kind: models.MentionUser, TargetID: "u-me", Text: "Me"}},
+		},
+	))
+	require.NoError(t, s.Put(otherKey,
+		&models.Message{
+			ID: "o1", Content: "old release", CreatedDateTime: baseTime.AddDate(0, -1, 0),
+			From: &models.MessageFrom{UserID: "u-me", DisplayName: "Me"},
+		},
+	))
+	require.NoError(t, s.Put(chatKey,
+		&models.Message{
+			ID: "h1", Content: "lunch &amp; release party?", ContentType: models.MessageContentTypeHTML,
+			CreatedDateTime: baseTime.Add(2 * time.Hour), From: &models.MessageFrom{UserID: "u-me", DisplayName: "Me"},
+		},
+	))
+	return s
+}
+
+func hitIDs(res *search.SearchResults) []string {
+	return util.MapSlices(res.Messages, func(r *search.SearchResult) string { return r.Message.ID })
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	me := &models.MessageFrom{UserID: "u-me", DisplayName: "Me"}
+	tests := []struct {
+		name  string
+		scope Scope
+		opts  *search.SearchMessagesOptions
+		want  []string
+	}{
+		{name: "all terms must match", opts: &search.SearchMessagesOptions{Query: util.Ptr("release new")}, want: []string{"c1"}},
+		{name: "HTML is stripped", opts: &search.SearchMessagesOptions{Query: util.Ptr("lunch")}, want: []string{"h1"}},
+		{name: "prefix term", opts: &search.SearchMessagesOptions{Query: util.Ptr("deploy*")}, want: []string{"c1"}},
+		{name: "phrase", opts: &search.SearchMessagesOptions{Query: util.Ptr(`"notes are"`)}, want: []string{"c2"}},
+		{name: "phrase in wrong order", opts: &search.SearchMessagesOptions{Query: util.Ptr(`"are notes"`)}, want: []string{}},
+		{name: "excluded words", opts: &search.SearchMessagesOptions{Query: util.Ptr("release -notes NOT old")}, want: []string{"h1", "c1"}},
+		{name: "OR is a plain word", opts: &search.SearchMessagesOptions{Query: util.Ptr("lunch OR deployment")}, want: []string{}},
+		{name: "empty query returns everything newest first", opts: &search.SearchMessagesOptions{}, want: []string{"h1", "c2", "c1", "o1"}},
+		{name: "channel scope", scope: ChannelScope(nil, nil), opts: &search.SearchMessagesOptions{Query: util.Ptr("release")}, want: []string{"c2", "c1", "o1"}},
+		{name: "single channel scope", scope: ChannelScope(util.Ptr("team-1"), util.Ptr("19:chan@thread.tacv2")), opts: &search.SearchMessagesOptions{}, want: []string{"c2", "c1"}},
+		{name: "chat scope", scope: ChatScope(nil), opts: &search.SearchMessagesOptions{}, want: []string{"h1"}},
+		{name: "from by display name", opts: &search.SearchMessagesOptions{From: []string{"alice"}}, want: []string{"c1"}},
+		{name: "not from by user ID", opts: &search.SearchMessagesOptions{NotFrom: []string{"u-me"}}, want: []string{"c2", "c1"}},
+		{name: "to matches mentions", opts: &search.SearchMessagesOptions{To: []string{"u-me"}}, want: []string{"c2"}},
+		{
+			name: "time range",
+			opts: &search.SearchMessagesOptions{StartTime: util.Ptr(baseTime), EndTime: util.Ptr(baseTime.Add(2 * time.Hour))},
+			want: []string{"c2", "c1"},
+		},
+		{name: "interval", opts: &search.SearchMessagesOptions{Interval: util.Ptr(search.LastMonth)}, want: []string{"o1"}},
+		{name: "oldest first", opts: &search.SearchMessagesOptions{Sort: search.SortByCreatedAsc}, want: []string{"o1", "c1", "c2", "h1"}},
+		{name: "newest first", opts: &search.SearchMessagesOptions{Sort: search.SortByCreatedDesc}, want: []string{"h1", "c2", "c1", "o1"}},
+		{name: "is mentioned", opts: &search.SearchMessagesOptions{IsMentioned: util.Ptr(true)}, want: []string{"c2"}},
+		{name: "from me", opts: &search.SearchMessagesOptions{FromMe: true}, want: []string{"h1", "o1"}},
+		{name: "not from me", opts: &search.SearchMessagesOptions{NotFromMe: true, IsMentioned: util.Ptr(false)}, want: []string{"c1"}},
+	}
+
+	s := searchFixture(t)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := Search(s, tc.scope, tc.opts, me, baseTime)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, hitIDs(res))
+		})
+	}
+}
+
+func TestSearch_IntervalInTimeZone(t *testing.T) {
+	t.Parallel()
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	now := baseTime.Add(13 * time.Hour) // Jan 2 in UTC, still Jan 1 in Los Angeles
+	s := searchFixture(t)
+
+	res, err := Search(s, nil, &search.SearchMessagesOptions{Interval: util.Ptr(search.Today)}, nil, now)
+	require.NoError(t, err)
+	require.Empty(t, hitIDs(res))
+
+	res, err = Search(s, nil, &search.SearchMessagesOptions{Interval: util.Ptr(search.Today), TimeZone: la}, nil, now)
+	require.NoError(t, err)
+	require.Equal(t, []string{"h1", "c2", "c1"}, hitIDs(res))
+}
+
+func TestSearch_FilterNotSupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := Search(searchFixture(t), nil, &search.SearchMessagesOptions{Filter: search.Term("x")}, nil, baseTime)
+	require.ErrorIs(t, err, ErrFilterNotSupported)
+}
+
+func TestSearch_Locations(t *testing.T) {
+	t.Parallel()
+
+	res, err := Search(searchFixture(t), nil, &search.SearchMessagesOptions{Query: util.Ptr("release")}, nil, baseTime)
+	require.NoError(t, err)
+	require.Len(t, res.Messages, 4)
+
+	chat, channel := res.Messages[0], res.Messages[1]
+	require.Equal(t, "19:chat@thread.v2", *chat.ChatID)
+	require.Nil(t, chat.TeamID)
+	require.Equal(t, "team-1", *channel.TeamID)
+	require.Equal(t, "19:chan@thread.tacv2", *channel.ChannelID)
+	require.Nil(t, channel.ChatID)
+}
+
+func TestSearch_Paging(t *testing.T) {
+	t.Parallel()
+
+	s := searchFixture(t)
+	opts := &search.SearchMessagesOptions{SearchPage: &search.SearchPage{Size: util.Ptr(int32(3))}}
+
+	res, err := Search(s, nil, opts, nil, baseTime)
+	require.NoError(t, err)
+	require.Equal(t, []string{"h1", "c2", "c1"}, hitIDs(res))
+	require.Equal(t, int32(3), *res.NextFrom)
+	require.Equal(t, int32(4), *res.Total)
+	require.True(t, res.MoreResultsAvailable)
+
+	opts.SearchPage.From = res.NextFrom
+	res, err = Search(s, nil, opts, nil, baseTime)
+	require.NoError(t, err)
+	require.Equal(t, []string{"o1"}, hitIDs(res))
+	require.Nil(t, res.NextFrom)
+	require.False(t, res.MoreResultsAvailable)
+}
+
+func TestSearch_IndexFollowsUpdates(t *testing.T) {
+	t.Parallel()
+
+	s, path := tempStore(t)
+	key := ChatKey("chat-1")
+	msg := &models.Message{ID: "m1", Content: "first draft", CreatedDateTime: baseTime}
+	require.NoError(t, s.Put(key, msg))
+
+	msg.Content = "final version"
+	require.NoError(t, s.Sync(key, []*models.Message{msg}))
+
+	hits, err := s.Search([]string{"draft"})
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	hits, err = newJSONFileStore(path).Search([]string{"final"})
+	require.NoError(t, err)
+	require.Len(t, hits, 1, "index is rebuilt when the store is loaded")
+
+	require.NoError(t, s.Clear())
+	hits, err = s.Search(nil)
+	require.NoError(t, err)
+	require.Empty(t, hits)
+}
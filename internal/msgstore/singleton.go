@@ -0,0 +1,57 @@
+package msgstore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/pzsp-teams/lib/config"
+)
+
+// ErrProtectedStore is returned when the message store is enabled together with cache protection.
+// Stored messages are kept in plain text, which would defeat the protection.
+var ErrProtectedStore = errors.New("message store cannot be used with cache protection")
+
+var Singleton Store
+
+// GetMessageStore returns the message store configured in cfg,
+// or nil when caching or the message store is disabled.
+func GetMessageStore(cfg *config.CacheConfig) (Store, error) {
+	if Singleton == nil {
+		store, err := NewMessageStore(cfg)
+		if err != nil {
+			return nil, err
+		}
+		Singleton = store
+	}
+	return Singleton, nil
+}
+
+func NewMessageStore(cfg *config.CacheConfig) (Store, error) {
+	if cfg == nil || cfg.Mode == config.CacheDisabled || cfg.MessageStore == nil {
+		return nil, nil
+	}
+	if cfg.Protection != "" && cfg.Protection != config.CacheProtectionNone {
+		return nil, ErrProtectedStore
+	}
+	var path string
+	if cfg.MessageStore.Path != nil {
+		path = *cfg.MessageStore.Path
+	} else {
+		path = defaultStorePath(cfg.Path)
+	}
+	return newJSONFileStore(path), nil
+}
+
+func defaultStorePath(cachePath *string) string {
+	if cachePath != nil {
+		return filepath.Join(filepath.Dir(*cachePath), "messages.json")
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "pzsp-teams-messages.json"
+	}
+	p := filepath.Join(dir, "pzsp-teams", "messages.json")
+	_ = os.MkdirAll(filepath.Dir(p), 0o755)
+	return p
+}
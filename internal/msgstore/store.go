@@ -0,0 +1,81 @@
+// Package msgstore contains a local store of chat and channel messages, including:
+//   - the Store interface,
+//   - a JSON file-backed store with an inverted index of message words,
+//   - offline search over stored messages,
+//   - conversation key builders.
+//
+// Messages are grouped by conversation (channel or chat) and kept together with
+// the time of the last synchronization with Graph.
+package msgstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// Conversation holds all locally known messages of a channel or chat.
+type Conversation struct {
+	Messages map[string]*models.Message `json:"messages"`
+
+	// SyncedAt is the last modification time of messages already fetched from Graph.
+	// Changes made after it are fetched on the next refresh.
+	SyncedAt *time.Time `json:"syncedAt,omitempty"`
+
+	// NextLink points to messages older than the ones fetched on the first read.
+	NextLink *string `json:"nextLink,omitempty"`
+
+	// PageSize is the number of messages on a full page returned by Graph on the first read, zero if unknown.
+	PageSize int32 `json:"pageSize,omitempty"`
+}
+
+// Hit is a stored message found by a search, together with its conversation.
+type Hit struct {
+	Conversation string
+	Message      *models.Message
+}
+
+// Store persists messages grouped by conversation.
+type Store interface {
+	// Get returns a single message of the conversation.
+	Get(conversation, messageID string) (*models.Message, bool, error)
+
+	// Load returns a copy of the whole conversation, nil if nothing is stored yet.
+	Load(conversation string) (*Conversation, error)
+
+	// Put adds or replaces messages of the conversation.
+	Put(conversation string, messages ...*models.Message) error
+
+	// Delete removes messages from the conversation, e.g. when their stored copy went stale.
+	Delete(conversation string, messageIDs ...string) error
+
+	// Sync adds or replaces messages fetched as a refresh of the conversation
+	// and advances SyncedAt to the newest last modification time among them.
+	Sync(conversation string, messages []*models.Message) error
+
+	// SetNextLink remembers the link to older messages of the conversation.
+	SetNextLink(conversation string, nextLink *string) error
+
+	// SetPageSize remembers the size of pages returned by Graph for the conversation.
+	SetPageSize(conversation string, size int32) error
+
+	// Conversations returns keys of all stored conversations.
+	Conversations() ([]string, error)
+
+	// Search returns stored messages containing all the given terms, or all messages when no terms are given.
+	// A term ending with "*" matches any word starting with it.
+	Search(terms []string) ([]Hit, error)
+
+	Clear() error
+}
+
+// ChannelKey builds a conversation key of a channel.
+func ChannelKey(teamID, channelID string) string {
+	return fmt.Sprintf("channel:%s:%s", teamID, channelID)
+}
+
+// ChatKey builds a conversation key of a chat.
+func ChatKey(chatID string) string {
+	return fmt.Sprintf("chat:%s", chatID)
+}
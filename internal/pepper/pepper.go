@@ -1,8 +1,8 @@
 // Package pepper provides helpers for obtaining and persisting a secret "pepper" value.
 //
-// The pepper is stored in the system keyring and is used as an additional secret when
-// hashing or deriving cache keys. If the value is missing, the user is prompted on stdin
-// and the result is saved to the keyring.
+// By default the pepper is stored in the system keyring and is used as an additional secret when
+// hashing or deriving cache keys. Other sources (environment variable, file, callback) are passed
+// to Get, Exists and Store explicitly, so every cache reads the pepper from its own source.
 package pepper
 
 import (
@@ -23,32 +23,17 @@ var ErrPepperNotSet = errors.New("pepper not set in keyring")
 var keyringGet = keyring.Get
 var keyringSet = keyring.Set
 
+// GetPepper returns the pepper from the system keyring.
 func GetPepper() (string, error) {
-	value, err := keyringGet(serviceName, userName)
-	if err != nil {
-		if errors.Is(err, keyring.ErrNotFound) {
-			return "", ErrPepperNotSet
-		}
-		return "", fmt.Errorf("retrieving pepper from keyring: %w", err)
-	}
-	value = strings.TrimSpace(value)
-	if value == "" {
-		return "", ErrPepperNotSet
-	}
-	return value, nil
+	return Get(KeyringSource{})
 }
 
+// SetPepper stores the pepper in the system keyring.
 func SetPepper(pepper string) error {
-	pepper = strings.TrimSpace(pepper)
-	if pepper == "" {
-		return fmt.Errorf("pepper cannot be empty")
-	}
-	if err := keyringSet(serviceName, userName, pepper); err != nil {
-		return fmt.Errorf("storing pepper in keyring: %w", err)
-	}
-	return nil
+	return Store(KeyringSource{}, pepper)
 }
 
+// PepperExists checks whether a pepper is set in the system keyring.
 func PepperExists() (bool, error) {
 	value, err := keyringGet(serviceName, userName)
 	if err != nil {
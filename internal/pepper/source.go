@@ -0,0 +1,138 @@
+package pepper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// DefaultEnvVar is the environment variable read by EnvSource when no name is given.
+const DefaultEnvVar = "PZSP_TEAMS_PEPPER"
+
+// ErrReadOnlySource is returned when a pepper has to be stored in a source which cannot be written to.
+var ErrReadOnlySource = errors.New("pepper source is read-only")
+
+// Source provides the pepper value.
+//
+// Implementations return ErrPepperNotSet when the pepper is missing.
+type Source interface {
+	Get() (string, error)
+}
+
+// WritableSource is a Source which can also persist a new pepper.
+type WritableSource interface {
+	Source
+	Set(pepper string) error
+}
+
+// KeyringSource reads the pepper from the system keyring.
+type KeyringSource struct{}
+
+func (KeyringSource) Get() (string, error) {
+	value, err := keyringGet(serviceName, userName)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return "", ErrPepperNotSet
+		}
+		return "", fmt.Errorf("retrieving pepper from keyring: %w", err)
+	}
+	return value, nil
+}
+
+func (KeyringSource) Set(pepper string) error {
+	if err := keyringSet(serviceName, userName, pepper); err != nil {
+		return fmt.Errorf("storing pepper in keyring: %w", err)
+	}
+	return nil
+}
+
+// EnvSource reads the pepper from an environment variable (DefaultEnvVar if Name is empty).
+type EnvSource struct {
+	Name string
+}
+
+func (s EnvSource) Get() (string, error) {
+	name := s.Name
+	if name == "" {
+		name = DefaultEnvVar
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", ErrPepperNotSet
+	}
+	return value, nil
+}
+
+// FileSource reads the pepper from a file, e.g. a secret mounted into a container.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Get() (string, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrPepperNotSet
+		}
+		return "", fmt.Errorf("reading pepper file: %w", err)
+	}
+	return string(data), nil
+}
+
+func (s FileSource) Set(pepper string) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("storing pepper in file: %w", err)
+	}
+	if err := os.WriteFile(s.Path, []byte(pepper), 0o600); err != nil {
+		return fmt.Errorf("storing pepper in file: %w", err)
+	}
+	return nil
+}
+
+// FuncSource obtains the pepper from a callback, e.g. a secret manager client.
+type FuncSource func() (string, error)
+
+func (f FuncSource) Get() (string, error) {
+	return f()
+}
+
+// Get returns the trimmed pepper from src, or ErrPepperNotSet when it is missing or blank.
+func Get(src Source) (string, error) {
+	value, err := src.Get()
+	if err != nil {
+		return "", err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", ErrPepperNotSet
+	}
+	return value, nil
+}
+
+// Exists checks whether src holds a pepper.
+func Exists(src Source) (bool, error) {
+	_, err := Get(src)
+	if errors.Is(err, ErrPepperNotSet) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Store persists the pepper in src.
+//
+// Read-only sources (environment variable, callback) return ErrReadOnlySource - the caller has to update them.
+func Store(src Source, pepper string) error {
+	pepper = strings.TrimSpace(pepper)
+	if pepper == "" {
+		return fmt.Errorf("pepper cannot be empty")
+	}
+	writable, ok := src.(WritableSource)
+	if !ok {
+		return ErrReadOnlySource
+	}
+	return writable.Set(pepper)
+}
@@ -0,0 +1,98 @@
+package pepper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestEnvSource(t *testing.T) {
+	t.Setenv(DefaultEnvVar, " env-pepper ")
+	t.Setenv("CUSTOM_PEPPER", "custom")
+
+	got, err := Get(EnvSource{})
+	require.NoError(t, err)
+	require.Equal(t, "env-pepper", got)
+
+	got, err = Get(EnvSource{Name: "CUSTOM_PEPPER"})
+	require.NoError(t, err)
+	require.Equal(t, "custom", got)
+
+	_, err = Get(EnvSource{Name: "PZSP_MISSING_PEPPER"})
+	require.ErrorIs(t, err, ErrPepperNotSet)
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets", "pepper")
+	src := FileSource{Path: path}
+
+	_, err := Get(src)
+	require.ErrorIs(t, err, ErrPepperNotSet)
+
+	require.NoError(t, Store(src, " file-pepper\n"))
+
+	got, err := Get(src)
+	require.NoError(t, err)
+	require.Equal(t, "file-pepper", got)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestFuncSource(t *testing.T) {
+	sentinel := errors.New("boom")
+
+	_, err := Get(FuncSource(func() (string, error) { return "", sentinel }))
+	require.ErrorIs(t, err, sentinel)
+
+	got, err := Get(FuncSource(func() (string, error) { return "cb", nil }))
+	require.NoError(t, err)
+	require.Equal(t, "cb", got)
+}
+
+func TestExists(t *testing.T) {
+	t.Setenv("CUSTOM_PEPPER", " ")
+
+	ok, err := Exists(EnvSource{Name: "CUSTOM_PEPPER"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = Exists(FuncSource(func() (string, error) { return "cb", nil }))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	sentinel := errors.New("boom")
+	_, err = Exists(FuncSource(func() (string, error) { return "", sentinel }))
+	require.ErrorIs(t, err, sentinel)
+}
+
+func TestStore(t *testing.T) {
+	t.Run("keyring source persists pepper", func(t *testing.T) {
+		keyring.MockInit()
+
+		require.NoError(t, Store(KeyringSource{}, "new"))
+
+		got, err := GetPepper()
+		require.NoError(t, err)
+		require.Equal(t, "new", got)
+	})
+
+	t.Run("read-only source -> ErrReadOnlySource", func(t *testing.T) {
+		t.Setenv(DefaultEnvVar, "old")
+
+		require.ErrorIs(t, Store(EnvSource{}, "new"), ErrReadOnlySource)
+
+		got, err := Get(EnvSource{})
+		require.NoError(t, err)
+		require.Equal(t, "old", got)
+	})
+
+	t.Run("empty pepper -> error", func(t *testing.T) {
+		require.Error(t, Store(KeyringSource{}, "  "))
+	})
+}
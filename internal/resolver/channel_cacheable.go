@@ -87,7 +87,7 @@ func (res *ChannelResolverCacheable) newChannelMemberResolveContext(
 ) resolverContext[msmodels.ConversationMemberCollectionResponseable] {
 	ref := strings.TrimSpace(userRef)
 	return resolverContext[msmodels.ConversationMemberCollectionResponseable]{
-		cacheKey:    cacher.NewChannelMemberKey(teamID, channelID, ref, nil),
+		cacheKey:    cacher.NewChannelMemberKey(teamID, channelID, ref, res.cacheHandler.Pepper()),
 		ref:         ref,
 		isAlreadyID: func() bool { return util.IsLikelyGUID(ref) },
 		fetch: func(ctx context.Context) (msmodels.ConversationMemberCollectionResponseable, *sender.RequestError) {
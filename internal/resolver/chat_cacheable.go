@@ -85,7 +85,7 @@ func (m *ChatResolverCacheable) newOneOnOneResolveContext(
 ) resolverContext[msmodels.ChatCollectionResponseable] {
 	ref := strings.TrimSpace(userRef)
 	return resolverContext[msmodels.ChatCollectionResponseable]{
-		cacheKey:    cacher.NewOneOnOneChatKey(ref, nil),
+		cacheKey:    cacher.NewOneOnOneChatKey(ref, m.cacheHandler.Pepper()),
 		ref:         ref,
 		isAlreadyID: func() bool { return util.IsLikelyChatID(ref) },
 		fetch: func(ctx context.Context) (msmodels.ChatCollectionResponseable, *sender.RequestError) {
@@ -121,7 +121,7 @@ func (m *ChatResolverCacheable) newChatMemberResolveContext(
 ) resolverContext[msmodels.ConversationMemberCollectionResponseable] {
 	ref := strings.TrimSpace(userRef)
 	return resolverContext[msmodels.ConversationMemberCollectionResponseable]{
-		cacheKey:    cacher.NewGroupChatMemberKey(chatID, ref, nil),
+		cacheKey:    cacher.NewGroupChatMemberKey(chatID, ref, m.cacheHandler.Pepper()),
 		ref:         ref,
 		isAlreadyID: func() bool { return util.IsLikelyGUID(ref) },
 		fetch: func(ctx context.Context) (msmodels.ConversationMemberCollectionResponseable, *sender.RequestError) {
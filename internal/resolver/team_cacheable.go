@@ -88,7 +88,7 @@ func (r *TeamResolverCacheable) newTeamMemberResolveContext(
 ) resolverContext[msmodels.ConversationMemberCollectionResponseable] {
 	ref := strings.TrimSpace(userRef)
 	return resolverContext[msmodels.ConversationMemberCollectionResponseable]{
-		cacheKey:    cacher.NewTeamMemberKey(teamID, ref, nil),
+		cacheKey:    cacher.NewTeamMemberKey(teamID, ref, r.cacheHandler.Pepper()),
 		ref:         ref,
 		isAlreadyID: func() bool { return util.IsLikelyGUID(ref) },
 		fetch: func(ctx context.Context) (msmodels.ConversationMemberCollectionResponseable, *sender.RequestError) {
@@ -12,6 +12,8 @@ const (
 	Message       Resource = "MESSAGE"
 	PinnedMessage Resource = "PINNED_MESSAGE"
 	Mention       Resource = "MENTION"
+	Subscription  Resource = "SUBSCRIPTION"
+	HostedContent Resource = "HOSTED_CONTENT"
 )
 
 type Key string
@@ -25,4 +27,5 @@ const (
 	UserRef         Key = "user_ref"
 	MessageRef      Key = "message_ref"
 	MentionRef      Key = "mention_ref"
+	SubscriptionRef Key = "subscription_ref"
 )
@@ -136,13 +136,15 @@ func NewChatCollection(chats ...msmodels.Chatable) msmodels.ChatCollectionRespon
 // MESSAGE
 
 type NewMessageParams struct {
-	ID              *string
-	Content         *string
-	ContentType     *msmodels.BodyType
-	CreatedDateTime *time.Time
-	FromUserID      *string
-	FromDisplayName *string
-	ReplyCount      *int
+	ID                   *string
+	Content              *string
+	ContentType          *msmodels.BodyType
+	CreatedDateTime      *time.Time
+	LastModifiedDateTime *time.Time
+	LastEditedDateTime   *time.Time
+	FromUserID           *string
+	FromDisplayName      *string
+	ReplyCount           *int
 }
 
 func NewGraphMessage(params *NewMessageParams) msmodels.ChatMessageable {
@@ -160,6 +162,8 @@ func NewGraphMessage(params *NewMessageParams) msmodels.ChatMessageable {
 	}
 
 	graphMessage.SetCreatedDateTime(params.CreatedDateTime)
+	graphMessage.SetLastModifiedDateTime(params.LastModifiedDateTime)
+	graphMessage.SetLastEditedDateTime(params.LastEditedDateTime)
 
 	if params.FromUserID != nil || params.FromDisplayName != nil {
 		from := msmodels.NewChatMessageFromIdentitySet()
@@ -11,7 +11,9 @@ package testutil
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/microsoftgraph/msgraph-sdk-go/models"
 	api "github.com/pzsp-teams/lib/internal/api"
@@ -103,6 +105,34 @@ func (mr *MockChannelAPIMockRecorder) DeleteChannel(ctx, teamID, channelID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockChannelAPI)(nil).DeleteChannel), ctx, teamID, channelID)
 }
 
+// DeleteMessage mocks base method.
+func (m *MockChannelAPI) DeleteMessage(ctx context.Context, teamID, channelID, messageID string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, teamID, channelID, messageID)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockChannelAPIMockRecorder) DeleteMessage(ctx, teamID, channelID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockChannelAPI)(nil).DeleteMessage), ctx, teamID, channelID, messageID)
+}
+
+// DeleteReply mocks base method.
+func (m *MockChannelAPI) DeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReply", ctx, teamID, channelID, messageID, replyID)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// DeleteReply indicates an expected call of DeleteReply.
+func (mr *MockChannelAPIMockRecorder) DeleteReply(ctx, teamID, channelID, messageID, replyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReply", reflect.TypeOf((*MockChannelAPI)(nil).DeleteReply), ctx, teamID, channelID, messageID, replyID)
+}
+
 // GetChannel mocks base method.
 func (m *MockChannelAPI) GetChannel(ctx context.Context, teamID, channelID string) (models.Channelable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -118,6 +148,21 @@ func (mr *MockChannelAPIMockRecorder) GetChannel(ctx, teamID, channelID any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockChannelAPI)(nil).GetChannel), ctx, teamID, channelID)
 }
 
+// GetHostedContent mocks base method.
+func (m *MockChannelAPI) GetHostedContent(ctx context.Context, teamID, channelID, messageID, hostedContentID string) ([]byte, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHostedContent", ctx, teamID, channelID, messageID, hostedContentID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// GetHostedContent indicates an expected call of GetHostedContent.
+func (mr *MockChannelAPIMockRecorder) GetHostedContent(ctx, teamID, channelID, messageID, hostedContentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHostedContent", reflect.TypeOf((*MockChannelAPI)(nil).GetHostedContent), ctx, teamID, channelID, messageID, hostedContentID)
+}
+
 // GetMessage mocks base method.
 func (m *MockChannelAPI) GetMessage(ctx context.Context, teamID, channelID, messageID string) (models.ChatMessageable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -148,6 +193,21 @@ func (mr *MockChannelAPIMockRecorder) GetReply(ctx, teamID, channelID, messageID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReply", reflect.TypeOf((*MockChannelAPI)(nil).GetReply), ctx, teamID, channelID, messageID, replyID)
 }
 
+// GetReplyHostedContent mocks base method.
+func (m *MockChannelAPI) GetReplyHostedContent(ctx context.Context, teamID, channelID, messageID, replyID, hostedContentID string) ([]byte, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplyHostedContent", ctx, teamID, channelID, messageID, replyID, hostedContentID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// GetReplyHostedContent indicates an expected call of GetReplyHostedContent.
+func (mr *MockChannelAPIMockRecorder) GetReplyHostedContent(ctx, teamID, channelID, messageID, replyID, hostedContentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplyHostedContent", reflect.TypeOf((*MockChannelAPI)(nil).GetReplyHostedContent), ctx, teamID, channelID, messageID, replyID, hostedContentID)
+}
+
 // ListChannels mocks base method.
 func (m *MockChannelAPI) ListChannels(ctx context.Context, teamID string) (models.ChannelCollectionResponseable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -163,6 +223,21 @@ func (mr *MockChannelAPIMockRecorder) ListChannels(ctx, teamID any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChannels", reflect.TypeOf((*MockChannelAPI)(nil).ListChannels), ctx, teamID)
 }
 
+// ListHostedContents mocks base method.
+func (m *MockChannelAPI) ListHostedContents(ctx context.Context, teamID, channelID, messageID string) (models.ChatMessageHostedContentCollectionResponseable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHostedContents", ctx, teamID, channelID, messageID)
+	ret0, _ := ret[0].(models.ChatMessageHostedContentCollectionResponseable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// ListHostedContents indicates an expected call of ListHostedContents.
+func (mr *MockChannelAPIMockRecorder) ListHostedContents(ctx, teamID, channelID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostedContents", reflect.TypeOf((*MockChannelAPI)(nil).ListHostedContents), ctx, teamID, channelID, messageID)
+}
+
 // ListMembers mocks base method.
 func (m *MockChannelAPI) ListMembers(ctx context.Context, teamID, channelID string) (models.ConversationMemberCollectionResponseable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -179,18 +254,33 @@ func (mr *MockChannelAPIMockRecorder) ListMembers(ctx, teamID, channelID any) *g
 }
 
 // ListMessages mocks base method.
-func (m *MockChannelAPI) ListMessages(ctx context.Context, teamID, channelID string, top *int32, includeSystem bool) (models.ChatMessageCollectionResponseable, *sender.RequestError) {
+func (m *MockChannelAPI) ListMessages(ctx context.Context, teamID, channelID string, top *int32, expandReplies, includeSystem bool) (models.ChatMessageCollectionResponseable, *sender.RequestError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListMessages", ctx, teamID, channelID, top, includeSystem)
+	ret := m.ctrl.Call(m, "ListMessages", ctx, teamID, channelID, top, expandReplies, includeSystem)
 	ret0, _ := ret[0].(models.ChatMessageCollectionResponseable)
 	ret1, _ := ret[1].(*sender.RequestError)
 	return ret0, ret1
 }
 
 // ListMessages indicates an expected call of ListMessages.
-func (mr *MockChannelAPIMockRecorder) ListMessages(ctx, teamID, channelID, top, includeSystem any) *gomock.Call {
+func (mr *MockChannelAPIMockRecorder) ListMessages(ctx, teamID, channelID, top, expandReplies, includeSystem any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockChannelAPI)(nil).ListMessages), ctx, teamID, channelID, top, expandReplies, includeSystem)
+}
+
+// ListMessagesModifiedSince mocks base method.
+func (m *MockChannelAPI) ListMessagesModifiedSince(ctx context.Context, teamID, channelID string, since time.Time) ([]models.ChatMessageable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessagesModifiedSince", ctx, teamID, channelID, since)
+	ret0, _ := ret[0].([]models.ChatMessageable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// ListMessagesModifiedSince indicates an expected call of ListMessagesModifiedSince.
+func (mr *MockChannelAPIMockRecorder) ListMessagesModifiedSince(ctx, teamID, channelID, since any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockChannelAPI)(nil).ListMessages), ctx, teamID, channelID, top, includeSystem)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessagesModifiedSince", reflect.TypeOf((*MockChannelAPI)(nil).ListMessagesModifiedSince), ctx, teamID, channelID, since)
 }
 
 // ListMessagesNext mocks base method.
@@ -238,6 +328,36 @@ func (mr *MockChannelAPIMockRecorder) ListRepliesNext(ctx, teamID, channelID, me
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepliesNext", reflect.TypeOf((*MockChannelAPI)(nil).ListRepliesNext), ctx, teamID, channelID, messageID, nextLink, includeSystem)
 }
 
+// ListReplyHostedContents mocks base method.
+func (m *MockChannelAPI) ListReplyHostedContents(ctx context.Context, teamID, channelID, messageID, replyID string) (models.ChatMessageHostedContentCollectionResponseable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReplyHostedContents", ctx, teamID, channelID, messageID, replyID)
+	ret0, _ := ret[0].(models.ChatMessageHostedContentCollectionResponseable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// ListReplyHostedContents indicates an expected call of ListReplyHostedContents.
+func (mr *MockChannelAPIMockRecorder) ListReplyHostedContents(ctx, teamID, channelID, messageID, replyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReplyHostedContents", reflect.TypeOf((*MockChannelAPI)(nil).ListReplyHostedContents), ctx, teamID, channelID, messageID, replyID)
+}
+
+// OpenFile mocks base method.
+func (m *MockChannelAPI) OpenFile(ctx context.Context, contentURL string) (io.ReadCloser, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFile", ctx, contentURL)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// OpenFile indicates an expected call of OpenFile.
+func (mr *MockChannelAPIMockRecorder) OpenFile(ctx, contentURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockChannelAPI)(nil).OpenFile), ctx, contentURL)
+}
+
 // RemoveMember mocks base method.
 func (m *MockChannelAPI) RemoveMember(ctx context.Context, teamID, channelID, memberID string) *sender.RequestError {
 	m.ctrl.T.Helper()
@@ -253,13 +373,12 @@ func (mr *MockChannelAPIMockRecorder) RemoveMember(ctx, teamID, channelID, membe
 }
 
 // SearchChannelMessages mocks base method.
-func (m *MockChannelAPI) SearchChannelMessages(ctx context.Context, teamID, channelID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) ([]*api.SearchMessage, *sender.RequestError, *int32) {
+func (m *MockChannelAPI) SearchChannelMessages(ctx context.Context, teamID, channelID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*api.SearchMessagesPage, *sender.RequestError) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SearchChannelMessages", ctx, teamID, channelID, opts, searchConfig)
-	ret0, _ := ret[0].([]*api.SearchMessage)
+	ret0, _ := ret[0].(*api.SearchMessagesPage)
 	ret1, _ := ret[1].(*sender.RequestError)
-	ret2, _ := ret[2].(*int32)
-	return ret0, ret1, ret2
+	return ret0, ret1
 }
 
 // SearchChannelMessages indicates an expected call of SearchChannelMessages.
@@ -269,33 +388,133 @@ func (mr *MockChannelAPIMockRecorder) SearchChannelMessages(ctx, teamID, channel
 }
 
 // SendMessage mocks base method.
-func (m *MockChannelAPI) SendMessage(ctx context.Context, teamID, channelID, content, contentType string, mentions []models.ChatMessageMentionable) (models.ChatMessageable, *sender.RequestError) {
+func (m *MockChannelAPI) SendMessage(ctx context.Context, teamID, channelID, content, contentType, subject, importance string, mentions []models.ChatMessageMentionable, attachments []models.ChatMessageAttachmentable, hostedContents []models.ChatMessageHostedContentable) (models.ChatMessageable, *sender.RequestError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendMessage", ctx, teamID, channelID, content, contentType, mentions)
+	ret := m.ctrl.Call(m, "SendMessage", ctx, teamID, channelID, content, contentType, subject, importance, mentions, attachments, hostedContents)
 	ret0, _ := ret[0].(models.ChatMessageable)
 	ret1, _ := ret[1].(*sender.RequestError)
 	return ret0, ret1
 }
 
 // SendMessage indicates an expected call of SendMessage.
-func (mr *MockChannelAPIMockRecorder) SendMessage(ctx, teamID, channelID, content, contentType, mentions any) *gomock.Call {
+func (mr *MockChannelAPIMockRecorder) SendMessage(ctx, teamID, channelID, content, contentType, subject, importance, mentions, attachments, hostedContents any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockChannelAPI)(nil).SendMessage), ctx, teamID, channelID, content, contentType, mentions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockChannelAPI)(nil).SendMessage), ctx, teamID, channelID, content, contentType, subject, importance, mentions, attachments, hostedContents)
 }
 
 // SendReply mocks base method.
-func (m *MockChannelAPI) SendReply(ctx context.Context, teamID, channelID, messageID, content, contentType string, mentions []models.ChatMessageMentionable) (models.ChatMessageable, *sender.RequestError) {
+func (m *MockChannelAPI) SendReply(ctx context.Context, teamID, channelID, messageID, content, contentType, subject, importance string, mentions []models.ChatMessageMentionable, attachments []models.ChatMessageAttachmentable, hostedContents []models.ChatMessageHostedContentable) (models.ChatMessageable, *sender.RequestError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendReply", ctx, teamID, channelID, messageID, content, contentType, mentions)
+	ret := m.ctrl.Call(m, "SendReply", ctx, teamID, channelID, messageID, content, contentType, subject, importance, mentions, attachments, hostedContents)
 	ret0, _ := ret[0].(models.ChatMessageable)
 	ret1, _ := ret[1].(*sender.RequestError)
 	return ret0, ret1
 }
 
 // SendReply indicates an expected call of SendReply.
-func (mr *MockChannelAPIMockRecorder) SendReply(ctx, teamID, channelID, messageID, content, contentType, mentions any) *gomock.Call {
+func (mr *MockChannelAPIMockRecorder) SendReply(ctx, teamID, channelID, messageID, content, contentType, subject, importance, mentions, attachments, hostedContents any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendReply", reflect.TypeOf((*MockChannelAPI)(nil).SendReply), ctx, teamID, channelID, messageID, content, contentType, mentions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendReply", reflect.TypeOf((*MockChannelAPI)(nil).SendReply), ctx, teamID, channelID, messageID, content, contentType, subject, importance, mentions, attachments, hostedContents)
+}
+
+// SetReaction mocks base method.
+func (m *MockChannelAPI) SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReaction", ctx, teamID, channelID, messageID, reactionType)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// SetReaction indicates an expected call of SetReaction.
+func (mr *MockChannelAPIMockRecorder) SetReaction(ctx, teamID, channelID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReaction", reflect.TypeOf((*MockChannelAPI)(nil).SetReaction), ctx, teamID, channelID, messageID, reactionType)
+}
+
+// SetReplyReaction mocks base method.
+func (m *MockChannelAPI) SetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReplyReaction", ctx, teamID, channelID, messageID, replyID, reactionType)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// SetReplyReaction indicates an expected call of SetReplyReaction.
+func (mr *MockChannelAPIMockRecorder) SetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplyReaction", reflect.TypeOf((*MockChannelAPI)(nil).SetReplyReaction), ctx, teamID, channelID, messageID, replyID, reactionType)
+}
+
+// SyncMessages mocks base method.
+func (m *MockChannelAPI) SyncMessages(ctx context.Context, teamID, channelID, deltaLink string) ([]models.ChatMessageable, string, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncMessages", ctx, teamID, channelID, deltaLink)
+	ret0, _ := ret[0].([]models.ChatMessageable)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(*sender.RequestError)
+	return ret0, ret1, ret2
+}
+
+// SyncMessages indicates an expected call of SyncMessages.
+func (mr *MockChannelAPIMockRecorder) SyncMessages(ctx, teamID, channelID, deltaLink any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncMessages", reflect.TypeOf((*MockChannelAPI)(nil).SyncMessages), ctx, teamID, channelID, deltaLink)
+}
+
+// UndoDeleteMessage mocks base method.
+func (m *MockChannelAPI) UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoDeleteMessage", ctx, teamID, channelID, messageID)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UndoDeleteMessage indicates an expected call of UndoDeleteMessage.
+func (mr *MockChannelAPIMockRecorder) UndoDeleteMessage(ctx, teamID, channelID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoDeleteMessage", reflect.TypeOf((*MockChannelAPI)(nil).UndoDeleteMessage), ctx, teamID, channelID, messageID)
+}
+
+// UndoDeleteReply mocks base method.
+func (m *MockChannelAPI) UndoDeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoDeleteReply", ctx, teamID, channelID, messageID, replyID)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UndoDeleteReply indicates an expected call of UndoDeleteReply.
+func (mr *MockChannelAPIMockRecorder) UndoDeleteReply(ctx, teamID, channelID, messageID, replyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoDeleteReply", reflect.TypeOf((*MockChannelAPI)(nil).UndoDeleteReply), ctx, teamID, channelID, messageID, replyID)
+}
+
+// UnsetReaction mocks base method.
+func (m *MockChannelAPI) UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetReaction", ctx, teamID, channelID, messageID, reactionType)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UnsetReaction indicates an expected call of UnsetReaction.
+func (mr *MockChannelAPIMockRecorder) UnsetReaction(ctx, teamID, channelID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetReaction", reflect.TypeOf((*MockChannelAPI)(nil).UnsetReaction), ctx, teamID, channelID, messageID, reactionType)
+}
+
+// UnsetReplyReaction mocks base method.
+func (m *MockChannelAPI) UnsetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetReplyReaction", ctx, teamID, channelID, messageID, replyID, reactionType)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UnsetReplyReaction indicates an expected call of UnsetReplyReaction.
+func (mr *MockChannelAPIMockRecorder) UnsetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetReplyReaction", reflect.TypeOf((*MockChannelAPI)(nil).UnsetReplyReaction), ctx, teamID, channelID, messageID, replyID, reactionType)
 }
 
 // UpdateMemberRoles mocks base method.
@@ -312,3 +531,46 @@ func (mr *MockChannelAPIMockRecorder) UpdateMemberRoles(ctx, teamID, channelID,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRoles", reflect.TypeOf((*MockChannelAPI)(nil).UpdateMemberRoles), ctx, teamID, channelID, memberID, roles)
 }
+
+// UpdateMessage mocks base method.
+func (m *MockChannelAPI) UpdateMessage(ctx context.Context, teamID, channelID, messageID, content, contentType string, mentions []models.ChatMessageMentionable, attachments []models.ChatMessageAttachmentable) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", ctx, teamID, channelID, messageID, content, contentType, mentions, attachments)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockChannelAPIMockRecorder) UpdateMessage(ctx, teamID, channelID, messageID, content, contentType, mentions, attachments any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockChannelAPI)(nil).UpdateMessage), ctx, teamID, channelID, messageID, content, contentType, mentions, attachments)
+}
+
+// UpdateReply mocks base method.
+func (m *MockChannelAPI) UpdateReply(ctx context.Context, teamID, channelID, messageID, replyID, content, contentType string, mentions []models.ChatMessageMentionable, attachments []models.ChatMessageAttachmentable) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReply", ctx, teamID, channelID, messageID, replyID, content, contentType, mentions, attachments)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UpdateReply indicates an expected call of UpdateReply.
+func (mr *MockChannelAPIMockRecorder) UpdateReply(ctx, teamID, channelID, messageID, replyID, content, contentType, mentions, attachments any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReply", reflect.TypeOf((*MockChannelAPI)(nil).UpdateReply), ctx, teamID, channelID, messageID, replyID, content, contentType, mentions, attachments)
+}
+
+// UploadFile mocks base method.
+func (m *MockChannelAPI) UploadFile(ctx context.Context, teamID, channelID, name string, r io.Reader, size int64) (models.DriveItemable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", ctx, teamID, channelID, name, r, size)
+	ret0, _ := ret[0].(models.DriveItemable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockChannelAPIMockRecorder) UploadFile(ctx, teamID, channelID, name, r, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockChannelAPI)(nil).UploadFile), ctx, teamID, channelID, name, r, size)
+}
@@ -11,7 +11,9 @@ package testutil
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/pzsp-teams/lib/models"
 	search "github.com/pzsp-teams/lib/search"
@@ -101,6 +103,76 @@ func (mr *MockchannelOpsMockRecorder) DeleteChannel(ctx, teamID, channelID, chan
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockchannelOps)(nil).DeleteChannel), ctx, teamID, channelID, channelRef)
 }
 
+// DeleteMessage mocks base method.
+func (m *MockchannelOps) DeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, teamID, channelID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockchannelOpsMockRecorder) DeleteMessage(ctx, teamID, channelID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockchannelOps)(nil).DeleteMessage), ctx, teamID, channelID, messageID)
+}
+
+// DeleteReply mocks base method.
+func (m *MockchannelOps) DeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReply", ctx, teamID, channelID, messageID, replyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReply indicates an expected call of DeleteReply.
+func (mr *MockchannelOpsMockRecorder) DeleteReply(ctx, teamID, channelID, messageID, replyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReply", reflect.TypeOf((*MockchannelOps)(nil).DeleteReply), ctx, teamID, channelID, messageID, replyID)
+}
+
+// DownloadFile mocks base method.
+func (m *MockchannelOps) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadFile", ctx, attachment, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadFile indicates an expected call of DownloadFile.
+func (mr *MockchannelOpsMockRecorder) DownloadFile(ctx, attachment, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockchannelOps)(nil).DownloadFile), ctx, attachment, w)
+}
+
+// DownloadHostedContent mocks base method.
+func (m *MockchannelOps) DownloadHostedContent(ctx context.Context, teamID, channelID, messageID, hostedContentID string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadHostedContent", ctx, teamID, channelID, messageID, hostedContentID, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadHostedContent indicates an expected call of DownloadHostedContent.
+func (mr *MockchannelOpsMockRecorder) DownloadHostedContent(ctx, teamID, channelID, messageID, hostedContentID, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadHostedContent", reflect.TypeOf((*MockchannelOps)(nil).DownloadHostedContent), ctx, teamID, channelID, messageID, hostedContentID, w)
+}
+
+// DownloadReplyHostedContent mocks base method.
+func (m *MockchannelOps) DownloadReplyHostedContent(ctx context.Context, teamID, channelID, messageID, replyID, hostedContentID string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadReplyHostedContent", ctx, teamID, channelID, messageID, replyID, hostedContentID, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadReplyHostedContent indicates an expected call of DownloadReplyHostedContent.
+func (mr *MockchannelOpsMockRecorder) DownloadReplyHostedContent(ctx, teamID, channelID, messageID, replyID, hostedContentID, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadReplyHostedContent", reflect.TypeOf((*MockchannelOps)(nil).DownloadReplyHostedContent), ctx, teamID, channelID, messageID, replyID, hostedContentID, w)
+}
+
 // GetChannelByID mocks base method.
 func (m *MockchannelOps) GetChannelByID(ctx context.Context, teamID, channelID string) (*models.Channel, error) {
 	m.ctrl.T.Helper()
@@ -116,6 +188,21 @@ func (mr *MockchannelOpsMockRecorder) GetChannelByID(ctx, teamID, channelID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelByID", reflect.TypeOf((*MockchannelOps)(nil).GetChannelByID), ctx, teamID, channelID)
 }
 
+// GetCurrentUser mocks base method.
+func (m *MockchannelOps) GetCurrentUser(ctx context.Context) (*models.MessageFrom, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUser", ctx)
+	ret0, _ := ret[0].(*models.MessageFrom)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUser indicates an expected call of GetCurrentUser.
+func (mr *MockchannelOpsMockRecorder) GetCurrentUser(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockchannelOps)(nil).GetCurrentUser), ctx)
+}
+
 // GetMentions mocks base method.
 func (m *MockchannelOps) GetMentions(ctx context.Context, teamID, teamRef, channelRef, channelID string, rawMentions []string) ([]models.Mention, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +263,21 @@ func (mr *MockchannelOpsMockRecorder) ListChannelsByTeamID(ctx, teamID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChannelsByTeamID", reflect.TypeOf((*MockchannelOps)(nil).ListChannelsByTeamID), ctx, teamID)
 }
 
+// ListHostedContents mocks base method.
+func (m *MockchannelOps) ListHostedContents(ctx context.Context, teamID, channelID, messageID string) ([]*models.HostedContent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHostedContents", ctx, teamID, channelID, messageID)
+	ret0, _ := ret[0].([]*models.HostedContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHostedContents indicates an expected call of ListHostedContents.
+func (mr *MockchannelOpsMockRecorder) ListHostedContents(ctx, teamID, channelID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostedContents", reflect.TypeOf((*MockchannelOps)(nil).ListHostedContents), ctx, teamID, channelID, messageID)
+}
+
 // ListMembers mocks base method.
 func (m *MockchannelOps) ListMembers(ctx context.Context, teamID, channelID string) ([]*models.Member, error) {
 	m.ctrl.T.Helper()
@@ -206,6 +308,21 @@ func (mr *MockchannelOpsMockRecorder) ListMessages(ctx, teamID, channelID, opts,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockchannelOps)(nil).ListMessages), ctx, teamID, channelID, opts, includeSystem)
 }
 
+// ListMessagesModifiedSince mocks base method.
+func (m *MockchannelOps) ListMessagesModifiedSince(ctx context.Context, teamID, channelID string, since time.Time) ([]*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessagesModifiedSince", ctx, teamID, channelID, since)
+	ret0, _ := ret[0].([]*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessagesModifiedSince indicates an expected call of ListMessagesModifiedSince.
+func (mr *MockchannelOpsMockRecorder) ListMessagesModifiedSince(ctx, teamID, channelID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessagesModifiedSince", reflect.TypeOf((*MockchannelOps)(nil).ListMessagesModifiedSince), ctx, teamID, channelID, since)
+}
+
 // ListMessagesNext mocks base method.
 func (m *MockchannelOps) ListMessagesNext(ctx context.Context, teamID, channelID, nextLink string, includeSystem bool) (*models.MessageCollection, error) {
 	m.ctrl.T.Helper()
@@ -251,6 +368,21 @@ func (mr *MockchannelOpsMockRecorder) ListRepliesNext(ctx, teamID, channelID, me
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepliesNext", reflect.TypeOf((*MockchannelOps)(nil).ListRepliesNext), ctx, teamID, channelID, messageID, nextLink, includeSystem)
 }
 
+// ListReplyHostedContents mocks base method.
+func (m *MockchannelOps) ListReplyHostedContents(ctx context.Context, teamID, channelID, messageID, replyID string) ([]*models.HostedContent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReplyHostedContents", ctx, teamID, channelID, messageID, replyID)
+	ret0, _ := ret[0].([]*models.HostedContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReplyHostedContents indicates an expected call of ListReplyHostedContents.
+func (mr *MockchannelOpsMockRecorder) ListReplyHostedContents(ctx, teamID, channelID, messageID, replyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReplyHostedContents", reflect.TypeOf((*MockchannelOps)(nil).ListReplyHostedContents), ctx, teamID, channelID, messageID, replyID)
+}
+
 // RemoveMember mocks base method.
 func (m *MockchannelOps) RemoveMember(ctx context.Context, teamID, channelID, memberID, userRef string) error {
 	m.ctrl.T.Helper()
@@ -310,6 +442,106 @@ func (mr *MockchannelOpsMockRecorder) SendReply(ctx, teamID, channelID, messageI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendReply", reflect.TypeOf((*MockchannelOps)(nil).SendReply), ctx, teamID, channelID, messageID, body)
 }
 
+// SetReaction mocks base method.
+func (m *MockchannelOps) SetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReaction", ctx, teamID, channelID, messageID, reactionType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReaction indicates an expected call of SetReaction.
+func (mr *MockchannelOpsMockRecorder) SetReaction(ctx, teamID, channelID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReaction", reflect.TypeOf((*MockchannelOps)(nil).SetReaction), ctx, teamID, channelID, messageID, reactionType)
+}
+
+// SetReplyReaction mocks base method.
+func (m *MockchannelOps) SetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReplyReaction", ctx, teamID, channelID, messageID, replyID, reactionType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReplyReaction indicates an expected call of SetReplyReaction.
+func (mr *MockchannelOpsMockRecorder) SetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReplyReaction", reflect.TypeOf((*MockchannelOps)(nil).SetReplyReaction), ctx, teamID, channelID, messageID, replyID, reactionType)
+}
+
+// SyncMessages mocks base method.
+func (m *MockchannelOps) SyncMessages(ctx context.Context, teamID, channelID, token string) (*models.MessageChanges, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncMessages", ctx, teamID, channelID, token)
+	ret0, _ := ret[0].(*models.MessageChanges)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SyncMessages indicates an expected call of SyncMessages.
+func (mr *MockchannelOpsMockRecorder) SyncMessages(ctx, teamID, channelID, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncMessages", reflect.TypeOf((*MockchannelOps)(nil).SyncMessages), ctx, teamID, channelID, token)
+}
+
+// UndoDeleteMessage mocks base method.
+func (m *MockchannelOps) UndoDeleteMessage(ctx context.Context, teamID, channelID, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoDeleteMessage", ctx, teamID, channelID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndoDeleteMessage indicates an expected call of UndoDeleteMessage.
+func (mr *MockchannelOpsMockRecorder) UndoDeleteMessage(ctx, teamID, channelID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoDeleteMessage", reflect.TypeOf((*MockchannelOps)(nil).UndoDeleteMessage), ctx, teamID, channelID, messageID)
+}
+
+// UndoDeleteReply mocks base method.
+func (m *MockchannelOps) UndoDeleteReply(ctx context.Context, teamID, channelID, messageID, replyID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoDeleteReply", ctx, teamID, channelID, messageID, replyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndoDeleteReply indicates an expected call of UndoDeleteReply.
+func (mr *MockchannelOpsMockRecorder) UndoDeleteReply(ctx, teamID, channelID, messageID, replyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoDeleteReply", reflect.TypeOf((*MockchannelOps)(nil).UndoDeleteReply), ctx, teamID, channelID, messageID, replyID)
+}
+
+// UnsetReaction mocks base method.
+func (m *MockchannelOps) UnsetReaction(ctx context.Context, teamID, channelID, messageID, reactionType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetReaction", ctx, teamID, channelID, messageID, reactionType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetReaction indicates an expected call of UnsetReaction.
+func (mr *MockchannelOpsMockRecorder) UnsetReaction(ctx, teamID, channelID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetReaction", reflect.TypeOf((*MockchannelOps)(nil).UnsetReaction), ctx, teamID, channelID, messageID, reactionType)
+}
+
+// UnsetReplyReaction mocks base method.
+func (m *MockchannelOps) UnsetReplyReaction(ctx context.Context, teamID, channelID, messageID, replyID, reactionType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetReplyReaction", ctx, teamID, channelID, messageID, replyID, reactionType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetReplyReaction indicates an expected call of UnsetReplyReaction.
+func (mr *MockchannelOpsMockRecorder) UnsetReplyReaction(ctx, teamID, channelID, messageID, replyID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetReplyReaction", reflect.TypeOf((*MockchannelOps)(nil).UnsetReplyReaction), ctx, teamID, channelID, messageID, replyID, reactionType)
+}
+
 // UpdateMemberRoles mocks base method.
 func (m *MockchannelOps) UpdateMemberRoles(ctx context.Context, teamID, channelID, memberID string, isOwner bool) (*models.Member, error) {
 	m.ctrl.T.Helper()
@@ -324,3 +556,48 @@ func (mr *MockchannelOpsMockRecorder) UpdateMemberRoles(ctx, teamID, channelID,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRoles", reflect.TypeOf((*MockchannelOps)(nil).UpdateMemberRoles), ctx, teamID, channelID, memberID, isOwner)
 }
+
+// UpdateMessage mocks base method.
+func (m *MockchannelOps) UpdateMessage(ctx context.Context, teamID, channelID, messageID string, body models.MessageBody) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", ctx, teamID, channelID, messageID, body)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockchannelOpsMockRecorder) UpdateMessage(ctx, teamID, channelID, messageID, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockchannelOps)(nil).UpdateMessage), ctx, teamID, channelID, messageID, body)
+}
+
+// UpdateReply mocks base method.
+func (m *MockchannelOps) UpdateReply(ctx context.Context, teamID, channelID, messageID, replyID string, body models.MessageBody) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReply", ctx, teamID, channelID, messageID, replyID, body)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReply indicates an expected call of UpdateReply.
+func (mr *MockchannelOpsMockRecorder) UpdateReply(ctx, teamID, channelID, messageID, replyID, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReply", reflect.TypeOf((*MockchannelOps)(nil).UpdateReply), ctx, teamID, channelID, messageID, replyID, body)
+}
+
+// UploadFile mocks base method.
+func (m *MockchannelOps) UploadFile(ctx context.Context, teamID, channelID, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", ctx, teamID, channelID, name, r, size)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockchannelOpsMockRecorder) UploadFile(ctx, teamID, channelID, name, r, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockchannelOps)(nil).UploadFile), ctx, teamID, channelID, name, r, size)
+}
@@ -11,6 +11,7 @@ package testutil
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 	time "time"
 
@@ -286,6 +287,21 @@ func (mr *MockChatAPIMockRecorder) GetGroupChat(ctx, chatID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupChat", reflect.TypeOf((*MockChatAPI)(nil).GetGroupChat), ctx, chatID)
 }
 
+// GetHostedContent mocks base method.
+func (m *MockChatAPI) GetHostedContent(ctx context.Context, chatID, messageID, hostedContentID string) ([]byte, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHostedContent", ctx, chatID, messageID, hostedContentID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// GetHostedContent indicates an expected call of GetHostedContent.
+func (mr *MockChatAPIMockRecorder) GetHostedContent(ctx, chatID, messageID, hostedContentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHostedContent", reflect.TypeOf((*MockChatAPI)(nil).GetHostedContent), ctx, chatID, messageID, hostedContentID)
+}
+
 // GetMessage mocks base method.
 func (m *MockChatAPI) GetMessage(ctx context.Context, chatID, messageID string) (models.ChatMessageable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -361,6 +377,21 @@ func (mr *MockChatAPIMockRecorder) ListGroupChatMembers(ctx, chatID any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGroupChatMembers", reflect.TypeOf((*MockChatAPI)(nil).ListGroupChatMembers), ctx, chatID)
 }
 
+// ListHostedContents mocks base method.
+func (m *MockChatAPI) ListHostedContents(ctx context.Context, chatID, messageID string) (models.ChatMessageHostedContentCollectionResponseable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHostedContents", ctx, chatID, messageID)
+	ret0, _ := ret[0].(models.ChatMessageHostedContentCollectionResponseable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// ListHostedContents indicates an expected call of ListHostedContents.
+func (mr *MockChatAPIMockRecorder) ListHostedContents(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostedContents", reflect.TypeOf((*MockChatAPI)(nil).ListHostedContents), ctx, chatID, messageID)
+}
+
 // ListMessages mocks base method.
 func (m *MockChatAPI) ListMessages(ctx context.Context, chatID string, includeSystem bool) (models.ChatMessageCollectionResponseable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -376,6 +407,21 @@ func (mr *MockChatAPIMockRecorder) ListMessages(ctx, chatID, includeSystem any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockChatAPI)(nil).ListMessages), ctx, chatID, includeSystem)
 }
 
+// ListMessagesModifiedSince mocks base method.
+func (m *MockChatAPI) ListMessagesModifiedSince(ctx context.Context, chatID string, since time.Time) ([]models.ChatMessageable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessagesModifiedSince", ctx, chatID, since)
+	ret0, _ := ret[0].([]models.ChatMessageable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// ListMessagesModifiedSince indicates an expected call of ListMessagesModifiedSince.
+func (mr *MockChatAPIMockRecorder) ListMessagesModifiedSince(ctx, chatID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessagesModifiedSince", reflect.TypeOf((*MockChatAPI)(nil).ListMessagesModifiedSince), ctx, chatID, since)
+}
+
 // ListMessagesNext mocks base method.
 func (m *MockChatAPI) ListMessagesNext(ctx context.Context, chatID, nextLink string, includeSystem bool) (models.ChatMessageCollectionResponseable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -406,6 +452,21 @@ func (mr *MockChatAPIMockRecorder) ListPinnedMessages(ctx, chatID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPinnedMessages", reflect.TypeOf((*MockChatAPI)(nil).ListPinnedMessages), ctx, chatID)
 }
 
+// OpenFile mocks base method.
+func (m *MockChatAPI) OpenFile(ctx context.Context, contentURL string) (io.ReadCloser, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenFile", ctx, contentURL)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// OpenFile indicates an expected call of OpenFile.
+func (mr *MockChatAPIMockRecorder) OpenFile(ctx, contentURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockChatAPI)(nil).OpenFile), ctx, contentURL)
+}
+
 // PinMessage mocks base method.
 func (m *MockChatAPI) PinMessage(ctx context.Context, chatID, messageID string) *sender.RequestError {
 	m.ctrl.T.Helper()
@@ -435,13 +496,12 @@ func (mr *MockChatAPIMockRecorder) RemoveMemberFromGroupChat(ctx, chatID, member
 }
 
 // SearchChatMessages mocks base method.
-func (m *MockChatAPI) SearchChatMessages(ctx context.Context, chatID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) ([]*api.SearchMessage, *sender.RequestError, *int32) {
+func (m *MockChatAPI) SearchChatMessages(ctx context.Context, chatID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*api.SearchMessagesPage, *sender.RequestError) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SearchChatMessages", ctx, chatID, opts, searchConfig)
-	ret0, _ := ret[0].([]*api.SearchMessage)
+	ret0, _ := ret[0].(*api.SearchMessagesPage)
 	ret1, _ := ret[1].(*sender.RequestError)
-	ret2, _ := ret[2].(*int32)
-	return ret0, ret1, ret2
+	return ret0, ret1
 }
 
 // SearchChatMessages indicates an expected call of SearchChatMessages.
@@ -451,18 +511,46 @@ func (mr *MockChatAPIMockRecorder) SearchChatMessages(ctx, chatID, opts, searchC
 }
 
 // SendMessage mocks base method.
-func (m *MockChatAPI) SendMessage(ctx context.Context, chatID, content, contentType string, mentions []models.ChatMessageMentionable) (models.ChatMessageable, *sender.RequestError) {
+func (m *MockChatAPI) SendMessage(ctx context.Context, chatID, content, contentType, subject, importance string, mentions []models.ChatMessageMentionable, attachments []models.ChatMessageAttachmentable, hostedContents []models.ChatMessageHostedContentable) (models.ChatMessageable, *sender.RequestError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendMessage", ctx, chatID, content, contentType, mentions)
+	ret := m.ctrl.Call(m, "SendMessage", ctx, chatID, content, contentType, subject, importance, mentions, attachments, hostedContents)
 	ret0, _ := ret[0].(models.ChatMessageable)
 	ret1, _ := ret[1].(*sender.RequestError)
 	return ret0, ret1
 }
 
 // SendMessage indicates an expected call of SendMessage.
-func (mr *MockChatAPIMockRecorder) SendMessage(ctx, chatID, content, contentType, mentions any) *gomock.Call {
+func (mr *MockChatAPIMockRecorder) SendMessage(ctx, chatID, content, contentType, subject, importance, mentions, attachments, hostedContents any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockChatAPI)(nil).SendMessage), ctx, chatID, content, contentType, mentions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockChatAPI)(nil).SendMessage), ctx, chatID, content, contentType, subject, importance, mentions, attachments, hostedContents)
+}
+
+// SetReaction mocks base method.
+func (m *MockChatAPI) SetReaction(ctx context.Context, chatID, messageID, reactionType string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReaction", ctx, chatID, messageID, reactionType)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// SetReaction indicates an expected call of SetReaction.
+func (mr *MockChatAPIMockRecorder) SetReaction(ctx, chatID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReaction", reflect.TypeOf((*MockChatAPI)(nil).SetReaction), ctx, chatID, messageID, reactionType)
+}
+
+// UndoDeleteMessage mocks base method.
+func (m *MockChatAPI) UndoDeleteMessage(ctx context.Context, chatID, messageID string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoDeleteMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UndoDeleteMessage indicates an expected call of UndoDeleteMessage.
+func (mr *MockChatAPIMockRecorder) UndoDeleteMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoDeleteMessage", reflect.TypeOf((*MockChatAPI)(nil).UndoDeleteMessage), ctx, chatID, messageID)
 }
 
 // UnpinMessage mocks base method.
@@ -479,6 +567,20 @@ func (mr *MockChatAPIMockRecorder) UnpinMessage(ctx, chatID, pinnedID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinMessage", reflect.TypeOf((*MockChatAPI)(nil).UnpinMessage), ctx, chatID, pinnedID)
 }
 
+// UnsetReaction mocks base method.
+func (m *MockChatAPI) UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetReaction", ctx, chatID, messageID, reactionType)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UnsetReaction indicates an expected call of UnsetReaction.
+func (mr *MockChatAPIMockRecorder) UnsetReaction(ctx, chatID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetReaction", reflect.TypeOf((*MockChatAPI)(nil).UnsetReaction), ctx, chatID, messageID, reactionType)
+}
+
 // UpdateGroupChatTopic mocks base method.
 func (m *MockChatAPI) UpdateGroupChatTopic(ctx context.Context, chatID, topic string) (models.Chatable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -493,3 +595,32 @@ func (mr *MockChatAPIMockRecorder) UpdateGroupChatTopic(ctx, chatID, topic any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupChatTopic", reflect.TypeOf((*MockChatAPI)(nil).UpdateGroupChatTopic), ctx, chatID, topic)
 }
+
+// UpdateMessage mocks base method.
+func (m *MockChatAPI) UpdateMessage(ctx context.Context, chatID, messageID, content, contentType string, mentions []models.ChatMessageMentionable, attachments []models.ChatMessageAttachmentable) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", ctx, chatID, messageID, content, contentType, mentions, attachments)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockChatAPIMockRecorder) UpdateMessage(ctx, chatID, messageID, content, contentType, mentions, attachments any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockChatAPI)(nil).UpdateMessage), ctx, chatID, messageID, content, contentType, mentions, attachments)
+}
+
+// UploadFile mocks base method.
+func (m *MockChatAPI) UploadFile(ctx context.Context, name string, r io.Reader, size int64) (models.DriveItemable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", ctx, name, r, size)
+	ret0, _ := ret[0].(models.DriveItemable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockChatAPIMockRecorder) UploadFile(ctx, name, r, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockChatAPI)(nil).UploadFile), ctx, name, r, size)
+}
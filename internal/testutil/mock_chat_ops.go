@@ -0,0 +1,502 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: chats/ops_interface.go
+//
+// Generated by this command:
+//
+//	mockgen --source=chats/ops_interface.go --destination=internal/testutil/mock_chat_ops.go --package=testutil
+//
+
+// Package testutil is a generated GoMock package.
+package testutil
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/pzsp-teams/lib/models"
+	search "github.com/pzsp-teams/lib/search"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockchatOps is a mock of chatOps interface.
+type MockchatOps struct {
+	ctrl     *gomock.Controller
+	recorder *MockchatOpsMockRecorder
+	isgomock struct{}
+}
+
+// MockchatOpsMockRecorder is the mock recorder for MockchatOps.
+type MockchatOpsMockRecorder struct {
+	mock *MockchatOps
+}
+
+// NewMockchatOps creates a new mock instance.
+func NewMockchatOps(ctrl *gomock.Controller) *MockchatOps {
+	mock := &MockchatOps{ctrl: ctrl}
+	mock.recorder = &MockchatOpsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockchatOps) EXPECT() *MockchatOpsMockRecorder {
+	return m.recorder
+}
+
+// AddMemberToGroupChat mocks base method.
+func (m *MockchatOps) AddMemberToGroupChat(ctx context.Context, chatID, userID string) (*models.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMemberToGroupChat", ctx, chatID, userID)
+	ret0, _ := ret[0].(*models.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMemberToGroupChat indicates an expected call of AddMemberToGroupChat.
+func (mr *MockchatOpsMockRecorder) AddMemberToGroupChat(ctx, chatID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMemberToGroupChat", reflect.TypeOf((*MockchatOps)(nil).AddMemberToGroupChat), ctx, chatID, userID)
+}
+
+// CreateGroup mocks base method.
+func (m *MockchatOps) CreateGroup(ctx context.Context, userIDs []string, topic string, includeMe bool) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroup", ctx, userIDs, topic, includeMe)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGroup indicates an expected call of CreateGroup.
+func (mr *MockchatOpsMockRecorder) CreateGroup(ctx, userIDs, topic, includeMe any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroup", reflect.TypeOf((*MockchatOps)(nil).CreateGroup), ctx, userIDs, topic, includeMe)
+}
+
+// CreateOneOnOne mocks base method.
+func (m *MockchatOps) CreateOneOnOne(ctx context.Context, userID string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOneOnOne", ctx, userID)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOneOnOne indicates an expected call of CreateOneOnOne.
+func (mr *MockchatOpsMockRecorder) CreateOneOnOne(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOneOnOne", reflect.TypeOf((*MockchatOps)(nil).CreateOneOnOne), ctx, userID)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockchatOps) DeleteMessage(ctx context.Context, chatID, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockchatOpsMockRecorder) DeleteMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockchatOps)(nil).DeleteMessage), ctx, chatID, messageID)
+}
+
+// DownloadFile mocks base method.
+func (m *MockchatOps) DownloadFile(ctx context.Context, attachment models.Attachment, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadFile", ctx, attachment, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadFile indicates an expected call of DownloadFile.
+func (mr *MockchatOpsMockRecorder) DownloadFile(ctx, attachment, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadFile", reflect.TypeOf((*MockchatOps)(nil).DownloadFile), ctx, attachment, w)
+}
+
+// DownloadHostedContent mocks base method.
+func (m *MockchatOps) DownloadHostedContent(ctx context.Context, chatID, messageID, hostedContentID string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadHostedContent", ctx, chatID, messageID, hostedContentID, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DownloadHostedContent indicates an expected call of DownloadHostedContent.
+func (mr *MockchatOpsMockRecorder) DownloadHostedContent(ctx, chatID, messageID, hostedContentID, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadHostedContent", reflect.TypeOf((*MockchatOps)(nil).DownloadHostedContent), ctx, chatID, messageID, hostedContentID, w)
+}
+
+// GetCurrentUser mocks base method.
+func (m *MockchatOps) GetCurrentUser(ctx context.Context) (*models.MessageFrom, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUser", ctx)
+	ret0, _ := ret[0].(*models.MessageFrom)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUser indicates an expected call of GetCurrentUser.
+func (mr *MockchatOpsMockRecorder) GetCurrentUser(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockchatOps)(nil).GetCurrentUser), ctx)
+}
+
+// GetGroupChat mocks base method.
+func (m *MockchatOps) GetGroupChat(ctx context.Context, chatID string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupChat", ctx, chatID)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupChat indicates an expected call of GetGroupChat.
+func (mr *MockchatOpsMockRecorder) GetGroupChat(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupChat", reflect.TypeOf((*MockchatOps)(nil).GetGroupChat), ctx, chatID)
+}
+
+// GetMentions mocks base method.
+func (m *MockchatOps) GetMentions(ctx context.Context, chatID string, isGroup bool, rawMentions []string) ([]models.Mention, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMentions", ctx, chatID, isGroup, rawMentions)
+	ret0, _ := ret[0].([]models.Mention)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMentions indicates an expected call of GetMentions.
+func (mr *MockchatOpsMockRecorder) GetMentions(ctx, chatID, isGroup, rawMentions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMentions", reflect.TypeOf((*MockchatOps)(nil).GetMentions), ctx, chatID, isGroup, rawMentions)
+}
+
+// GetMessage mocks base method.
+func (m *MockchatOps) GetMessage(ctx context.Context, chatID, messageID string) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessage indicates an expected call of GetMessage.
+func (mr *MockchatOpsMockRecorder) GetMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessage", reflect.TypeOf((*MockchatOps)(nil).GetMessage), ctx, chatID, messageID)
+}
+
+// GetOneOnOneChat mocks base method.
+func (m *MockchatOps) GetOneOnOneChat(ctx context.Context, chatID string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOneOnOneChat", ctx, chatID)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOneOnOneChat indicates an expected call of GetOneOnOneChat.
+func (mr *MockchatOpsMockRecorder) GetOneOnOneChat(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneOnOneChat", reflect.TypeOf((*MockchatOps)(nil).GetOneOnOneChat), ctx, chatID)
+}
+
+// ListAllMessages mocks base method.
+func (m *MockchatOps) ListAllMessages(ctx context.Context, startTime, endTime *time.Time, top *int32) ([]*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllMessages", ctx, startTime, endTime, top)
+	ret0, _ := ret[0].([]*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllMessages indicates an expected call of ListAllMessages.
+func (mr *MockchatOpsMockRecorder) ListAllMessages(ctx, startTime, endTime, top any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllMessages", reflect.TypeOf((*MockchatOps)(nil).ListAllMessages), ctx, startTime, endTime, top)
+}
+
+// ListChats mocks base method.
+func (m *MockchatOps) ListChats(ctx context.Context, chatType *models.ChatType) ([]*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", ctx, chatType)
+	ret0, _ := ret[0].([]*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockchatOpsMockRecorder) ListChats(ctx, chatType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockchatOps)(nil).ListChats), ctx, chatType)
+}
+
+// ListGroupChatMembers mocks base method.
+func (m *MockchatOps) ListGroupChatMembers(ctx context.Context, chatID string) ([]*models.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGroupChatMembers", ctx, chatID)
+	ret0, _ := ret[0].([]*models.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGroupChatMembers indicates an expected call of ListGroupChatMembers.
+func (mr *MockchatOpsMockRecorder) ListGroupChatMembers(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGroupChatMembers", reflect.TypeOf((*MockchatOps)(nil).ListGroupChatMembers), ctx, chatID)
+}
+
+// ListHostedContents mocks base method.
+func (m *MockchatOps) ListHostedContents(ctx context.Context, chatID, messageID string) ([]*models.HostedContent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHostedContents", ctx, chatID, messageID)
+	ret0, _ := ret[0].([]*models.HostedContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHostedContents indicates an expected call of ListHostedContents.
+func (mr *MockchatOpsMockRecorder) ListHostedContents(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHostedContents", reflect.TypeOf((*MockchatOps)(nil).ListHostedContents), ctx, chatID, messageID)
+}
+
+// ListMessages mocks base method.
+func (m *MockchatOps) ListMessages(ctx context.Context, chatID string, includeSystem bool) (*models.MessageCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessages", ctx, chatID, includeSystem)
+	ret0, _ := ret[0].(*models.MessageCollection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessages indicates an expected call of ListMessages.
+func (mr *MockchatOpsMockRecorder) ListMessages(ctx, chatID, includeSystem any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessages", reflect.TypeOf((*MockchatOps)(nil).ListMessages), ctx, chatID, includeSystem)
+}
+
+// ListMessagesModifiedSince mocks base method.
+func (m *MockchatOps) ListMessagesModifiedSince(ctx context.Context, chatID string, since time.Time) ([]*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessagesModifiedSince", ctx, chatID, since)
+	ret0, _ := ret[0].([]*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessagesModifiedSince indicates an expected call of ListMessagesModifiedSince.
+func (mr *MockchatOpsMockRecorder) ListMessagesModifiedSince(ctx, chatID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessagesModifiedSince", reflect.TypeOf((*MockchatOps)(nil).ListMessagesModifiedSince), ctx, chatID, since)
+}
+
+// ListMessagesNext mocks base method.
+func (m *MockchatOps) ListMessagesNext(ctx context.Context, chatID, nextLink string, includeSystem bool) (*models.MessageCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMessagesNext", ctx, chatID, nextLink, includeSystem)
+	ret0, _ := ret[0].(*models.MessageCollection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMessagesNext indicates an expected call of ListMessagesNext.
+func (mr *MockchatOpsMockRecorder) ListMessagesNext(ctx, chatID, nextLink, includeSystem any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMessagesNext", reflect.TypeOf((*MockchatOps)(nil).ListMessagesNext), ctx, chatID, nextLink, includeSystem)
+}
+
+// ListPinnedMessages mocks base method.
+func (m *MockchatOps) ListPinnedMessages(ctx context.Context, chatID string) ([]*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPinnedMessages", ctx, chatID)
+	ret0, _ := ret[0].([]*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPinnedMessages indicates an expected call of ListPinnedMessages.
+func (mr *MockchatOpsMockRecorder) ListPinnedMessages(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPinnedMessages", reflect.TypeOf((*MockchatOps)(nil).ListPinnedMessages), ctx, chatID)
+}
+
+// PinMessage mocks base method.
+func (m *MockchatOps) PinMessage(ctx context.Context, chatID, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinMessage indicates an expected call of PinMessage.
+func (mr *MockchatOpsMockRecorder) PinMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinMessage", reflect.TypeOf((*MockchatOps)(nil).PinMessage), ctx, chatID, messageID)
+}
+
+// RemoveMemberFromGroupChat mocks base method.
+func (m *MockchatOps) RemoveMemberFromGroupChat(ctx context.Context, chatID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMemberFromGroupChat", ctx, chatID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMemberFromGroupChat indicates an expected call of RemoveMemberFromGroupChat.
+func (mr *MockchatOpsMockRecorder) RemoveMemberFromGroupChat(ctx, chatID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMemberFromGroupChat", reflect.TypeOf((*MockchatOps)(nil).RemoveMemberFromGroupChat), ctx, chatID, userID)
+}
+
+// SearchChatMessages mocks base method.
+func (m *MockchatOps) SearchChatMessages(ctx context.Context, chatID *string, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchChatMessages", ctx, chatID, opts, searchConfig)
+	ret0, _ := ret[0].(*search.SearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchChatMessages indicates an expected call of SearchChatMessages.
+func (mr *MockchatOpsMockRecorder) SearchChatMessages(ctx, chatID, opts, searchConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchChatMessages", reflect.TypeOf((*MockchatOps)(nil).SearchChatMessages), ctx, chatID, opts, searchConfig)
+}
+
+// SendMessage mocks base method.
+func (m *MockchatOps) SendMessage(ctx context.Context, chatID string, body models.MessageBody) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", ctx, chatID, body)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockchatOpsMockRecorder) SendMessage(ctx, chatID, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockchatOps)(nil).SendMessage), ctx, chatID, body)
+}
+
+// SetReaction mocks base method.
+func (m *MockchatOps) SetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReaction", ctx, chatID, messageID, reactionType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReaction indicates an expected call of SetReaction.
+func (mr *MockchatOpsMockRecorder) SetReaction(ctx, chatID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReaction", reflect.TypeOf((*MockchatOps)(nil).SetReaction), ctx, chatID, messageID, reactionType)
+}
+
+// SyncMessages mocks base method.
+func (m *MockchatOps) SyncMessages(ctx context.Context, chatID, token string) (*models.MessageChanges, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncMessages", ctx, chatID, token)
+	ret0, _ := ret[0].(*models.MessageChanges)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SyncMessages indicates an expected call of SyncMessages.
+func (mr *MockchatOpsMockRecorder) SyncMessages(ctx, chatID, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncMessages", reflect.TypeOf((*MockchatOps)(nil).SyncMessages), ctx, chatID, token)
+}
+
+// UndoDeleteMessage mocks base method.
+func (m *MockchatOps) UndoDeleteMessage(ctx context.Context, chatID, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoDeleteMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndoDeleteMessage indicates an expected call of UndoDeleteMessage.
+func (mr *MockchatOpsMockRecorder) UndoDeleteMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoDeleteMessage", reflect.TypeOf((*MockchatOps)(nil).UndoDeleteMessage), ctx, chatID, messageID)
+}
+
+// UnpinMessage mocks base method.
+func (m *MockchatOps) UnpinMessage(ctx context.Context, chatID, messageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinMessage indicates an expected call of UnpinMessage.
+func (mr *MockchatOpsMockRecorder) UnpinMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinMessage", reflect.TypeOf((*MockchatOps)(nil).UnpinMessage), ctx, chatID, messageID)
+}
+
+// UnsetReaction mocks base method.
+func (m *MockchatOps) UnsetReaction(ctx context.Context, chatID, messageID, reactionType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsetReaction", ctx, chatID, messageID, reactionType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsetReaction indicates an expected call of UnsetReaction.
+func (mr *MockchatOpsMockRecorder) UnsetReaction(ctx, chatID, messageID, reactionType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsetReaction", reflect.TypeOf((*MockchatOps)(nil).UnsetReaction), ctx, chatID, messageID, reactionType)
+}
+
+// UpdateGroupChatTopic mocks base method.
+func (m *MockchatOps) UpdateGroupChatTopic(ctx context.Context, chatID, topic string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGroupChatTopic", ctx, chatID, topic)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGroupChatTopic indicates an expected call of UpdateGroupChatTopic.
+func (mr *MockchatOpsMockRecorder) UpdateGroupChatTopic(ctx, chatID, topic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupChatTopic", reflect.TypeOf((*MockchatOps)(nil).UpdateGroupChatTopic), ctx, chatID, topic)
+}
+
+// UpdateMessage mocks base method.
+func (m *MockchatOps) UpdateMessage(ctx context.Context, chatID, messageID string, body models.MessageBody) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessage", ctx, chatID, messageID, body)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMessage indicates an expected call of UpdateMessage.
+func (mr *MockchatOpsMockRecorder) UpdateMessage(ctx, chatID, messageID, body any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessage", reflect.TypeOf((*MockchatOps)(nil).UpdateMessage), ctx, chatID, messageID, body)
+}
+
+// UploadFile mocks base method.
+func (m *MockchatOps) UploadFile(ctx context.Context, name string, r io.Reader, size int64) (*models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadFile", ctx, name, r, size)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadFile indicates an expected call of UploadFile.
+func (mr *MockchatOpsMockRecorder) UploadFile(ctx, name, r, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadFile", reflect.TypeOf((*MockchatOps)(nil).UploadFile), ctx, name, r, size)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/resolver/chat_cacheable.go
+//
+// Generated by this command:
+//
+//	mockgen --source=internal/resolver/chat_cacheable.go --destination=internal/testutil/mock_chat_resolver.go --package=testutil
+//
+
+// Package testutil is a generated GoMock package.
+package testutil
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChatResolver is a mock of ChatResolver interface.
+type MockChatResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatResolverMockRecorder
+	isgomock struct{}
+}
+
+// MockChatResolverMockRecorder is the mock recorder for MockChatResolver.
+type MockChatResolverMockRecorder struct {
+	mock *MockChatResolver
+}
+
+// NewMockChatResolver creates a new mock instance.
+func NewMockChatResolver(ctrl *gomock.Controller) *MockChatResolver {
+	mock := &MockChatResolver{ctrl: ctrl}
+	mock.recorder = &MockChatResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatResolver) EXPECT() *MockChatResolverMockRecorder {
+	return m.recorder
+}
+
+// ResolveChatMemberRefToID mocks base method.
+func (m *MockChatResolver) ResolveChatMemberRefToID(ctx context.Context, chatID, userRef string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveChatMemberRefToID", ctx, chatID, userRef)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveChatMemberRefToID indicates an expected call of ResolveChatMemberRefToID.
+func (mr *MockChatResolverMockRecorder) ResolveChatMemberRefToID(ctx, chatID, userRef any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveChatMemberRefToID", reflect.TypeOf((*MockChatResolver)(nil).ResolveChatMemberRefToID), ctx, chatID, userRef)
+}
+
+// ResolveGroupChatRefToID mocks base method.
+func (m *MockChatResolver) ResolveGroupChatRefToID(ctx context.Context, topic string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveGroupChatRefToID", ctx, topic)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveGroupChatRefToID indicates an expected call of ResolveGroupChatRefToID.
+func (mr *MockChatResolverMockRecorder) ResolveGroupChatRefToID(ctx, topic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveGroupChatRefToID", reflect.TypeOf((*MockChatResolver)(nil).ResolveGroupChatRefToID), ctx, topic)
+}
+
+// ResolveOneOnOneChatRefToID mocks base method.
+func (m *MockChatResolver) ResolveOneOnOneChatRefToID(ctx context.Context, userRef string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveOneOnOneChatRefToID", ctx, userRef)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveOneOnOneChatRefToID indicates an expected call of ResolveOneOnOneChatRefToID.
+func (mr *MockChatResolverMockRecorder) ResolveOneOnOneChatRefToID(ctx, userRef any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveOneOnOneChatRefToID", reflect.TypeOf((*MockChatResolver)(nil).ResolveOneOnOneChatRefToID), ctx, userRef)
+}
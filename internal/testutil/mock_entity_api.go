@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/api/entity.go
+//
+// Generated by this command:
+//
+//	mockgen --source=internal/api/entity.go --destination=internal/testutil/mock_entity_api.go --package=testutil
+//
+
+// Package testutil is a generated GoMock package.
+package testutil
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/microsoftgraph/msgraph-sdk-go/models"
+	api "github.com/pzsp-teams/lib/internal/api"
+	sender "github.com/pzsp-teams/lib/internal/sender"
+	search "github.com/pzsp-teams/lib/search"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEntityAPI is a mock of EntityAPI interface.
+type MockEntityAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntityAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockEntityAPIMockRecorder is the mock recorder for MockEntityAPI.
+type MockEntityAPIMockRecorder struct {
+	mock *MockEntityAPI
+}
+
+// NewMockEntityAPI creates a new mock instance.
+func NewMockEntityAPI(ctrl *gomock.Controller) *MockEntityAPI {
+	mock := &MockEntityAPI{ctrl: ctrl}
+	mock.recorder = &MockEntityAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntityAPI) EXPECT() *MockEntityAPIMockRecorder {
+	return m.recorder
+}
+
+// SearchEvents mocks base method.
+func (m *MockEntityAPI) SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*api.EntityPage[models.Eventable], *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEvents", ctx, opts, searchConfig)
+	ret0, _ := ret[0].(*api.EntityPage[models.Eventable])
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// SearchEvents indicates an expected call of SearchEvents.
+func (mr *MockEntityAPIMockRecorder) SearchEvents(ctx, opts, searchConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEvents", reflect.TypeOf((*MockEntityAPI)(nil).SearchEvents), ctx, opts, searchConfig)
+}
+
+// SearchFiles mocks base method.
+func (m *MockEntityAPI) SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*api.EntityPage[models.DriveItemable], *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFiles", ctx, opts, searchConfig)
+	ret0, _ := ret[0].(*api.EntityPage[models.DriveItemable])
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// SearchFiles indicates an expected call of SearchFiles.
+func (mr *MockEntityAPIMockRecorder) SearchFiles(ctx, opts, searchConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFiles", reflect.TypeOf((*MockEntityAPI)(nil).SearchFiles), ctx, opts, searchConfig)
+}
+
+// SearchPeople mocks base method.
+func (m *MockEntityAPI) SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*api.EntityPage[models.Personable], *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPeople", ctx, opts, searchConfig)
+	ret0, _ := ret[0].(*api.EntityPage[models.Personable])
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// SearchPeople indicates an expected call of SearchPeople.
+func (mr *MockEntityAPIMockRecorder) SearchPeople(ctx, opts, searchConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPeople", reflect.TypeOf((*MockEntityAPI)(nil).SearchPeople), ctx, opts, searchConfig)
+}
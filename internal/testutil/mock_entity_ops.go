@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: search/entities/ops_interface.go
+//
+// Generated by this command:
+//
+//	mockgen --source=search/entities/ops_interface.go --destination=internal/testutil/mock_entity_ops.go --package=testutil
+//
+
+// Package testutil is a generated GoMock package.
+package testutil
+
+import (
+	context "context"
+	reflect "reflect"
+
+	search "github.com/pzsp-teams/lib/search"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockentityOps is a mock of entityOps interface.
+type MockentityOps struct {
+	ctrl     *gomock.Controller
+	recorder *MockentityOpsMockRecorder
+	isgomock struct{}
+}
+
+// MockentityOpsMockRecorder is the mock recorder for MockentityOps.
+type MockentityOpsMockRecorder struct {
+	mock *MockentityOps
+}
+
+// NewMockentityOps creates a new mock instance.
+func NewMockentityOps(ctrl *gomock.Controller) *MockentityOps {
+	mock := &MockentityOps{ctrl: ctrl}
+	mock.recorder = &MockentityOpsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockentityOps) EXPECT() *MockentityOpsMockRecorder {
+	return m.recorder
+}
+
+// SearchEvents mocks base method.
+func (m *MockentityOps) SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Event], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEvents", ctx, opts, searchConfig)
+	ret0, _ := ret[0].(*search.EntityResults[search.Event])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEvents indicates an expected call of SearchEvents.
+func (mr *MockentityOpsMockRecorder) SearchEvents(ctx, opts, searchConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEvents", reflect.TypeOf((*MockentityOps)(nil).SearchEvents), ctx, opts, searchConfig)
+}
+
+// SearchFiles mocks base method.
+func (m *MockentityOps) SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.File], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchFiles", ctx, opts, searchConfig)
+	ret0, _ := ret[0].(*search.EntityResults[search.File])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchFiles indicates an expected call of SearchFiles.
+func (mr *MockentityOpsMockRecorder) SearchFiles(ctx, opts, searchConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchFiles", reflect.TypeOf((*MockentityOps)(nil).SearchFiles), ctx, opts, searchConfig)
+}
+
+// SearchPeople mocks base method.
+func (m *MockentityOps) SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Person], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPeople", ctx, opts, searchConfig)
+	ret0, _ := ret[0].(*search.EntityResults[search.Person])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPeople indicates an expected call of SearchPeople.
+func (mr *MockentityOpsMockRecorder) SearchPeople(ctx, opts, searchConfig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPeople", reflect.TypeOf((*MockentityOps)(nil).SearchPeople), ctx, opts, searchConfig)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	models "github.com/microsoftgraph/msgraph-sdk-go/models"
 	search "github.com/microsoftgraph/msgraph-sdk-go/search"
 	sender "github.com/pzsp-teams/lib/internal/sender"
 	search0 "github.com/pzsp-teams/lib/search"
@@ -43,6 +44,21 @@ func (m *MockSearchAPI) EXPECT() *MockSearchAPIMockRecorder {
 	return m.recorder
 }
 
+// SearchEntities mocks base method.
+func (m *MockSearchAPI) SearchEntities(ctx context.Context, entityType models.EntityType, queryString string, page *search0.SearchPage, sort search0.SortOrder) (search.QueryPostResponseable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEntities", ctx, entityType, queryString, page, sort)
+	ret0, _ := ret[0].(search.QueryPostResponseable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// SearchEntities indicates an expected call of SearchEntities.
+func (mr *MockSearchAPIMockRecorder) SearchEntities(ctx, entityType, queryString, page, sort any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEntities", reflect.TypeOf((*MockSearchAPI)(nil).SearchEntities), ctx, entityType, queryString, page, sort)
+}
+
 // SearchMessages mocks base method.
 func (m *MockSearchAPI) SearchMessages(ctx context.Context, searchRequest *search0.SearchMessagesOptions) (search.QueryPostResponseable, *sender.RequestError) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/api/subscription.go
+//
+// Generated by this command:
+//
+//	mockgen --source=internal/api/subscription.go --destination=internal/testutil/mock_subscription_api.go --package=testutil
+//
+
+// Package testutil is a generated GoMock package.
+package testutil
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/microsoftgraph/msgraph-sdk-go/models"
+	sender "github.com/pzsp-teams/lib/internal/sender"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSubscriptionAPI is a mock of SubscriptionAPI interface.
+type MockSubscriptionAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockSubscriptionAPIMockRecorder is the mock recorder for MockSubscriptionAPI.
+type MockSubscriptionAPIMockRecorder struct {
+	mock *MockSubscriptionAPI
+}
+
+// NewMockSubscriptionAPI creates a new mock instance.
+func NewMockSubscriptionAPI(ctrl *gomock.Controller) *MockSubscriptionAPI {
+	mock := &MockSubscriptionAPI{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionAPI) EXPECT() *MockSubscriptionAPIMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSubscriptionAPI) Create(ctx context.Context, subscription models.Subscriptionable) (models.Subscriptionable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subscription)
+	ret0, _ := ret[0].(models.Subscriptionable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSubscriptionAPIMockRecorder) Create(ctx, subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSubscriptionAPI)(nil).Create), ctx, subscription)
+}
+
+// Delete mocks base method.
+func (m *MockSubscriptionAPI) Delete(ctx context.Context, subscriptionID string) *sender.RequestError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, subscriptionID)
+	ret0, _ := ret[0].(*sender.RequestError)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSubscriptionAPIMockRecorder) Delete(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubscriptionAPI)(nil).Delete), ctx, subscriptionID)
+}
+
+// List mocks base method.
+func (m *MockSubscriptionAPI) List(ctx context.Context) (models.SubscriptionCollectionResponseable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].(models.SubscriptionCollectionResponseable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSubscriptionAPIMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSubscriptionAPI)(nil).List), ctx)
+}
+
+// Update mocks base method.
+func (m *MockSubscriptionAPI) Update(ctx context.Context, subscriptionID string, patch models.Subscriptionable) (models.Subscriptionable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, subscriptionID, patch)
+	ret0, _ := ret[0].(models.Subscriptionable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSubscriptionAPIMockRecorder) Update(ctx, subscriptionID, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubscriptionAPI)(nil).Update), ctx, subscriptionID, patch)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: subscriptions/ops_interface.go
+//
+// Generated by this command:
+//
+//	mockgen --source=subscriptions/ops_interface.go --destination=internal/testutil/mock_subscription_ops.go --package=testutil
+//
+
+// Package testutil is a generated GoMock package.
+package testutil
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/pzsp-teams/lib/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MocksubscriptionOps is a mock of subscriptionOps interface.
+type MocksubscriptionOps struct {
+	ctrl     *gomock.Controller
+	recorder *MocksubscriptionOpsMockRecorder
+	isgomock struct{}
+}
+
+// MocksubscriptionOpsMockRecorder is the mock recorder for MocksubscriptionOps.
+type MocksubscriptionOpsMockRecorder struct {
+	mock *MocksubscriptionOps
+}
+
+// NewMocksubscriptionOps creates a new mock instance.
+func NewMocksubscriptionOps(ctrl *gomock.Controller) *MocksubscriptionOps {
+	mock := &MocksubscriptionOps{ctrl: ctrl}
+	mock.recorder = &MocksubscriptionOpsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksubscriptionOps) EXPECT() *MocksubscriptionOpsMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MocksubscriptionOps) Create(ctx context.Context, resource string, opts *models.SubscriptionOptions, expiration time.Time) (*models.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, resource, opts, expiration)
+	ret0, _ := ret[0].(*models.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MocksubscriptionOpsMockRecorder) Create(ctx, resource, opts, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MocksubscriptionOps)(nil).Create), ctx, resource, opts, expiration)
+}
+
+// Delete mocks base method.
+func (m *MocksubscriptionOps) Delete(ctx context.Context, subscriptionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, subscriptionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MocksubscriptionOpsMockRecorder) Delete(ctx, subscriptionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MocksubscriptionOps)(nil).Delete), ctx, subscriptionID)
+}
+
+// List mocks base method.
+func (m *MocksubscriptionOps) List(ctx context.Context) ([]*models.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*models.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MocksubscriptionOpsMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MocksubscriptionOps)(nil).List), ctx)
+}
+
+// Renew mocks base method.
+func (m *MocksubscriptionOps) Renew(ctx context.Context, subscriptionID string, expiration time.Time) (*models.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, subscriptionID, expiration)
+	ret0, _ := ret[0].(*models.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MocksubscriptionOpsMockRecorder) Renew(ctx, subscriptionID, expiration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MocksubscriptionOps)(nil).Renew), ctx, subscriptionID, expiration)
+}
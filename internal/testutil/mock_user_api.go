@@ -42,6 +42,21 @@ func (m *MockUserAPI) EXPECT() *MockUserAPIMockRecorder {
 	return m.recorder
 }
 
+// GetMe mocks base method.
+func (m *MockUserAPI) GetMe(ctx context.Context) (models.Userable, *sender.RequestError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMe", ctx)
+	ret0, _ := ret[0].(models.Userable)
+	ret1, _ := ret[1].(*sender.RequestError)
+	return ret0, ret1
+}
+
+// GetMe indicates an expected call of GetMe.
+func (mr *MockUserAPIMockRecorder) GetMe(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMe", reflect.TypeOf((*MockUserAPI)(nil).GetMe), ctx)
+}
+
 // GetUserByEmailOrUPN mocks base method.
 func (m *MockUserAPI) GetUserByEmailOrUPN(ctx context.Context, emailOrUPN string) (models.Userable, *sender.RequestError) {
 	m.ctrl.T.Helper()
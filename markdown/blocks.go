@@ -0,0 +1,340 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingLine   = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematicBreak = regexp.MustCompile(`^(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	listMarker    = regexp.MustCompile(`^( {0,3})([-*+]|(\d{1,9})[.)])( +|$)`)
+	tableDelimRow = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+)
+
+// blocks renders lines as block elements. In tight lists paragraphs are rendered without <p> tags.
+func (r *renderer) blocks(lines []string, tight bool) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+
+		switch {
+		case trimmed == "":
+			i++
+		case indent < 4 && fenceOf(trimmed) != "":
+			i = r.codeBlock(lines, i, indent)
+		case indent < 4 && headingLine.MatchString(trimmed):
+			m := headingLine.FindStringSubmatch(trimmed)
+			level := len(m[1])
+			fmt.Fprintf(&r.sb, "<h%d>", level)
+			r.inline(m[2])
+			fmt.Fprintf(&r.sb, "</h%d>", level)
+			i++
+		case indent < 4 && thematicBreak.MatchString(trimmed):
+			r.sb.WriteString("<hr>")
+			i++
+		case indent < 4 && strings.HasPrefix(trimmed, ">"):
+			i = r.blockQuote(lines, i)
+		case listMarker.MatchString(line):
+			i = r.list(lines, i)
+		case isTableStart(lines, i):
+			i = r.table(lines, i)
+		default:
+			i = r.paragraph(lines, i, tight)
+		}
+	}
+}
+
+// fenceOf returns the opening code fence of line, or "" when line does not open a fenced code block.
+func fenceOf(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n < 3 {
+			continue
+		}
+		if c == "`" && strings.Contains(line[n:], "`") {
+			return ""
+		}
+		return line[:n]
+	}
+	return ""
+}
+
+func (r *renderer) codeBlock(lines []string, start, indent int) int {
+	fence := fenceOf(strings.TrimLeft(lines[start], " "))
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" ") == "" {
+			i++
+			break
+		}
+		code = append(code, trimIndent(lines[i], indent))
+	}
+	r.sb.WriteString("<pre><code>")
+	r.sb.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	r.sb.WriteString("</code></pre>")
+	return i
+}
+
+func (r *renderer) blockQuote(lines []string, start int) int {
+	var inner []string
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		inner = append(inner, strings.TrimPrefix(trimmed, " "))
+	}
+	r.sb.WriteString("<blockquote>")
+	r.blocks(inner, false)
+	r.sb.WriteString("</blockquote>")
+	return i
+}
+
+func (r *renderer) list(lines []string, start int) int {
+	first := listMarker.FindStringSubmatch(lines[start])
+	indent := len(first[1])
+	ordered := first[3] != ""
+	delim := first[2][len(first[2])-1:]
+
+	sameList := func(line string) []string {
+		m := listMarker.FindStringSubmatch(line)
+		if m == nil || len(m[1]) != indent || (m[3] != "") != ordered || m[2][len(m[2])-1:] != delim {
+			return nil
+		}
+		return m
+	}
+
+	// items holds the lines of each item, relative to the item content column
+	var items [][]string
+	loose := false
+	i := start
+	for i < len(lines) {
+		m := sameList(lines[i])
+		if m == nil {
+			break
+		}
+
+		offset := len(m[0])
+		if len(m[4]) > 4 {
+			offset = len(m[1]) + len(m[2]) + 1
+		}
+		item := []string{lines[i][offset:]}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+			lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+			switch {
+			case strings.TrimSpace(line) == "":
+				item = append(item, "")
+				i++
+				continue
+			case lineIndent >= offset:
+				item = append(item, line[offset:])
+				i++
+				continue
+			case lineIndent > indent && listMarker.MatchString(strings.TrimLeft(line, " ")):
+				item = append(item, line[lineIndent:])
+				i++
+				continue
+			case item[len(item)-1] != "" && !listMarker.MatchString(line) && !startsBlock(line):
+				// lazy continuation of the last paragraph
+				item = append(item, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+			break
+		}
+
+		trailing := 0
+		for len(item) > 0 && item[len(item)-1] == "" {
+			item = item[:len(item)-1]
+			trailing++
+		}
+		if hasInnerBlankLine(item) || (trailing > 0 && i < len(lines) && sameList(lines[i]) != nil) {
+			loose = true
+		}
+		items = append(items, item)
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	if n, _ := strconv.Atoi(first[3]); ordered && n != 1 {
+		fmt.Fprintf(&r.sb, `<ol start="%d">`, n)
+	} else {
+		fmt.Fprintf(&r.sb, "<%s>", tag)
+	}
+	for _, item := range items {
+		r.sb.WriteString("<li>")
+		r.blocks(item, !loose)
+		r.sb.WriteString("</li>")
+	}
+	fmt.Fprintf(&r.sb, "</%s>", tag)
+	return i
+}
+
+// hasInnerBlankLine reports whether blank lines separate paragraphs of an item, outside of nested lists
+// and code blocks.
+func hasInnerBlankLine(lines []string) bool {
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f := fenceOf(trimmed); f != "" && len(line) == len(trimmed) {
+			fence = f
+			continue
+		}
+		if line == "" && i+1 < len(lines) && !strings.HasPrefix(lines[i+1], " ") {
+			return true
+		}
+	}
+	return false
+}
+
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) || !strings.Contains(lines[i], "|") || !tableDelimRow.MatchString(strings.TrimSpace(lines[i+1])) {
+		return false
+	}
+	return len(splitRow(lines[i])) == len(splitRow(lines[i+1]))
+}
+
+func (r *renderer) table(lines []string, start int) int {
+	header := splitRow(lines[start])
+	aligns := make([]string, len(header))
+	for j, cell := range splitRow(lines[start+1]) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		switch {
+		case left && right:
+			aligns[j] = "center"
+		case right:
+			aligns[j] = "right"
+		case left:
+			aligns[j] = "left"
+		}
+	}
+
+	r.sb.WriteString("<table><thead><tr>")
+	for j, cell := range header {
+		r.cell("th", cell, aligns[j])
+	}
+	r.sb.WriteString("</tr></thead>")
+
+	i := start + 2
+	if i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|") {
+		r.sb.WriteString("<tbody>")
+		for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.Contains(lines[i], "|"); i++ {
+			cells := splitRow(lines[i])
+			r.sb.WriteString("<tr>")
+			for j := range header {
+				cell := ""
+				if j < len(cells) {
+					cell = cells[j]
+				}
+				r.cell("td", cell, aligns[j])
+			}
+			r.sb.WriteString("</tr>")
+		}
+		r.sb.WriteString("</tbody>")
+	}
+	r.sb.WriteString("</table>")
+	return i
+}
+
+func (r *renderer) cell(tag, content, align string) {
+	if align != "" {
+		fmt.Fprintf(&r.sb, `<%s style="text-align:%s">`, tag, align)
+	} else {
+		fmt.Fprintf(&r.sb, "<%s>", tag)
+	}
+	r.inline(content)
+	fmt.Fprintf(&r.sb, "</%s>", tag)
+}
+
+// splitRow splits a table row into trimmed cells. Pipes escaped as \| are kept in cells.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func (r *renderer) paragraph(lines []string, start int, tight bool) int {
+	var text []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || (i > start && (startsBlock(line) || isTableStart(lines, i))) {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) {
+			line = strings.TrimSuffix(line, `\`)
+		}
+		text = append(text, line)
+	}
+	if !tight {
+		r.sb.WriteString("<p>")
+	}
+	r.inline(strings.Join(text, "\n"))
+	if !tight {
+		r.sb.WriteString("</p>")
+	}
+	return i
+}
+
+// startsBlock reports whether line starts a block that interrupts a paragraph.
+func startsBlock(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) >= 4 {
+		return false
+	}
+	if m := listMarker.FindStringSubmatch(line); m != nil {
+		// only ordered lists starting at 1 interrupt a paragraph, so that "2025. was a good year" does not
+		return m[3] == "" || m[3] == "1"
+	}
+	return fenceOf(trimmed) != "" ||
+		headingLine.MatchString(trimmed) ||
+		thematicBreak.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, ">")
+}
+
+// trimIndent removes up to n leading spaces from line.
+func trimIndent(line string, n int) string {
+	for n > 0 && strings.HasPrefix(line, " ") {
+		line = line[1:]
+		n--
+	}
+	return line
+}
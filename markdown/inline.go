@@ -0,0 +1,263 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	autolink     = regexp.MustCompile(`^<((?:https?|mailto):[^\s<>]+)>`)
+	bareURL      = regexp.MustCompile(`^https?://[^\s<]+`)
+	emailMention = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	wordMention  = regexp.MustCompile(`^[a-zA-Z]+`)
+)
+
+// inline renders the inline content s. Newlines are rendered as <br>.
+func (r *renderer) inline(s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			r.sb.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+		case c == '\n':
+			r.sb.WriteString("<br>")
+			i++
+		case c == '`':
+			i = r.codeSpan(s, i)
+		case c == '*' || c == '_' || c == '~':
+			i = r.emphasis(s, i)
+		case c == '[' || (c == '!' && i+1 < len(s) && s[i+1] == '['):
+			i = r.link(s, i)
+		case c == '<' && autolink.MatchString(s[i:]):
+			m := autolink.FindStringSubmatch(s[i:])
+			r.anchor(m[1], func() { r.sb.WriteString(html.EscapeString(strings.TrimPrefix(m[1], "mailto:"))) })
+			i += len(m[0])
+		case c == 'h' && !isWordByte(prev(s, i)) && bareURL.MatchString(s[i:]):
+			u := trimURL(bareURL.FindString(s[i:]))
+			r.anchor(u, func() { r.sb.WriteString(html.EscapeString(u)) })
+			i += len(u)
+		case c == '@':
+			i = r.mentionToken(s, i)
+		default:
+			r.sb.WriteString(html.EscapeString(s[i : i+1]))
+			i++
+		}
+	}
+}
+
+func (r *renderer) codeSpan(s string, i int) int {
+	n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+	ticks := s[i : i+n]
+	for j := i + n; j < len(s); {
+		k := strings.Index(s[j:], ticks)
+		if k < 0 {
+			break
+		}
+		k += j
+		end := k + n
+		if end < len(s) && s[end] == '`' {
+			// a longer backtick run does not close the span
+			j = end + len(s[end:]) - len(strings.TrimLeft(s[end:], "`"))
+			continue
+		}
+		code := strings.ReplaceAll(s[i+n:k], "\n", " ")
+		if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		r.sb.WriteString("<code>")
+		r.sb.WriteString(html.EscapeString(code))
+		r.sb.WriteString("</code>")
+		return end
+	}
+	r.sb.WriteString(ticks)
+	return i + n
+}
+
+// emphasis renders **strong**, __strong__, *em*, _em_ and ~~strikethrough~~ starting at s[i].
+func (r *renderer) emphasis(s string, i int) int {
+	c := s[i]
+	delim := s[i : i+1]
+	if i+1 < len(s) && s[i+1] == c {
+		delim = s[i : i+2]
+	}
+	tag := map[string]string{"**": "strong", "__": "strong", "*": "em", "_": "em", "~~": "s"}[delim]
+
+	open := i + len(delim)
+	intraword := c == '_' && isWordByte(prev(s, i))
+	if tag == "" || intraword || open >= len(s) || isSpace(s[open]) {
+		r.sb.WriteString(delim)
+		return open
+	}
+	end := findCloser(s, open, delim)
+	if end < 0 {
+		r.sb.WriteString(delim)
+		return open
+	}
+	r.sb.WriteString("<" + tag + ">")
+	r.inline(s[open:end])
+	r.sb.WriteString("</" + tag + ">")
+	return end + len(delim)
+}
+
+// findCloser returns the index of the delimiter closing an emphasis opened before s[from], or -1.
+// Code spans and escaped characters are skipped, and single delimiters do not match double ones.
+func findCloser(s string, from int, delim string) int {
+	c := delim[0]
+	for j := from; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			n := len(s[j:]) - len(strings.TrimLeft(s[j:], "`"))
+			if k := strings.Index(s[j+n:], s[j:j+n]); k >= 0 {
+				j += n + k + n - 1
+			} else {
+				j += n - 1
+			}
+			continue
+		}
+		if !strings.HasPrefix(s[j:], delim) || j == from || isSpace(s[j-1]) {
+			continue
+		}
+		after := j + len(delim)
+		if len(delim) == 1 && ((after < len(s) && s[after] == c) || s[j-1] == c) {
+			continue
+		}
+		if c == '_' && after < len(s) && isWordByte(s[after]) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// link renders [text](url) and ![alt](url) starting at s[i]. Images are rendered as links.
+func (r *renderer) link(s string, i int) int {
+	open := i
+	if s[i] == '!' {
+		open++
+	}
+	closeText := matchBracket(s, open, '[', ']')
+	if closeText < 0 || closeText+1 >= len(s) || s[closeText+1] != '(' {
+		r.sb.WriteString(html.EscapeString(s[i : open+1]))
+		return open + 1
+	}
+	closeDest := matchBracket(s, closeText+1, '(', ')')
+	if closeDest < 0 {
+		r.sb.WriteString(html.EscapeString(s[i : open+1]))
+		return open + 1
+	}
+
+	text := s[open+1 : closeText]
+	dest := strings.TrimSpace(s[closeText+2 : closeDest])
+	if fields := strings.Fields(dest); len(fields) > 0 {
+		// drop an optional title: [text](url "title")
+		dest = strings.Trim(fields[0], "<>")
+	}
+	r.anchor(dest, func() { r.inline(text) })
+	return closeDest + 1
+}
+
+// anchor renders a link to u with text written by text. Links with unsupported schemes are rendered as text only.
+func (r *renderer) anchor(u string, text func()) {
+	if !isSafeURL(u) {
+		text()
+		return
+	}
+	r.sb.WriteString(`<a href="` + html.EscapeString(u) + `">`)
+	text()
+	r.sb.WriteString("</a>")
+}
+
+// mentionToken renders @user@example.com, @channel, @team and @everyone starting at s[i].
+func (r *renderer) mentionToken(s string, i int) int {
+	p := prev(s, i)
+	if r.mention == nil || isWordByte(p) || strings.IndexByte("._%+-@", p) >= 0 {
+		r.sb.WriteByte('@')
+		return i + 1
+	}
+
+	raw, token := emailMention.FindString(s[i+1:]), ""
+	if raw != "" {
+		token = raw
+	} else if raw = wordMention.FindString(s[i+1:]); isKeyword(strings.ToLower(raw)) {
+		token = strings.ToLower(raw)
+	}
+	if token == "" {
+		r.sb.WriteByte('@')
+		return i + 1
+	}
+
+	if out, ok := r.mention(token); ok {
+		r.sb.WriteString(out)
+	} else {
+		r.sb.WriteString(html.EscapeString("@" + raw))
+	}
+	return i + 1 + len(raw)
+}
+
+// matchBracket returns the index of the bracket closing s[i], or -1.
+func matchBracket(s string, i int, open, closing byte) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return j
+			}
+		case '\n':
+			if open == '(' {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+func isSafeURL(u string) bool {
+	low := strings.ToLower(u)
+	return strings.HasPrefix(low, "https://") || strings.HasPrefix(low, "http://") || strings.HasPrefix(low, "mailto:")
+}
+
+// trimURL removes trailing punctuation of a bare URL, keeping closing parentheses that are balanced.
+func trimURL(u string) string {
+	for len(u) > 0 {
+		last := u[len(u)-1]
+		switch {
+		case strings.IndexByte(`.,:;!?'"*_~`, last) >= 0:
+			u = u[:len(u)-1]
+		case last == ')' && strings.Count(u, ")") > strings.Count(u, "("):
+			u = u[:len(u)-1]
+		default:
+			return u
+		}
+	}
+	return u
+}
+
+func prev(s string, i int) byte {
+	if i == 0 {
+		return ' '
+	}
+	return s[i-1]
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
@@ -0,0 +1,141 @@
+// Package markdown renders Markdown as HTML that can be sent in Microsoft Teams messages.
+//
+// The supported syntax is a practical subset of CommonMark and GitHub Flavored Markdown:
+//   - ATX headings (# Title), paragraphs and thematic breaks (---),
+//   - emphasis (*italic*, **bold**, ~~strikethrough~~), inline code and fenced code blocks,
+//   - block quotes, nested ordered and unordered lists and pipe tables,
+//   - links ([text](url)), autolinks (<https://...>) and bare http(s) URLs.
+//
+// Raw HTML is escaped and only http, https and mailto links are rendered. Images are rendered as links,
+// since Teams only displays images uploaded as hosted contents. As in the Teams compose box, line breaks
+// inside paragraphs are kept.
+//
+// Mentions are written as @user@example.com, @channel, @team or @everyone. The channels and chats services
+// resolve them and number the <at> tags:
+//
+//	body, err := client.Channels.NewMarkdownMessageBody(ctx, "Team", "General", "**Deploy** finished, @jane@example.com please verify")
+//	...
+//	msg, err := client.Channels.SendMessage(ctx, "Team", "General", *body)
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// Mention keywords recognized after '@' besides email addresses.
+const (
+	MentionChannel  = "channel"
+	MentionTeam     = "team"
+	MentionEveryone = "everyone"
+)
+
+var mentionKeywords = []string{MentionChannel, MentionTeam, MentionEveryone}
+
+// ToHTML renders src as HTML. Mention tokens are rendered as plain text.
+func ToHTML(src string) string {
+	r := &renderer{}
+	r.render(src)
+	return r.sb.String()
+}
+
+// MentionTokens returns the distinct mention tokens of src without the leading '@', in order of their first
+// appearance. Email addresses are always returned; keyword mentions (MentionChannel, MentionTeam,
+// MentionEveryone) only when listed in keywords, lower-cased. Tokens in code spans and code blocks are ignored.
+func MentionTokens(src string, keywords ...string) []string {
+	var out []string
+	r := &renderer{mention: func(token string) (string, bool) {
+		if isKeyword(token) && !slices.Contains(keywords, token) {
+			return "", false
+		}
+		if !slices.Contains(out, token) {
+			out = append(out, token)
+		}
+		return "", false
+	}}
+	r.render(src)
+	return out
+}
+
+// NewMessageBody renders src as an HTML message body. Every occurrence of a token with an entry in mentions
+// (keyed as returned by MentionTokens) becomes an <at> tag with its own Mention, numbered from 0 in order of
+// appearance; Kind, TargetID and Text are taken from the entry. Other tokens are rendered as plain text.
+func NewMessageBody(src string, mentions map[string]models.Mention) models.MessageBody {
+	var out []models.Mention
+	r := &renderer{mention: func(token string) (string, bool) {
+		m, ok := mentions[token]
+		if !ok {
+			return "", false
+		}
+		m.AtID = int32(len(out))
+		out = append(out, m)
+		return fmt.Sprintf(`<at id="%d">%s</at>`, m.AtID, html.EscapeString(m.Text)), true
+	}}
+	r.render(src)
+	return models.MessageBody{
+		Content:     r.sb.String(),
+		ContentType: models.MessageContentTypeHTML,
+		Mentions:    out,
+	}
+}
+
+func isKeyword(token string) bool {
+	return slices.Contains(mentionKeywords, token)
+}
+
+// renderer writes HTML of Markdown blocks and inlines to sb.
+type renderer struct {
+	sb strings.Builder
+	// mention returns the HTML of a mention token, or false to render the token as text.
+	mention func(token string) (string, bool)
+}
+
+func (r *renderer) render(src string) {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+	lines := strings.Split(src, "\n")
+	// tabs are expanded in indentation only, code blocks keep them
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		switch {
+		case fence == "":
+			fence = fenceOf(trimmed)
+			lines[i] = expandTabs(line)
+		case strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]+" \t") == "":
+			fence = ""
+			lines[i] = expandTabs(line)
+		}
+	}
+	r.blocks(lines, false)
+}
+
+// expandTabs replaces tabs in the indentation of line with spaces, up to the next multiple of 4.
+func expandTabs(line string) string {
+	if !strings.Contains(leadingSpace(line), "\t") {
+		return line
+	}
+	var sb strings.Builder
+	col := 0
+	i := 0
+	for ; i < len(line) && (line[i] == ' ' || line[i] == '\t'); i++ {
+		if line[i] == ' ' {
+			sb.WriteByte(' ')
+			col++
+			continue
+		}
+		n := 4 - col%4
+		sb.WriteString(strings.Repeat(" ", n))
+		col += n
+	}
+	sb.WriteString(line[i:])
+	return sb.String()
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
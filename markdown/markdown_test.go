@@ -0,0 +1,169 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "empty",
+			src:  "",
+			want: "",
+		},
+		{
+			name: "headings and paragraphs",
+			src:  "# Release\n\n## Notes ##\nFirst line\nsecond line",
+			want: "<h1>Release</h1><h2>Notes</h2><p>First line<br>second line</p>",
+		},
+		{
+			name: "hashtag is not a heading",
+			src:  "#release",
+			want: "<p>#release</p>",
+		},
+		{
+			name: "emphasis",
+			src:  "**bold**, *italic*, _em_, __strong__, ~~gone~~ and a *b **c** d*",
+			want: "<p><strong>bold</strong>, <em>italic</em>, <em>em</em>, <strong>strong</strong>, <s>gone</s> and a <em>b <strong>c</strong> d</em></p>",
+		},
+		{
+			name: "intraword underscores and lone delimiters",
+			src:  "snake_case_name, 2 * 3 and **open",
+			want: "<p>snake_case_name, 2 * 3 and **open</p>",
+		},
+		{
+			name: "raw html is escaped",
+			src:  `<script>alert("x")</script> & more`,
+			want: "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; more</p>",
+		},
+		{
+			name: "backslash escapes",
+			src:  `\*not em\* and \# hash`,
+			want: "<p>*not em* and # hash</p>",
+		},
+		{
+			name: "code span",
+			src:  "run `go test ./... <x>` or ``a ` b``",
+			want: "<p>run <code>go test ./... &lt;x&gt;</code> or <code>a ` b</code></p>",
+		},
+		{
+			name: "fenced code block",
+			src:  "```go\nif a < b {\n\t**x**\n}\n```\nafter",
+			want: "<pre><code>if a &lt; b {\n\t**x**\n}</code></pre><p>after</p>",
+		},
+		{
+			name: "unterminated code block runs to the end",
+			src:  "~~~\ncode",
+			want: "<pre><code>code</code></pre>",
+		},
+		{
+			name: "nested tight list",
+			src:  "- a\n- b\n  - c\n  - d\n- e",
+			want: "<ul><li>a</li><li>b<ul><li>c</li><li>d</li></ul></li><li>e</li></ul>",
+		},
+		{
+			name: "ordered list with start",
+			src:  "3. three\n4. four",
+			want: `<ol start="3"><li>three</li><li>four</li></ol>`,
+		},
+		{
+			name: "loose list",
+			src:  "1. one\n\n2. two",
+			want: "<ol><li><p>one</p></li><li><p>two</p></li></ol>",
+		},
+		{
+			name: "list interrupts paragraph",
+			src:  "Todo:\n- a\n- b",
+			want: "<p>Todo:</p><ul><li>a</li><li>b</li></ul>",
+		},
+		{
+			name: "number does not interrupt paragraph",
+			src:  "We shipped in\n2025. It was late.",
+			want: "<p>We shipped in<br>2025. It was late.</p>",
+		},
+		{
+			name: "block quote",
+			src:  "> quoted\n> **text**\n\nreply",
+			want: "<blockquote><p>quoted<br><strong>text</strong></p></blockquote><p>reply</p>",
+		},
+		{
+			name: "thematic break",
+			src:  "above\n\n---\n\nbelow",
+			want: "<p>above</p><hr><p>below</p>",
+		},
+		{
+			name: "table",
+			src:  "| Service | Status |\n|:--|:-:|\n| api | **up** |\n| db \\| cache |",
+			want: `<table><thead><tr><th style="text-align:left">Service</th><th style="text-align:center">Status</th></tr></thead>` +
+				`<tbody><tr><td style="text-align:left">api</td><td style="text-align:center"><strong>up</strong></td></tr>` +
+				`<tr><td style="text-align:left">db | cache</td><td style="text-align:center"></td></tr></tbody></table>`,
+		},
+		{
+			name: "links",
+			src:  `[docs](https://example.com/a_(b) "Docs"), <https://example.com>, <mailto:a@b.com> and https://example.com/x.`,
+			want: `<p><a href="https://example.com/a_(b)">docs</a>, <a href="https://example.com">https://example.com</a>, ` +
+				`<a href="mailto:a@b.com">a@b.com</a> and <a href="https://example.com/x">https://example.com/x</a>.</p>`,
+		},
+		{
+			name: "unsafe links and images",
+			src:  "[click](javascript:alert(1)) ![logo](https://example.com/logo.png)",
+			want: `<p>click <a href="https://example.com/logo.png">logo</a></p>`,
+		},
+		{
+			name: "mentions are plain text",
+			src:  "@jane@example.com @channel",
+			want: "<p>@jane@example.com @channel</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, ToHTML(tt.src))
+		})
+	}
+}
+
+func TestMentionTokens(t *testing.T) {
+	t.Parallel()
+
+	src := "@jane@example.com, @Channel and @team please check. Mail ops@example.com, not `@bob@example.com`.\n" +
+		"```\n@alice@example.com\n```\n@everyone @jane@example.com @channels"
+
+	require.Equal(t, []string{"jane@example.com", "channel", "team"}, MentionTokens(src, MentionChannel, MentionTeam))
+	require.Equal(t, []string{"jane@example.com", "everyone"}, MentionTokens(src, MentionEveryone))
+	require.Empty(t, MentionTokens("no mentions here"))
+}
+
+func TestNewMessageBody(t *testing.T) {
+	t.Parallel()
+
+	jane := models.Mention{Kind: models.MentionUser, TargetID: "user-1", Text: "Jane <Ops>"}
+	channel := models.Mention{Kind: models.MentionChannel, TargetID: "chan-1", Text: "General"}
+
+	body := NewMessageBody("**Deploy** done, @jane@example.com and @channel.\n- @jane@example.com again, @team stays text", map[string]models.Mention{
+		"jane@example.com": jane,
+		"channel":          channel,
+	})
+
+	require.Equal(t, models.MessageContentTypeHTML, body.ContentType)
+	require.Equal(t,
+		`<p><strong>Deploy</strong> done, <at id="0">Jane &lt;Ops&gt;</at> and <at id="1">General</at>.</p>`+
+			`<ul><li><at id="2">Jane &lt;Ops&gt;</at> again, @team stays text</li></ul>`,
+		body.Content,
+	)
+	require.Equal(t, []models.Mention{
+		{Kind: models.MentionUser, TargetID: "user-1", Text: "Jane <Ops>", AtID: 0},
+		{Kind: models.MentionChannel, TargetID: "chan-1", Text: "General", AtID: 1},
+		{Kind: models.MentionUser, TargetID: "user-1", Text: "Jane <Ops>", AtID: 2},
+	}, body.Mentions)
+}
@@ -17,20 +17,99 @@ const (
 	MessageContentTypeHTML MessageContentType = "html"
 )
 
+// MessageType represents the type of a Microsoft Teams message.
+type MessageType string
+
+const (
+	// MessageTypeMessage represents a regular message.
+	MessageTypeMessage MessageType = "message"
+	// MessageTypeChatEvent represents a chat event.
+	MessageTypeChatEvent MessageType = "chatEvent"
+	// MessageTypeTyping represents a typing indicator.
+	MessageTypeTyping MessageType = "typing"
+	// MessageTypeSystemEvent represents a system event, e.g. a member added to a chat.
+	MessageTypeSystemEvent MessageType = "systemEventMessage"
+)
+
+// MessageImportance represents the importance of a Microsoft Teams message.
+type MessageImportance string
+
+const (
+	// MessageImportanceNormal represents a message of normal importance.
+	MessageImportanceNormal MessageImportance = "normal"
+	// MessageImportanceHigh represents an important message.
+	MessageImportanceHigh MessageImportance = "high"
+	// MessageImportanceUrgent represents an urgent message; Teams notifies recipients repeatedly.
+	MessageImportanceUrgent MessageImportance = "urgent"
+)
+
 // Message represents a Microsoft Teams chat message. It can be used in both chats and channels.
 type Message struct {
-	ID              string
-	Content         string
-	ContentType     MessageContentType
+	ID                   string
+	ReplyToID            string
+	MessageType          MessageType
+	Subject              string
+	Content              string
+	ContentType          MessageContentType
+	Importance           MessageImportance
+	CreatedDateTime      time.Time
+	LastModifiedDateTime *time.Time
+	LastEditedDateTime   *time.Time
+	DeletedDateTime      *time.Time
+	From                 *MessageFrom
+	ReplyCount           int
+	Mentions             []Mention
+	Reactions            []Reaction
+	Attachments          []Attachment
+	WebURL               string
+	// ChatID is set for chat messages, TeamID and ChannelID for channel messages.
+	ChatID    string
+	TeamID    string
+	ChannelID string
+}
+
+// Reaction represents a reaction of a user to a message in Microsoft Teams.
+type Reaction struct {
+	// ReactionType is the reaction emoji, e.g. "👍". Legacy reaction names reported by Graph
+	// (like, heart, laugh, surprised, sad, angry) are converted to their emoji.
+	ReactionType    string
+	DisplayName     string
+	User            *MessageFrom
 	CreatedDateTime time.Time
-	From            *MessageFrom
-	ReplyCount      int
 }
 
 // MessageFrom represents the sender of a message in Microsoft Teams.
+// Messages sent by apps and bots carry ApplicationID instead of UserID.
 type MessageFrom struct {
-	UserID      string
-	DisplayName string
+	UserID        string
+	ApplicationID string
+	DisplayName   string
+}
+
+// AttachmentContentTypeReference is the content type of attachments referencing files in OneDrive or SharePoint.
+const AttachmentContentTypeReference = "reference"
+
+// Attachment represents an attachment of a message in Microsoft Teams, e.g. a file reference or a card.
+type Attachment struct {
+	ID string
+	// ContentType is the MIME type of the attachment, or e.g. "reference" for files
+	// and "application/vnd.microsoft.card.adaptive" for Adaptive Cards.
+	ContentType  string
+	ContentURL   string
+	Content      string
+	Name         string
+	ThumbnailURL string
+	TeamsAppID   string
+}
+
+// HostedContent represents content hosted in a message in Microsoft Teams, such as an inline image.
+type HostedContent struct {
+	// ID identifies the content of a received message. When sending, it is a temporary ID
+	// referenced from the HTML body as <img src="../hostedContents/{ID}/$value">.
+	ID          string
+	ContentType string
+	// Content is the raw content. It is required when sending and not set when listing.
+	Content []byte
 }
 
 // MessageBody represents the body of a message in Microsoft Teams.
@@ -38,6 +117,16 @@ type MessageBody struct {
 	Content     string
 	ContentType MessageContentType
 	Mentions    []Mention
+	// Subject is the headline of a channel post; replies and chat messages cannot have one.
+	Subject string
+	// Importance is normal when empty. Urgent messages notify chat members repeatedly.
+	Importance MessageImportance
+	// Attachments are sent with the message, e.g. Adaptive Cards built with package adaptivecards.
+	// Each attachment is referenced from Content with an <attachment id="..."> tag, appended when missing.
+	Attachments []Attachment
+	// HostedContents are uploaded with the message, e.g. inline images. Each is referenced from Content
+	// with an <img src="../hostedContents/{ID}/$value"> tag, appended when missing.
+	HostedContents []HostedContent
 }
 
 // ListMessagesOptions contains options for listing messages.
@@ -51,3 +140,17 @@ type MessageCollection struct {
 	Messages []*Message
 	NextLink *string
 }
+
+// MessageChanges contains messages created, edited or deleted in a conversation since its previous sync.
+type MessageChanges struct {
+	// New contains messages created since the previous sync, including those edited in between.
+	New []*Message
+	// Edited contains messages created before the previous sync and changed since,
+	// e.g. edited or reacted to.
+	Edited []*Message
+	// Deleted contains messages deleted since the previous sync.
+	Deleted []*Message
+	// Reset is set when the sync started over (first sync or expired sync state);
+	// New then contains all messages of the conversation.
+	Reset bool
+}
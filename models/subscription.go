@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// ChangeType is a type of change reported by change notifications.
+type ChangeType string
+
+const (
+	// ChangeTypeCreated reports created resources.
+	ChangeTypeCreated ChangeType = "created"
+	// ChangeTypeUpdated reports updated resources.
+	ChangeTypeUpdated ChangeType = "updated"
+	// ChangeTypeDeleted reports deleted resources.
+	ChangeTypeDeleted ChangeType = "deleted"
+)
+
+// Subscription represents a change-notification subscription on a Microsoft Graph resource.
+type Subscription struct {
+	ID                       string
+	Resource                 string
+	ChangeTypes              []ChangeType
+	NotificationURL          string
+	LifecycleNotificationURL string
+	// ClientState is the secret sent with every notification; Graph does not return it when listing.
+	ClientState         string
+	IncludeResourceData bool
+	ExpirationDateTime  time.Time
+}
+
+// SubscriptionOptions describes how notifications of a subscription are delivered.
+type SubscriptionOptions struct {
+	// NotificationURL receives change notifications. Required; must be HTTPS.
+	NotificationURL string
+
+	// LifecycleNotificationURL receives lifecycle notifications (e.g. reauthorizationRequired, missed).
+	LifecycleNotificationURL string
+
+	// ClientState is a secret sent with every notification, used to verify its origin.
+	ClientState string
+
+	// ChangeTypes selects reported changes; all of created, updated and deleted when empty.
+	ChangeTypes []ChangeType
+
+	// IncludeResourceData requests encrypted message payloads in notifications.
+	// It requires EncryptionCertificate and EncryptionCertificateID.
+	IncludeResourceData bool
+
+	// EncryptionCertificate is the base64-encoded public certificate used to encrypt resource data.
+	EncryptionCertificate string
+
+	// EncryptionCertificateID identifies the certificate, so the receiver can pick the matching private key.
+	EncryptionCertificateID string
+}
@@ -0,0 +1,248 @@
+// Package msgtext converts the HTML content of received Microsoft Teams messages to plain text or Markdown.
+//
+// Teams markup is made readable: mentions become @Display Name (names split over several <at> tags are
+// joined), emoji become their characters, quoted replies become "> Sender: preview" quotes, files and cards
+// referenced with <attachment> tags are described by name, and system event markup is dropped:
+//
+//	msg, err := client.Chats.GetMessage(ctx, chatRef, messageID)
+//	...
+//	log.Println(msgtext.Convert(msg, msgtext.PlainText))
+package msgtext
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// Format is the output format of a conversion.
+type Format int
+
+const (
+	// PlainText drops all formatting.
+	PlainText Format = iota
+	// Markdown keeps formatting as CommonMark with GitHub Flavored Markdown tables and strikethrough.
+	Markdown
+)
+
+const (
+	// messageReferenceContentType is the content type of attachments quoting the message replied to.
+	messageReferenceContentType = "messageReference"
+	cardContentTypePrefix       = "application/vnd.microsoft.card."
+	// Structural spaces and newlines, e.g. list indentation and lines of code blocks, are written as
+	// placeholders so that whitespace normalization keeps them; they are replaced at the end.
+	keptSpace   = "\x01"
+	keptNewline = "\x02"
+	keptTab     = "\x03"
+)
+
+var (
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+	newlineRuns = regexp.MustCompile(`\n{2,}`)
+	spaceRuns   = regexp.MustCompile(`[\s\x{00a0}]+`)
+)
+
+// Convert converts the content of msg to format. Attachments that are not referenced from the content,
+// such as the quote of a replied message, are appended.
+func Convert(msg *models.Message, format Format) string {
+	if msg == nil {
+		return ""
+	}
+	c := newConverter(format, msg.Mentions, msg.Attachments)
+
+	var out string
+	if msg.ContentType == models.MessageContentTypeText {
+		out = c.escape(msg.Content)
+	} else {
+		out = c.convert(msg.Content)
+	}
+
+	var extra []string
+	for _, att := range msg.Attachments {
+		if _, ok := c.used[att.ID]; !ok {
+			extra = append(extra, c.attachment(att))
+		}
+	}
+	if len(extra) > 0 {
+		out = strings.Join(append([]string{out}, extra...), c.blockSep())
+	}
+	return finish(out)
+}
+
+// ConvertHTML converts Teams HTML content to format. Without the message, mentions keep the text
+// of their <at> tags and <attachment> tags are dropped.
+func ConvertHTML(content string, format Format) string {
+	return finish(newConverter(format, nil, nil).convert(content))
+}
+
+type converter struct {
+	format      Format
+	mentions    map[int32]models.Mention
+	attachments map[string]models.Attachment
+	// used holds the IDs of attachments referenced from the content.
+	used map[string]struct{}
+}
+
+func newConverter(format Format, mentions []models.Mention, attachments []models.Attachment) *converter {
+	c := &converter{
+		format:      format,
+		mentions:    make(map[int32]models.Mention, len(mentions)),
+		attachments: make(map[string]models.Attachment, len(attachments)),
+		used:        map[string]struct{}{},
+	}
+	for _, m := range mentions {
+		c.mentions[m.AtID] = m
+	}
+	for _, att := range attachments {
+		c.attachments[att.ID] = att
+	}
+	return c
+}
+
+func (c *converter) convert(content string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return c.escape(content)
+	}
+	parts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		parts = append(parts, c.node(n))
+	}
+	return c.normalize(strings.Join(parts, ""))
+}
+
+// blockSep separates blocks: paragraphs need a blank line in Markdown.
+func (c *converter) blockSep() string {
+	if c.format == Markdown {
+		return "\n\n"
+	}
+	return "\n"
+}
+
+func (c *converter) block(s string) string {
+	return c.blockSep() + s + c.blockSep()
+}
+
+// normalize trims spaces around lines and collapses blank lines.
+func (c *converter) normalize(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Trim(line, " \t")
+	}
+	s = strings.Join(lines, "\n")
+	if c.format == Markdown {
+		s = blankLines.ReplaceAllString(s, "\n\n")
+	} else {
+		s = newlineRuns.ReplaceAllString(s, "\n")
+	}
+	return strings.Trim(s, "\n")
+}
+
+// finish restores structural whitespace.
+func finish(s string) string {
+	s = strings.ReplaceAll(s, keptSpace, " ")
+	s = strings.ReplaceAll(s, keptNewline, "\n")
+	s = strings.ReplaceAll(s, keptTab, "\t")
+	return strings.TrimSpace(s)
+}
+
+// prefixLines prefixes every line of s with first for the first line and rest for the others.
+func prefixLines(s, first, rest string) string {
+	var sb strings.Builder
+	sb.WriteString(first)
+	for _, r := range s {
+		sb.WriteRune(r)
+		if r == '\n' || string(r) == keptNewline {
+			sb.WriteString(rest)
+		}
+	}
+	return sb.String()
+}
+
+func (c *converter) attachment(att models.Attachment) string {
+	c.used[att.ID] = struct{}{}
+	switch {
+	case att.ContentType == messageReferenceContentType:
+		return c.quote(att.Content)
+	case att.ContentType == models.AttachmentContentTypeReference:
+		if c.format == Markdown && att.ContentURL != "" {
+			return "[" + c.escape(att.Name) + "](" + escapeURL(att.ContentURL) + ")"
+		}
+		return "[File: " + c.escape(att.Name) + "]"
+	case strings.HasPrefix(att.ContentType, cardContentTypePrefix):
+		return "[Card]"
+	case att.Name != "":
+		return "[Attachment: " + c.escape(att.Name) + "]"
+	default:
+		return "[Attachment]"
+	}
+}
+
+// messageReference is the content of a messageReference attachment.
+type messageReference struct {
+	MessagePreview string `json:"messagePreview"`
+	MessageSender  struct {
+		User        *struct{ DisplayName string } `json:"user"`
+		Application *struct{ DisplayName string } `json:"application"`
+	} `json:"messageSender"`
+}
+
+// quote renders the message quoted in a reply.
+func (c *converter) quote(content string) string {
+	var ref messageReference
+	if err := json.Unmarshal([]byte(content), &ref); err != nil {
+		return ""
+	}
+	sender := ""
+	switch {
+	case ref.MessageSender.User != nil:
+		sender = ref.MessageSender.User.DisplayName
+	case ref.MessageSender.Application != nil:
+		sender = ref.MessageSender.Application.DisplayName
+	}
+	return c.quoteOf(sender, c.escape(ref.MessagePreview))
+}
+
+func (c *converter) quoteOf(sender, preview string) string {
+	text := preview
+	if sender != "" {
+		text = c.escape(sender) + ": " + preview
+	}
+	return prefixLines(text, "> ", "> ")
+}
+
+// escape escapes characters of text that Markdown would interpret.
+func (c *converter) escape(text string) string {
+	if c.format != Markdown {
+		return text
+	}
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case strings.IndexByte("\\`*[]<", ch) >= 0:
+			sb.WriteByte('\\')
+		case ch == '_' && (i == 0 || !isWordByte(text[i-1]) || i+1 == len(text) || !isWordByte(text[i+1])):
+			sb.WriteByte('\\')
+		case ch == '>' && (i == 0 || text[i-1] == '\n'):
+			sb.WriteByte('\\')
+		case strings.IndexByte("#-+", ch) >= 0 && (i == 0 || text[i-1] == '\n') && i+1 < len(text) && text[i+1] == ' ':
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
+
+func escapeURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
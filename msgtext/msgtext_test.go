@@ -0,0 +1,156 @@
+package msgtext
+
+import (
+	"testing"
+
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		wantText string
+		wantMD   string
+	}{
+		{
+			name:     "paragraphs and formatting",
+			content:  "<p>Deploy <b>finished</b>&nbsp;in <i>3 min</i></p><p>Next <s>today</s> tomorrow</p>",
+			wantText: "Deploy finished in 3 min\nNext today tomorrow",
+			wantMD:   "Deploy **finished** in *3 min*\n\nNext ~~today~~ tomorrow",
+		},
+		{
+			name:     "line breaks and entities",
+			content:  "<div>a &lt; b &amp;&amp; c<br>second</div>",
+			wantText: "a < b && c\nsecond",
+			wantMD:   "a \\< b && c\nsecond",
+		},
+		{
+			name:     "markdown characters are escaped",
+			content:  "<p>*not bold* snake_case _x_ [x]</p>",
+			wantText: "*not bold* snake_case _x_ [x]",
+			wantMD:   `\*not bold\* snake_case \_x\_ \[x\]`,
+		},
+		{
+			name:     "emoji",
+			content:  `<p>thanks <emoji id="smile" alt="😄" title="Smile"></emoji> <img alt="👍" itemtype="http://schema.skype.com/Emoji" src="https://example.com/like.png"></p>`,
+			wantText: "thanks 😄 👍",
+			wantMD:   "thanks 😄 👍",
+		},
+		{
+			name:     "mentions keep their text",
+			content:  `<p><at id="0">Jane</at> hi</p>`,
+			wantText: "@Jane hi",
+			wantMD:   "@Jane hi",
+		},
+		{
+			name:     "system event markup is dropped",
+			content:  "<systemEventMessage/>",
+			wantText: "",
+			wantMD:   "",
+		},
+		{
+			name:     "unknown attachments are dropped",
+			content:  `<attachment id="1"></attachment><p>text</p>`,
+			wantText: "text",
+			wantMD:   "text",
+		},
+		{
+			name:     "links and images",
+			content:  `<p><a href="https://example.com/docs">docs</a>, <a href="https://example.com">https://example.com</a> <img src="https://graph.microsoft.com/hostedContents/1/$value" alt="image"></p>`,
+			wantText: "docs (https://example.com/docs), https://example.com [Image]",
+			wantMD:   "[docs](https://example.com/docs), [https://example.com](https://example.com) ![image](https://graph.microsoft.com/hostedContents/1/$value)",
+		},
+		{
+			name:     "headings and rules",
+			content:  "<h2>Status</h2><p>ok</p><hr><p>end</p>",
+			wantText: "Status\nok\nend",
+			wantMD:   "## Status\n\nok\n\n---\n\nend",
+		},
+		{
+			name:     "nested lists",
+			content:  `<ul><li>one<ul><li>nested</li></ul></li><li>two</li></ul><ol start="3"><li>three</li><li>four</li></ol>`,
+			wantText: "- one\n  - nested\n- two\n3. three\n4. four",
+			wantMD:   "- one\n  - nested\n- two\n\n3. three\n4. four",
+		},
+		{
+			name:     "code",
+			content:  "<p>run <code>go test</code></p><pre><code class=\"language-go\">if x {\n\treturn\n}\n\n// done</code></pre>",
+			wantText: "run go test\nif x {\n\treturn\n}\n\n// done",
+			wantMD:   "run `go test`\n\n```go\nif x {\n\treturn\n}\n\n// done\n```",
+		},
+		{
+			name:     "tables",
+			content:  "<table><tbody><tr><td>Service</td><td>Status</td></tr><tr><td>api</td><td>up | ok</td></tr></tbody></table>",
+			wantText: "Service | Status\napi | up | ok",
+			wantMD:   "| Service | Status |\n| --- | --- |\n| api | up \\| ok |",
+		},
+		{
+			name:     "block quotes",
+			content:  "<blockquote><p>quoted</p><p>text</p></blockquote><p>reply</p>",
+			wantText: "> quoted\n> text\nreply",
+			wantMD:   "> quoted\n>\n> text\n\nreply",
+		},
+		{
+			name:     "quoted reply markup",
+			content:  `<blockquote itemscope="" itemtype="http://schema.skype.com/Reply" itemid="17"><strong itemprop="mri" itemid="8:orgid:1">Jane Doe</strong><span itemprop="time" itemid="17"></span><p itemprop="preview">is the build green?</p></blockquote><p>yes</p>`,
+			wantText: "> Jane Doe: is the build green?\nyes",
+			wantMD:   "> Jane Doe: is the build green?\n\nyes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.wantText, ConvertHTML(tt.content, PlainText))
+			require.Equal(t, tt.wantMD, ConvertHTML(tt.content, Markdown))
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	msg := &models.Message{
+		ContentType: models.MessageContentTypeHTML,
+		Content: `<attachment id="1700000000000"></attachment>` +
+			`<p>Thanks <at id="0">Jane</at>&nbsp;<at id="1">Doe</at> and <at id="2">Bob</at>, report: <attachment id="file-1"></attachment></p>`,
+		Mentions: []models.Mention{
+			{Kind: models.MentionUser, AtID: 0, Text: "Jane", TargetID: "user-1"},
+			{Kind: models.MentionUser, AtID: 1, Text: "Doe", TargetID: "user-1"},
+			{Kind: models.MentionUser, AtID: 2, Text: "Bob", TargetID: "user-2"},
+		},
+		Attachments: []models.Attachment{
+			{
+				ID:          "1700000000000",
+				ContentType: "messageReference",
+				Content:     `{"messageId":"1700000000000","messagePreview":"is *prod* down?","messageSender":{"application":null,"device":null,"user":{"id":"user-2","displayName":"Bob"}}}`,
+			},
+			{ID: "file-1", ContentType: models.AttachmentContentTypeReference, Name: "report.pdf", ContentURL: "https://contoso.sharepoint.com/report.pdf"},
+			{ID: "card-1", ContentType: "application/vnd.microsoft.card.adaptive", Content: "{}"},
+		},
+	}
+
+	require.Equal(t,
+		"> Bob: is *prod* down?\nThanks @Jane Doe and @Bob, report:\n[File: report.pdf]\n[Card]",
+		Convert(msg, PlainText),
+	)
+	require.Equal(t,
+		"> Bob: is \\*prod\\* down?\n\nThanks @Jane Doe and @Bob, report:\n\n[report.pdf](https://contoso.sharepoint.com/report.pdf)\n\n[Card]",
+		Convert(msg, Markdown),
+	)
+}
+
+func TestConvert_TextAndEmpty(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, Convert(nil, PlainText))
+	require.Empty(t, Convert(&models.Message{ContentType: models.MessageContentTypeHTML}, Markdown))
+
+	msg := &models.Message{ContentType: models.MessageContentTypeText, Content: "a <b> *c*"}
+	require.Equal(t, "a <b> *c*", Convert(msg, PlainText))
+	require.Equal(t, `a \<b> \*c\*`, Convert(msg, Markdown))
+}
@@ -0,0 +1,328 @@
+package msgtext
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	emojiItemType = "schema.skype.com/Emoji"
+	replyItemType = "schema.skype.com/Reply"
+)
+
+// node converts n and its children.
+func (c *converter) node(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return c.escape(spaceRuns.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.Data {
+	case "script", "style", "systemeventmessage":
+		return ""
+	case "br":
+		return "\n"
+	case "p", "div":
+		return c.block(c.children(n))
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := c.normalize(c.children(n))
+		if c.format == Markdown {
+			level, _ := strconv.Atoi(n.Data[1:])
+			text = strings.Repeat("#", level) + " " + strings.ReplaceAll(text, "\n", " ")
+		}
+		return c.block(text)
+	case "strong", "b":
+		return c.wrap(c.children(n), "**")
+	case "em", "i":
+		return c.wrap(c.children(n), "*")
+	case "s", "strike", "del":
+		return c.wrap(c.children(n), "~~")
+	case "code":
+		return c.inlineCode(textContent(n))
+	case "pre", "codeblock":
+		return c.codeBlock(n)
+	case "blockquote":
+		return c.blockQuote(n)
+	case "ul", "ol":
+		return c.list(n)
+	case "table":
+		return c.table(n)
+	case "hr":
+		if c.format == Markdown {
+			return c.block("---")
+		}
+		return c.block("")
+	case "a":
+		return c.link(n)
+	case "img":
+		return c.image(n)
+	case "emoji":
+		return attr(n, "alt")
+	case "at":
+		name, _ := c.mention(n)
+		return "@" + name
+	case "attachment":
+		att, ok := c.attachments[attr(n, "id")]
+		if !ok {
+			return ""
+		}
+		return c.block(c.attachment(att))
+	default:
+		return c.children(n)
+	}
+}
+
+// children converts the children of n. Adjacent mentions of the same target, which Teams writes
+// for names with several words, are joined into one.
+func (c *converter) children(n *html.Node) string {
+	var parts []string
+	lastAt, lastTarget := -1, ""
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.Type == html.ElementNode && ch.Data == "at" {
+			name, target := c.mention(ch)
+			if lastAt >= 0 && target != "" && target == lastTarget && strings.TrimSpace(strings.Join(parts[lastAt+1:], "")) == "" {
+				parts = parts[:lastAt+1]
+				parts[lastAt] += " " + name
+				continue
+			}
+			parts = append(parts, "@"+name)
+			lastAt, lastTarget = len(parts)-1, target
+			continue
+		}
+		s := c.node(ch)
+		parts = append(parts, s)
+		if strings.TrimSpace(s) != "" {
+			lastAt = -1
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// mention returns the display name and the target of the mention n.
+func (c *converter) mention(n *html.Node) (name, target string) {
+	text := strings.TrimSpace(spaceRuns.ReplaceAllString(textContent(n), " "))
+	id, err := strconv.ParseInt(attr(n, "id"), 10, 32)
+	if m, ok := c.mentions[int32(id)]; ok && err == nil {
+		if m.Text != "" {
+			text = m.Text
+		}
+		return c.escape(text), m.TargetID
+	}
+	return c.escape(text), ""
+}
+
+// wrap wraps text in a Markdown delimiter, keeping surrounding spaces outside of it.
+func (c *converter) wrap(text, delim string) string {
+	core := strings.TrimSpace(text)
+	if c.format != Markdown || core == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, core)]
+	trail := text[len(lead)+len(core):]
+	return lead + delim + core + delim + trail
+}
+
+func (c *converter) inlineCode(code string) string {
+	code = spaceRuns.ReplaceAllString(code, " ")
+	if c.format != Markdown {
+		return code
+	}
+	if strings.Contains(code, "`") {
+		return "`` " + code + " ``"
+	}
+	return "`" + code + "`"
+}
+
+func (c *converter) codeBlock(n *html.Node) string {
+	code := strings.Trim(textContent(n), "\n")
+	code = strings.NewReplacer("\n", keptNewline, " ", keptSpace, "\t", keptTab).Replace(code)
+	if c.format != Markdown {
+		return c.block(code)
+	}
+	lang := strings.ToLower(attr(n, "class"))
+	if lang == "" && n.FirstChild != nil && n.FirstChild.Type == html.ElementNode {
+		lang = strings.TrimPrefix(attr(n.FirstChild, "class"), "language-")
+	}
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if strings.ContainsAny(lang, " `") {
+		lang = ""
+	}
+	return c.block(fence + lang + "\n" + code + "\n" + fence)
+}
+
+func (c *converter) blockQuote(n *html.Node) string {
+	if strings.Contains(attr(n, "itemtype"), replyItemType) {
+		sender, preview := "", ""
+		if mri := findByItemProp(n, "mri"); mri != nil {
+			sender = strings.TrimSpace(textContent(mri))
+		}
+		if p := findByItemProp(n, "preview"); p != nil {
+			preview = c.normalize(c.children(p))
+		}
+		return c.block(c.quoteOf(sender, preview))
+	}
+	return c.block(prefixLines(c.normalize(c.children(n)), "> ", "> "))
+}
+
+func (c *converter) list(n *html.Node) string {
+	ordered := n.Data == "ol"
+	num := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		num = start
+	}
+	var items []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(num) + ". "
+			num++
+		}
+		// items stay tight, nested lists included
+		text := newlineRuns.ReplaceAllString(c.normalize(c.children(li)), "\n")
+		items = append(items, prefixLines(text, marker, strings.Repeat(keptSpace, len(marker))))
+	}
+	return c.block(strings.Join(items, "\n"))
+}
+
+func (c *converter) table(n *html.Node) string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+			if ch.Type != html.ElementNode {
+				continue
+			}
+			switch ch.Data {
+			case "thead", "tbody", "tfoot":
+				walk(ch)
+			case "tr":
+				var row []string
+				for cell := ch.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+						text := strings.ReplaceAll(c.normalize(c.children(cell)), "\n", " ")
+						if c.format == Markdown {
+							text = strings.ReplaceAll(text, "|", `\|`)
+						}
+						row = append(row, text)
+					}
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	if c.format != Markdown {
+		lines := make([]string, 0, len(rows))
+		for _, row := range rows {
+			lines = append(lines, strings.Join(row, " | "))
+		}
+		return c.block(strings.Join(lines, "\n"))
+	}
+
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	if cols == 0 {
+		return ""
+	}
+	line := func(cells []string) string {
+		padded := make([]string, cols)
+		copy(padded, cells)
+		return "| " + strings.Join(padded, " | ") + " |"
+	}
+	lines := []string{line(rows[0]), line(slices.Repeat([]string{"---"}, cols))}
+	for _, row := range rows[1:] {
+		lines = append(lines, line(row))
+	}
+	return c.block(strings.Join(lines, "\n"))
+}
+
+func (c *converter) link(n *html.Node) string {
+	text := c.children(n)
+	href := attr(n, "href")
+	if href == "" {
+		return text
+	}
+	core := strings.TrimSpace(text)
+	if c.format == Markdown {
+		if core == "" {
+			core = c.escape(href)
+		}
+		return "[" + core + "](" + escapeURL(href) + ")"
+	}
+	if core == "" || core == href || "mailto:"+core == href {
+		return href
+	}
+	return text + " (" + href + ")"
+}
+
+func (c *converter) image(n *html.Node) string {
+	alt := attr(n, "alt")
+	if strings.Contains(attr(n, "itemtype"), emojiItemType) {
+		return alt
+	}
+	if c.format == Markdown {
+		return "![" + c.escape(alt) + "](" + escapeURL(attr(n, "src")) + ")"
+	}
+	if alt == "" || alt == "image" {
+		return "[Image]"
+	}
+	return "[Image: " + alt + "]"
+}
+
+// textContent returns the text of n and its descendants, with <br> as newlines.
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			sb.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			sb.WriteByte('\n')
+		}
+		for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+			walk(ch)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func findByItemProp(n *html.Node, prop string) *html.Node {
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.Type == html.ElementNode && attr(ch, "itemprop") == prop {
+			return ch
+		}
+		if found := findByItemProp(ch, prop); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pzsp-teams/lib/internal/adapter"
+	"github.com/pzsp-teams/lib/internal/api"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/search"
+)
+
+type ops struct {
+	entityAPI api.EntityAPI
+}
+
+func NewOps(entityAPI api.EntityAPI) entityOps {
+	return &ops{
+		entityAPI: entityAPI,
+	}
+}
+
+func (o *ops) SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.File], error) {
+	if err := validateOpts(opts); err != nil {
+		return nil, err
+	}
+	resp, requestErr := o.entityAPI.SearchFiles(ctx, opts, searchConfig)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	return mapEntityPage(resp, adapter.MapGraphFile), nil
+}
+
+func (o *ops) SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Person], error) {
+	if err := validateOpts(opts); err != nil {
+		return nil, err
+	}
+	resp, requestErr := o.entityAPI.SearchPeople(ctx, opts, searchConfig)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	return mapEntityPage(resp, adapter.MapGraphPerson), nil
+}
+
+func (o *ops) SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Event], error) {
+	if err := validateOpts(opts); err != nil {
+		return nil, err
+	}
+	resp, requestErr := o.entityAPI.SearchEvents(ctx, opts, searchConfig)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	return mapEntityPage(resp, adapter.MapGraphEvent), nil
+}
+
+func validateOpts(opts *search.SearchEntitiesOptions) error {
+	if opts == nil {
+		return errors.New("missing opts")
+	}
+	if opts.Query == nil && opts.Filter == nil {
+		return errors.New("missing query")
+	}
+	return opts.Validate()
+}
+
+func mapEntityPage[G any, T any](page *api.EntityPage[G], mapper func(G) *T) *search.EntityResults[T] {
+	results := make([]*search.EntityResult[T], 0, len(page.Hits))
+	for _, hit := range page.Hits {
+		results = append(results, &search.EntityResult[T]{
+			Item:    mapper(hit.Item),
+			Summary: hit.Hit.Summary,
+			Rank:    hit.Hit.Rank,
+			HitID:   hit.Hit.HitID,
+		})
+	}
+	return &search.EntityResults[T]{
+		Results:              results,
+		NextFrom:             page.NextFrom,
+		Total:                page.Total,
+		MoreResultsAvailable: page.MoreResultsAvailable,
+	}
+}
@@ -0,0 +1,13 @@
+package entities
+
+import (
+	"context"
+
+	"github.com/pzsp-teams/lib/search"
+)
+
+type entityOps interface {
+	SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.File], error)
+	SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Person], error)
+	SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Event], error)
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"context"
+	"testing"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/internal/api"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/testutil"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newOpsSUT(t *testing.T, setup func(entityAPI *testutil.MockEntityAPI)) (entityOps, context.Context) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	entityAPI := testutil.NewMockEntityAPI(ctrl)
+	if setup != nil {
+		setup(entityAPI)
+	}
+
+	return NewOps(entityAPI), context.Background()
+}
+
+func TestOps_SearchFiles(t *testing.T) {
+	t.Parallel()
+
+	opts := &search.SearchEntitiesOptions{Query: util.Ptr("report")}
+	item := msmodels.NewDriveItem()
+	item.SetId(util.Ptr("file-1"))
+	item.SetName(util.Ptr("report.docx"))
+
+	ops, ctx := newOpsSUT(t, func(entityAPI *testutil.MockEntityAPI) {
+		entityAPI.EXPECT().
+			SearchFiles(gomock.Any(), opts, gomock.Any()).
+			Return(&api.EntityPage[msmodels.DriveItemable]{
+				Hits: []*api.EntityHit[msmodels.DriveItemable]{
+					{Item: item, Hit: api.SearchHit{HitID: util.Ptr("hit-1"), Rank: util.Ptr(int32(1)), Summary: util.Ptr("<c0>report</c0>")}},
+				},
+				NextFrom:             util.Ptr(int32(1)),
+				Total:                util.Ptr(int32(7)),
+				MoreResultsAvailable: true,
+			}, nil).
+			Times(1)
+	})
+
+	got, err := ops.SearchFiles(ctx, opts, nil)
+	require.NoError(t, err)
+	require.Len(t, got.Results, 1)
+	require.Equal(t, "file-1", got.Results[0].Item.ID)
+	require.Equal(t, "report.docx", got.Results[0].Item.Name)
+	require.Equal(t, "hit-1", *got.Results[0].HitID)
+	require.Equal(t, int32(1), *got.Results[0].Rank)
+	require.Equal(t, "<c0>report</c0>", *got.Results[0].Summary)
+	require.Equal(t, int32(1), *got.NextFrom)
+	require.Equal(t, int32(7), *got.Total)
+	require.True(t, got.MoreResultsAvailable)
+}
+
+func TestOps_SearchPeople_MapsError(t *testing.T) {
+	t.Parallel()
+
+	ops, ctx := newOpsSUT(t, func(entityAPI *testutil.MockEntityAPI) {
+		entityAPI.EXPECT().SearchPeople(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, testutil.ReqErr(403)).Times(1)
+	})
+
+	got, err := ops.SearchPeople(ctx, &search.SearchEntitiesOptions{Query: util.Ptr("jane")}, nil)
+	require.Nil(t, got)
+	var forbidden *snd.ErrAccessForbidden
+	require.ErrorAs(t, err, &forbidden)
+}
+
+func TestOps_SearchEvents_InvalidOpts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts *search.SearchEntitiesOptions
+	}{
+		{name: "nil opts", opts: nil},
+		{name: "no query", opts: &search.SearchEntitiesOptions{}},
+		{name: "invalid filter", opts: &search.SearchEntitiesOptions{Filter: search.Or()}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ops, ctx := newOpsSUT(t, nil)
+			got, err := ops.SearchEvents(ctx, tc.opts, nil)
+			require.Nil(t, got)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"context"
+
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/search"
+)
+
+type service struct {
+	entityOps entityOps
+}
+
+// NewService creates a new instance of the entity search service.
+func NewService(entityOps entityOps) Service {
+	return &service{entityOps: entityOps}
+}
+
+func (s *service) SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.File], error) {
+	if searchConfig == nil {
+		searchConfig = search.DefaultSearchConfig()
+	}
+	resp, err := s.entityOps.SearchFiles(ctx, opts, searchConfig)
+	if err != nil {
+		return nil, snd.Wrap("SearchFiles", err)
+	}
+	return resp, nil
+}
+
+func (s *service) SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Person], error) {
+	if searchConfig == nil {
+		searchConfig = search.DefaultSearchConfig()
+	}
+	resp, err := s.entityOps.SearchPeople(ctx, opts, searchConfig)
+	if err != nil {
+		return nil, snd.Wrap("SearchPeople", err)
+	}
+	return resp, nil
+}
+
+func (s *service) SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Event], error) {
+	if searchConfig == nil {
+		searchConfig = search.DefaultSearchConfig()
+	}
+	resp, err := s.entityOps.SearchEvents(ctx, opts, searchConfig)
+	if err != nil {
+		return nil, snd.Wrap("SearchEvents", err)
+	}
+	return resp, nil
+}
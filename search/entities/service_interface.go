@@ -0,0 +1,28 @@
+// Package entities provides searches for entities other than chat messages and abstracts the underlying Microsoft Graph API calls.
+//
+// Concepts:
+//   - Files are drive items, e.g. files shared in channels or chats.
+//   - People are people relevant to the authenticated user (contacts, colleagues).
+//   - Events are events of the authenticated user's calendar, e.g. Teams meetings.
+//   - Queries use the same syntax and the same search.Expr builder as message search.
+//   - Paging uses search.SearchPage; SearchConfig controls the worker pool used to fetch
+//     full entities and lite mode, which returns the partial entities from the search response.
+package entities
+
+import (
+	"context"
+
+	"github.com/pzsp-teams/lib/search"
+)
+
+// Service defines the interface for searching files, people and events.
+type Service interface {
+	// SearchFiles searches files accessible to the authenticated user.
+	SearchFiles(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.File], error)
+
+	// SearchPeople searches people relevant to the authenticated user.
+	SearchPeople(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Person], error)
+
+	// SearchEvents searches events of the authenticated user's calendar.
+	SearchEvents(ctx context.Context, opts *search.SearchEntitiesOptions, searchConfig *search.SearchConfig) (*search.EntityResults[search.Event], error)
+}
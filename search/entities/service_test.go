@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pzsp-teams/lib/internal/testutil"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newSUT(t *testing.T, setup func(ops *testutil.MockentityOps)) (Service, context.Context) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	opsMock := testutil.NewMockentityOps(ctrl)
+	if setup != nil {
+		setup(opsMock)
+	}
+
+	return NewService(opsMock), context.Background()
+}
+
+func TestService_SearchEvents_DefaultConfig(t *testing.T) {
+	t.Parallel()
+
+	want := &search.EntityResults[search.Event]{
+		Results: []*search.EntityResult[search.Event]{{Item: &search.Event{ID: "e1"}}},
+	}
+	svc, ctx := newSUT(t, func(ops *testutil.MockentityOps) {
+		ops.EXPECT().
+			SearchEvents(gomock.Any(), gomock.Any(), search.DefaultSearchConfig()).
+			Return(want, nil).
+			Times(1)
+	})
+
+	got, err := svc.SearchEvents(ctx, &search.SearchEntitiesOptions{Query: util.Ptr("standup")}, nil)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestService_SearchFiles_WrapsError(t *testing.T) {
+	t.Parallel()
+
+	svc, ctx := newSUT(t, func(ops *testutil.MockentityOps) {
+		ops.EXPECT().SearchFiles(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, testutil.ReqErr(500)).Times(1)
+	})
+
+	got, err := svc.SearchFiles(ctx, &search.SearchEntitiesOptions{Query: util.Ptr("x")}, nil)
+	require.Nil(t, got)
+	testutil.RequireReqErrCode(t, err, 500)
+	require.ErrorContains(t, err, "SearchFiles")
+}
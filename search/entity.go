@@ -0,0 +1,86 @@
+package search
+
+import "time"
+
+// SearchEntitiesOptions describes a search for files, people or events.
+type SearchEntitiesOptions struct {
+	// Query is the full-text query string (provider-dependent syntax).
+	Query *string
+
+	// Filter is an additional query expression, combined with Query using AND.
+	Filter Expr
+
+	// SearchPage controls pagination (From/Size).
+	SearchPage *SearchPage
+}
+
+// Validate checks that the options can be turned into a query.
+func (o *SearchEntitiesOptions) Validate() error {
+	if o.Filter == nil {
+		return nil
+	}
+	_, err := Compile(o.Filter)
+	return err
+}
+
+// File is a file (drive item) found by a search, e.g. a file shared in a channel.
+type File struct {
+	ID                   string
+	Name                 string
+	WebURL               string
+	Size                 *int64
+	DriveID              string
+	LastModifiedDateTime *time.Time
+	LastModifiedBy       string
+}
+
+// Person is a person relevant to the current user found by a search.
+type Person struct {
+	ID                string
+	DisplayName       string
+	Email             string
+	UserPrincipalName string
+	JobTitle          string
+	Department        string
+}
+
+// Event is a calendar event, e.g. a Teams meeting, found by a search.
+type Event struct {
+	ID              string
+	Subject         string
+	Start           *time.Time
+	End             *time.Time
+	Organizer       string
+	Location        string
+	WebLink         string
+	IsOnlineMeeting bool
+	JoinURL         string
+}
+
+// EntityResult is a single search hit of a file, person or event.
+type EntityResult[T any] struct {
+	// Item is the found entity.
+	Item *T
+
+	// Summary is a snippet of the entity with matched terms highlighted, if provided.
+	Summary *string
+	// Rank is the position of the hit in the provider's relevance order, if provided.
+	Rank *int32
+	// HitID is the provider's internal identifier of the hit, if provided.
+	HitID *string
+}
+
+// EntityResults is a paginated container of file, person or event search hits.
+type EntityResults[T any] struct {
+	// Results contains the list of hits for this page.
+	Results []*EntityResult[T]
+
+	// NextFrom is the pagination cursor/index to continue from (if available).
+	NextFrom *int32
+
+	// Total is the total number of hits reported by the provider, if known.
+	Total *int32
+
+	// MoreResultsAvailable reports whether searching again from NextFrom can return more hits.
+	MoreResultsAvailable bool
+}
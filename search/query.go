@@ -0,0 +1,300 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// ErrInvalidQuery is returned when a query expression cannot be compiled.
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Expr is a node of a KQL (Keyword Query Language) query.
+//
+// Expressions are built with the functions of this package and turned into
+// a query string with Compile, which validates the whole tree first.
+type Expr interface {
+	writeKQL(b *strings.Builder, nested bool) error
+}
+
+// Compile validates the expression and returns it as a KQL query string.
+func Compile(e Expr) (string, error) {
+	if e == nil {
+		return "", fmt.Errorf("%w: nil expression", ErrInvalidQuery)
+	}
+	var b strings.Builder
+	if err := e.writeKQL(&b, false); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Quote returns value as a KQL quoted string, escaping backslashes and double quotes.
+func Quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// Term matches messages containing the word.
+// The word must not contain whitespace, quotes, parentheses or colons; use Phrase for such text.
+func Term(word string) Expr {
+	return termExpr{value: word}
+}
+
+// Prefix matches messages containing a word starting with prefix.
+func Prefix(prefix string) Expr {
+	return termExpr{value: prefix, prefix: true}
+}
+
+// Phrase matches messages containing the exact text.
+func Phrase(text string) Expr {
+	return phraseExpr{value: text}
+}
+
+// And matches messages matching all the expressions.
+func And(exprs ...Expr) Expr {
+	return groupExpr{op: "AND", items: exprs}
+}
+
+// Or matches messages matching any of the expressions.
+func Or(exprs ...Expr) Expr {
+	return groupExpr{op: "OR", items: exprs}
+}
+
+// Not matches messages not matching the expression.
+func Not(e Expr) Expr {
+	return notExpr{inner: e}
+}
+
+// Property restricts a property to the value, for properties without a dedicated helper.
+func Property(name, value string) Expr {
+	return propertyExpr{name: name, op: ":", value: value}
+}
+
+// From matches messages sent by any of the users (email addresses or user principal names).
+func From(users ...string) Expr {
+	return anyOf("from", users)
+}
+
+// To matches messages addressed to any of the users (email addresses or user principal names).
+func To(users ...string) Expr {
+	return anyOf("to", users)
+}
+
+// IsRead matches messages by read status.
+func IsRead(read bool) Expr {
+	return boolProperty("IsRead", read)
+}
+
+// IsMentioned matches messages by whether the current user is mentioned.
+func IsMentioned(mentioned bool) Expr {
+	return boolProperty("IsMentioned", mentioned)
+}
+
+// HasAttachment matches messages by whether they have attachments.
+func HasAttachment(has bool) Expr {
+	return boolProperty("HasAttachment", has)
+}
+
+// Subject matches messages whose subject contains the text.
+func Subject(text string) Expr {
+	return propertyExpr{name: "Subject", op: ":", value: text}
+}
+
+// WithImportance matches messages of the given importance.
+func WithImportance(importance models.MessageImportance) Expr {
+	return importanceExpr{value: importance}
+}
+
+// ChannelName matches messages posted in channels of the given name.
+func ChannelName(name string) Expr {
+	return propertyExpr{name: "ChannelName", op: ":", value: name}
+}
+
+// TeamName matches messages posted in teams of the given name.
+func TeamName(name string) Expr {
+	return propertyExpr{name: "TeamName", op: ":", value: name}
+}
+
+// SentWithin matches messages sent within the predefined interval, as interpreted by the provider.
+// For a reproducible range use SentBetween with the bounds of TimeInterval.Range.
+func SentWithin(interval TimeInterval) Expr {
+	return intervalExpr{value: interval}
+}
+
+// SentBetween matches messages sent between start and end.
+func SentBetween(start, end time.Time) Expr {
+	return rangeExpr{start: &start, end: &end}
+}
+
+// SentAfter matches messages sent at or after t.
+func SentAfter(t time.Time) Expr {
+	return rangeExpr{start: &t}
+}
+
+// SentBefore matches messages sent at or before t.
+func SentBefore(t time.Time) Expr {
+	return rangeExpr{end: &t}
+}
+
+var reservedWords = map[string]struct{}{"AND": {}, "OR": {}, "NOT": {}, "NEAR": {}, "ONEAR": {}}
+
+type termExpr struct {
+	value  string
+	prefix bool
+}
+
+func (e termExpr) writeKQL(b *strings.Builder, _ bool) error {
+	if e.value == "" {
+		return fmt.Errorf("%w: empty term", ErrInvalidQuery)
+	}
+	if strings.ContainsFunc(e.value, func(r rune) bool {
+		return r <= ' ' || strings.ContainsRune(`"():*\`, r)
+	}) {
+		return fmt.Errorf("%w: term %q contains whitespace or reserved characters", ErrInvalidQuery, e.value)
+	}
+	if _, ok := reservedWords[e.value]; ok {
+		return fmt.Errorf("%w: term %q is a reserved word", ErrInvalidQuery, e.value)
+	}
+	b.WriteString(e.value)
+	if e.prefix {
+		b.WriteByte('*')
+	}
+	return nil
+}
+
+type phraseExpr struct {
+	value string
+}
+
+func (e phraseExpr) writeKQL(b *strings.Builder, _ bool) error {
+	if strings.TrimSpace(e.value) == "" {
+		return fmt.Errorf("%w: empty phrase", ErrInvalidQuery)
+	}
+	b.WriteString(Quote(e.value))
+	return nil
+}
+
+type groupExpr struct {
+	op    string
+	items []Expr
+}
+
+func (e groupExpr) writeKQL(b *strings.Builder, nested bool) error {
+	if len(e.items) == 0 {
+		return fmt.Errorf("%w: empty %s group", ErrInvalidQuery, e.op)
+	}
+	if len(e.items) == 1 {
+		return writeChild(b, e.items[0], nested)
+	}
+	if nested {
+		b.WriteByte('(')
+	}
+	for i, item := range e.items {
+		if i > 0 {
+			b.WriteString(" " + e.op + " ")
+		}
+		if err := writeChild(b, item, true); err != nil {
+			return err
+		}
+	}
+	if nested {
+		b.WriteByte(')')
+	}
+	return nil
+}
+
+type notExpr struct {
+	inner Expr
+}
+
+func (e notExpr) writeKQL(b *strings.Builder, _ bool) error {
+	b.WriteString("NOT ")
+	return writeChild(b, e.inner, true)
+}
+
+func writeChild(b *strings.Builder, e Expr, nested bool) error {
+	if e == nil {
+		return fmt.Errorf("%w: nil expression", ErrInvalidQuery)
+	}
+	return e.writeKQL(b, nested)
+}
+
+type propertyExpr struct {
+	name  string
+	op    string
+	value string
+}
+
+func (e propertyExpr) writeKQL(b *strings.Builder, _ bool) error {
+	if strings.TrimSpace(e.name) == "" || strings.ContainsFunc(e.name, func(r rune) bool {
+		return r <= ' ' || strings.ContainsRune(`"():<>=*\`, r)
+	}) {
+		return fmt.Errorf("%w: invalid property name %q", ErrInvalidQuery, e.name)
+	}
+	if strings.TrimSpace(e.value) == "" {
+		return fmt.Errorf("%w: empty value of property %s", ErrInvalidQuery, e.name)
+	}
+	b.WriteString(e.name + e.op + Quote(e.value))
+	return nil
+}
+
+func anyOf(name string, values []string) Expr {
+	items := make([]Expr, 0, len(values))
+	for _, v := range values {
+		items = append(items, propertyExpr{name: name, op: ":", value: v})
+	}
+	return groupExpr{op: "OR", items: items}
+}
+
+func boolProperty(name string, value bool) Expr {
+	return propertyExpr{name: name, op: ":", value: fmt.Sprint(value)}
+}
+
+type importanceExpr struct {
+	value models.MessageImportance
+}
+
+func (e importanceExpr) writeKQL(b *strings.Builder, nested bool) error {
+	switch e.value {
+	case models.MessageImportanceNormal, models.MessageImportanceHigh, models.MessageImportanceUrgent:
+		return propertyExpr{name: "Importance", op: ":", value: string(e.value)}.writeKQL(b, nested)
+	}
+	return fmt.Errorf("%w: unknown importance %q", ErrInvalidQuery, e.value)
+}
+
+type intervalExpr struct {
+	value TimeInterval
+}
+
+func (e intervalExpr) writeKQL(b *strings.Builder, nested bool) error {
+	switch e.value {
+	case Today, Yesterday, ThisWeek, ThisMonth, LastMonth, ThisYear, LastYear:
+		return propertyExpr{name: "sent", op: ":", value: string(e.value)}.writeKQL(b, nested)
+	}
+	return fmt.Errorf("%w: unknown interval %q", ErrInvalidQuery, e.value)
+}
+
+type rangeExpr struct {
+	start, end *time.Time
+}
+
+func (e rangeExpr) writeKQL(b *strings.Builder, nested bool) error {
+	switch {
+	case e.start != nil && e.end != nil:
+		if e.end.Before(*e.start) {
+			return fmt.Errorf("%w: sent range ends before it starts", ErrInvalidQuery)
+		}
+		value := e.start.Format(time.RFC3339) + ".." + e.end.Format(time.RFC3339)
+		return propertyExpr{name: "sent", op: ":", value: value}.writeKQL(b, nested)
+	case e.start != nil:
+		return propertyExpr{name: "sent", op: ">=", value: e.start.Format(time.RFC3339)}.writeKQL(b, nested)
+	case e.end != nil:
+		return propertyExpr{name: "sent", op: "<=", value: e.end.Format(time.RFC3339)}.writeKQL(b, nested)
+	}
+	return fmt.Errorf("%w: empty sent range", ErrInvalidQuery)
+}
@@ -0,0 +1,167 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 10, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr Expr
+		want string
+	}{
+		{name: "term", expr: Term("deploy"), want: `deploy`},
+		{name: "prefix", expr: Prefix("depl"), want: `depl*`},
+		{name: "phrase escapes quotes and backslashes", expr: Phrase(`say "hi" \o/`), want: `"say \"hi\" \\o/"`},
+		{name: "top-level group has no parentheses", expr: And(Term("a"), Term("b")), want: `a AND b`},
+		{name: "nested groups", expr: And(Term("a"), Or(Term("b"), Term("c"))), want: `a AND (b OR c)`},
+		{name: "single item group is unwrapped", expr: And(Or(Term("a"))), want: `a`},
+		{name: "nested not", expr: Not(Or(Term("a"), Not(Term("b")))), want: `NOT (a OR NOT b)`},
+		{name: "from several users", expr: From("a@x.com", "b@x.com"), want: `from:"a@x.com" OR from:"b@x.com"`},
+		{name: "to", expr: And(To("a@x.com"), Term("x")), want: `to:"a@x.com" AND x`},
+		{name: "bool properties", expr: And(IsRead(false), IsMentioned(true), HasAttachment(true)), want: `IsRead:"false" AND IsMentioned:"true" AND HasAttachment:"true"`},
+		{name: "subject", expr: Subject(`Q1 "plan"`), want: `Subject:"Q1 \"plan\""`},
+		{name: "importance", expr: WithImportance(models.MessageImportanceHigh), want: `Importance:"high"`},
+		{name: "urgent importance", expr: WithImportance(models.MessageImportanceUrgent), want: `Importance:"urgent"`},
+		{name: "channel and team", expr: And(ChannelName("General"), TeamName("Ops")), want: `ChannelName:"General" AND TeamName:"Ops"`},
+		{name: "custom property", expr: Property("Author", "Jane"), want: `Author:"Jane"`},
+		{name: "interval", expr: SentWithin(ThisWeek), want: `sent:"this week"`},
+		{name: "range", expr: SentBetween(start, end), want: `sent:"2026-01-09T00:00:00Z..2026-01-10T23:59:59Z"`},
+		{name: "after", expr: SentAfter(start), want: `sent>="2026-01-09T00:00:00Z"`},
+		{name: "before", expr: SentBefore(end), want: `sent<="2026-01-10T23:59:59Z"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Compile(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr Expr
+	}{
+		{name: "nil", expr: nil},
+		{name: "empty group", expr: Or()},
+		{name: "nil in group", expr: And(Term("a"), nil)},
+		{name: "nil in not", expr: Not(nil)},
+		{name: "empty term", expr: Term("")},
+		{name: "term with space", expr: Term("two words")},
+		{name: "term with quote", expr: Term(`a"b`)},
+		{name: "term with colon", expr: Term("from:x")},
+		{name: "reserved word", expr: Term("OR")},
+		{name: "prefix with star", expr: Prefix("a*")},
+		{name: "blank phrase", expr: Phrase("  ")},
+		{name: "no users", expr: From()},
+		{name: "blank user", expr: To(" ")},
+		{name: "invalid property name", expr: Property("a b", "x")},
+		{name: "unknown importance", expr: WithImportance("low")},
+		{name: "unknown interval", expr: SentWithin("someday")},
+		{name: "reversed range", expr: SentBetween(start, start.Add(-time.Hour))},
+		{name: "invalid nested deep", expr: And(Term("a"), Or(Term("b"), Not(Term(""))))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Compile(tc.expr)
+			require.ErrorIs(t, err, ErrInvalidQuery)
+		})
+	}
+}
+
+func TestSearchMessagesOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&SearchMessagesOptions{}).Validate())
+	require.NoError(t, (&SearchMessagesOptions{Filter: Term("a")}).Validate())
+	require.ErrorIs(t, (&SearchMessagesOptions{Filter: And()}).Validate(), ErrInvalidQuery)
+	require.NoError(t, (&SearchMessagesOptions{Sort: SortByCreatedDesc}).Validate())
+	require.ErrorIs(t, (&SearchMessagesOptions{Sort: "size"}).Validate(), ErrInvalidQuery)
+	someday := TimeInterval("someday")
+	require.ErrorIs(t, (&SearchMessagesOptions{Interval: &someday}).Validate(), ErrInvalidQuery)
+}
+
+func TestSearchEntitiesOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&SearchEntitiesOptions{}).Validate())
+	require.NoError(t, (&SearchEntitiesOptions{Filter: Subject("plan")}).Validate())
+	require.ErrorIs(t, (&SearchEntitiesOptions{Filter: Not(nil)}).Validate(), ErrInvalidQuery)
+}
+
+func TestTimeInterval_Range(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 3, 13, 15, 0, 0, 0, time.UTC) // Thursday
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		interval   TimeInterval
+		start, end time.Time
+	}{
+		{Today, day(3, 13), day(3, 14)},
+		{Yesterday, day(3, 12), day(3, 13)},
+		{ThisWeek, day(3, 10), day(3, 17)},
+		{ThisMonth, day(3, 1), day(4, 1)},
+		{LastMonth, day(2, 1), day(3, 1)},
+		{ThisYear, day(1, 1), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{LastYear, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), day(1, 1)},
+	}
+	for _, tc := range tests {
+		start, end, err := tc.interval.Range(now)
+		require.NoError(t, err, tc.interval)
+		require.Equal(t, tc.start, start, tc.interval)
+		require.Equal(t, tc.end, end, tc.interval)
+	}
+
+	_, _, err := TimeInterval("someday").Range(now)
+	require.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestSearchMessagesOptions_TimeRange(t *testing.T) {
+	t.Parallel()
+
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	require.NoError(t, err)
+	now := time.Date(2025, 3, 13, 23, 30, 0, 0, time.UTC) // already Friday in Warsaw
+	today := Today
+
+	start, end, err := (&SearchMessagesOptions{Interval: &today}).TimeRange(now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC), *start)
+	require.Equal(t, time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), *end)
+
+	start, end, err = (&SearchMessagesOptions{Interval: &today, TimeZone: warsaw}).TimeRange(now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 3, 14, 0, 0, 0, 0, warsaw), *start)
+	require.Equal(t, time.Date(2025, 3, 15, 0, 0, 0, 0, warsaw), *end)
+	require.Equal(t, warsaw, start.Location())
+
+	startTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	start, end, err = (&SearchMessagesOptions{StartTime: &startTime, TimeZone: warsaw}).TimeRange(now)
+	require.NoError(t, err)
+	require.True(t, startTime.Equal(*start))
+	require.Equal(t, warsaw, start.Location())
+	require.Nil(t, end)
+}
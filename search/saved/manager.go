@@ -0,0 +1,272 @@
+package saved
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/cacher"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/search"
+)
+
+// MaxSeenHits is the number of seen hits remembered per search.
+// Hits returned by the latest poll are always kept, even when there are more of them.
+const MaxSeenHits = 1000
+
+// Manager registers saved searches and polls them for hits not reported before.
+type Manager interface {
+	// Register adds a saved search. Names must be unique.
+	Register(s *SavedSearch) error
+
+	// Unregister removes a saved search. Its seen hits are kept; use Reset to forget them.
+	Unregister(name string) error
+
+	// List returns the names of registered searches in registration order.
+	List() []string
+
+	// Poll runs a registered search once and returns its hits not reported before, marking them as seen.
+	Poll(ctx context.Context, name string) ([]*search.SearchResult, error)
+
+	// Reset forgets the seen hits of a search, so its next poll starts over like the first one.
+	Reset(name string) error
+
+	// Run polls all searches registered when it is called, each on its own interval,
+	// and calls handle for every poll that found new hits or failed.
+	// Calls of handle are never concurrent. Run blocks until ctx is done.
+	Run(ctx context.Context, handle func(Notification)) error
+
+	// Watch is like Run, but delivers notifications on the returned channel,
+	// which is closed once ctx is done and polling has stopped.
+	Watch(ctx context.Context) <-chan Notification
+}
+
+type manager struct {
+	mu       sync.Mutex
+	searches map[string]*SavedSearch
+	order    []string
+	state    stateStore
+}
+
+// NewManager creates a saved search manager persisting seen hits via the cache configured by cacheCfg.
+// When cacheCfg is nil or cache is disabled, seen hits are kept in memory only.
+func NewManager(cacheCfg *config.CacheConfig) (Manager, error) {
+	if cacheCfg == nil {
+		return newManager(nil), nil
+	}
+	handler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	if handler == nil {
+		return newManager(nil), nil
+	}
+	return newManager(newCacherState(handler.Cacher)), nil
+}
+
+func newManager(state stateStore) *manager {
+	if state == nil {
+		state = newMemoryState()
+	}
+	return &manager{
+		searches: make(map[string]*SavedSearch),
+		state:    state,
+	}
+}
+
+func (m *manager) Register(s *SavedSearch) error {
+	if s == nil || strings.TrimSpace(s.Name) == "" || s.Source == nil || s.Options == nil {
+		return ErrInvalidSearch
+	}
+	if err := s.Options.Validate(); err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidSearch, s.Name, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.searches[s.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateSearch, s.Name)
+	}
+	m.searches[s.Name] = s
+	m.order = append(m.order, s.Name)
+	return nil
+}
+
+func (m *manager) Unregister(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.searches[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownSearch, name)
+	}
+	delete(m.searches, name)
+	m.order = slices.DeleteFunc(m.order, func(n string) bool { return n == name })
+	return nil
+}
+
+func (m *manager) List() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.order)
+}
+
+func (m *manager) Poll(ctx context.Context, name string) ([]*search.SearchResult, error) {
+	s, err := m.get(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.poll(ctx, s)
+}
+
+func (m *manager) Reset(name string) error {
+	if _, err := m.get(name); err != nil {
+		return err
+	}
+	return m.state.Delete(name)
+}
+
+func (m *manager) Run(ctx context.Context, handle func(Notification)) error {
+	m.mu.Lock()
+	searches := make([]*SavedSearch, 0, len(m.order))
+	for _, name := range m.order {
+		searches = append(searches, m.searches[name])
+	}
+	m.mu.Unlock()
+
+	var handleMu sync.Mutex
+	var wg sync.WaitGroup
+	for _, s := range searches {
+		wg.Go(func() {
+			m.loop(ctx, s, func(n Notification) {
+				handleMu.Lock()
+				defer handleMu.Unlock()
+				handle(n)
+			})
+		})
+	}
+	wg.Wait()
+	return nil
+}
+
+func (m *manager) Watch(ctx context.Context) <-chan Notification {
+	out := make(chan Notification)
+	go func() {
+		defer close(out)
+		_ = m.Run(ctx, func(n Notification) {
+			select {
+			case out <- n:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return out
+}
+
+func (m *manager) get(name string) (*SavedSearch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.searches[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSearch, name)
+	}
+	return s, nil
+}
+
+func (m *manager) loop(ctx context.Context, s *SavedSearch, notify func(Notification)) {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := m.poll(ctx, s)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil || len(results) > 0 {
+			notify(Notification{Search: s.Name, Results: results, Err: err})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *manager) poll(ctx context.Context, s *SavedSearch) ([]*search.SearchResult, error) {
+	previous, polled, err := m.state.Load(s.Name)
+	if err != nil {
+		return nil, fmt.Errorf("loading state of saved search %q: %w", s.Name, err)
+	}
+	seen := make(map[string]struct{}, len(previous))
+	for _, id := range previous {
+		seen[id] = struct{}{}
+	}
+
+	// Searches may modify the options (e.g. resolving FromMe), so every poll gets its own copy.
+	opts := *s.Options
+	searchConfig := s.Config
+	if searchConfig == nil {
+		searchConfig = search.DefaultSearchConfig()
+	}
+	resp, err := s.Source.SearchMessages(ctx, &opts, searchConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var fresh []*search.SearchResult
+	var current []string
+	inCurrent := make(map[string]struct{})
+	for _, r := range resp.Messages {
+		id := hitKey(r)
+		if id == "" {
+			continue
+		}
+		if _, ok := inCurrent[id]; ok {
+			continue
+		}
+		inCurrent[id] = struct{}{}
+		current = append(current, id)
+		if _, ok := seen[id]; !ok {
+			fresh = append(fresh, r)
+		}
+	}
+
+	if err := m.state.Save(s.Name, keepSeen(previous, current)); err != nil {
+		return nil, fmt.Errorf("saving state of saved search %q: %w", s.Name, err)
+	}
+
+	if !polled && !s.EmitExisting {
+		return nil, nil
+	}
+	return fresh, nil
+}
+
+// keepSeen returns the hits to remember after a poll: the previous hits followed by the current ones,
+// which are moved to the end, trimmed to MaxSeenHits by dropping the oldest.
+func keepSeen(previous, current []string) []string {
+	out := slices.DeleteFunc(slices.Clone(previous), func(id string) bool { return slices.Contains(current, id) })
+	out = append(out, current...)
+	if limit := max(MaxSeenHits, len(current)); len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
+
+// hitKey identifies a hit by its conversation and message ID.
+func hitKey(r *search.SearchResult) string {
+	if r == nil || r.Message == nil || r.Message.ID == "" {
+		return ""
+	}
+	if r.ChatID != nil {
+		return "chat/" + *r.ChatID + "/" + r.Message.ID
+	}
+	return "channel/" + util.Deref(r.TeamID) + "/" + util.Deref(r.ChannelID) + "/" + r.Message.ID
+}
@@ -0,0 +1,315 @@
+package saved
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/cacher"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/pzsp-teams/lib/search"
+	"github.com/stretchr/testify/require"
+)
+
+func newCacheConfig(t *testing.T, protection config.CacheProtection) *config.CacheConfig {
+	t.Helper()
+	dir := t.TempDir()
+	pepperFile := filepath.Join(dir, "pepper")
+	require.NoError(t, os.WriteFile(pepperFile, []byte("test-pepper"), 0o600))
+	return &config.CacheConfig{
+		Mode:       config.CacheSync,
+		Provider:   config.CacheProviderJSONFile,
+		Path:       util.Ptr(filepath.Join(dir, "cache.json")),
+		Protection: protection,
+		Pepper:     &config.PepperConfig{Source: config.PepperSourceFile, FilePath: pepperFile},
+	}
+}
+
+func newCacheHandler(t *testing.T, cfg *config.CacheConfig) *cacher.CacheHandler {
+	t.Helper()
+	handler, err := cacher.NewCacheHandler(cfg)
+	require.NoError(t, err)
+	return handler
+}
+
+// scriptedSource returns the next scripted page of chat message IDs on each search.
+type scriptedSource struct {
+	mu    sync.Mutex
+	pages [][]string
+	err   error
+	opts  []*search.SearchMessagesOptions
+}
+
+func (s *scriptedSource) SearchMessages(_ context.Context, opts *search.SearchMessagesOptions, _ *search.SearchConfig) (*search.SearchResults, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts = append(s.opts, opts)
+	opts.From = append(opts.From, "me@example.com")
+	if s.err != nil {
+		return nil, s.err
+	}
+	var ids []string
+	if len(s.pages) > 0 {
+		ids, s.pages = s.pages[0], s.pages[1:]
+	}
+	out := &search.SearchResults{}
+	for _, id := range ids {
+		out.Messages = append(out.Messages, &search.SearchResult{
+			Message: &models.Message{ID: id},
+			ChatID:  util.Ptr("chat-1"),
+		})
+	}
+	return out, nil
+}
+
+func ids(results []*search.SearchResult) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Message.ID)
+	}
+	return out
+}
+
+func newSearch(name string, src Source) *SavedSearch {
+	return &SavedSearch{Name: name, Source: src, Options: &search.SearchMessagesOptions{Query: util.Ptr("incident-123")}}
+}
+
+func TestManager_Poll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("first poll only records existing hits", func(t *testing.T) {
+		t.Parallel()
+
+		src := &scriptedSource{pages: [][]string{{"m1", "m2"}, {"m3", "m2", "m1"}, {"m3"}}}
+		m := newManager(nil)
+		require.NoError(t, m.Register(newSearch("s", src)))
+
+		got, err := m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		got, err = m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Equal(t, []string{"m3"}, ids(got))
+
+		got, err = m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("emit existing reports first poll", func(t *testing.T) {
+		t.Parallel()
+
+		src := &scriptedSource{pages: [][]string{{"m1"}, {}, {"m1", "m2"}}}
+		s := newSearch("s", src)
+		s.EmitExisting = true
+		m := newManager(nil)
+		require.NoError(t, m.Register(s))
+
+		got, err := m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Equal(t, []string{"m1"}, ids(got))
+
+		got, err = m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		got, err = m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Equal(t, []string{"m2"}, ids(got))
+	})
+
+	t.Run("state survives a new manager", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newCacheConfig(t, config.CacheProtectionNone)
+		first := newManager(newCacherState(newCacheHandler(t, cfg).Cacher))
+		require.NoError(t, first.Register(newSearch("s", &scriptedSource{pages: [][]string{{"m1"}}})))
+		_, err := first.Poll(context.Background(), "s")
+		require.NoError(t, err)
+
+		second := newManager(newCacherState(newCacheHandler(t, cfg).Cacher))
+		require.NoError(t, second.Register(newSearch("s", &scriptedSource{pages: [][]string{{"m2", "m1"}}})))
+		got, err := second.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Equal(t, []string{"m2"}, ids(got))
+	})
+
+	t.Run("state is protected like the cache", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := newCacheConfig(t, config.CacheProtectionEncrypt)
+		m := newManager(newCacherState(newCacheHandler(t, cfg).Cacher))
+		require.NoError(t, m.Register(newSearch("incident", &scriptedSource{pages: [][]string{{"m1"}, {"m2", "m1"}}})))
+		_, err := m.Poll(context.Background(), "incident")
+		require.NoError(t, err)
+
+		raw, err := os.ReadFile(*cfg.Path)
+		require.NoError(t, err)
+		require.NotContains(t, string(raw), "incident")
+		require.NotContains(t, string(raw), "chat-1")
+
+		got, err := m.Poll(context.Background(), "incident")
+		require.NoError(t, err)
+		require.Equal(t, []string{"m2"}, ids(got))
+	})
+
+	t.Run("clearing the cache starts over", func(t *testing.T) {
+		t.Parallel()
+
+		handler := newCacheHandler(t, newCacheConfig(t, config.CacheProtectionNone))
+		m := newManager(newCacherState(handler.Cacher))
+		require.NoError(t, m.Register(newSearch("s", &scriptedSource{pages: [][]string{{"m1"}, {"m2", "m1"}}})))
+		_, err := m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+
+		require.NoError(t, handler.Cacher.Clear())
+		got, err := m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("reset starts over", func(t *testing.T) {
+		t.Parallel()
+
+		src := &scriptedSource{pages: [][]string{{"m1"}, {"m1", "m2"}}}
+		m := newManager(nil)
+		require.NoError(t, m.Register(newSearch("s", src)))
+		_, err := m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+
+		require.NoError(t, m.Reset("s"))
+		got, err := m.Poll(context.Background(), "s")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("each poll gets its own copy of options", func(t *testing.T) {
+		t.Parallel()
+
+		src := &scriptedSource{}
+		s := newSearch("s", src)
+		m := newManager(nil)
+		require.NoError(t, m.Register(s))
+		for range 2 {
+			_, err := m.Poll(context.Background(), "s")
+			require.NoError(t, err)
+		}
+
+		require.Empty(t, s.Options.From)
+		require.Len(t, src.opts, 2)
+		require.NotSame(t, src.opts[0], src.opts[1])
+	})
+
+	t.Run("search error", func(t *testing.T) {
+		t.Parallel()
+
+		boom := errors.New("boom")
+		m := newManager(nil)
+		require.NoError(t, m.Register(newSearch("s", &scriptedSource{err: boom})))
+
+		_, err := m.Poll(context.Background(), "s")
+		require.ErrorIs(t, err, boom)
+	})
+
+	t.Run("unknown search", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newManager(nil).Poll(context.Background(), "nope")
+		require.ErrorIs(t, err, ErrUnknownSearch)
+	})
+}
+
+func TestKeepSeen(t *testing.T) {
+	t.Parallel()
+
+	hits := func(from, to int) []string {
+		var out []string
+		for i := from; i < to; i++ {
+			out = append(out, fmt.Sprintf("h%d", i))
+		}
+		return out
+	}
+
+	t.Run("current hits move to the end", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, []string{"h0", "h2", "h1", "h3"}, keepSeen(hits(0, 3), []string{"h1", "h3"}))
+	})
+
+	t.Run("oldest hits are dropped", func(t *testing.T) {
+		t.Parallel()
+		got := keepSeen(hits(0, MaxSeenHits), hits(MaxSeenHits, MaxSeenHits+2))
+		require.Len(t, got, MaxSeenHits)
+		require.Equal(t, "h2", got[0])
+		require.Equal(t, fmt.Sprintf("h%d", MaxSeenHits+1), got[len(got)-1])
+	})
+
+	t.Run("current hits are always kept", func(t *testing.T) {
+		t.Parallel()
+		current := hits(0, MaxSeenHits+5)
+		require.Equal(t, current, keepSeen([]string{"old"}, current))
+	})
+}
+
+func TestManager_Register(t *testing.T) {
+	t.Parallel()
+
+	m := newManager(nil)
+	src := &scriptedSource{}
+
+	require.ErrorIs(t, m.Register(nil), ErrInvalidSearch)
+	require.ErrorIs(t, m.Register(&SavedSearch{Name: "x", Source: src}), ErrInvalidSearch)
+	require.ErrorIs(t, m.Register(&SavedSearch{Name: " ", Source: src, Options: &search.SearchMessagesOptions{}}), ErrInvalidSearch)
+	require.ErrorIs(t, m.Register(&SavedSearch{Name: "x", Source: src, Options: &search.SearchMessagesOptions{Filter: search.And()}}), search.ErrInvalidQuery)
+
+	require.NoError(t, m.Register(newSearch("a", src)))
+	require.NoError(t, m.Register(newSearch("b", src)))
+	require.ErrorIs(t, m.Register(newSearch("a", src)), ErrDuplicateSearch)
+	require.Equal(t, []string{"a", "b"}, m.List())
+
+	require.NoError(t, m.Unregister("a"))
+	require.ErrorIs(t, m.Unregister("a"), ErrUnknownSearch)
+	require.Equal(t, []string{"b"}, m.List())
+}
+
+func TestManager_Watch(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("boom")
+	m := newManager(nil)
+	hits := newSearch("hits", &scriptedSource{pages: [][]string{{"m1"}, {"m1", "m2"}, {"m3"}}})
+	hits.Interval = time.Millisecond
+	failing := newSearch("failing", &scriptedSource{err: boom})
+	failing.Interval = time.Hour
+	require.NoError(t, m.Register(hits))
+	require.NoError(t, m.Register(failing))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var gotHits []string
+	var gotErr error
+	ch := m.Watch(ctx)
+	for n := range ch {
+		switch n.Search {
+		case "hits":
+			require.NoError(t, n.Err)
+			gotHits = append(gotHits, ids(n.Results)...)
+		case "failing":
+			gotErr = n.Err
+		}
+		if len(gotHits) == 2 && gotErr != nil {
+			cancel()
+		}
+	}
+
+	require.Equal(t, []string{"m2", "m3"}, gotHits)
+	require.ErrorIs(t, gotErr, boom)
+}
@@ -0,0 +1,102 @@
+// Package saved provides saved message searches that are polled on a schedule and report only new hits.
+//
+// Concepts:
+//   - A SavedSearch is a named set of search.SearchMessagesOptions run against a Source
+//     (channel messages via ChannelSource, chat messages via ChatSource).
+//   - A hit is identified by its conversation (chat or team/channel) and message ID.
+//     Hits already reported are remembered and never reported again.
+//   - The seen hits are persisted in the cache (when cache is enabled), so they survive restarts
+//     and are protected like other cache entries. Without cache they are kept in memory.
+//     Clearing the cache forgets them, like Manager.Reset does for a single search.
+//   - At most MaxSeenHits hits are remembered per search, forgetting the oldest ones first.
+//   - The first poll of a search without any saved state only records the current hits,
+//     unless EmitExisting is set.
+//   - Each poll runs a single search; use SearchPage and SearchConfig to control how many hits it covers.
+//
+// Use Manager.Poll for a single poll, or Manager.Run / Manager.Watch to poll all registered
+// searches in the background and receive notifications through a callback or a channel.
+package saved
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/pzsp-teams/lib/channels"
+	"github.com/pzsp-teams/lib/chats"
+	"github.com/pzsp-teams/lib/search"
+)
+
+// DefaultInterval is the polling interval used when SavedSearch.Interval is not set.
+const DefaultInterval = 5 * time.Minute
+
+var (
+	// ErrDuplicateSearch is returned when a search with the same name is already registered.
+	ErrDuplicateSearch = errors.New("saved search already registered")
+	// ErrUnknownSearch is returned when no search with the given name is registered.
+	ErrUnknownSearch = errors.New("saved search not registered")
+	// ErrInvalidSearch is returned when a saved search is missing its name, source or options.
+	ErrInvalidSearch = errors.New("invalid saved search")
+)
+
+// Source runs message searches for a saved search.
+type Source interface {
+	SearchMessages(ctx context.Context, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error)
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(ctx context.Context, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error)
+
+// SearchMessages calls f.
+func (f SourceFunc) SearchMessages(ctx context.Context, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error) {
+	return f(ctx, opts, searchConfig)
+}
+
+// ChannelSource searches channel messages, scoped like channels.Service.SearchMessages:
+// a nil channelRef searches all channels of the team, a nil teamRef searches all teams.
+func ChannelSource(svc channels.Service, teamRef, channelRef *string) Source {
+	return SourceFunc(func(ctx context.Context, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error) {
+		return svc.SearchMessages(ctx, teamRef, channelRef, opts, searchConfig)
+	})
+}
+
+// ChatSource searches chat messages, scoped like chats.Service.SearchMessages:
+// a nil chatRef searches all chats.
+func ChatSource(svc chats.Service, chatRef chats.ChatRef) Source {
+	return SourceFunc(func(ctx context.Context, opts *search.SearchMessagesOptions, searchConfig *search.SearchConfig) (*search.SearchResults, error) {
+		return svc.SearchMessages(ctx, chatRef, opts, searchConfig)
+	})
+}
+
+// SavedSearch is a named message search polled for new hits.
+type SavedSearch struct {
+	// Name identifies the search, also in the persisted state. Required.
+	Name string
+
+	// Source runs the search. Required.
+	Source Source
+
+	// Options are the search options. Required.
+	Options *search.SearchMessagesOptions
+
+	// Config is the search configuration; defaults to search.DefaultSearchConfig.
+	Config *search.SearchConfig
+
+	// Interval is the time between polls in Run and Watch; defaults to DefaultInterval.
+	Interval time.Duration
+
+	// EmitExisting reports the hits found by the first poll instead of only recording them.
+	EmitExisting bool
+}
+
+// Notification reports the result of a poll of a saved search: either new hits or an error.
+type Notification struct {
+	// Search is the name of the saved search.
+	Search string
+
+	// Results contains the hits not reported before, in the order returned by the search.
+	Results []*search.SearchResult
+
+	// Err is the error of the poll, if it failed.
+	Err error
+}
@@ -0,0 +1,95 @@
+package saved
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/pzsp-teams/lib/internal/cacher"
+)
+
+// stateStore keeps the seen hits of saved searches.
+type stateStore interface {
+	// Load returns the seen hits of a search, oldest first, and whether it was polled before.
+	Load(name string) (seen []string, polled bool, err error)
+
+	// Save replaces the seen hits of a search and marks it as polled.
+	Save(name string, seen []string) error
+
+	// Delete forgets a search.
+	Delete(name string) error
+}
+
+// memoryState keeps saved search state in memory when the cache is disabled.
+type memoryState struct {
+	mu     sync.Mutex
+	states map[string][]string
+}
+
+func newMemoryState() *memoryState {
+	return &memoryState{states: make(map[string][]string)}
+}
+
+func (s *memoryState) Load(name string) (seen []string, polled bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seen, polled = s.states[name]
+	return slices.Clone(seen), polled, nil
+}
+
+func (s *memoryState) Save(name string, seen []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[name] = append([]string{}, seen...)
+	return nil
+}
+
+func (s *memoryState) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, name)
+	return nil
+}
+
+// cacherState keeps saved search state in the cache, so it is protected with the cache's pepper
+// and cleared or inspected together with other cache entries.
+// The seen hits of a search are stored as a single JSON encoded value, replaced on every save.
+type cacherState struct {
+	cache cacher.Cacher
+}
+
+func newCacherState(c cacher.Cacher) *cacherState {
+	return &cacherState{cache: c}
+}
+
+func (s *cacherState) Load(name string) (seen []string, polled bool, err error) {
+	value, found, err := s.cache.Get(cacher.NewSavedSearchKey(name))
+	if err != nil || !found {
+		return nil, false, err
+	}
+	values, ok := value.([]string)
+	if !ok || len(values) != 1 {
+		return nil, false, fmt.Errorf("invalid saved search state %v", value)
+	}
+	if err := json.Unmarshal([]byte(values[0]), &seen); err != nil {
+		return nil, false, fmt.Errorf("decoding saved search state: %w", err)
+	}
+	return seen, true, nil
+}
+
+func (s *cacherState) Save(name string, seen []string) error {
+	data, err := json.Marshal(append([]string{}, seen...))
+	if err != nil {
+		return err
+	}
+	key := cacher.NewSavedSearchKey(name)
+	if err := s.cache.Invalidate(key); err != nil {
+		return err
+	}
+	return s.cache.Set(key, string(data))
+}
+
+func (s *cacherState) Delete(name string) error {
+	return s.cache.Invalidate(cacher.NewSavedSearchKey(name))
+}
@@ -4,17 +4,23 @@
 // The package defines:
 //   - query options (SearchMessagesOptions),
 //   - pagination controls (SearchPage),
-//   - predefined time windows (TimeInterval),
+//   - predefined time windows (TimeInterval), resolved to exact ranges on the client,
+//   - result ordering (SortOrder),
 //   - result containers (SearchResult, SearchResults),
+//   - options and result models of file, person and event searches (SearchEntitiesOptions,
+//     File, Person, Event, EntityResults), served by the search/entities package,
+//   - a KQL query builder (Expr, Compile),
 //   - and a small concurrency configuration (SearchConfig).
 //
 // Notes:
 //   - If Interval is provided, it takes precedence over StartTime and EndTime.
+//     It is resolved in TimeZone (UTC by default) when the search starts, so all pages cover the same range.
 //   - "To" / "NotTo" filters typically work for chats, not for team channels.
 //   - Some providers may not support all filters (e.g., IsRead), depending on API limitations.
 package search
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/pzsp-teams/lib/models"
@@ -53,6 +59,47 @@ const (
 	LastYear TimeInterval = "last year"
 )
 
+// Range resolves the interval to the [start, end) range containing now, in the location of now.
+// Weeks start on Monday.
+func (i TimeInterval) Range(now time.Time) (start, end time.Time, err error) {
+	y, m, d := now.Date()
+	loc := now.Location()
+	today := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	month := time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	year := time.Date(y, 1, 1, 0, 0, 0, 0, loc)
+
+	switch i {
+	case Today:
+		return today, today.AddDate(0, 0, 1), nil
+	case Yesterday:
+		return today.AddDate(0, 0, -1), today, nil
+	case ThisWeek:
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return monday, monday.AddDate(0, 0, 7), nil
+	case ThisMonth:
+		return month, month.AddDate(0, 1, 0), nil
+	case LastMonth:
+		return month.AddDate(0, -1, 0), month, nil
+	case ThisYear:
+		return year, year.AddDate(1, 0, 0), nil
+	case LastYear:
+		return year.AddDate(-1, 0, 0), year, nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%w: unknown interval %q", ErrInvalidQuery, i)
+}
+
+// SortOrder is the order of search results.
+type SortOrder string
+
+const (
+	// SortByRelevance orders results by the provider's relevance ranking (default).
+	SortByRelevance SortOrder = ""
+	// SortByCreatedAsc orders results by creation date, oldest first.
+	SortByCreatedAsc SortOrder = "createdDateTime asc"
+	// SortByCreatedDesc orders results by creation date, newest first.
+	SortByCreatedDesc SortOrder = "createdDateTime desc"
+)
+
 // SearchMessagesOptions describes filters and parameters used to search for messages.
 //
 // Interval takes precedence over StartTime and EndTime.
@@ -92,8 +139,16 @@ type SearchMessagesOptions struct {
 	EndTime *time.Time
 
 	// Interval is a predefined time window; when set it overrides StartTime/EndTime.
+	// It is resolved to an exact range in TimeZone when the search starts.
 	Interval *TimeInterval
 
+	// TimeZone is the time zone in which Interval is resolved and StartTime/EndTime are sent.
+	// UTC is used when nil.
+	TimeZone *time.Location
+
+	// Sort is the order of results; relevance order is used when empty.
+	Sort SortOrder
+
 	// NotFromMe excludes messages sent by the current user.
 	NotFromMe bool
 	// NotToMe excludes messages sent to the current user (provider-dependent).
@@ -102,6 +157,53 @@ type SearchMessagesOptions struct {
 	FromMe bool
 	// ToMe includes only messages sent to the current user (provider-dependent).
 	ToMe bool
+
+	// Filter is an additional query expression, combined with the other options using AND.
+	Filter Expr
+}
+
+// Validate checks that the options can be turned into a query.
+func (o *SearchMessagesOptions) Validate() error {
+	switch o.Sort {
+	case SortByRelevance, SortByCreatedAsc, SortByCreatedDesc:
+	default:
+		return fmt.Errorf("%w: unknown sort order %q", ErrInvalidQuery, o.Sort)
+	}
+	if o.Interval != nil {
+		if _, _, err := o.Interval.Range(time.Now()); err != nil {
+			return err
+		}
+	}
+	if o.Filter == nil {
+		return nil
+	}
+	_, err := Compile(o.Filter)
+	return err
+}
+
+// TimeRange returns the sent-time range of the options in TimeZone:
+// the range of Interval containing now if set, StartTime and EndTime otherwise.
+func (o *SearchMessagesOptions) TimeRange(now time.Time) (start, end *time.Time, err error) {
+	loc := o.TimeZone
+	if loc == nil {
+		loc = time.UTC
+	}
+	if o.Interval != nil {
+		s, e, err := o.Interval.Range(now.In(loc))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &s, &e, nil
+	}
+	if o.StartTime != nil {
+		s := o.StartTime.In(loc)
+		start = &s
+	}
+	if o.EndTime != nil {
+		e := o.EndTime.In(loc)
+		end = &e
+	}
+	return start, end, nil
 }
 
 // SearchResult is a single search hit together with its location context.
@@ -117,13 +219,32 @@ type SearchResult struct {
 	TeamID *string
 	// ChatID is set when the message was found in a chat.
 	ChatID *string
+
+	// Summary is a snippet of the message with matched terms highlighted, if provided.
+	// Graph marks highlighted terms with <c0></c0> tags.
+	Summary *string
+	// Rank is the position of the hit in the provider's relevance order, if provided.
+	Rank *int32
+	// HitID is the provider's internal identifier of the hit, if provided.
+	HitID *string
 }
 
 // SearchResults is a paginated container of search hits.
+//
+// A page is filled up to the requested size across several provider pages when needed,
+// so it is only shorter than requested when MoreResultsAvailable is false
+// or the SearchConfig.MaxPages limit was reached.
 type SearchResults struct {
 	// Messages contains the list of hits for this page.
 	Messages []*SearchResult
 
 	// NextFrom is the pagination cursor/index to continue from (if available).
 	NextFrom *int32
+
+	// Total is the total number of hits reported by the provider, if known.
+	// For Graph it counts hits before those outside the searched team, channel or chat are dropped.
+	Total *int32
+
+	// MoreResultsAvailable reports whether searching again from NextFrom can return more hits.
+	MoreResultsAvailable bool
 }
@@ -1,17 +1,42 @@
 package search
 
+import "errors"
+
+// ErrLocalSearchUnavailable is returned when a local search is requested
+// but the local message store is not enabled.
+var ErrLocalSearchUnavailable = errors.New("local search requires the message store to be enabled")
+
+// DefaultMaxPages is the number of provider pages fetched to fill a single page of results
+// when SearchConfig.MaxPages is not set.
+const DefaultMaxPages = 5
+
 // SearchConfig configures how search operations are executed.
 //
 // MaxWorkers defines the maximum number of concurrent workers used to fetch
 // and enrich search results. Higher values can speed up searches but increase
 // resource usage and pressure on the upstream API.
+//
+// MaxPages limits how many provider pages are requested to fill a single page of results,
+// when hits are dropped because they fall outside the searched scope or were deleted.
+// Zero means DefaultMaxPages.
+//
+// Lite skips fetching the full message of every hit. Results then carry only what the search
+// response contains: the summary, the rank and a partial message without its body.
+//
+// Local runs the search against messages synced to the local message store
+// instead of the Graph search endpoint. It works offline and without indexing lag,
+// but only finds messages that were listed or fetched before.
 type SearchConfig struct {
 	MaxWorkers int
+	MaxPages   int
+	Lite       bool
+	Local      bool
 }
 
 // DefaultSearchConfig returns a SearchConfig initialized with sane defaults.
 func DefaultSearchConfig() *SearchConfig {
 	return &SearchConfig{
 		MaxWorkers: 8,
+		MaxPages:   DefaultMaxPages,
 	}
 }
@@ -1,17 +1,46 @@
 // Package setup provides setup utilities for the application.
-// It includes functionalities for managing peppers and clearing caches.
+// It includes functionalities for managing peppers, clearing, inspecting, exporting and importing caches.
 package setup
 
 import (
 	"github.com/pzsp-teams/lib/config"
 	"github.com/pzsp-teams/lib/internal/cacher"
+	"github.com/pzsp-teams/lib/internal/msgstore"
 )
 
 // ClearCache clears the cache based on the provided cache configuration.
+// If the message store is enabled, stored messages are removed as well.
 func ClearCache(cacheCfg *config.CacheConfig) error {
-	cacheHandler := cacher.GetCacheHandler(cacheCfg)
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return err
+	}
 	if cacheHandler == nil {
 		return nil
 	}
+	store, err := msgstore.GetMessageStore(cacheCfg)
+	if err != nil {
+		return err
+	}
+	if store != nil {
+		if err := store.Clear(); err != nil {
+			return err
+		}
+	}
 	return cacheHandler.Cacher.Clear()
 }
+
+// MigrateCache rewrites existing cache entries to the protection set in the provided cache configuration,
+// e.g. encrypts a cache file created before CacheProtectionEncrypt was enabled.
+//
+// Caches written with CacheProtectionHash cannot be migrated back, they have to be cleared instead.
+func MigrateCache(cacheCfg *config.CacheConfig) error {
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return err
+	}
+	if cacheHandler == nil {
+		return ErrCacheDisabled
+	}
+	return cacher.Migrate(cacheHandler)
+}
@@ -0,0 +1,109 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/cacher"
+)
+
+// ErrCacheDisabled is returned when an operation requires the cache, but caching is disabled.
+var ErrCacheDisabled = errors.New("cache is disabled")
+
+// CacheStats holds usage counters of the cache collected in the current process,
+// number of entries per key type (e.g. "team", "channel", "team-member") and the size of the cache file.
+type CacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Invalidations uint64
+	EntriesByType map[string]int
+	FileSize      int64
+}
+
+// CacheEntry represents a single cache entry.
+//
+// Key holds the reference part of the key - team and channel names are kept in plain text,
+// while member references are stored (and therefore listed) as peppered hashes.
+// With config.CacheProtectionHash all keys are listed hashed, with config.CacheProtectionEncrypt
+// keys and values are decrypted before listing.
+type CacheEntry struct {
+	Type   string
+	Key    string
+	Values []string
+}
+
+// GetCacheStats returns usage statistics of the cache based on the provided cache configuration.
+func GetCacheStats(cacheCfg *config.CacheConfig) (*CacheStats, error) {
+	inspector, err := getInspector(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := inspector.Stats()
+	if err != nil {
+		return nil, err
+	}
+	entriesByType := make(map[string]int, len(stats.EntriesByType))
+	for keyType, count := range stats.EntriesByType {
+		entriesByType[string(keyType)] = count
+	}
+	return &CacheStats{
+		Hits:          stats.Hits,
+		Misses:        stats.Misses,
+		Invalidations: stats.Invalidations,
+		EntriesByType: entriesByType,
+		FileSize:      stats.FileSize,
+	}, nil
+}
+
+// ListCacheEntries lists all entries stored in the cache, ordered by type and key.
+func ListCacheEntries(cacheCfg *config.CacheConfig) ([]CacheEntry, error) {
+	inspector, err := getInspector(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := inspector.Entries()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]CacheEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, CacheEntry{Type: string(e.Type), Key: e.Key, Values: e.Values})
+	}
+	return out, nil
+}
+
+// ExportCache writes all cache entries to w as a versioned JSON document,
+// independent of the underlying cache provider.
+func ExportCache(cacheCfg *config.CacheConfig, w io.Writer) error {
+	inspector, err := getInspector(cacheCfg)
+	if err != nil {
+		return err
+	}
+	return inspector.Export(w)
+}
+
+// ImportCache reads a document created by ExportCache from r and merges its entries into the cache.
+func ImportCache(cacheCfg *config.CacheConfig, r io.Reader) error {
+	inspector, err := getInspector(cacheCfg)
+	if err != nil {
+		return err
+	}
+	return inspector.Import(r)
+}
+
+func getInspector(cacheCfg *config.CacheConfig) (cacher.Inspector, error) {
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return nil, err
+	}
+	if cacheHandler == nil {
+		return nil, ErrCacheDisabled
+	}
+	inspector, ok := cacheHandler.Cacher.(cacher.Inspector)
+	if !ok {
+		return nil, fmt.Errorf("cache provider %q does not support inspection", cacheCfg.Provider)
+	}
+	return inspector, nil
+}
@@ -1,17 +1,81 @@
 package setup
 
-import "github.com/pzsp-teams/lib/internal/pepper"
+import (
+	"errors"
+	"strings"
+
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/cacher"
+	"github.com/pzsp-teams/lib/internal/pepper"
+)
+
+// ErrReadOnlyPepperSource is returned by SetPepperFor and RotatePepper when the new pepper cannot be persisted
+// in the configured source (environment variable or callback). RotatePepper has already re-keyed the cache
+// and the new pepper is used for the rest of the process - the source has to be updated by the caller.
+var ErrReadOnlyPepperSource = pepper.ErrReadOnlySource
+
+// ErrCacheCleared is returned by RotatePepper when the cache could neither be re-keyed nor restored and was cleared.
+var ErrCacheCleared = cacher.ErrCacheCleared
 
 // PepperExists checks whether a pepper is set in the system keyring.
 func PepperExists() bool {
-	exists, err := pepper.PepperExists()
+	return PepperExistsFor(nil)
+}
+
+// SetPepper sets the given pepper in the system keyring.
+func SetPepper(pep string) error {
+	return SetPepperFor(nil, pep)
+}
+
+// PepperExistsFor checks whether a pepper is set in the source configured in cacheCfg.Pepper
+// (system keyring when cacheCfg or its Pepper is nil).
+func PepperExistsFor(cacheCfg *config.CacheConfig) bool {
+	exists, err := pepper.Exists(pepperSource(cacheCfg))
 	if err != nil {
 		return false
 	}
 	return exists
 }
 
-// SetPepper sets the given pepper in the system keyring.
-func SetPepper(pep string) error {
-	return pepper.SetPepper(pep)
+// SetPepperFor stores the given pepper in the source configured in cacheCfg.Pepper
+// (system keyring when cacheCfg or its Pepper is nil).
+func SetPepperFor(cacheCfg *config.CacheConfig, pep string) error {
+	return pepper.Store(pepperSource(cacheCfg), pep)
+}
+
+// RotatePepper replaces the pepper with newPepper and re-keys existing cache entries.
+//
+// Team, channel and group chat entries are kept, entries keyed by hashed user references
+// are dropped and resolved again on next use. If the cache cannot be re-keyed, its entries are restored
+// and the pepper is left unchanged (the cache is cleared if restoring fails as well, see ErrCacheCleared).
+// The new pepper is stored in the source configured in cacheCfg.Pepper (system keyring by default).
+func RotatePepper(cacheCfg *config.CacheConfig, newPepper string) error {
+	newPepper = strings.TrimSpace(newPepper)
+	if newPepper == "" {
+		return errors.New("pepper cannot be empty")
+	}
+
+	src := pepperSource(cacheCfg)
+	cacheHandler, err := cacher.GetCacheHandler(cacheCfg)
+	if err != nil {
+		return err
+	}
+	if cacheHandler == nil {
+		return pepper.Store(src, newPepper)
+	}
+	if err := cacher.Rotate(cacheHandler, newPepper); err != nil {
+		return err
+	}
+	err = pepper.Store(src, newPepper)
+	if err != nil && !errors.Is(err, pepper.ErrReadOnlySource) {
+		return errors.Join(err, cacheHandler.Cacher.Clear())
+	}
+	return err
+}
+
+func pepperSource(cacheCfg *config.CacheConfig) pepper.Source {
+	if cacheCfg == nil {
+		return cacher.NewPepperSource(nil)
+	}
+	return cacher.NewPepperSource(cacheCfg.Pepper)
 }
@@ -0,0 +1,86 @@
+package subscriptions
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/internal/adapter"
+	"github.com/pzsp-teams/lib/internal/api"
+	"github.com/pzsp-teams/lib/internal/resources"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+)
+
+type ops struct {
+	subscriptionAPI api.SubscriptionAPI
+}
+
+func NewOps(subscriptionAPI api.SubscriptionAPI) subscriptionOps {
+	return &ops{
+		subscriptionAPI: subscriptionAPI,
+	}
+}
+
+func (o *ops) Create(ctx context.Context, resource string, opts *models.SubscriptionOptions, expiration time.Time) (*models.Subscription, error) {
+	types := make([]string, 0, len(opts.ChangeTypes))
+	for _, ct := range changeTypes(opts) {
+		types = append(types, string(ct))
+	}
+
+	body := msmodels.NewSubscription()
+	body.SetResource(&resource)
+	body.SetChangeType(util.Ptr(strings.Join(types, ",")))
+	body.SetNotificationUrl(&opts.NotificationURL)
+	body.SetExpirationDateTime(&expiration)
+	if opts.LifecycleNotificationURL != "" {
+		body.SetLifecycleNotificationUrl(&opts.LifecycleNotificationURL)
+	}
+	if opts.ClientState != "" {
+		body.SetClientState(&opts.ClientState)
+	}
+	if opts.IncludeResourceData {
+		body.SetIncludeResourceData(&opts.IncludeResourceData)
+		body.SetEncryptionCertificate(&opts.EncryptionCertificate)
+		body.SetEncryptionCertificateId(&opts.EncryptionCertificateID)
+	}
+
+	resp, requestErr := o.subscriptionAPI.Create(ctx, body)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	sub := adapter.MapGraphSubscription(resp)
+	if sub.ClientState == "" {
+		sub.ClientState = opts.ClientState
+	}
+	return sub, nil
+}
+
+func (o *ops) Renew(ctx context.Context, subscriptionID string, expiration time.Time) (*models.Subscription, error) {
+	patch := msmodels.NewSubscription()
+	patch.SetExpirationDateTime(&expiration)
+
+	resp, requestErr := o.subscriptionAPI.Update(ctx, subscriptionID, patch)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr, snd.WithResource(resources.Subscription, subscriptionID))
+	}
+	return adapter.MapGraphSubscription(resp), nil
+}
+
+func (o *ops) List(ctx context.Context) ([]*models.Subscription, error) {
+	resp, requestErr := o.subscriptionAPI.List(ctx)
+	if requestErr != nil {
+		return nil, snd.MapError(requestErr)
+	}
+	return util.MapSlices(resp.GetValue(), adapter.MapGraphSubscription), nil
+}
+
+func (o *ops) Delete(ctx context.Context, subscriptionID string) error {
+	requestErr := o.subscriptionAPI.Delete(ctx, subscriptionID)
+	if requestErr != nil {
+		return snd.MapError(requestErr, snd.WithResource(resources.Subscription, subscriptionID))
+	}
+	return nil
+}
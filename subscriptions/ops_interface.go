@@ -0,0 +1,15 @@
+package subscriptions
+
+import (
+	"context"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+type subscriptionOps interface {
+	Create(ctx context.Context, resource string, opts *models.SubscriptionOptions, expiration time.Time) (*models.Subscription, error)
+	Renew(ctx context.Context, subscriptionID string, expiration time.Time) (*models.Subscription, error)
+	List(ctx context.Context) ([]*models.Subscription, error)
+	Delete(ctx context.Context, subscriptionID string) error
+}
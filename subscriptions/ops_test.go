@@ -0,0 +1,145 @@
+package subscriptions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/testutil"
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newOpsSUT(t *testing.T, setup func(api *testutil.MockSubscriptionAPI)) (subscriptionOps, context.Context) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	apiMock := testutil.NewMockSubscriptionAPI(ctrl)
+	if setup != nil {
+		setup(apiMock)
+	}
+	return NewOps(apiMock), context.Background()
+}
+
+func newGraphSubscription(id string, expiration time.Time) msmodels.Subscriptionable {
+	sub := msmodels.NewSubscription()
+	sub.SetId(util.Ptr(id))
+	sub.SetResource(util.Ptr("/chats/c1/messages"))
+	sub.SetChangeType(util.Ptr("created,updated"))
+	sub.SetExpirationDateTime(&expiration)
+	return sub
+}
+
+func TestOps_Create_BuildsRequest(t *testing.T) {
+	t.Parallel()
+
+	exp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	o, ctx := newOpsSUT(t, func(api *testutil.MockSubscriptionAPI) {
+		api.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, body msmodels.Subscriptionable) (msmodels.Subscriptionable, *snd.RequestError) {
+				assert.Equal(t, "/chats/c1/messages", util.Deref(body.GetResource()))
+				assert.Equal(t, "created,updated,deleted", util.Deref(body.GetChangeType()))
+				assert.Equal(t, "https://example.com/hook", util.Deref(body.GetNotificationUrl()))
+				assert.Equal(t, "https://example.com/lifecycle", util.Deref(body.GetLifecycleNotificationUrl()))
+				assert.Equal(t, "secret", util.Deref(body.GetClientState()))
+				assert.Equal(t, exp, util.Deref(body.GetExpirationDateTime()))
+				assert.Nil(t, body.GetIncludeResourceData())
+				return newGraphSubscription("s1", exp), nil
+			}).
+			Times(1)
+	})
+
+	got, err := o.Create(ctx, "/chats/c1/messages", &models.SubscriptionOptions{
+		NotificationURL:          "https://example.com/hook",
+		LifecycleNotificationURL: "https://example.com/lifecycle",
+		ClientState:              "secret",
+	}, exp)
+	require.NoError(t, err)
+	assert.Equal(t, "s1", got.ID)
+	assert.Equal(t, "secret", got.ClientState, "client state is not returned by Graph and is taken from options")
+	assert.Equal(t, []models.ChangeType{models.ChangeTypeCreated, models.ChangeTypeUpdated}, got.ChangeTypes)
+}
+
+func TestOps_Create_WithResourceData(t *testing.T) {
+	t.Parallel()
+
+	exp := time.Now()
+	o, ctx := newOpsSUT(t, func(api *testutil.MockSubscriptionAPI) {
+		api.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, body msmodels.Subscriptionable) (msmodels.Subscriptionable, *snd.RequestError) {
+				assert.Equal(t, "created", util.Deref(body.GetChangeType()))
+				assert.True(t, util.Deref(body.GetIncludeResourceData()))
+				assert.Equal(t, "cert", util.Deref(body.GetEncryptionCertificate()))
+				assert.Equal(t, "cert-id", util.Deref(body.GetEncryptionCertificateId()))
+				return newGraphSubscription("s1", exp), nil
+			}).
+			Times(1)
+	})
+
+	_, err := o.Create(ctx, "/chats/c1/messages", &models.SubscriptionOptions{
+		NotificationURL:         "https://example.com/hook",
+		ChangeTypes:             []models.ChangeType{models.ChangeTypeCreated},
+		IncludeResourceData:     true,
+		EncryptionCertificate:   "cert",
+		EncryptionCertificateID: "cert-id",
+	}, exp)
+	require.NoError(t, err)
+}
+
+func TestOps_Renew_PatchesExpiration(t *testing.T) {
+	t.Parallel()
+
+	exp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	o, ctx := newOpsSUT(t, func(api *testutil.MockSubscriptionAPI) {
+		api.EXPECT().
+			Update(gomock.Any(), "s1", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, patch msmodels.Subscriptionable) (msmodels.Subscriptionable, *snd.RequestError) {
+				assert.Equal(t, exp, util.Deref(patch.GetExpirationDateTime()))
+				assert.Nil(t, patch.GetResource())
+				return newGraphSubscription("s1", exp), nil
+			}).
+			Times(1)
+	})
+
+	got, err := o.Renew(ctx, "s1", exp)
+	require.NoError(t, err)
+	assert.Equal(t, exp, got.ExpirationDateTime)
+}
+
+func TestOps_List(t *testing.T) {
+	t.Parallel()
+
+	exp := time.Now()
+	o, ctx := newOpsSUT(t, func(api *testutil.MockSubscriptionAPI) {
+		resp := msmodels.NewSubscriptionCollectionResponse()
+		resp.SetValue([]msmodels.Subscriptionable{newGraphSubscription("s1", exp), newGraphSubscription("s2", exp)})
+		api.EXPECT().List(gomock.Any()).Return(resp, nil).Times(1)
+	})
+
+	got, err := o.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "s2", got[1].ID)
+}
+
+func TestOps_Delete_MapsNotFound(t *testing.T) {
+	t.Parallel()
+
+	o, ctx := newOpsSUT(t, func(api *testutil.MockSubscriptionAPI) {
+		api.EXPECT().Delete(gomock.Any(), "s1").Return(testutil.ReqErr(404)).Times(1)
+	})
+
+	err := o.Delete(ctx, "s1")
+	var notFound *snd.ErrResourceNotFound
+	require.ErrorAs(t, err, &notFound)
+	assert.Contains(t, err.Error(), "s1")
+}
@@ -0,0 +1,319 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pzsp-teams/lib/chats"
+	"github.com/pzsp-teams/lib/config"
+	"github.com/pzsp-teams/lib/internal/resolver"
+	"github.com/pzsp-teams/lib/internal/resources"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/models"
+)
+
+const (
+	// DefaultLifetime is the lifetime requested for subscriptions when not configured.
+	DefaultLifetime = 55 * time.Minute
+	// DefaultRenewBefore is how long before expiry subscriptions are renewed when not configured.
+	DefaultRenewBefore = 10 * time.Minute
+)
+
+type service struct {
+	subscriptionOps subscriptionOps
+	teamResolver    resolver.TeamResolver
+	channelResolver resolver.ChannelResolver
+	chatResolver    resolver.ChatResolver
+	store           Store
+	lifetime        time.Duration
+	renewBefore     time.Duration
+	now             func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewService creates a new instance of the subscription service.
+// A nil store keeps subscriptions in memory; a nil cfg uses the defaults.
+func NewService(
+	subscriptionOps subscriptionOps,
+	tr resolver.TeamResolver,
+	cr resolver.ChannelResolver,
+	chr resolver.ChatResolver,
+	store Store,
+	cfg *config.SubscriptionConfig,
+) Service {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	s := &service{
+		subscriptionOps: subscriptionOps,
+		teamResolver:    tr,
+		channelResolver: cr,
+		chatResolver:    chr,
+		store:           store,
+		lifetime:        DefaultLifetime,
+		renewBefore:     DefaultRenewBefore,
+		now:             time.Now,
+	}
+	if cfg != nil && cfg.Lifetime > 0 {
+		s.lifetime = cfg.Lifetime
+	}
+	if cfg != nil && cfg.RenewBefore > 0 {
+		s.renewBefore = cfg.RenewBefore
+	}
+	return s
+}
+
+func (s *service) SubscribeChannelMessages(ctx context.Context, teamRef, channelRef string, opts *models.SubscriptionOptions) (*models.Subscription, error) {
+	params := []snd.Param{
+		snd.NewParam(resources.TeamRef, teamRef),
+		snd.NewParam(resources.ChannelRef, channelRef),
+	}
+	teamID, err := s.teamResolver.ResolveTeamRefToID(ctx, teamRef)
+	if err != nil {
+		return nil, snd.Wrap("SubscribeChannelMessages", err, params...)
+	}
+	channelID, err := s.channelResolver.ResolveChannelRefToID(ctx, teamID, channelRef)
+	if err != nil {
+		return nil, snd.Wrap("SubscribeChannelMessages", err, params...)
+	}
+
+	sub, err := s.subscribe(ctx, fmt.Sprintf("/teams/%s/channels/%s/messages", teamID, channelID), opts)
+	if err != nil {
+		return nil, snd.Wrap("SubscribeChannelMessages", err, params...)
+	}
+	return sub, nil
+}
+
+func (s *service) SubscribeChatMessages(ctx context.Context, chatRef chats.ChatRef, opts *models.SubscriptionOptions) (*models.Subscription, error) {
+	var chatID, ref string
+	var err error
+	switch r := chatRef.(type) {
+	case chats.GroupChatRef:
+		ref = r.Ref
+		chatID, err = s.chatResolver.ResolveGroupChatRefToID(ctx, r.Ref)
+	case chats.OneOnOneChatRef:
+		ref = r.Ref
+		chatID, err = s.chatResolver.ResolveOneOnOneChatRefToID(ctx, r.Ref)
+	default:
+		err = fmt.Errorf("unknown chat reference type")
+	}
+	if err != nil {
+		return nil, snd.Wrap("SubscribeChatMessages", err,
+			snd.NewParam(resources.ChatRef, ref),
+		)
+	}
+
+	sub, err := s.subscribe(ctx, fmt.Sprintf("/chats/%s/messages", chatID), opts)
+	if err != nil {
+		return nil, snd.Wrap("SubscribeChatMessages", err,
+			snd.NewParam(resources.ChatRef, ref),
+		)
+	}
+	return sub, nil
+}
+
+func (s *service) SubscribeAllChannelMessages(ctx context.Context, opts *models.SubscriptionOptions) (*models.Subscription, error) {
+	sub, err := s.subscribe(ctx, "/teams/getAllMessages", opts)
+	if err != nil {
+		return nil, snd.Wrap("SubscribeAllChannelMessages", err)
+	}
+	return sub, nil
+}
+
+func (s *service) List(ctx context.Context) ([]*models.Subscription, error) {
+	resp, err := s.subscriptionOps.List(ctx)
+	if err != nil {
+		return nil, snd.Wrap("List", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.store.Load()
+	if err != nil {
+		return nil, snd.Wrap("List", err)
+	}
+	for _, sub := range resp {
+		if i := indexOf(stored, sub.ID); i >= 0 && sub.ClientState == "" {
+			sub.ClientState = stored[i].ClientState
+		}
+	}
+	return resp, nil
+}
+
+func (s *service) Renew(ctx context.Context, subscriptionID string) (*models.Subscription, error) {
+	sub, err := s.renew(ctx, subscriptionID)
+	if err != nil {
+		return nil, snd.Wrap("Renew", err,
+			snd.NewParam(resources.SubscriptionRef, subscriptionID),
+		)
+	}
+	return sub, nil
+}
+
+func (s *service) Delete(ctx context.Context, subscriptionID string) error {
+	err := s.subscriptionOps.Delete(ctx, subscriptionID)
+	if err != nil && !isNotFound(err) {
+		return snd.Wrap("Delete", err,
+			snd.NewParam(resources.SubscriptionRef, subscriptionID),
+		)
+	}
+	if err := s.forget(subscriptionID); err != nil {
+		return snd.Wrap("Delete", err,
+			snd.NewParam(resources.SubscriptionRef, subscriptionID),
+		)
+	}
+	return nil
+}
+
+func (s *service) Run(ctx context.Context, handle func(RenewalEvent)) error {
+	interval := max(s.renewBefore/4, time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.renewDue(ctx, handle); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// renewDue renews stored subscriptions expiring within renewBefore.
+func (s *service) renewDue(ctx context.Context, handle func(RenewalEvent)) error {
+	s.mu.Lock()
+	stored, err := s.store.Load()
+	s.mu.Unlock()
+	if err != nil {
+		return snd.Wrap("Run", err)
+	}
+
+	deadline := s.now().Add(s.renewBefore)
+	for _, sub := range stored {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if sub.ExpirationDateTime.After(deadline) {
+			continue
+		}
+		renewed, err := s.renew(ctx, sub.ID)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if isNotFound(err) {
+				err = joinForgetErr(err, s.forget(sub.ID))
+			}
+			renewed = sub
+			err = snd.Wrap("Run", err, snd.NewParam(resources.SubscriptionRef, sub.ID))
+		}
+		if handle != nil {
+			handle(RenewalEvent{Subscription: renewed, Err: err})
+		}
+	}
+	return nil
+}
+
+func (s *service) subscribe(ctx context.Context, resource string, opts *models.SubscriptionOptions) (*models.Subscription, error) {
+	if err := validateOptions(opts); err != nil {
+		return nil, err
+	}
+	sub, err := s.subscriptionOps.Create(ctx, resource, opts, s.now().Add(s.lifetime))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Save(append(stored, sub)); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *service) renew(ctx context.Context, subscriptionID string) (*models.Subscription, error) {
+	sub, err := s.subscriptionOps.Renew(ctx, subscriptionID, s.now().Add(s.lifetime))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	i := indexOf(stored, subscriptionID)
+	if i < 0 {
+		return sub, nil
+	}
+	stored[i].ExpirationDateTime = sub.ExpirationDateTime
+	if err := s.store.Save(stored); err != nil {
+		return nil, err
+	}
+	if sub.ClientState == "" {
+		sub.ClientState = stored[i].ClientState
+	}
+	return sub, nil
+}
+
+func (s *service) forget(subscriptionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.store.Load()
+	if err != nil {
+		return err
+	}
+	i := indexOf(stored, subscriptionID)
+	if i < 0 {
+		return nil
+	}
+	return s.store.Save(slices.Delete(stored, i, i+1))
+}
+
+func validateOptions(o *models.SubscriptionOptions) error {
+	if o == nil || strings.TrimSpace(o.NotificationURL) == "" {
+		return fmt.Errorf("%w: missing notification URL", ErrInvalidOptions)
+	}
+	if o.IncludeResourceData && (o.EncryptionCertificate == "" || o.EncryptionCertificateID == "") {
+		return fmt.Errorf("%w: resource data requires an encryption certificate and its ID", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// changeTypes returns the change types of o, defaulting to all of them.
+func changeTypes(o *models.SubscriptionOptions) []models.ChangeType {
+	if len(o.ChangeTypes) == 0 {
+		return []models.ChangeType{models.ChangeTypeCreated, models.ChangeTypeUpdated, models.ChangeTypeDeleted}
+	}
+	return o.ChangeTypes
+}
+
+func indexOf(subs []*models.Subscription, id string) int {
+	return slices.IndexFunc(subs, func(sub *models.Subscription) bool { return sub.ID == id })
+}
+
+func isNotFound(err error) bool {
+	code, ok := snd.StatusCode(err)
+	return ok && code == http.StatusNotFound
+}
+
+func joinForgetErr(err, forgetErr error) error {
+	if forgetErr == nil {
+		return err
+	}
+	return fmt.Errorf("%w (removing from store: %v)", err, forgetErr)
+}
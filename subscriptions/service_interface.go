@@ -0,0 +1,57 @@
+// Package subscriptions manages Microsoft Graph change-notification subscriptions for channel and chat messages.
+//
+// Concepts:
+//   - A subscription delivers notifications about created, updated or deleted messages to a notification URL.
+//   - Supported resources are messages of a channel, messages of a chat and all channel messages
+//     across teams (/teams/getAllMessages, which requires application permissions).
+//   - Subscriptions expire after a short lifetime (at most 60 minutes for messages) and must be renewed.
+//     Run renews the subscriptions created by the service in the background before they expire.
+//   - Subscriptions created by the service are kept in a Store, so they are renewed again after a restart.
+//     Subscriptions which no longer exist are dropped from the Store when renewal fails with 404.
+//   - teamRef, channelRef and chatRef are references (IDs or names) resolved like in the channels and chats packages.
+package subscriptions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pzsp-teams/lib/chats"
+	"github.com/pzsp-teams/lib/models"
+)
+
+// ErrInvalidOptions is returned when subscription options are incomplete.
+var ErrInvalidOptions = errors.New("invalid subscription options")
+
+// RenewalEvent reports the result of a background renewal of a subscription.
+type RenewalEvent struct {
+	// Subscription is the renewed subscription, or the stored one when renewal failed.
+	Subscription *models.Subscription
+
+	// Err is the error of the renewal, if it failed.
+	Err error
+}
+
+// Service defines the interface for managing change-notification subscriptions.
+type Service interface {
+	// SubscribeChannelMessages subscribes to messages (and replies) of a channel.
+	SubscribeChannelMessages(ctx context.Context, teamRef, channelRef string, opts *models.SubscriptionOptions) (*models.Subscription, error)
+
+	// SubscribeChatMessages subscribes to messages of a chat.
+	SubscribeChatMessages(ctx context.Context, chatRef chats.ChatRef, opts *models.SubscriptionOptions) (*models.Subscription, error)
+
+	// SubscribeAllChannelMessages subscribes to messages of all channels of all teams in the tenant.
+	SubscribeAllChannelMessages(ctx context.Context, opts *models.SubscriptionOptions) (*models.Subscription, error)
+
+	// List returns the subscriptions of the application. Client states are filled in from the Store.
+	List(ctx context.Context) ([]*models.Subscription, error)
+
+	// Renew extends the expiry of a subscription by the configured lifetime.
+	Renew(ctx context.Context, subscriptionID string) (*models.Subscription, error)
+
+	// Delete deletes a subscription and removes it from the Store.
+	Delete(ctx context.Context, subscriptionID string) error
+
+	// Run renews stored subscriptions before they expire until ctx is done.
+	// handle, if not nil, is called after every renewal attempt.
+	Run(ctx context.Context, handle func(RenewalEvent)) error
+}
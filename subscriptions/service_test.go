@@ -0,0 +1,299 @@
+package subscriptions
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/chats"
+	"github.com/pzsp-teams/lib/config"
+	snd "github.com/pzsp-teams/lib/internal/sender"
+	"github.com/pzsp-teams/lib/internal/testutil"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var testNow = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+type sutDeps struct {
+	ops     *testutil.MocksubscriptionOps
+	teams   *testutil.MockTeamResolver
+	channel *testutil.MockChannelResolver
+	chats   *testutil.MockChatResolver
+}
+
+func newSUT(t *testing.T, store Store, setup func(d sutDeps)) (*service, context.Context) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	d := sutDeps{
+		ops:     testutil.NewMocksubscriptionOps(ctrl),
+		teams:   testutil.NewMockTeamResolver(ctrl),
+		channel: testutil.NewMockChannelResolver(ctrl),
+		chats:   testutil.NewMockChatResolver(ctrl),
+	}
+	if setup != nil {
+		setup(d)
+	}
+
+	svc := NewService(d.ops, d.teams, d.channel, d.chats, store, &config.SubscriptionConfig{
+		Lifetime:    time.Hour,
+		RenewBefore: 10 * time.Minute,
+	}).(*service)
+	svc.now = func() time.Time { return testNow }
+	return svc, context.Background()
+}
+
+func validOptions() *models.SubscriptionOptions {
+	return &models.SubscriptionOptions{NotificationURL: "https://example.com/hook", ClientState: "secret"}
+}
+
+func TestService_SubscribeChannelMessages_ResolvesAndStores(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	svc, ctx := newSUT(t, store, func(d sutDeps) {
+		d.teams.EXPECT().ResolveTeamRefToID(gomock.Any(), "Team").Return("t1", nil).Times(1)
+		d.channel.EXPECT().ResolveChannelRefToID(gomock.Any(), "t1", "General").Return("c1", nil).Times(1)
+		d.ops.EXPECT().
+			Create(gomock.Any(), "/teams/t1/channels/c1/messages", gomock.Any(), testNow.Add(time.Hour)).
+			Return(&models.Subscription{ID: "s1", ClientState: "secret"}, nil).
+			Times(1)
+	})
+
+	got, err := svc.SubscribeChannelMessages(ctx, "Team", "General", validOptions())
+	require.NoError(t, err)
+	assert.Equal(t, "s1", got.ID)
+
+	stored, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "secret", stored[0].ClientState)
+}
+
+func TestService_SubscribeChatMessages_ResolvesRefs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		chatRef chats.ChatRef
+		expect  func(r *testutil.MockChatResolver)
+	}{
+		{
+			name:    "group chat",
+			chatRef: chats.GroupChatRef{Ref: "Topic"},
+			expect: func(r *testutil.MockChatResolver) {
+				r.EXPECT().ResolveGroupChatRefToID(gomock.Any(), "Topic").Return("chat-1", nil).Times(1)
+			},
+		},
+		{
+			name:    "one-on-one chat",
+			chatRef: chats.OneOnOneChatRef{Ref: "user@example.com"},
+			expect: func(r *testutil.MockChatResolver) {
+				r.EXPECT().ResolveOneOnOneChatRefToID(gomock.Any(), "user@example.com").Return("chat-1", nil).Times(1)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, ctx := newSUT(t, nil, func(d sutDeps) {
+				tc.expect(d.chats)
+				d.ops.EXPECT().
+					Create(gomock.Any(), "/chats/chat-1/messages", gomock.Any(), gomock.Any()).
+					Return(&models.Subscription{ID: "s1"}, nil).
+					Times(1)
+			})
+
+			_, err := svc.SubscribeChatMessages(ctx, tc.chatRef, validOptions())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_SubscribeAllChannelMessages(t *testing.T) {
+	t.Parallel()
+
+	svc, ctx := newSUT(t, nil, func(d sutDeps) {
+		d.ops.EXPECT().
+			Create(gomock.Any(), "/teams/getAllMessages", gomock.Any(), gomock.Any()).
+			Return(nil, testutil.ReqErr(403)).
+			Times(1)
+	})
+
+	got, err := svc.SubscribeAllChannelMessages(ctx, validOptions())
+	require.Nil(t, got)
+	testutil.RequireReqErrCode(t, err, 403)
+	require.ErrorContains(t, err, "SubscribeAllChannelMessages")
+}
+
+func TestService_Subscribe_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts *models.SubscriptionOptions
+	}{
+		{name: "nil options"},
+		{name: "missing notification URL", opts: &models.SubscriptionOptions{ClientState: "secret"}},
+		{
+			name: "resource data without certificate",
+			opts: &models.SubscriptionOptions{NotificationURL: "https://example.com/hook", IncludeResourceData: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc, ctx := newSUT(t, nil, nil)
+			_, err := svc.SubscribeAllChannelMessages(ctx, tc.opts)
+			require.ErrorIs(t, err, ErrInvalidOptions)
+		})
+	}
+}
+
+func TestService_List_FillsClientState(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	require.NoError(t, store.Save([]*models.Subscription{{ID: "s1", ClientState: "secret"}}))
+	svc, ctx := newSUT(t, store, func(d sutDeps) {
+		d.ops.EXPECT().List(gomock.Any()).Return([]*models.Subscription{{ID: "s1"}, {ID: "s2"}}, nil).Times(1)
+	})
+
+	got, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "secret", got[0].ClientState)
+	assert.Empty(t, got[1].ClientState)
+}
+
+func TestService_Renew_UpdatesStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	require.NoError(t, store.Save([]*models.Subscription{{ID: "s1", ClientState: "secret", ExpirationDateTime: testNow}}))
+	renewed := testNow.Add(time.Hour)
+	svc, ctx := newSUT(t, store, func(d sutDeps) {
+		d.ops.EXPECT().Renew(gomock.Any(), "s1", renewed).
+			Return(&models.Subscription{ID: "s1", ExpirationDateTime: renewed}, nil).
+			Times(1)
+	})
+
+	got, err := svc.Renew(ctx, "s1")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", got.ClientState)
+
+	stored, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, renewed, stored[0].ExpirationDateTime)
+}
+
+func TestService_Delete_RemovesFromStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		apiErr error
+	}{
+		{name: "deleted"},
+		{name: "already gone", apiErr: snd.MapError(testutil.ReqErr(404))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewMemoryStore()
+			require.NoError(t, store.Save([]*models.Subscription{{ID: "s1"}, {ID: "s2"}}))
+			svc, ctx := newSUT(t, store, func(d sutDeps) {
+				d.ops.EXPECT().Delete(gomock.Any(), "s1").Return(tc.apiErr).Times(1)
+			})
+
+			require.NoError(t, svc.Delete(ctx, "s1"))
+			stored, err := store.Load()
+			require.NoError(t, err)
+			require.Len(t, stored, 1)
+			assert.Equal(t, "s2", stored[0].ID)
+		})
+	}
+}
+
+func TestService_Delete_KeepsStoreOnError(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	require.NoError(t, store.Save([]*models.Subscription{{ID: "s1"}}))
+	svc, ctx := newSUT(t, store, func(d sutDeps) {
+		d.ops.EXPECT().Delete(gomock.Any(), "s1").Return(testutil.ReqErr(500)).Times(1)
+	})
+
+	err := svc.Delete(ctx, "s1")
+	testutil.RequireReqErrCode(t, err, 500)
+	stored, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
+
+func TestService_Run_RenewsDueSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	require.NoError(t, store.Save([]*models.Subscription{
+		{ID: "due", ExpirationDateTime: testNow.Add(5 * time.Minute)},
+		{ID: "gone", ExpirationDateTime: testNow.Add(time.Minute)},
+		{ID: "fresh", ExpirationDateTime: testNow.Add(30 * time.Minute)},
+	}))
+	renewed := testNow.Add(time.Hour)
+	svc, _ := newSUT(t, store, func(d sutDeps) {
+		d.ops.EXPECT().Renew(gomock.Any(), "due", renewed).
+			Return(&models.Subscription{ID: "due", ExpirationDateTime: renewed}, nil).
+			Times(1)
+		d.ops.EXPECT().Renew(gomock.Any(), "gone", renewed).
+			Return(nil, snd.MapError(testutil.ReqErr(404))).
+			Times(1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var events []RenewalEvent
+	done := make(chan error, 1)
+	go func() {
+		done <- svc.Run(ctx, func(e RenewalEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+			if len(events) == 2 {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after cancel")
+	}
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "due", events[0].Subscription.ID)
+	require.NoError(t, events[0].Err)
+	assert.Equal(t, "gone", events[1].Subscription.ID)
+	require.Error(t, events[1].Err)
+
+	stored, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, "due", stored[0].ID)
+	assert.Equal(t, renewed, stored[0].ExpirationDateTime)
+	assert.Equal(t, "fresh", stored[1].ID)
+}
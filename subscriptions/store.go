@@ -0,0 +1,113 @@
+package subscriptions
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// Store keeps the subscriptions created by the service, so they are renewed after a restart.
+type Store interface {
+	// Load returns all stored subscriptions.
+	Load() ([]*models.Subscription, error)
+	// Save replaces the stored subscriptions.
+	Save(subscriptions []*models.Subscription) error
+}
+
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a Store keeping subscriptions in a JSON file.
+// The file holds client states, so it is readable by its owner only.
+func NewFileStore(path string) Store {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) Load() ([]*models.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []*models.Subscription
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *fileStore) Save(subscriptions []*models.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(subscriptions)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+type memoryStore struct {
+	mu            sync.Mutex
+	subscriptions []*models.Subscription
+}
+
+// NewMemoryStore creates a Store keeping subscriptions in memory only.
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+func (s *memoryStore) Load() ([]*models.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneSubscriptions(s.subscriptions), nil
+}
+
+func (s *memoryStore) Save(subscriptions []*models.Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions = cloneSubscriptions(subscriptions)
+	return nil
+}
+
+func cloneSubscriptions(in []*models.Subscription) []*models.Subscription {
+	out := make([]*models.Subscription, 0, len(in))
+	for _, sub := range in {
+		c := *sub
+		c.ChangeTypes = slices.Clone(sub.ChangeTypes)
+		out = append(out, &c)
+	}
+	return out
+}
+
+// DefaultStatePath returns the default location of the subscription state file:
+// next to the cache file when cachePath is set, otherwise in the user cache directory.
+func DefaultStatePath(cachePath *string) string {
+	if cachePath != nil {
+		return filepath.Join(filepath.Dir(*cachePath), "subscriptions.json")
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "pzsp-teams-subscriptions.json"
+	}
+	return filepath.Join(dir, "pzsp-teams", "subscriptions.json")
+}
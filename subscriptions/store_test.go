@@ -0,0 +1,64 @@
+package subscriptions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/internal/util"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", "subscriptions.json")
+	store := NewFileStore(path)
+
+	got, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	want := []*models.Subscription{{
+		ID:                 "s1",
+		Resource:           "/chats/c1/messages",
+		ChangeTypes:        []models.ChangeType{models.ChangeTypeCreated},
+		ClientState:        "secret",
+		ExpirationDateTime: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+	require.NoError(t, store.Save(want))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	got, err = NewFileStore(path).Load()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestMemoryStore_ReturnsCopies(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+	require.NoError(t, store.Save([]*models.Subscription{{ID: "s1"}}))
+
+	got, err := store.Load()
+	require.NoError(t, err)
+	got[0].ID = "changed"
+
+	got, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "s1", got[0].ID)
+}
+
+func TestDefaultStatePath(t *testing.T) {
+	t.Parallel()
+
+	got := DefaultStatePath(util.Ptr(filepath.Join("cache", "cache.json")))
+	assert.Equal(t, filepath.Join("cache", "subscriptions.json"), got)
+	assert.Equal(t, "subscriptions.json", filepath.Base(DefaultStatePath(nil)))
+}
@@ -226,7 +226,7 @@ func (o *opsWithCache) addMembersToCache(teamID string, members ...models.Member
 		if util.AnyBlank(member.Email) {
 			continue
 		}
-		key := cacher.NewTeamMemberKey(teamID, member.Email, nil)
+		key := cacher.NewTeamMemberKey(teamID, member.Email, o.cacheHandler.Pepper())
 		_ = o.cacheHandler.Cacher.Set(key, member.ID)
 	}
 }
@@ -235,6 +235,6 @@ func (o *opsWithCache) removeMemberFromCache(teamID, userRef string) {
 	if util.AnyBlank(teamID, userRef) {
 		return
 	}
-	key := cacher.NewTeamMemberKey(teamID, userRef, nil)
+	key := cacher.NewTeamMemberKey(teamID, userRef, o.cacheHandler.Pepper())
 	_ = o.cacheHandler.Cacher.Invalidate(key)
 }
@@ -0,0 +1,123 @@
+// Package watch polls channels and chats for new and edited messages, as a fallback
+// for environments where change notifications (package webhook) cannot be received.
+//
+// Concepts:
+//   - A Target is a channel (optionally with replies) or a chat. Targets are polled through
+//     channels.Service and chats.Service, so caching and reference resolution apply as usual.
+//   - Every poll reads the newest page of messages (Config.Top) and, for channel targets with replies,
+//     the newest page of replies of every thread on that page.
+//   - Messages are de-duplicated by ID and last modification time: an unseen message is reported
+//     as created, a seen message modified since as edited. The first poll of a target only records
+//     the current messages, unless Config.EmitExisting is set. Only messages returned by the latest poll
+//     are remembered, so a message dropping off the page and coming back is reported as created again.
+//   - Each target is polled on its own adaptive interval: it drops to Config.MinInterval after a poll
+//     that found changes and doubles up to Config.MaxInterval after polls that found none or failed.
+//   - Events are delivered on a channel, which is closed once ctx is done and polling has stopped.
+package watch
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/pzsp-teams/lib/chats"
+	"github.com/pzsp-teams/lib/models"
+)
+
+const (
+	// DefaultMinInterval is the polling interval used when Config.MinInterval is not set.
+	DefaultMinInterval = 10 * time.Second
+	// DefaultMaxInterval is the longest polling interval used when Config.MaxInterval is not set.
+	DefaultMaxInterval = 2 * time.Minute
+	// DefaultTop is the number of messages read by a poll when Config.Top is not set.
+	DefaultTop int32 = 20
+)
+
+var (
+	// ErrInvalidTarget is returned when a target is neither a channel nor a chat.
+	ErrInvalidTarget = errors.New("invalid watch target")
+	// ErrMissingService is returned when targets need a service that is not configured.
+	ErrMissingService = errors.New("missing service for watch target")
+)
+
+// ChannelLister lists channel messages and replies; channels.Service implements it.
+type ChannelLister interface {
+	ListMessages(ctx context.Context, teamRef, channelRef string, opts *models.ListMessagesOptions, includeSystem bool, nextLink *string) (*models.MessageCollection, error)
+	ListReplies(ctx context.Context, teamRef, channelRef, messageID string, top *int32, includeSystem bool, nextLink *string) (*models.MessageCollection, error)
+}
+
+// ChatLister lists chat messages; chats.Service implements it.
+type ChatLister interface {
+	ListMessages(ctx context.Context, chatRef chats.ChatRef, includeSystem bool, nextLink *string) (*models.MessageCollection, error)
+}
+
+// Target is a channel or chat to watch. Create it with ChannelTarget or ChatTarget.
+type Target struct {
+	TeamRef        string
+	ChannelRef     string
+	IncludeReplies bool
+	ChatRef        chats.ChatRef
+}
+
+// ChannelTarget returns a target watching messages of a channel, and their replies if includeReplies is set.
+func ChannelTarget(teamRef, channelRef string, includeReplies bool) Target {
+	return Target{TeamRef: teamRef, ChannelRef: channelRef, IncludeReplies: includeReplies}
+}
+
+// ChatTarget returns a target watching messages of a chat.
+func ChatTarget(chatRef chats.ChatRef) Target {
+	return Target{ChatRef: chatRef}
+}
+
+func (t Target) isChat() bool {
+	return t.ChatRef != nil
+}
+
+// Config configures a Watcher.
+type Config struct {
+	// Channels lists channel messages; required for channel targets.
+	Channels ChannelLister
+
+	// Chats lists chat messages; required for chat targets.
+	Chats ChatLister
+
+	// MinInterval is the shortest time between polls of a target; defaults to DefaultMinInterval.
+	MinInterval time.Duration
+
+	// MaxInterval is the longest time between polls of a target; defaults to DefaultMaxInterval.
+	MaxInterval time.Duration
+
+	// Top is the number of newest messages (and replies per thread) read by a poll; defaults to DefaultTop.
+	// Chats are always read one page at a time, as sized by Graph.
+	Top int32
+
+	// EmitExisting reports the messages found by the first poll of a target as created.
+	EmitExisting bool
+}
+
+// EventType is the kind of a watch event.
+type EventType string
+
+const (
+	// EventCreated reports a message not seen before.
+	EventCreated EventType = "created"
+	// EventEdited reports a message modified since it was last seen.
+	EventEdited EventType = "edited"
+	// EventError reports a failed poll; polling of the target continues.
+	EventError EventType = "error"
+)
+
+// Event is a change found by polling a target.
+type Event struct {
+	Type   EventType
+	Target Target
+
+	// Message is the created or edited message.
+	Message *models.Message
+
+	// ReplyToID is the ID of the thread's root message when Message is a channel reply.
+	ReplyToID string
+
+	// Err is the error of a failed poll.
+	Err error
+}
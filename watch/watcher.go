@@ -0,0 +1,219 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// Watcher polls targets for new and edited messages. Create it with New.
+type Watcher struct {
+	cfg Config
+}
+
+// New creates a Watcher. A nil cfg uses the defaults and no services.
+func New(cfg *Config) *Watcher {
+	w := &Watcher{}
+	if cfg != nil {
+		w.cfg = *cfg
+	}
+	if w.cfg.MinInterval <= 0 {
+		w.cfg.MinInterval = DefaultMinInterval
+	}
+	if w.cfg.MaxInterval <= 0 {
+		w.cfg.MaxInterval = DefaultMaxInterval
+	}
+	w.cfg.MaxInterval = max(w.cfg.MaxInterval, w.cfg.MinInterval)
+	if w.cfg.Top <= 0 {
+		w.cfg.Top = DefaultTop
+	}
+	return w
+}
+
+// Watch starts polling targets and returns the channel delivering their events.
+// The channel is closed once ctx is done and all polling has stopped.
+func (w *Watcher) Watch(ctx context.Context, targets ...Target) (<-chan Event, error) {
+	for _, t := range targets {
+		if err := w.validate(t); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make(chan Event)
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Go(func() {
+			w.loop(ctx, t, func(e Event) bool {
+				select {
+				case out <- e:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, nil
+}
+
+func (w *Watcher) validate(t Target) error {
+	switch {
+	case t.isChat():
+		if w.cfg.Chats == nil {
+			return fmt.Errorf("%w: chats", ErrMissingService)
+		}
+	case t.TeamRef != "" && t.ChannelRef != "":
+		if w.cfg.Channels == nil {
+			return fmt.Errorf("%w: channels", ErrMissingService)
+		}
+	default:
+		return ErrInvalidTarget
+	}
+	return nil
+}
+
+func (w *Watcher) loop(ctx context.Context, t Target, emit func(Event) bool) {
+	seen := newSeenSet()
+	interval := w.cfg.MinInterval
+	first := true
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		events, err := w.poll(ctx, t, seen)
+		if ctx.Err() != nil {
+			return
+		}
+		if first && !w.cfg.EmitExisting {
+			events = nil
+		}
+		if err == nil {
+			first = false
+		}
+		changed := len(events) > 0
+		if err != nil {
+			events = append(events, Event{Type: EventError, Target: t, Err: err})
+		}
+
+		for _, e := range events {
+			if !emit(e) {
+				return
+			}
+		}
+
+		interval = w.next(interval, changed)
+		timer.Reset(interval)
+	}
+}
+
+// next returns the interval after a poll: the minimum after activity, otherwise double the current one.
+func (w *Watcher) next(interval time.Duration, active bool) time.Duration {
+	if active {
+		return w.cfg.MinInterval
+	}
+	return min(interval*2, w.cfg.MaxInterval)
+}
+
+// poll reads the newest messages of the target and returns events for those changed since seen.
+// When reading replies fails, the events found so far are returned with the error.
+func (w *Watcher) poll(ctx context.Context, t Target, seen *seenSet) ([]Event, error) {
+	if t.isChat() {
+		page, err := w.cfg.Chats.ListMessages(ctx, t.ChatRef, false, nil)
+		if err != nil {
+			return nil, err
+		}
+		return seen.diff(t, "", page.Messages), nil
+	}
+
+	top := w.cfg.Top
+	page, err := w.cfg.Channels.ListMessages(ctx, t.TeamRef, t.ChannelRef, &models.ListMessagesOptions{Top: &top}, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	events := seen.diff(t, "", page.Messages)
+	seen.prune(page.Messages)
+	if !t.IncludeReplies {
+		return events, nil
+	}
+	for _, msg := range page.Messages {
+		if msg == nil {
+			continue
+		}
+		replies, err := w.cfg.Channels.ListReplies(ctx, t.TeamRef, t.ChannelRef, msg.ID, &top, false, nil)
+		if err != nil {
+			return events, err
+		}
+		events = append(events, seen.diff(t, msg.ID, replies.Messages)...)
+	}
+	return events, nil
+}
+
+// seenSet remembers the last modification time of messages returned by the latest poll of every thread
+// in a target, keyed by the ID of the message replied to ("" for top-level messages).
+// Only messages on the polled pages can be reported, so older ones are forgotten.
+type seenSet struct {
+	threads map[string]map[string]time.Time
+}
+
+func newSeenSet() *seenSet {
+	return &seenSet{threads: make(map[string]map[string]time.Time)}
+}
+
+// diff replaces the thread's messages with msgs and returns events for those not seen before
+// or modified since, oldest first.
+func (s *seenSet) diff(t Target, replyToID string, msgs []*models.Message) []Event {
+	last := s.threads[replyToID]
+	current := make(map[string]time.Time, len(msgs))
+	var events []Event
+	for i := len(msgs) - 1; i >= 0; i-- {
+		msg := msgs[i]
+		if msg == nil || msg.ID == "" {
+			continue
+		}
+		modified := msg.CreatedDateTime
+		if msg.LastModifiedDateTime != nil {
+			modified = *msg.LastModifiedDateTime
+		}
+
+		previous, ok := last[msg.ID]
+		switch {
+		case !ok:
+			events = append(events, Event{Type: EventCreated, Target: t, Message: msg, ReplyToID: replyToID})
+		case modified.After(previous):
+			events = append(events, Event{Type: EventEdited, Target: t, Message: msg, ReplyToID: replyToID})
+		default:
+			modified = previous
+		}
+		current[msg.ID] = modified
+	}
+	s.threads[replyToID] = current
+	return events
+}
+
+// prune forgets replies of threads whose messages are no longer on the polled page.
+func (s *seenSet) prune(msgs []*models.Message) {
+	onPage := make(map[string]struct{}, len(msgs))
+	for _, msg := range msgs {
+		if msg != nil {
+			onPage[msg.ID] = struct{}{}
+		}
+	}
+	for replyToID := range s.threads {
+		if _, ok := onPage[replyToID]; !ok && replyToID != "" {
+			delete(s.threads, replyToID)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pzsp-teams/lib/chats"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func msg(id string, modifiedAfter time.Duration) *models.Message {
+	modified := base.Add(modifiedAfter)
+	return &models.Message{ID: id, CreatedDateTime: base, LastModifiedDateTime: &modified}
+}
+
+// script returns the pages of consecutive polls, repeating the last one.
+type script struct {
+	mu    sync.Mutex
+	pages [][]*models.Message
+	errs  []error
+	calls int
+}
+
+func (s *script) next() (*models.MessageCollection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := min(s.calls, len(s.pages)-1)
+	s.calls++
+	if i < len(s.errs) && s.errs[i] != nil {
+		return nil, s.errs[i]
+	}
+	return &models.MessageCollection{Messages: s.pages[i]}, nil
+}
+
+type fakeChannels struct {
+	messages *script
+	replies  map[string]*script
+}
+
+func (f *fakeChannels) ListMessages(context.Context, string, string, *models.ListMessagesOptions, bool, *string) (*models.MessageCollection, error) {
+	return f.messages.next()
+}
+
+func (f *fakeChannels) ListReplies(_ context.Context, _, _, messageID string, _ *int32, _ bool, _ *string) (*models.MessageCollection, error) {
+	if s, ok := f.replies[messageID]; ok {
+		return s.next()
+	}
+	return &models.MessageCollection{}, nil
+}
+
+type fakeChats struct {
+	messages *script
+}
+
+func (f *fakeChats) ListMessages(context.Context, chats.ChatRef, bool, *string) (*models.MessageCollection, error) {
+	return f.messages.next()
+}
+
+func fastConfig() *Config {
+	return &Config{MinInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond}
+}
+
+func collect(t *testing.T, events <-chan Event, n int) []Event {
+	t.Helper()
+
+	var out []Event
+	timeout := time.After(5 * time.Second)
+	for len(out) < n {
+		select {
+		case e, ok := <-events:
+			require.True(t, ok, "events closed after %d events", len(out))
+			out = append(out, e)
+		case <-timeout:
+			t.Fatalf("got %d of %d events", len(out), n)
+		}
+	}
+	return out
+}
+
+func TestWatcher_ChannelCreatedAndEdited(t *testing.T) {
+	t.Parallel()
+
+	cfg := fastConfig()
+	cfg.Channels = &fakeChannels{
+		messages: &script{pages: [][]*models.Message{
+			{msg("m1", 0)},
+			{msg("m2", 0), msg("m1", 0)},
+			{msg("m2", 0), msg("m1", time.Minute)},
+		}},
+		replies: map[string]*script{
+			"m2": {pages: [][]*models.Message{nil, {msg("r1", 0)}}},
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	target := ChannelTarget("team", "channel", true)
+	events, err := New(cfg).Watch(ctx, target)
+	require.NoError(t, err)
+
+	got := collect(t, events, 3)
+	assert.Equal(t, EventCreated, got[0].Type)
+	assert.Equal(t, "m2", got[0].Message.ID)
+	assert.Equal(t, target, got[0].Target)
+	assert.Equal(t, EventEdited, got[1].Type)
+	assert.Equal(t, "m1", got[1].Message.ID)
+	assert.Equal(t, EventCreated, got[2].Type, "replies follow top-level messages of the same poll")
+	assert.Equal(t, "r1", got[2].Message.ID)
+	assert.Equal(t, "m2", got[2].ReplyToID)
+}
+
+func TestWatcher_ChatEmitExisting(t *testing.T) {
+	t.Parallel()
+
+	cfg := fastConfig()
+	cfg.EmitExisting = true
+	cfg.Chats = &fakeChats{messages: &script{pages: [][]*models.Message{{msg("m2", 0), msg("m1", 0)}}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := New(cfg).Watch(ctx, ChatTarget(chats.GroupChatRef{Ref: "Topic"}))
+	require.NoError(t, err)
+
+	got := collect(t, events, 2)
+	assert.Equal(t, "m1", got[0].Message.ID, "older messages are reported first")
+	assert.Equal(t, "m2", got[1].Message.ID)
+}
+
+func TestWatcher_ReportsErrorsAndKeepsPolling(t *testing.T) {
+	t.Parallel()
+
+	boom := errors.New("boom")
+	cfg := fastConfig()
+	cfg.Chats = &fakeChats{messages: &script{
+		pages: [][]*models.Message{nil, {msg("m1", 0)}, {msg("m2", 0), msg("m1", 0)}},
+		errs:  []error{nil, boom},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := New(cfg).Watch(ctx, ChatTarget(chats.OneOnOneChatRef{Ref: "user"}))
+	require.NoError(t, err)
+
+	got := collect(t, events, 3)
+	assert.Equal(t, EventError, got[0].Type)
+	require.ErrorIs(t, got[0].Err, boom)
+	assert.Equal(t, EventCreated, got[1].Type)
+	assert.Equal(t, "m1", got[1].Message.ID)
+	assert.Equal(t, "m2", got[2].Message.ID)
+}
+
+func TestWatcher_ClosesEventsOnCancel(t *testing.T) {
+	t.Parallel()
+
+	cfg := fastConfig()
+	cfg.Chats = &fakeChats{messages: &script{pages: [][]*models.Message{nil}}}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := New(cfg).Watch(ctx, ChatTarget(chats.GroupChatRef{Ref: "a"}), ChatTarget(chats.GroupChatRef{Ref: "b"}))
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("events not closed after cancel")
+	}
+}
+
+func TestWatcher_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     *Config
+		target  Target
+		wantErr error
+	}{
+		{name: "empty target", cfg: &Config{Chats: &fakeChats{}}, target: Target{}, wantErr: ErrInvalidTarget},
+		{name: "channel without service", cfg: &Config{Chats: &fakeChats{}}, target: ChannelTarget("t", "c", false), wantErr: ErrMissingService},
+		{name: "chat without service", cfg: nil, target: ChatTarget(chats.GroupChatRef{Ref: "x"}), wantErr: ErrMissingService},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			events, err := New(tc.cfg).Watch(context.Background(), tc.target)
+			require.ErrorIs(t, err, tc.wantErr)
+			assert.Nil(t, events)
+		})
+	}
+}
+
+func TestWatcher_Next(t *testing.T) {
+	t.Parallel()
+
+	w := New(&Config{MinInterval: time.Second, MaxInterval: 5 * time.Second})
+	assert.Equal(t, 2*time.Second, w.next(time.Second, false))
+	assert.Equal(t, 5*time.Second, w.next(4*time.Second, false))
+	assert.Equal(t, time.Second, w.next(4*time.Second, true))
+
+	defaults := New(nil)
+	assert.Equal(t, DefaultMinInterval, defaults.cfg.MinInterval)
+	assert.Equal(t, DefaultMaxInterval, defaults.cfg.MaxInterval)
+	assert.Equal(t, DefaultTop, defaults.cfg.Top)
+}
+
+func TestSeenSet_KeepsLatestPoll(t *testing.T) {
+	t.Parallel()
+
+	seen := newSeenSet()
+	target := Target{TeamRef: "team", ChannelRef: "chan", IncludeReplies: true}
+
+	seen.diff(target, "", []*models.Message{msg("m2", 0), msg("m1", 0)})
+	seen.diff(target, "m1", []*models.Message{msg("r1", 0)})
+	seen.diff(target, "m2", []*models.Message{msg("r2", 0)})
+
+	page := []*models.Message{msg("m3", 0), msg("m2", 0)}
+	events := seen.diff(target, "", page)
+	seen.prune(page)
+	require.Len(t, events, 1)
+	assert.Equal(t, "m3", events[0].Message.ID)
+
+	assert.Len(t, seen.threads, 2, "replies of m1 are forgotten once it left the page")
+	assert.NotContains(t, seen.threads, "m1")
+	assert.Equal(t, map[string]time.Time{"m3": base, "m2": base}, seen.threads[""])
+	assert.Contains(t, seen.threads["m2"], "r2")
+}
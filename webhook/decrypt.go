@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	jsonserialization "github.com/microsoft/kiota-serialization-json-go"
+	msmodels "github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/pzsp-teams/lib/internal/adapter"
+	"github.com/pzsp-teams/lib/models"
+)
+
+// ParsePrivateKey parses a PEM-encoded RSA private key in PKCS #1 or PKCS #8 form,
+// e.g. the key of the certificate used for rich notifications.
+func ParsePrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	for {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			return nil, errors.New("no private key found in PEM data")
+		}
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			rsaKey, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("unsupported private key type %T", key)
+			}
+			return rsaKey, nil
+		}
+	}
+}
+
+func (h *Handler) decryptMessage(content *encryptedContent) (*models.Message, error) {
+	if h.cfg.PrivateKey == nil {
+		return nil, fmt.Errorf("%w: no private key configured", ErrDecryption)
+	}
+	if h.cfg.CertificateID != "" && content.EncryptionCertificateID != h.cfg.CertificateID {
+		return nil, fmt.Errorf("%w: unknown certificate %q", ErrDecryption, content.EncryptionCertificateID)
+	}
+
+	data, err := decryptContent(h.cfg.PrivateKey, content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryption, err)
+	}
+
+	node, err := jsonserialization.NewJsonParseNode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryption, err)
+	}
+	parsed, err := node.GetObjectValue(msmodels.CreateChatMessageFromDiscriminatorValue)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryption, err)
+	}
+	msg, ok := parsed.(msmodels.ChatMessageable)
+	if !ok {
+		return nil, fmt.Errorf("%w: resource data is not a chat message", ErrDecryption)
+	}
+	return adapter.MapGraphMessage(msg), nil
+}
+
+// decryptContent decrypts resource data of a rich notification: the symmetric key is decrypted
+// with RSA-OAEP, the data is verified with HMAC-SHA256 and decrypted with AES-CBC using
+// the first 16 bytes of the key as IV.
+func decryptContent(privateKey *rsa.PrivateKey, content *encryptedContent) ([]byte, error) {
+	dataKey, err := base64.StdEncoding.DecodeString(content.DataKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding data key: %w", err)
+	}
+	key, err := rsa.DecryptOAEP(sha1.New(), nil, privateKey, dataKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting data key: %w", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(content.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding data: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(content.DataSignature)
+	if err != nil {
+		return nil, fmt.Errorf("decoding data signature: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	if !hmac.Equal(mac.Sum(nil), signature) {
+		return nil, errors.New("data signature mismatch")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("data is not a multiple of the block size")
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, key[:aes.BlockSize]).CryptBlocks(plain, data)
+	return unpad(plain)
+}
+
+// unpad removes PKCS #7 padding.
+func unpad(data []byte) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > aes.BlockSize || n > len(data) || !bytes.Equal(data[len(data)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, errors.New("invalid padding")
+	}
+	return data[:len(data)-n], nil
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrivateKey(t *testing.T) {
+	t.Parallel()
+
+	key := testEncryptionKey()
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		pem  []byte
+	}{
+		{
+			name: "PKCS #1",
+			pem:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+		{
+			name: "PKCS #8 after certificate",
+			pem: append(
+				pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("cert")}),
+				pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})...,
+			),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePrivateKey(tc.pem)
+			require.NoError(t, err)
+			assert.True(t, key.Equal(got))
+		})
+	}
+
+	_, err = ParsePrivateKey([]byte("no pem"))
+	assert.Error(t, err)
+}
+
+func TestDecryptContent_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	key := testEncryptionKey()
+	for _, data := range []string{"{}", "exactly 16 bytes", `{"id":"1","body":{"content":"a longer message"}}`} {
+		got, err := decryptContent(key, encrypt(t, &key.PublicKey, []byte(data)))
+		require.NoError(t, err)
+		assert.Equal(t, data, string(got))
+	}
+}
@@ -0,0 +1,177 @@
+// Package webhook provides an http.Handler receiving Microsoft Graph change notifications
+// for subscriptions created with the subscriptions package.
+//
+// Concepts:
+//   - Subscription validation: Graph calls the notification URL with a validationToken query parameter
+//     when a subscription is created; the handler echoes the token back.
+//   - Every notification carries the client state of its subscription. Notifications with a client state
+//     other than Config.ClientState are dropped and reported to Config.OnError.
+//   - Lifecycle notifications (reauthorizationRequired, missed, subscriptionRemoved) are delivered
+//     to Config.OnLifecycle. The same handler may serve both the notification and the lifecycle URL.
+//   - Rich notifications (subscriptions with resource data) carry the message encrypted with the public key
+//     of the subscription certificate. The handler decrypts it with Config.PrivateKey, after validating
+//     the validation tokens (JWTs issued by Microsoft) sent with the notifications against Config.AppIDs.
+//   - Message changes are delivered to Config.OnMessage as MessageEvent values. The Message is set only
+//     for rich notifications of created or updated messages; otherwise fetch it using the IDs of the event.
+//
+// Callbacks run before the handler responds, so they should return quickly; Graph retries notifications
+// which are not acknowledged within a few seconds.
+package webhook
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// maxBodySize limits the size of a notification batch read by the handler.
+const maxBodySize = 4 << 20
+
+var (
+	// ErrInvalidClientState is reported for notifications whose client state does not match Config.ClientState.
+	ErrInvalidClientState = errors.New("invalid client state")
+	// ErrInvalidToken is returned when validation tokens of rich notifications are missing or invalid.
+	ErrInvalidToken = errors.New("invalid validation token")
+	// ErrDecryption is reported for rich notifications whose resource data cannot be decrypted.
+	ErrDecryption = errors.New("cannot decrypt resource data")
+)
+
+// Config configures the notification handler.
+type Config struct {
+	// ClientState is the secret set as SubscriptionOptions.ClientState of the subscriptions.
+	ClientState string
+
+	// PrivateKey is the private key of the encryption certificate, required for rich notifications.
+	PrivateKey *rsa.PrivateKey
+
+	// CertificateID is the EncryptionCertificateID of the subscriptions. When set, rich notifications
+	// encrypted with another certificate are rejected.
+	CertificateID string
+
+	// AppIDs are the application (client) IDs accepted as the audience of validation tokens.
+	// Required for rich notifications.
+	AppIDs []string
+
+	// TenantIDs, when set, restricts the tenants whose validation tokens are accepted.
+	TenantIDs []string
+
+	// Keys provides the keys signing validation tokens; defaults to the Microsoft identity platform keys.
+	Keys KeySet
+
+	// OnMessage is called for every verified notification about a message.
+	OnMessage func(ctx context.Context, event MessageEvent)
+
+	// OnLifecycle is called for every verified lifecycle notification.
+	OnLifecycle func(ctx context.Context, event LifecycleEvent)
+
+	// OnError is called for every notification that was dropped.
+	OnError func(ctx context.Context, err error)
+}
+
+// Handler handles Microsoft Graph change notifications. Create it with NewHandler.
+type Handler struct {
+	cfg Config
+	now func() time.Time
+}
+
+// NewHandler creates a notification handler.
+func NewHandler(cfg *Config) *Handler {
+	h := &Handler{now: time.Now}
+	if cfg != nil {
+		h.cfg = *cfg
+	}
+	if h.cfg.Keys == nil {
+		h.cfg.Keys = NewJWKSKeySet(DefaultJWKSURL, nil)
+	}
+	return h
+}
+
+// ServeHTTP answers validation requests and processes notification batches.
+// It responds 202 Accepted once the batch was processed; malformed batches get 400 Bad Request
+// and batches of rich notifications with invalid validation tokens 401 Unauthorized.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if token, ok := r.URL.Query()["validationToken"]; ok {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, firstOrEmpty(token))
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var batch notificationBatch
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&batch); err != nil {
+		http.Error(w, "malformed notification", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if batch.hasEncryptedContent() {
+		if err := h.validateTokens(ctx, batch.ValidationTokens); err != nil {
+			h.reportError(ctx, err)
+			http.Error(w, "invalid validation token", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	for _, n := range batch.Value {
+		if err := h.process(ctx, n); err != nil {
+			h.reportError(ctx, fmt.Errorf("subscription %s: %w", n.SubscriptionID, err))
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) process(ctx context.Context, n *notification) error {
+	if n == nil {
+		return nil
+	}
+	if n.ClientState != h.cfg.ClientState {
+		return ErrInvalidClientState
+	}
+
+	if n.LifecycleEvent != "" {
+		if h.cfg.OnLifecycle != nil {
+			h.cfg.OnLifecycle(ctx, n.lifecycleEvent())
+		}
+		return nil
+	}
+
+	event, err := n.messageEvent()
+	if err != nil {
+		return err
+	}
+	if n.EncryptedContent != nil && event.ChangeType != models.ChangeTypeDeleted {
+		event.Message, err = h.decryptMessage(n.EncryptedContent)
+		if err != nil {
+			return err
+		}
+	}
+	if h.cfg.OnMessage != nil {
+		h.cfg.OnMessage(ctx, event)
+	}
+	return nil
+}
+
+func (h *Handler) reportError(ctx context.Context, err error) {
+	if h.cfg.OnError != nil {
+		h.cfg.OnError(ctx, err)
+	}
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
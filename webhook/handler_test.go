@@ -0,0 +1,401 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pzsp-teams/lib/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAppID    = "11111111-2222-3333-4444-555555555555"
+	testTenantID = "tenant-1"
+	testKeyID    = "key-1"
+	testCertID   = "cert-1"
+	testState    = "secret"
+)
+
+var (
+	testNow = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	// Generating RSA keys is slow, so the keys are shared by all tests.
+	testEncryptionKey = sync.OnceValue(func() *rsa.PrivateKey { return mustGenerateKey() })
+	testSigningKey    = sync.OnceValue(func() *rsa.PrivateKey { return mustGenerateKey() })
+)
+
+func mustGenerateKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+type recorder struct {
+	mu        sync.Mutex
+	messages  []MessageEvent
+	lifecycle []LifecycleEvent
+	errs      []error
+}
+
+// newTestHandler creates a handler accepting tokens signed with testSigningKey, served by a local key set.
+func newTestHandler(t *testing.T) (*Handler, *recorder) {
+	t.Helper()
+
+	pub := testSigningKey().PublicKey
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(jwks.Close)
+
+	rec := &recorder{}
+	h := NewHandler(&Config{
+		ClientState:   testState,
+		PrivateKey:    testEncryptionKey(),
+		CertificateID: testCertID,
+		AppIDs:        []string{testAppID},
+		Keys:          NewJWKSKeySet(jwks.URL, jwks.Client()),
+		OnMessage: func(_ context.Context, e MessageEvent) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.messages = append(rec.messages, e)
+		},
+		OnLifecycle: func(_ context.Context, e LifecycleEvent) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.lifecycle = append(rec.lifecycle, e)
+		},
+		OnError: func(_ context.Context, err error) {
+			rec.mu.Lock()
+			defer rec.mu.Unlock()
+			rec.errs = append(rec.errs, err)
+		},
+	})
+	h.now = func() time.Time { return testNow }
+	return h, rec
+}
+
+func post(t *testing.T, h http.Handler, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notifications", bytes.NewReader(data)))
+	return w
+}
+
+func signToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(testSigningKey())
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"aud": testAppID,
+		"iss": issuerPrefix + testTenantID + "/",
+		"azp": notificationServiceAppID,
+		"exp": testNow.Add(time.Hour).Unix(),
+		"nbf": testNow.Add(-time.Minute).Unix(),
+	}
+}
+
+// encrypt encrypts data the way Graph encrypts resource data of rich notifications.
+func encrypt(t *testing.T, pub *rsa.PublicKey, data []byte) *encryptedContent {
+	t.Helper()
+
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	n := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append(bytes.Clone(data), bytes.Repeat([]byte{byte(n)}, n)...)
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, key[:aes.BlockSize]).CryptBlocks(encrypted, padded)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(encrypted)
+
+	dataKey, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub, key, nil)
+	require.NoError(t, err)
+
+	return &encryptedContent{
+		Data:                    base64.StdEncoding.EncodeToString(encrypted),
+		DataKey:                 base64.StdEncoding.EncodeToString(dataKey),
+		DataSignature:           base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+		EncryptionCertificateID: testCertID,
+	}
+}
+
+func richNotification(t *testing.T, changeType string) *notification {
+	t.Helper()
+
+	message := `{"id":"1700","body":{"contentType":"html","content":"<p>hi</p>"},` +
+		`"createdDateTime":"2025-01-02T03:04:05Z","from":{"user":{"id":"u1","displayName":"Ann"}}}`
+	return &notification{
+		SubscriptionID:   "s1",
+		ChangeType:       changeType,
+		Resource:         "chats('19:abc@thread.v2')/messages('1700')",
+		ClientState:      testState,
+		TenantID:         testTenantID,
+		EncryptedContent: encrypt(t, &testEncryptionKey().PublicKey, []byte(message)),
+	}
+}
+
+func TestHandler_EchoesValidationToken(t *testing.T) {
+	t.Parallel()
+
+	h, rec := newTestHandler(t)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notifications?validationToken=Validation%3A+token", http.NoBody))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+	assert.Equal(t, "Validation: token", w.Body.String())
+	assert.Empty(t, rec.messages)
+}
+
+func TestHandler_RejectsMalformedRequests(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newTestHandler(t)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/notifications", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notifications", http.NoBody))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandler_DeliversMessageEvents(t *testing.T) {
+	t.Parallel()
+
+	h, rec := newTestHandler(t)
+	w := post(t, h, notificationBatch{Value: []*notification{
+		{
+			SubscriptionID: "s1",
+			ChangeType:     "updated",
+			Resource:       "teams('t1')/channels('19:c1@thread.tacv2')/messages('100')/replies('200')",
+			ClientState:    testState,
+			TenantID:       testTenantID,
+		},
+		{
+			SubscriptionID: "s2",
+			ChangeType:     "deleted",
+			Resource:       "chats/19:abc@thread.v2/messages/300",
+			ClientState:    testState,
+		},
+	}})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Empty(t, rec.errs)
+	require.Len(t, rec.messages, 2)
+	assert.Equal(t, MessageEvent{
+		ChangeType:     models.ChangeTypeUpdated,
+		SubscriptionID: "s1",
+		TenantID:       testTenantID,
+		Resource:       "teams('t1')/channels('19:c1@thread.tacv2')/messages('100')/replies('200')",
+		TeamID:         "t1",
+		ChannelID:      "19:c1@thread.tacv2",
+		MessageID:      "200",
+		ReplyToID:      "100",
+	}, rec.messages[0])
+	assert.Equal(t, models.ChangeTypeDeleted, rec.messages[1].ChangeType)
+	assert.Equal(t, "19:abc@thread.v2", rec.messages[1].ChatID)
+	assert.Equal(t, "300", rec.messages[1].MessageID)
+}
+
+func TestHandler_DropsInvalidClientState(t *testing.T) {
+	t.Parallel()
+
+	h, rec := newTestHandler(t)
+	w := post(t, h, notificationBatch{Value: []*notification{
+		{SubscriptionID: "s1", ChangeType: "created", Resource: "chats('c')/messages('1')", ClientState: "forged"},
+		{SubscriptionID: "s1", ChangeType: "created", Resource: "chats('c')/messages('2')", ClientState: testState},
+	}})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, rec.errs, 1)
+	assert.ErrorIs(t, rec.errs[0], ErrInvalidClientState)
+	require.Len(t, rec.messages, 1)
+	assert.Equal(t, "2", rec.messages[0].MessageID)
+}
+
+func TestHandler_DeliversLifecycleEvents(t *testing.T) {
+	t.Parallel()
+
+	h, rec := newTestHandler(t)
+	w := post(t, h, notificationBatch{Value: []*notification{{
+		SubscriptionID:                 "s1",
+		SubscriptionExpirationDateTime: testNow.Add(10 * time.Minute),
+		ClientState:                    testState,
+		TenantID:                       testTenantID,
+		LifecycleEvent:                 "reauthorizationRequired",
+	}}})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, rec.messages)
+	assert.Equal(t, []LifecycleEvent{{
+		Type:                           LifecycleReauthorizationRequired,
+		SubscriptionID:                 "s1",
+		SubscriptionExpirationDateTime: testNow.Add(10 * time.Minute),
+		TenantID:                       testTenantID,
+	}}, rec.lifecycle)
+}
+
+func TestHandler_DecryptsRichNotifications(t *testing.T) {
+	t.Parallel()
+
+	h, rec := newTestHandler(t)
+	w := post(t, h, notificationBatch{
+		Value:            []*notification{richNotification(t, "created")},
+		ValidationTokens: []string{signToken(t, validClaims())},
+	})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Empty(t, rec.errs)
+	require.Len(t, rec.messages, 1)
+	msg := rec.messages[0].Message
+	require.NotNil(t, msg)
+	assert.Equal(t, "1700", msg.ID)
+	assert.Equal(t, "<p>hi</p>", msg.Content)
+	assert.Equal(t, models.MessageContentTypeHTML, msg.ContentType)
+	assert.Equal(t, &models.MessageFrom{UserID: "u1", DisplayName: "Ann"}, msg.From)
+	assert.Equal(t, "19:abc@thread.v2", rec.messages[0].ChatID)
+}
+
+func TestHandler_RejectsInvalidValidationTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		tokens func(t *testing.T) []string
+	}{
+		{
+			name:   "missing",
+			tokens: func(*testing.T) []string { return nil },
+		},
+		{
+			name: "expired",
+			tokens: func(t *testing.T) []string {
+				claims := validClaims()
+				claims["exp"] = testNow.Add(-time.Minute).Unix()
+				return []string{signToken(t, claims)}
+			},
+		},
+		{
+			name: "other audience",
+			tokens: func(t *testing.T) []string {
+				claims := validClaims()
+				claims["aud"] = "other-app"
+				return []string{signToken(t, claims)}
+			},
+		},
+		{
+			name: "other issuer",
+			tokens: func(t *testing.T) []string {
+				claims := validClaims()
+				claims["iss"] = "https://example.com/"
+				return []string{signToken(t, claims)}
+			},
+		},
+		{
+			name: "not issued to notification service",
+			tokens: func(t *testing.T) []string {
+				claims := validClaims()
+				claims["azp"] = "other-app"
+				return []string{signToken(t, claims)}
+			},
+		},
+		{
+			name: "one of several invalid",
+			tokens: func(t *testing.T) []string {
+				return []string{signToken(t, validClaims()), "not-a-token"}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, rec := newTestHandler(t)
+			w := post(t, h, notificationBatch{
+				Value:            []*notification{richNotification(t, "created")},
+				ValidationTokens: tc.tokens(t),
+			})
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Empty(t, rec.messages)
+			require.Len(t, rec.errs, 1)
+			assert.ErrorIs(t, rec.errs[0], ErrInvalidToken)
+		})
+	}
+}
+
+func TestHandler_ReportsTamperedResourceData(t *testing.T) {
+	t.Parallel()
+
+	h, rec := newTestHandler(t)
+	n := richNotification(t, "updated")
+	n.EncryptedContent.DataSignature = base64.StdEncoding.EncodeToString([]byte("forged"))
+	w := post(t, h, notificationBatch{
+		Value:            []*notification{n},
+		ValidationTokens: []string{signToken(t, validClaims())},
+	})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, rec.messages)
+	require.Len(t, rec.errs, 1)
+	assert.ErrorIs(t, rec.errs[0], ErrDecryption)
+}
+
+func TestHandler_RichDeleteNeedsNoDecryption(t *testing.T) {
+	t.Parallel()
+
+	h, rec := newTestHandler(t)
+	n := richNotification(t, "deleted")
+	n.EncryptedContent.DataKey = ""
+	w := post(t, h, notificationBatch{
+		Value:            []*notification{n},
+		ValidationTokens: []string{signToken(t, validClaims())},
+	})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, rec.messages, 1)
+	assert.Nil(t, rec.messages[0].Message)
+	assert.Equal(t, "1700", rec.messages[0].MessageID)
+}
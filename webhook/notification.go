@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pzsp-teams/lib/models"
+)
+
+// LifecycleEventType is a type of lifecycle notification.
+type LifecycleEventType string
+
+const (
+	// LifecycleReauthorizationRequired is sent when the subscription needs to be renewed to keep receiving notifications.
+	LifecycleReauthorizationRequired LifecycleEventType = "reauthorizationRequired"
+	// LifecycleMissed is sent when some notifications were not delivered; resynchronize the resource.
+	LifecycleMissed LifecycleEventType = "missed"
+	// LifecycleSubscriptionRemoved is sent when the subscription was removed; create it again.
+	LifecycleSubscriptionRemoved LifecycleEventType = "subscriptionRemoved"
+)
+
+// MessageEvent is a verified notification about a created, updated or deleted message.
+type MessageEvent struct {
+	ChangeType     models.ChangeType
+	SubscriptionID string
+	TenantID       string
+	// Resource is the path of the changed message, e.g. "chats('19:...')/messages('1700000000000')".
+	Resource string
+	// TeamID and ChannelID are set for channel messages, ChatID for chat messages.
+	TeamID    string
+	ChannelID string
+	ChatID    string
+	MessageID string
+	// ReplyToID is the ID of the parent message when the message is a channel reply.
+	ReplyToID string
+	// Message is the changed message, decrypted from rich notifications of created and updated messages.
+	Message *models.Message
+}
+
+// LifecycleEvent is a verified lifecycle notification of a subscription.
+type LifecycleEvent struct {
+	Type                           LifecycleEventType
+	SubscriptionID                 string
+	SubscriptionExpirationDateTime time.Time
+	TenantID                       string
+}
+
+type notificationBatch struct {
+	Value            []*notification `json:"value"`
+	ValidationTokens []string        `json:"validationTokens"`
+}
+
+type notification struct {
+	SubscriptionID                 string            `json:"subscriptionId"`
+	SubscriptionExpirationDateTime time.Time         `json:"subscriptionExpirationDateTime"`
+	ChangeType                     string            `json:"changeType"`
+	Resource                       string            `json:"resource"`
+	ResourceData                   *resourceData     `json:"resourceData"`
+	ClientState                    string            `json:"clientState"`
+	TenantID                       string            `json:"tenantId"`
+	LifecycleEvent                 string            `json:"lifecycleEvent"`
+	EncryptedContent               *encryptedContent `json:"encryptedContent"`
+}
+
+type resourceData struct {
+	ID string `json:"id"`
+}
+
+type encryptedContent struct {
+	Data                    string `json:"data"`
+	DataKey                 string `json:"dataKey"`
+	DataSignature           string `json:"dataSignature"`
+	EncryptionCertificateID string `json:"encryptionCertificateId"`
+}
+
+func (b *notificationBatch) hasEncryptedContent() bool {
+	for _, n := range b.Value {
+		if n != nil && n.EncryptedContent != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *notification) lifecycleEvent() LifecycleEvent {
+	return LifecycleEvent{
+		Type:                           LifecycleEventType(n.LifecycleEvent),
+		SubscriptionID:                 n.SubscriptionID,
+		SubscriptionExpirationDateTime: n.SubscriptionExpirationDateTime,
+		TenantID:                       n.TenantID,
+	}
+}
+
+func (n *notification) messageEvent() (MessageEvent, error) {
+	changeType := models.ChangeType(n.ChangeType)
+	switch changeType {
+	case models.ChangeTypeCreated, models.ChangeTypeUpdated, models.ChangeTypeDeleted:
+	default:
+		return MessageEvent{}, fmt.Errorf("unknown change type %q", n.ChangeType)
+	}
+
+	event := MessageEvent{
+		ChangeType:     changeType,
+		SubscriptionID: n.SubscriptionID,
+		TenantID:       n.TenantID,
+		Resource:       n.Resource,
+	}
+	ids := parseResource(n.Resource)
+	event.TeamID = ids["teams"]
+	event.ChannelID = ids["channels"]
+	event.ChatID = ids["chats"]
+	event.MessageID = ids["messages"]
+	if reply, ok := ids["replies"]; ok {
+		event.ReplyToID = event.MessageID
+		event.MessageID = reply
+	}
+	if event.MessageID == "" && n.ResourceData != nil {
+		event.MessageID = n.ResourceData.ID
+	}
+	if event.MessageID == "" {
+		return MessageEvent{}, fmt.Errorf("unsupported resource %q", n.Resource)
+	}
+	return event, nil
+}
+
+// segmentPattern matches a resource path segment with a key, e.g. "messages('123')".
+var segmentPattern = regexp.MustCompile(`^(\w+)\('([^']*)'\)$`)
+
+// parseResource maps collection names of a resource path to the keys following them.
+// Both "chats('id')/messages('id')" and "chats/id/messages/id" forms are supported.
+func parseResource(resource string) map[string]string {
+	ids := make(map[string]string)
+	segments := strings.Split(strings.Trim(resource, "/"), "/")
+	for i := 0; i < len(segments); i++ {
+		if m := segmentPattern.FindStringSubmatch(segments[i]); m != nil {
+			ids[strings.ToLower(m[1])] = m[2]
+			continue
+		}
+		if i+1 < len(segments) {
+			ids[strings.ToLower(segments[i])] = segments[i+1]
+			i++
+		}
+	}
+	return ids
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resource string
+		want     map[string]string
+	}{
+		{
+			name:     "channel message",
+			resource: "teams('t1')/channels('19:c1@thread.tacv2')/messages('100')",
+			want:     map[string]string{"teams": "t1", "channels": "19:c1@thread.tacv2", "messages": "100"},
+		},
+		{
+			name:     "chat message in path form",
+			resource: "/chats/19:abc@thread.v2/messages/300",
+			want:     map[string]string{"chats": "19:abc@thread.v2", "messages": "300"},
+		},
+		{
+			name:     "channel reply",
+			resource: "Teams('t1')/Channels('c1')/Messages('100')/Replies('200')",
+			want:     map[string]string{"teams": "t1", "channels": "c1", "messages": "100", "replies": "200"},
+		},
+		{
+			name:     "empty",
+			resource: "",
+			want:     map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, parseResource(tc.resource))
+		})
+	}
+}
+
+func TestNotification_MessageEvent_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&notification{ChangeType: "moved", Resource: "chats('c')/messages('1')"}).messageEvent()
+	assert.ErrorContains(t, err, "unknown change type")
+
+	_, err = (&notification{ChangeType: "created", Resource: "chats('c')"}).messageEvent()
+	assert.ErrorContains(t, err, "unsupported resource")
+
+	event, err := (&notification{ChangeType: "created", Resource: "chats('c')", ResourceData: &resourceData{ID: "1"}}).messageEvent()
+	assert.NoError(t, err)
+	assert.Equal(t, "1", event.MessageID)
+}
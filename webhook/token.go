@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultJWKSURL is the location of the keys signing validation tokens of rich notifications.
+	DefaultJWKSURL = "https://login.microsoftonline.com/common/discovery/v2.0/keys"
+
+	// notificationServiceAppID is the application ID of the Microsoft Graph change notification publisher.
+	notificationServiceAppID = "0bf30f3b-4a52-48df-9a82-234910c4a086"
+
+	// issuerPrefix is the prefix of validation token issuers, followed by the tenant ID.
+	issuerPrefix = "https://sts.windows.net/"
+
+	// jwksRefreshInterval limits how often the key set is fetched again for an unknown key ID.
+	jwksRefreshInterval = 5 * time.Minute
+)
+
+// KeySet provides public keys verifying validation tokens by their key ID.
+type KeySet interface {
+	Key(ctx context.Context, keyID string) (crypto.PublicKey, error)
+}
+
+type jwksKeySet struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKSKeySet creates a KeySet fetching keys from a JSON Web Key Set at url.
+// Keys are cached and fetched again when a token is signed with an unknown key.
+// A nil client uses http.DefaultClient.
+func NewJWKSKeySet(url string, client *http.Client) KeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &jwksKeySet{url: url, client: client}
+}
+
+func (s *jwksKeySet) Key(ctx context.Context, keyID string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.keys[keyID]; ok {
+		return key, nil
+	}
+	if s.keys != nil && time.Since(s.fetchedAt) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.keys, s.fetchedAt = keys, time.Now()
+
+	if key, ok := s.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", keyID)
+}
+
+func (s *jwksKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching key set: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching key set: unexpected status %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decoding key set: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+// validateTokens validates the validation tokens sent with rich notifications.
+// Every token must be valid, and at least one must be present.
+func (h *Handler) validateTokens(ctx context.Context, tokens []string) error {
+	if len(h.cfg.AppIDs) == 0 {
+		return fmt.Errorf("%w: no app IDs configured", ErrInvalidToken)
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("%w: no validation tokens", ErrInvalidToken)
+	}
+	for _, token := range tokens {
+		if err := h.validateToken(ctx, token); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+	}
+	return nil
+}
+
+func (h *Handler) validateToken(ctx context.Context, token string) error {
+	keyFunc := func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return h.cfg.Keys.Key(ctx, kid)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(h.now),
+	)
+	if err != nil {
+		return err
+	}
+
+	audiences, err := claims.GetAudience()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(audiences, func(aud string) bool { return slices.Contains(h.cfg.AppIDs, aud) }) {
+		return errors.New("unexpected audience")
+	}
+
+	issuer, err := claims.GetIssuer()
+	if err != nil {
+		return err
+	}
+	tenantID, ok := strings.CutPrefix(issuer, issuerPrefix)
+	if !ok {
+		return errors.New("unexpected issuer")
+	}
+	if len(h.cfg.TenantIDs) > 0 && !slices.Contains(h.cfg.TenantIDs, strings.TrimSuffix(tenantID, "/")) {
+		return errors.New("unexpected tenant")
+	}
+
+	azp, _ := claims["azp"].(string)
+	if azp == "" {
+		azp, _ = claims["appid"].(string)
+	}
+	if azp != notificationServiceAppID {
+		return errors.New("token not issued to the change notification service")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSKeySet_CachesKeys(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "k1", "n": "AQAB", "e": "AQAB"},
+			{"kty": "EC", "kid": "k2"},
+		}})
+	}))
+	t.Cleanup(srv.Close)
+
+	keys := NewJWKSKeySet(srv.URL, srv.Client())
+	ctx := context.Background()
+
+	_, err := keys.Key(ctx, "k1")
+	require.NoError(t, err)
+	_, err = keys.Key(ctx, "k1")
+	require.NoError(t, err)
+	_, err = keys.Key(ctx, "k2")
+	assert.ErrorContains(t, err, "unknown key")
+	assert.Equal(t, int32(1), fetches.Load(), "unknown keys are not fetched again right after a fetch")
+}
+
+func TestJWKSKeySet_FetchError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewJWKSKeySet(srv.URL, srv.Client()).Key(context.Background(), "k1")
+	assert.ErrorContains(t, err, "unexpected status")
+}